@@ -36,14 +36,15 @@ func main() {
 
 	// 创建服务器，显式启用CORS支持
 	// 允许所有来源（开发环境），生产环境应限制为特定域名
-	// 允许必要的请求头
+	// 允许必要的请求头（包括撤销分享和访问会话时使用的所有者令牌）
 	server := rest.MustNewServer(c.RestConf,
 		rest.WithCors("*"),
-		rest.WithCorsHeaders("Content-Type", "Authorization"),
+		rest.WithCorsHeaders("Content-Type", "Authorization", "X-Share-Owner-Token", "X-Session-Owner-Token"),
 	)
 	defer server.Stop()
 
 	ctx := svc.NewServiceContext(c)
+	defer ctx.Close()
 	handler.RegisterHandlers(server, ctx)
 
 	// 注册静态文件服务（可选，用于 Docker 部署）
@@ -210,6 +211,17 @@ func loadAIConfigFromEnv(c *config.Config) {
 		c.AI.ImageGenerationModel = configpkg.DefaultImageGenerationModel
 	}
 
+	// 处理按角色划分的模型提供方配置（环境变量优先级高于 YAML）
+	loadModelRoleFromEnv("INTENT", &c.AI.Models.Intent)
+	loadModelRoleFromEnv("VISION", &c.AI.Models.Vision)
+	loadModelRoleFromEnv("TEXT", &c.AI.Models.Text)
+	loadModelRoleFromEnv("CARD", &c.AI.Models.Card)
+	loadModelRoleFromEnv("IMAGE_GEN", &c.AI.Models.ImageGen)
+	// 卡片生成模型列表（数组类型，需要手动解析，未设置则使用文本生成模型列表）
+	if models := parseCommaSeparatedList(os.Getenv("CARD_MODELS")); len(models) > 0 {
+		c.AI.Models.Card.Models = models
+	}
+
 	// 处理UseAIModel配置（优先级：环境变量 > 配置文件 > 默认值true）
 	useAIModelStr := os.Getenv("USE_AI_MODEL")
 	if useAIModelStr != "" {
@@ -228,6 +240,41 @@ func loadAIConfigFromEnv(c *config.Config) {
 	}
 }
 
+// loadModelRoleFromEnv 从环境变量加载单个模型角色的提供方配置
+// 变量名为 <prefix>_MODEL_PROVIDER、<prefix>_MODEL_BASE_URL、<prefix>_MODEL_API_KEY、<prefix>_MODEL_TIMEOUT、
+// <prefix>_MODEL_TEMPERATURE、<prefix>_MODEL_TOP_P、<prefix>_MODEL_MAX_TOKENS、<prefix>_MODEL_POLICY、<prefix>_MODEL_WEIGHTS，
+// 无法解析的数值忽略
+func loadModelRoleFromEnv(prefix string, role *configpkg.ModelRoleConfig) {
+	prefix += "_MODEL_"
+	if provider := os.Getenv(prefix + "PROVIDER"); provider != "" {
+		role.Provider = strings.ToLower(strings.TrimSpace(provider))
+	}
+	if baseURL := os.Getenv(prefix + "BASE_URL"); baseURL != "" {
+		role.BaseURL = baseURL
+	}
+	if apiKey := os.Getenv(prefix + "API_KEY"); apiKey != "" {
+		role.APIKey = apiKey
+	}
+	if timeout, err := strconv.Atoi(os.Getenv(prefix + "TIMEOUT")); err == nil {
+		role.TimeoutSeconds = timeout
+	}
+	if temperature, err := strconv.ParseFloat(os.Getenv(prefix+"TEMPERATURE"), 64); err == nil {
+		role.Temperature = temperature
+	}
+	if topP, err := strconv.ParseFloat(os.Getenv(prefix+"TOP_P"), 64); err == nil {
+		role.TopP = topP
+	}
+	if maxTokens, err := strconv.Atoi(os.Getenv(prefix + "MAX_TOKENS")); err == nil {
+		role.MaxTokens = maxTokens
+	}
+	if policy := os.Getenv(prefix + "POLICY"); policy != "" {
+		role.Policy = strings.ToLower(strings.TrimSpace(policy))
+	}
+	if weights := os.Getenv(prefix + "WEIGHTS"); weights != "" {
+		role.Weights = weights
+	}
+}
+
 // parseCommaSeparatedList 解析逗号分隔的字符串列表
 func parseCommaSeparatedList(s string) []string {
 	if s == "" {
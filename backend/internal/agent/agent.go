@@ -3,7 +3,9 @@ package agent
 import (
 	"context"
 
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/tools/mcp"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -15,8 +17,8 @@ type Agent struct {
 	ctx    context.Context
 }
 
-// NewAgent 创建新的Agent实例
-func NewAgent(ctx context.Context, cfg config.AIConfig) (*Agent, error) {
+// NewAgent 创建新的Agent实例，各节点通过 models 按角色创建模型客户端
+func NewAgent(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory) (*Agent, error) {
 	logger := logx.WithContext(ctx)
 
 	agent := &Agent{
@@ -26,7 +28,7 @@ func NewAgent(ctx context.Context, cfg config.AIConfig) (*Agent, error) {
 	}
 
 	// 初始化Graph
-	graph, err := NewGraph(ctx, cfg, logger)
+	graph, err := NewGraph(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +48,7 @@ func (a *Agent) GetGraph() *Graph {
 }
 
 // Close 关闭Agent，清理资源
+// MCP客户端在进程内共享，在此断开连接并结束stdio子进程
 func (a *Agent) Close() error {
-	// TODO: 清理eino相关资源
-	return nil
+	return mcp.CloseMCPClients()
 }
@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tango/explore/internal/types"
+)
+
+// 执行步骤状态
+const (
+	StepStarted   = "started"
+	StepCompleted = "completed"
+	StepFailed    = "failed"
+)
+
+// executionTrace 记录一次多Agent执行的轨迹（实现 nodes.StepRecorder）
+// 节点可能在流式处理的goroutine中结束，所有访问都需加锁
+type executionTrace struct {
+	mu      sync.Mutex
+	state   types.GraphExecutionState
+	pending []types.AgentStep // 尚未推送给客户端的步骤
+}
+
+// newExecutionTrace 创建执行轨迹
+func newExecutionTrace() *executionTrace {
+	return &executionTrace{
+		state: types.GraphExecutionState{
+			ExecutionPath:       []string{},
+			IntermediateResults: make(map[string]interface{}),
+			StartTime:           time.Now(),
+			Steps:               []types.AgentStep{},
+		},
+	}
+}
+
+// StartStep 记录节点开始执行，返回结束记录函数
+func (t *executionTrace) StartStep(node string) func(decision map[string]interface{}, err error) {
+	start := time.Now()
+
+	t.mu.Lock()
+	t.state.CurrentNode = node
+	t.state.ExecutionPath = append(t.state.ExecutionPath, node)
+	t.addStep(types.AgentStep{Node: node, Status: StepStarted, Time: start})
+	t.mu.Unlock()
+
+	return func(decision map[string]interface{}, err error) {
+		end := time.Now()
+		step := types.AgentStep{
+			Node:      node,
+			Status:    StepCompleted,
+			Decision:  decision,
+			LatencyMs: end.Sub(start).Milliseconds(),
+			Time:      end,
+		}
+		if err != nil {
+			step.Status = StepFailed
+			step.Error = err.Error()
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if err != nil {
+			t.state.ErrorState = node + ": " + err.Error()
+		}
+		if decision != nil {
+			// 同一节点执行多次（如多次工具调用）时按顺序保存为列表
+			switch existing := t.state.IntermediateResults[node].(type) {
+			case nil:
+				t.state.IntermediateResults[node] = decision
+			case map[string]interface{}:
+				t.state.IntermediateResults[node] = []map[string]interface{}{existing, decision}
+			case []map[string]interface{}:
+				t.state.IntermediateResults[node] = append(existing, decision)
+			}
+		}
+		t.addStep(step)
+	}
+}
+
+// addStep 保存步骤（调用方需持有锁）
+func (t *executionTrace) addStep(step types.AgentStep) {
+	t.state.Steps = append(t.state.Steps, step)
+	t.pending = append(t.pending, step)
+}
+
+// drain 取出尚未推送的步骤
+func (t *executionTrace) drain() []types.AgentStep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	steps := t.pending
+	t.pending = nil
+	return steps
+}
+
+// toolsCalled 已成功调用的工具名称（按调用顺序）
+func (t *executionTrace) toolsCalled() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tools := []string{}
+	for _, step := range t.state.Steps {
+		if step.Node == "Tool" && step.Status == StepCompleted {
+			if name, ok := step.Decision["tool"].(string); ok {
+				tools = append(tools, name)
+			}
+		}
+	}
+	return tools
+}
+
+// finish 记录执行结束
+func (t *executionTrace) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.state.EndTime = &now
+	t.state.CurrentNode = ""
+}
+
+// snapshot 返回执行状态的副本
+func (t *executionTrace) snapshot() types.GraphExecutionState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state
+	state.ExecutionPath = append([]string(nil), t.state.ExecutionPath...)
+	state.Steps = append([]types.AgentStep(nil), t.state.Steps...)
+	state.IntermediateResults = make(map[string]interface{}, len(t.state.IntermediateResults))
+	for k, v := range t.state.IntermediateResults {
+		state.IntermediateResults[k] = v
+	}
+	return state
+}
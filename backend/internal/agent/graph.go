@@ -3,10 +3,15 @@ package agent
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/agent/nodes"
+	"github.com/tango/explore/internal/cards"
 	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/taxonomy"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -22,40 +27,49 @@ type Graph struct {
 	imageGenerationNode   *nodes.ImageGenerationNode
 	intentRecognitionNode *nodes.IntentRecognitionNode
 	conversationNode      *nodes.ConversationNode
+
+	// cardRegistry 卡片生成器注册表（按卡片类型选择生成器）
+	cardRegistry *nodes.CardRegistry
 }
 
-// NewGraph 创建新的Graph实例
-func NewGraph(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*Graph, error) {
+// NewGraph 创建新的Graph实例，models 为空时按配置创建模型工厂
+func NewGraph(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*Graph, error) {
 	graph := &Graph{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
 	}
+	if models == nil {
+		models = llm.NewModelFactory(cfg)
+	}
 
 	// 初始化各个节点
 	var err error
 
-	graph.imageRecognitionNode, err = nodes.NewImageRecognitionNode(ctx, cfg, logger)
+	graph.imageRecognitionNode, err = nodes.NewImageRecognitionNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	graph.textGenerationNode, err = nodes.NewTextGenerationNode(ctx, cfg, logger)
+	graph.textGenerationNode, err = nodes.NewTextGenerationNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	graph.imageGenerationNode, err = nodes.NewImageGenerationNode(ctx, cfg, logger)
+	graph.cardRegistry = nodes.NewCardRegistry()
+	graph.textGenerationNode.RegisterCardGenerators(graph.cardRegistry)
+
+	graph.imageGenerationNode, err = nodes.NewImageGenerationNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	graph.intentRecognitionNode, err = nodes.NewIntentRecognitionNode(ctx, cfg, logger)
+	graph.intentRecognitionNode, err = nodes.NewIntentRecognitionNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	graph.conversationNode, err = nodes.NewConversationNode(ctx, cfg, logger)
+	graph.conversationNode, err = nodes.NewConversationNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +78,28 @@ func NewGraph(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*Gr
 	return graph, nil
 }
 
+// SetImageFetcher 设置图片识别节点使用的远程图片下载器
+func (g *Graph) SetImageFetcher(fetcher *imageproc.Fetcher) {
+	g.imageRecognitionNode.SetFetcher(fetcher)
+}
+
+// SetImageStorePrefix 设置本服务图片存储的URL前缀，图片识别节点按URL缓存这些图片的识别结果
+func (g *Graph) SetImageStorePrefix(prefix string) {
+	g.imageRecognitionNode.SetImageStorePrefix(prefix)
+}
+
+// SetTaxonomy 设置图片识别节点使用的类别树
+func (g *Graph) SetTaxonomy(t *taxonomy.Taxonomy) {
+	g.imageRecognitionNode.SetTaxonomy(t)
+}
+
+// ImageRecognitionEnabled 图片识别是否使用真实模型
+func (g *Graph) ImageRecognitionEnabled() bool {
+	return g.imageRecognitionNode.Enabled()
+}
+
 // ExecuteImageRecognition 执行图片识别流程
-// 输入: 图片 -> 输出: 对象名称、类别、关键词
+// 输入: 图片 -> 输出: 对象名称、类别、关键词、候选对象
 func (g *Graph) ExecuteImageRecognition(image string, age int) (*nodes.GraphData, error) {
 	data := &nodes.GraphData{
 		Image: image,
@@ -80,117 +114,84 @@ func (g *Graph) ExecuteImageRecognition(image string, age int) (*nodes.GraphData
 
 	data.ObjectName = result.ObjectName
 	data.ObjectCategory = result.ObjectCategory
+	data.CategoryPath = result.CategoryPath
 	data.Keywords = result.Keywords
+	data.Confidence = result.Confidence
+	data.Objects = result.Objects
 
 	g.logger.Infow("图片识别完成",
 		logx.Field("objectName", data.ObjectName),
 		logx.Field("category", data.ObjectCategory),
+		logx.Field("objectCount", len(data.Objects)),
 	)
 
 	return data, nil
 }
 
 // ExecuteCardGeneration 执行卡片生成流程
-// 输入: 对象名称、类别、年龄 -> 输出: 三张卡片（科学、诗词、英语）
-// 优化：并行生成三张卡片以减少响应时间，添加超时控制
-func (g *Graph) ExecuteCardGeneration(ctx context.Context, objectName, category string, age int, keywords []string) (*nodes.GraphData, error) {
+// 输入: 对象名称、类别、年龄、卡片类型 -> 输出: 卡片（cardTypes 为空时生成科学、诗词、英语三张）
+// 各卡片并行生成以减少响应时间
+func (g *Graph) ExecuteCardGeneration(ctx context.Context, objectName, category string, age int, keywords []string, cardTypes []string) (*nodes.GraphData, error) {
+	return g.ExecuteCardGenerationStream(ctx, objectName, category, age, keywords, cardTypes, nil)
+}
+
+// ExecuteCardGenerationStream 执行卡片生成流程，每张卡片生成成功后立即回调 onCard
+// idx 为卡片在 cardTypes 中的位置，回调按完成顺序在调用方的goroutine中依次执行；
+// 部分卡片失败时不返回错误，失败的卡片记录在 data.CardErrors 中；所有卡片都失败时返回错误
+func (g *Graph) ExecuteCardGenerationStream(ctx context.Context, objectName, category string, age int, keywords []string, cardTypes []string, onCard func(idx int, card interface{})) (*nodes.GraphData, error) {
 	data := &nodes.GraphData{
 		ObjectName:     objectName,
 		ObjectCategory: category,
 		Age:            age,
 		Keywords:       keywords,
 	}
+	if len(cardTypes) == 0 {
+		cardTypes = cards.DefaultTypes
+	}
 
-	// 使用 WaitGroup 和 channel 并行生成三张卡片
+	// 每种卡片一个 goroutine 并行生成（等待模型返回，不设置超时）
 	var wg sync.WaitGroup
 	type cardResult struct {
 		card interface{}
 		err  error
-		idx  int // 用于保持顺序：0-科学卡, 1-诗词卡, 2-英语卡
+		idx  int // 卡片在 cardTypes 中的位置，用于保持顺序
 	}
-	results := make(chan cardResult, 3)
-
-	// 1. 并行生成科学认知卡（等待模型返回，不设置超时）
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		g.logger.Infow("开始生成科学认知卡（goroutine）",
-			logx.Field("objectName", data.ObjectName),
-		)
-		card, err := g.textGenerationNode.GenerateScienceCard(ctx, data)
-		if err != nil {
-			g.logger.Errorw("科学认知卡生成失败（goroutine）",
-				logx.Field("objectName", data.ObjectName),
-				logx.Field("error", err),
-				logx.Field("errorDetail", err.Error()),
-			)
-		} else {
-			g.logger.Infow("科学认知卡生成成功（goroutine）",
-				logx.Field("objectName", data.ObjectName),
-			)
+	results := make(chan cardResult, len(cardTypes))
+	for i, cardType := range cardTypes {
+		generator, ok := g.cardRegistry.Get(cardType)
+		if !ok {
+			results <- cardResult{err: fmt.Errorf("不支持的卡片类型: %s", cardType), idx: i}
+			continue
 		}
-		results <- cardResult{card: card, err: err, idx: 0}
-	}()
-
-	// 2. 并行生成古诗词/人文卡（等待模型返回，不设置超时）
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		g.logger.Infow("开始生成古诗词卡（goroutine）",
-			logx.Field("objectName", data.ObjectName),
-		)
-		card, err := g.textGenerationNode.GeneratePoetryCard(ctx, data)
-		if err != nil {
-			g.logger.Errorw("古诗词卡生成失败（goroutine）",
-				logx.Field("objectName", data.ObjectName),
-				logx.Field("error", err),
-				logx.Field("errorDetail", err.Error()),
-			)
-		} else {
-			g.logger.Infow("古诗词卡生成成功（goroutine）",
+		wg.Add(1)
+		go func(idx int, generator nodes.CardGenerator) {
+			defer wg.Done()
+			g.logger.Infow("开始生成卡片（goroutine）",
 				logx.Field("objectName", data.ObjectName),
+				logx.Field("cardType", generator.Type()),
 			)
-		}
-		results <- cardResult{card: card, err: err, idx: 1}
-	}()
+			card, err := generator.Generate(ctx, data)
+			results <- cardResult{card: card, err: err, idx: idx}
+		}(i, generator)
+	}
 
-	// 3. 并行生成英语表达卡（等待模型返回，不设置超时）
-	wg.Add(1)
+	// 所有 goroutine 完成后关闭结果通道，下面按完成顺序接收结果
 	go func() {
-		defer wg.Done()
-		g.logger.Infow("开始生成英语表达卡（goroutine）",
-			logx.Field("objectName", data.ObjectName),
-		)
-		card, err := g.textGenerationNode.GenerateEnglishCard(ctx, data)
-		if err != nil {
-			g.logger.Errorw("英语表达卡生成失败（goroutine）",
-				logx.Field("objectName", data.ObjectName),
-				logx.Field("error", err),
-				logx.Field("errorDetail", err.Error()),
-			)
-		} else {
-			g.logger.Infow("英语表达卡生成成功（goroutine）",
-				logx.Field("objectName", data.ObjectName),
-			)
-		}
-		results <- cardResult{card: card, err: err, idx: 2}
+		wg.Wait()
+		close(results)
 	}()
 
-	// 等待所有 goroutine 完成
-	wg.Wait()
-	close(results)
-
 	// 收集结果并保持顺序
-	cards := make([]interface{}, 3)
+	generated := make([]interface{}, len(cardTypes))
 	var firstErr error
 	successCount := 0
-	cardTypes := []string{"科学认知卡", "古诗词卡", "英语表达卡"}
 	for result := range results {
 		cardType := cardTypes[result.idx]
 		if result.err != nil {
 			if firstErr == nil {
 				firstErr = result.err
 			}
+			data.CardErrors = append(data.CardErrors, nodes.CardError{Index: result.idx, Type: cardType, Err: result.err})
 			g.logger.Errorw("卡片生成失败",
 				logx.Field("objectName", data.ObjectName),
 				logx.Field("cardIndex", result.idx),
@@ -198,45 +199,41 @@ func (g *Graph) ExecuteCardGeneration(ctx context.Context, objectName, category
 				logx.Field("error", result.err),
 				logx.Field("errorDetail", result.err.Error()),
 			)
-			// 如果模型调用失败，返回错误，不使用Mock数据
-			// 让调用方决定如何处理错误
-		} else {
-			cards[result.idx] = result.card
-			successCount++
-			g.logger.Infow("卡片生成成功",
-				logx.Field("objectName", data.ObjectName),
-				logx.Field("cardIndex", result.idx),
-				logx.Field("cardType", cardType),
-			)
+			// 失败的卡片不使用Mock数据，由调用方决定如何展示
+			continue
+		}
+		generated[result.idx] = result.card
+		successCount++
+		if onCard != nil {
+			onCard(result.idx, result.card)
 		}
+		g.logger.Infow("卡片生成成功",
+			logx.Field("objectName", data.ObjectName),
+			logx.Field("cardIndex", result.idx),
+			logx.Field("cardType", cardType),
+		)
 	}
+	sort.Slice(data.CardErrors, func(i, j int) bool {
+		return data.CardErrors[i].Index < data.CardErrors[j].Index
+	})
 
 	// 如果所有卡片都失败，返回错误
-	if successCount == 0 && firstErr != nil {
+	if successCount == 0 {
 		return nil, fmt.Errorf("所有卡片生成失败: %w", firstErr)
 	}
-
-	// 如果有部分失败，返回错误（不再使用Mock降级）
-	if successCount < 3 {
-		g.logger.Errorw("部分卡片生成失败，返回错误",
+	if len(data.CardErrors) > 0 {
+		g.logger.Errorw("部分卡片生成失败，返回已生成的卡片",
 			logx.Field("objectName", data.ObjectName),
 			logx.Field("successCount", successCount),
-			logx.Field("totalCount", 3),
+			logx.Field("totalCount", len(cardTypes)),
 			logx.Field("firstError", firstErr),
-			logx.Field("firstErrorDetail", func() string {
-				if firstErr != nil {
-					return firstErr.Error()
-				}
-				return ""
-			}()),
 		)
-		return nil, fmt.Errorf("部分卡片生成失败（成功%d/3）: %w", successCount, firstErr)
 	}
 
-	// 4. 为每张卡片生成配图（图片生成）
+	// 为每张卡片生成配图（图片生成）
 	// TODO: 待APP ID提供后，启用图片生成
-	// for i := range cards {
-	// 	imageURL, err := g.imageGenerationNode.GenerateCardImage(data, cards[i])
+	// for i := range generated {
+	// 	imageURL, err := g.imageGenerationNode.GenerateCardImage(data, generated[i])
 	// 	if err != nil {
 	// 		g.logger.Errorw("生成卡片配图失败", logx.Field("error", err))
 	// 		continue
@@ -244,17 +241,26 @@ func (g *Graph) ExecuteCardGeneration(ctx context.Context, objectName, category
 	// 	// 将图片URL添加到卡片数据中
 	// }
 
-	data.Cards = cards
+	data.Cards = generated
 
 	g.logger.Infow("卡片生成完成（并行）",
 		logx.Field("objectName", data.ObjectName),
-		logx.Field("cardCount", len(cards)),
+		logx.Field("cardCount", len(generated)),
 		logx.Field("successCount", successCount),
-		logx.Field("useRealModel", successCount > 0),
 	)
 	return data, nil
 }
 
+// RegisterCardGenerator 注册卡片生成器，可以新增卡片类型或替换内置卡片类型的生成方式
+func (g *Graph) RegisterCardGenerator(generator nodes.CardGenerator) error {
+	return g.cardRegistry.Register(generator)
+}
+
+// CardTypes 返回支持生成的卡片类型
+func (g *Graph) CardTypes() []string {
+	return g.cardRegistry.Types()
+}
+
 // getMockCard 获取Mock卡片作为降级方案
 func (g *Graph) getMockCard(idx int, objectName string, age int) map[string]interface{} {
 	switch idx {
@@ -262,33 +268,36 @@ func (g *Graph) getMockCard(idx int, objectName string, age int) map[string]inte
 		return map[string]interface{}{
 			"type":  "science",
 			"title": objectName + "的科学知识",
-			"content": map[string]interface{}{
-				"name":        objectName,
-				"explanation": objectName + "是一个有趣的对象，值得我们探索和学习。",
-				"facts":       []string{"这是一个有趣的事实", "还有更多知识等待探索"},
-				"funFact":     "关于" + objectName + "还有很多有趣的知识等待发现！",
-			},
+			"content": cards.ToMap(cards.ScienceCard{
+				SchemaVersion: cards.ScienceSchemaVersion,
+				Name:          objectName,
+				Explanation:   objectName + "是一个有趣的对象，值得我们探索和学习。",
+				Facts:         []string{"这是一个有趣的事实", "还有更多知识等待探索"},
+				FunFact:       "关于" + objectName + "还有很多有趣的知识等待发现！",
+			}),
 		}
 	case 1: // 诗词卡
 		return map[string]interface{}{
 			"type":  "poetry",
 			"title": "古人怎么看" + objectName,
-			"content": map[string]interface{}{
-				"poem":        "关于" + objectName + "的古诗词，等待我们去发现。",
-				"poemSource":  "古诗词",
-				"explanation": "这句诗描写了" + objectName + "的美丽景象，让我们感受到古人的智慧和情感。",
-				"context":     "看到" + objectName + "，我们可以联想到相关的文化和历史，丰富我们的认知。",
-			},
+			"content": cards.ToMap(cards.PoetryCard{
+				SchemaVersion: cards.PoetrySchemaVersion,
+				Poem:          "关于" + objectName + "的古诗词，等待我们去发现。",
+				PoemSource:    "古诗词",
+				Explanation:   "这句诗描写了" + objectName + "的美丽景象，让我们感受到古人的智慧和情感。",
+				Context:       "看到" + objectName + "，我们可以联想到相关的文化和历史，丰富我们的认知。",
+			}),
 		}
 	case 2: // 英语卡
 		return map[string]interface{}{
 			"type":  "english",
 			"title": "用英语说" + objectName,
-			"content": map[string]interface{}{
-				"keywords":      []string{objectName, "object", "interesting"},
-				"expressions":   []string{"This is " + objectName + ".", "It's very interesting."},
-				"pronunciation": objectName + ": pronunciation",
-			},
+			"content": cards.ToMap(cards.EnglishCard{
+				SchemaVersion: cards.EnglishSchemaVersion,
+				Keywords:      []string{objectName, "object", "interesting"},
+				Expressions:   []string{"This is " + objectName + ".", "It's very interesting."},
+				Pronunciation: objectName + ": pronunciation",
+			}),
 		}
 	default:
 		return map[string]interface{}{}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tango/explore/internal/agent/nodes"
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+func TestGraph_ExecuteCardGeneration_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	graph, err := NewGraph(ctx, config.AIConfig{}, nil, logx.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("NewGraph() error = %v", err)
+	}
+	failing := nodes.CardGeneratorFunc{CardType: "math", Fn: func(ctx context.Context, data *nodes.GraphData) (map[string]interface{}, error) {
+		return nil, errors.New("模型超时")
+	}}
+	if err := graph.RegisterCardGenerator(failing); err != nil {
+		t.Fatalf("RegisterCardGenerator() error = %v", err)
+	}
+
+	var sent []int
+	data, err := graph.ExecuteCardGenerationStream(ctx, "银杏", "自然类", 8, nil, []string{"science", "math", "unknown", "history"},
+		func(idx int, card interface{}) {
+			sent = append(sent, idx)
+		})
+	if err != nil {
+		t.Fatalf("部分失败不应返回错误: %v", err)
+	}
+	if len(data.Cards) != 4 || data.Cards[0] == nil || data.Cards[1] != nil || data.Cards[2] != nil || data.Cards[3] == nil {
+		t.Errorf("Cards 应按请求位置排列，失败位置为 nil: %v", data.Cards)
+	}
+	if len(sent) != 2 {
+		t.Errorf("只应回调成功的卡片，got %v", sent)
+	}
+	if len(data.CardErrors) != 2 || data.CardErrors[0].Index != 1 || data.CardErrors[1].Index != 2 || data.CardErrors[1].Type != "unknown" {
+		t.Errorf("CardErrors = %+v", data.CardErrors)
+	}
+
+	if _, err := graph.ExecuteCardGeneration(ctx, "银杏", "自然类", 8, nil, []string{"math"}); err == nil {
+		t.Error("所有卡片都失败时应返回错误")
+	}
+}
@@ -0,0 +1,255 @@
+package history
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// SummaryDataKey 滚动摘要在会话数据中的键
+	SummaryDataKey = "contextSummary"
+	// summarizeTimeout 生成摘要的超时时间，超时后降级为截断
+	summarizeTimeout = 15 * time.Second
+)
+
+// Manager 对话上下文管理器，负责为模型组装对话历史
+// 按模型的token预算从最近的消息往前填充；未压缩的对话超出预算或消息数上限时，
+// 把较早的对话压缩进滚动摘要，只保留最近的消息原文
+type Manager struct {
+	summarizer    Summarizer
+	defaultBudget int            // 未单独声明预算的模型使用，小于0表示关闭摘要
+	modelBudgets  map[string]int // 按模型声明的预算
+	sharedModels  []string       // 未指定模型时（多Agent各节点随机选择模型）取这些模型中最小的预算
+	keepMessages  int
+	logger        logx.Logger
+}
+
+// NewManager 根据配置创建上下文管理器
+func NewManager(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) *Manager {
+	if models == nil {
+		models = llm.NewModelFactory(cfg)
+	}
+	manager := NewManagerWithSummarizer(NewSummarizer(ctx, cfg, models, logger), cfg.ContextTokenBudget, cfg.ContextKeepMessages, logger)
+	manager.SetModelBudgets(parseModelBudgets(cfg.ContextModelBudgets, logger), models.Models(llm.RoleText))
+	return manager
+}
+
+// NewManagerWithSummarizer 使用指定摘要器创建上下文管理器，预算和保留条数为0时使用默认值
+func NewManagerWithSummarizer(summarizer Summarizer, tokenBudget, keepMessages int, logger logx.Logger) *Manager {
+	if tokenBudget == 0 {
+		tokenBudget = config.DefaultContextTokenBudget
+	}
+	if keepMessages <= 0 {
+		keepMessages = config.DefaultContextKeepMessages
+	}
+	return &Manager{
+		summarizer:    summarizer,
+		defaultBudget: tokenBudget,
+		modelBudgets:  map[string]int{},
+		keepMessages:  keepMessages,
+		logger:        logger,
+	}
+}
+
+// SetModelBudgets 设置按模型声明的token预算，sharedModels 为未指定模型时参与计算的模型列表
+func (m *Manager) SetModelBudgets(budgets map[string]int, sharedModels []string) {
+	m.modelBudgets = budgets
+	m.sharedModels = sharedModels
+}
+
+// Build 组装模型的对话历史：摘要作为系统消息放在最前面，其后是预算内的最近消息
+// model 为实际调用的模型（为空时使用共享模型中最小的预算），maxMessages 为原文保留的消息数上限（0表示不限制）
+// 未配置上下文管理器时只按消息数截断
+func (m *Manager) Build(ctx context.Context, store storage.SessionStore, sessionId string, messages []types.ConversationMessage, model string, maxMessages int) []*schema.Message {
+	if m == nil {
+		if maxMessages > 0 && len(messages) > maxMessages {
+			messages = messages[len(messages)-maxMessages:]
+		}
+		return ToEinoMessages(messages)
+	}
+	summary, recent := m.Prepare(ctx, store, sessionId, messages, model, maxMessages)
+	return PrependSummary(summary, ToEinoMessages(recent))
+}
+
+// Prepare 整理会话上下文，返回较早对话的摘要（可能为空）和需要原文保留的最近消息
+// messages 为会话的全部消息（按时间顺序）；摘要失败时不更新摘要，按预算截断最近的消息
+func (m *Manager) Prepare(ctx context.Context, store storage.SessionStore, sessionId string, messages []types.ConversationMessage, model string, maxMessages int) (string, []types.ConversationMessage) {
+	budget := m.budgetFor(model)
+	estimator := EstimatorFor(model)
+	current := loadSummary(store, sessionId)
+	pending := messagesAfter(messages, current)
+	if budget < 0 || !exceeds(estimator, budget, current.Summary, pending, maxMessages) {
+		return current.Summary, fit(estimator, budget, current.Summary, pending, maxMessages)
+	}
+
+	keep := m.keepMessages
+	if maxMessages > 0 && keep > maxMessages {
+		keep = maxMessages
+	}
+	if len(pending) <= keep {
+		return current.Summary, fit(estimator, budget, current.Summary, pending, maxMessages)
+	}
+	older, recent := pending[:len(pending)-keep], pending[len(pending)-keep:]
+
+	summarizeCtx, cancel := context.WithTimeout(ctx, summarizeTimeout)
+	defer cancel()
+	summary, err := m.summarizer.Summarize(summarizeCtx, current.Summary, older)
+	if err != nil {
+		m.logger.Errorw("压缩对话上下文失败，降级为截断",
+			logx.Field("sessionId", sessionId),
+			logx.Field("messages", len(older)),
+			logx.Field("error", err),
+		)
+		return current.Summary, fit(estimator, budget, current.Summary, pending, maxMessages)
+	}
+
+	updated := &types.ContextSummary{
+		Summary:            summary,
+		LastMessageId:      older[len(older)-1].Id,
+		LastMessageTime:    older[len(older)-1].Timestamp,
+		SummarizedMessages: current.SummarizedMessages + len(older),
+		UpdatedAt:          time.Now(),
+	}
+	store.SetData(sessionId, SummaryDataKey, updated)
+	m.logger.Infow("对话上下文已压缩",
+		logx.Field("sessionId", sessionId),
+		logx.Field("model", model),
+		logx.Field("budget", budget),
+		logx.Field("summarizedMessages", updated.SummarizedMessages),
+		logx.Field("keptMessages", len(recent)),
+	)
+	return summary, fit(estimator, budget, summary, recent, maxMessages)
+}
+
+// budgetFor 返回模型的token预算
+func (m *Manager) budgetFor(model string) int {
+	if m.defaultBudget < 0 {
+		return m.defaultBudget
+	}
+	if model != "" {
+		if budget, ok := m.modelBudgets[model]; ok {
+			return budget
+		}
+		return m.defaultBudget
+	}
+
+	if len(m.sharedModels) == 0 {
+		return m.defaultBudget
+	}
+	budget := m.budgetFor(m.sharedModels[0])
+	for _, shared := range m.sharedModels[1:] {
+		if b := m.budgetFor(shared); b < budget {
+			budget = b
+		}
+	}
+	return budget
+}
+
+// exceeds 未压缩的对话是否超出预算
+func exceeds(estimator Estimator, budget int, summary string, messages []types.ConversationMessage, maxMessages int) bool {
+	if maxMessages > 0 && len(messages) > maxMessages {
+		return true
+	}
+	return estimator.Count(summary)+countTokens(estimator, messages) > budget
+}
+
+// fit 按消息数上限和token预算丢弃最早的消息，至少保留最后一条
+func fit(estimator Estimator, budget int, summary string, messages []types.ConversationMessage, maxMessages int) []types.ConversationMessage {
+	if maxMessages > 0 && len(messages) > maxMessages {
+		messages = messages[len(messages)-maxMessages:]
+	}
+	if budget < 0 {
+		return messages
+	}
+	tokens := estimator.Count(summary) + countTokens(estimator, messages)
+	for len(messages) > 1 && tokens > budget {
+		tokens -= messageTokens(estimator, messages[0])
+		messages = messages[1:]
+	}
+	return messages
+}
+
+// PrependSummary 把摘要作为系统消息放在对话历史最前面
+func PrependSummary(summary string, chatHistory []*schema.Message) []*schema.Message {
+	if summary == "" {
+		return chatHistory
+	}
+	result := make([]*schema.Message, 0, len(chatHistory)+1)
+	result = append(result, schema.SystemMessage("之前对话的摘要：\n"+summary))
+	return append(result, chatHistory...)
+}
+
+// loadSummary 读取会话的滚动摘要，不存在时返回空摘要
+func loadSummary(store storage.SessionStore, sessionId string) *types.ContextSummary {
+	if value, ok := store.GetData(sessionId, SummaryDataKey); ok {
+		if summary, ok := value.(*types.ContextSummary); ok && summary != nil {
+			return summary
+		}
+	}
+	return &types.ContextSummary{}
+}
+
+// messagesAfter 返回摘要游标之后的消息
+// 找不到游标消息（如已被存储上限裁剪）时按游标消息的时间戳跳过更早的消息，避免重复压缩；
+// 时间戳精确到秒，与游标同一秒的消息无法区分，保留等待压缩
+func messagesAfter(messages []types.ConversationMessage, cursor *types.ContextSummary) []types.ConversationMessage {
+	if cursor.LastMessageId != "" {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Id == cursor.LastMessageId {
+				return messages[i+1:]
+			}
+		}
+	}
+	cursorTime, err := time.Parse(time.RFC3339, cursor.LastMessageTime)
+	if err != nil {
+		return messages
+	}
+	for i, msg := range messages {
+		if sentAt, err := time.Parse(time.RFC3339, msg.Timestamp); err != nil || !sentAt.Before(cursorTime) {
+			return messages[i:]
+		}
+	}
+	return messages[len(messages):]
+}
+
+// messageTokens 估算一条消息渲染后占用的token数
+func messageTokens(estimator Estimator, msg types.ConversationMessage) int {
+	return estimator.Count(RenderContent(msg)) + messageOverheadTokens
+}
+
+// countTokens 估算消息列表的token数
+func countTokens(estimator Estimator, messages []types.ConversationMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += messageTokens(estimator, msg)
+	}
+	return total
+}
+
+// parseModelBudgets 解析按模型声明的预算，格式为 "模型=token数,模型=token数"
+func parseModelBudgets(s string, logger logx.Logger) map[string]int {
+	budgets := map[string]int{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		budget, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || strings.TrimSpace(name) == "" || budget <= 0 {
+			logger.Errorw("忽略无效的模型上下文预算配置", logx.Field("item", item))
+			continue
+		}
+		budgets[strings.TrimSpace(name)] = budget
+	}
+	return budgets
+}
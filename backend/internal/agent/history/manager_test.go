@@ -0,0 +1,256 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// fakeSummarizer 记录调用参数的摘要器
+type fakeSummarizer struct {
+	calls    int
+	previous string
+	received []types.ConversationMessage
+	err      error
+}
+
+func (s *fakeSummarizer) Summarize(ctx context.Context, previous string, messages []types.ConversationMessage) (string, error) {
+	s.calls++
+	s.previous = previous
+	s.received = messages
+	if s.err != nil {
+		return "", s.err
+	}
+	return fmt.Sprintf("摘要%d", s.calls), nil
+}
+
+// addMessages 向会话追加n条消息，返回追加后的全部消息
+func addMessages(store storage.SessionStore, sessionId string, start, n int) []types.ConversationMessage {
+	for i := start; i < start+n; i++ {
+		sender := "user"
+		if i%2 == 1 {
+			sender = "assistant"
+		}
+		store.AddMessage(sessionId, types.ConversationMessage{
+			Id:      fmt.Sprintf("msg-%d", i),
+			Type:    "text",
+			Sender:  sender,
+			Content: strings.Repeat("天", 20),
+		})
+	}
+	var messages []types.ConversationMessage
+	for _, raw := range store.GetMessages(sessionId) {
+		messages = append(messages, raw.(types.ConversationMessage))
+	}
+	return messages
+}
+
+func TestManager_Prepare(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	store := storage.NewMemoryStorage()
+	sessionId := "session-1"
+	summarizer := &fakeSummarizer{}
+	// 每条消息约24个token，预算可容纳约8条
+	manager := NewManagerWithSummarizer(summarizer, 200, 4, logger)
+
+	// 未超出预算时原样返回
+	messages := addMessages(store, sessionId, 0, 6)
+	summary, recent := manager.Prepare(context.Background(), store, sessionId, messages, "", 40)
+	if summary != "" || len(recent) != 6 || summarizer.calls != 0 {
+		t.Fatalf("未超出预算时不应压缩: summary=%q, recent=%d, calls=%d", summary, len(recent), summarizer.calls)
+	}
+
+	// 超出预算时压缩较早的消息，保留最近4条
+	messages = addMessages(store, sessionId, 6, 6)
+	summary, recent = manager.Prepare(context.Background(), store, sessionId, messages, "", 40)
+	if summary != "摘要1" || len(recent) != 4 || recent[0].Id != "msg-8" {
+		t.Fatalf("压缩结果错误: summary=%q, recent=%d", summary, len(recent))
+	}
+	if len(summarizer.received) != 8 || summarizer.received[7].Id != "msg-7" {
+		t.Errorf("应压缩最早的8条消息, 实际: %d", len(summarizer.received))
+	}
+
+	value, ok := store.GetData(sessionId, SummaryDataKey)
+	stored, _ := value.(*types.ContextSummary)
+	if !ok || stored == nil || stored.LastMessageId != "msg-7" || stored.SummarizedMessages != 8 {
+		t.Fatalf("摘要应保存在会话数据中: %+v", value)
+	}
+
+	// 摘要之后的消息未超出预算时直接使用已有摘要
+	summary, recent = manager.Prepare(context.Background(), store, sessionId, messages, "", 40)
+	if summary != "摘要1" || len(recent) != 4 || summarizer.calls != 1 {
+		t.Errorf("未超出预算时应复用摘要: summary=%q, recent=%d, calls=%d", summary, len(recent), summarizer.calls)
+	}
+
+	// 再次超出时在已有摘要上滚动合并
+	messages = addMessages(store, sessionId, 12, 6)
+	summary, recent = manager.Prepare(context.Background(), store, sessionId, messages, "", 40)
+	if summary != "摘要2" || summarizer.previous != "摘要1" || len(recent) != 4 {
+		t.Errorf("滚动摘要错误: summary=%q, previous=%q, recent=%d", summary, summarizer.previous, len(recent))
+	}
+	if len(summarizer.received) != 6 || summarizer.received[0].Id != "msg-8" {
+		t.Errorf("应只压缩上次摘要之后的消息, 实际: %d", len(summarizer.received))
+	}
+}
+
+func TestManager_Prepare_MaxMessages(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	store := storage.NewMemoryStorage()
+	sessionId := "session-1"
+	summarizer := &fakeSummarizer{}
+	manager := NewManagerWithSummarizer(summarizer, 10000, 4, logger)
+
+	// token未超出预算，但消息数超出上限时也应压缩
+	messages := addMessages(store, sessionId, 0, 10)
+	summary, recent := manager.Prepare(context.Background(), store, sessionId, messages, "", 6)
+	if summary != "摘要1" || len(recent) != 4 || summarizer.calls != 1 {
+		t.Errorf("超出消息数上限时应压缩: summary=%q, recent=%d, calls=%d", summary, len(recent), summarizer.calls)
+	}
+}
+
+func TestManager_Prepare_CursorTrimmed(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	store := storage.NewMemoryStorage()
+	sessionId := "session-1"
+	summarizer := &fakeSummarizer{}
+	manager := NewManagerWithSummarizer(summarizer, 200, 4, logger)
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	var messages []types.ConversationMessage
+	for i := 0; i < 8; i++ {
+		messages = append(messages, types.ConversationMessage{
+			Id:        fmt.Sprintf("msg-%d", i),
+			Type:      "text",
+			Sender:    "user",
+			Content:   "天",
+			Timestamp: base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+		})
+	}
+	store.SetData(sessionId, SummaryDataKey, &types.ContextSummary{
+		Summary:            "摘要0",
+		LastMessageId:      "msg-3",
+		LastMessageTime:    messages[3].Timestamp,
+		SummarizedMessages: 4,
+	})
+
+	// 游标消息已不在列表中时，按时间戳跳过已压缩的消息，不重复压缩
+	withoutCursor := append(append([]types.ConversationMessage{}, messages[1:3]...), messages[4:]...)
+	summary, recent := manager.Prepare(context.Background(), store, sessionId, withoutCursor, "", 40)
+	if summary != "摘要0" || summarizer.calls != 0 {
+		t.Fatalf("已压缩的消息不应再次压缩: summary=%q, calls=%d", summary, summarizer.calls)
+	}
+	if len(recent) != 4 || recent[0].Id != "msg-4" {
+		t.Errorf("应只保留游标之后的消息, 实际: %+v", recent)
+	}
+
+	// 游标及更早的消息都被裁剪时保留全部消息
+	summary, recent = manager.Prepare(context.Background(), store, sessionId, messages[5:], "", 40)
+	if summary != "摘要0" || len(recent) != 3 || recent[0].Id != "msg-5" {
+		t.Errorf("游标之后的消息应全部保留: summary=%q, recent=%d", summary, len(recent))
+	}
+}
+
+func TestManager_Prepare_SummarizeFailure(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	store := storage.NewMemoryStorage()
+	sessionId := "session-1"
+	manager := NewManagerWithSummarizer(&fakeSummarizer{err: errors.New("model unavailable")}, 200, 4, logger)
+
+	// 摘要失败时降级为按预算截断，不保存摘要
+	messages := addMessages(store, sessionId, 0, 12)
+	summary, recent := manager.Prepare(context.Background(), store, sessionId, messages, "", 40)
+	if summary != "" {
+		t.Errorf("摘要失败时不应返回摘要: %q", summary)
+	}
+	if len(recent) == 0 || len(recent) >= 12 || recent[len(recent)-1].Id != "msg-11" {
+		t.Errorf("应截断为最近的消息, 实际: %d", len(recent))
+	}
+	if _, ok := store.GetData(sessionId, SummaryDataKey); ok {
+		t.Error("摘要失败时不应保存摘要")
+	}
+}
+
+func TestMockSummarizer(t *testing.T) {
+	summarizer := &mockSummarizer{}
+	summary, err := summarizer.Summarize(context.Background(), "孩子问过：蝴蝶为什么会飞", []types.ConversationMessage{
+		{Sender: "user", Content: "毛毛虫吃什么？"},
+		{Sender: "assistant", Content: "毛毛虫吃叶子。"},
+	})
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if !strings.Contains(summary, "蝴蝶为什么会飞") || !strings.Contains(summary, "毛毛虫吃什么？") || strings.Contains(summary, "吃叶子") {
+		t.Errorf("Mock摘要错误: %q", summary)
+	}
+}
+
+func TestPrependSummary(t *testing.T) {
+	chatHistory := []*schema.Message{schema.UserMessage("你好")}
+	if got := PrependSummary("", chatHistory); len(got) != 1 {
+		t.Errorf("没有摘要时不应添加消息, 实际: %d", len(got))
+	}
+	got := PrependSummary("聊过蝴蝶", chatHistory)
+	if len(got) != 2 || got[0].Role != schema.System || !strings.Contains(got[0].Content, "聊过蝴蝶") {
+		t.Errorf("摘要应作为系统消息放在最前面: %+v", got)
+	}
+}
+
+func TestManager_ModelBudgets(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	manager := NewManagerWithSummarizer(&fakeSummarizer{}, 3000, 6, logger)
+	manager.SetModelBudgets(parseModelBudgets("doubao-pro=8000, gpt-4o-mini=1000, bad, other=x", logger),
+		[]string{"doubao-pro", "gpt-4o-mini"})
+
+	cases := map[string]int{
+		"doubao-pro":  8000,
+		"gpt-4o-mini": 1000,
+		"unknown":     3000,
+		"":            1000, // 未指定模型时取共享模型中最小的预算
+	}
+	for model, want := range cases {
+		if got := manager.budgetFor(model); got != want {
+			t.Errorf("budgetFor(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+func TestManager_Build(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	store := storage.NewMemoryStorage()
+	sessionId := "session-1"
+	messages := addMessages(store, sessionId, 0, 12)
+
+	// 未配置上下文管理器时只按消息数截断
+	var nilManager *Manager
+	if got := nilManager.Build(context.Background(), store, sessionId, messages, "", 4); len(got) != 4 {
+		t.Errorf("未配置管理器时应保留4条消息, 实际: %d", len(got))
+	}
+
+	manager := NewManagerWithSummarizer(&fakeSummarizer{}, 200, 4, logger)
+	got := manager.Build(context.Background(), store, sessionId, messages, "", 40)
+	if len(got) != 5 || got[0].Role != schema.System || got[1].Role != schema.User || got[2].Role != schema.Assistant {
+		t.Errorf("应为摘要系统消息加最近4条消息: %+v", got)
+	}
+}
+
+func TestEstimator(t *testing.T) {
+	if got := EstimateTokens("蝴蝶"); got != 2 {
+		t.Errorf("EstimateTokens(中文) = %d, want 2", got)
+	}
+	if got := EstimateTokens("butterfly"); got != 3 {
+		t.Errorf("EstimateTokens(英文) = %d, want 3", got)
+	}
+	// 针对中文优化的模型估算的token更少
+	text := strings.Repeat("银杏的叶子秋天会变黄", 10)
+	if doubao, gpt := EstimatorFor("doubao-seed-1.6").Count(text), EstimatorFor("gpt-4o").Count(text); doubao >= gpt {
+		t.Errorf("doubao估算应少于gpt: doubao=%d, gpt=%d", doubao, gpt)
+	}
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/types"
+)
+
+const (
+	// maxCardTextRunes 卡片内容渲染为文本时的最大字数
+	maxCardTextRunes = 80
+	// maxOtherTextRunes 其他非文本内容渲染为文本时的最大字数
+	maxOtherTextRunes = 200
+)
+
+// RenderContent 把消息内容渲染为紧凑的文本
+// 卡片只保留类型、标题和内容摘要，图片和语音载荷替换为标签，避免把原始JSON或base64放进上下文
+func RenderContent(msg types.ConversationMessage) string {
+	switch msg.Type {
+	case "image":
+		if msg.Sender == "user" {
+			return "[孩子发来一张图片]"
+		}
+		return "[图片]"
+	case "card":
+		return renderCard(msg.Content)
+	}
+
+	switch content := msg.Content.(type) {
+	case string:
+		return content
+	case nil:
+		return ""
+	}
+	if msg.Type == "voice" {
+		return "[语音]"
+	}
+	return truncateRunes(marshalText(msg.Content), maxOtherTextRunes)
+}
+
+// ToEinoMessages 把对话消息渲染为eino消息，跳过没有内容的消息
+func ToEinoMessages(messages []types.ConversationMessage) []*schema.Message {
+	result := make([]*schema.Message, 0, len(messages))
+	for _, msg := range messages {
+		text := RenderContent(msg)
+		if text == "" {
+			continue
+		}
+		if msg.Sender == "user" {
+			result = append(result, schema.UserMessage(text))
+		} else {
+			result = append(result, schema.AssistantMessage(text, nil))
+		}
+	}
+	return result
+}
+
+// renderCard 渲染卡片消息，如 "[知识卡片·science] 银杏：银杏是…"
+func renderCard(content interface{}) string {
+	if text, ok := content.(string); ok {
+		return "[知识卡片] " + text
+	}
+
+	card := toMap(content)
+	label := "[知识卡片]"
+	if cardType, ok := card["type"].(string); ok && cardType != "" {
+		label = "[知识卡片·" + cardType + "]"
+	}
+	title, _ := card["title"].(string)
+	summary := truncateRunes(strings.Join(collectText(card["content"]), "；"), maxCardTextRunes)
+
+	switch {
+	case title != "" && summary != "":
+		return label + " " + title + "：" + summary
+	case title != "":
+		return label + " " + title
+	case summary != "":
+		return label + " " + summary
+	default:
+		return label
+	}
+}
+
+// collectText 按键名顺序收集卡片内容中的文本字段
+func collectText(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if text := strings.TrimSpace(v); text != "" {
+			return []string{text}
+		}
+	case []interface{}:
+		var texts []string
+		for _, item := range v {
+			texts = append(texts, collectText(item)...)
+		}
+		return texts
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var texts []string
+		for _, key := range keys {
+			texts = append(texts, collectText(v[key])...)
+		}
+		return texts
+	}
+	return nil
+}
+
+// toMap 把结构体或map统一转换为 map[string]interface{}
+// 内存存储中保存的是原始类型，持久化存储还原后是map
+func toMap(value interface{}) map[string]interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// marshalText 把任意内容序列化为文本
+func marshalText(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
+
+// truncateRunes 截取前n个字符
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/types"
+)
+
+func TestRenderContent(t *testing.T) {
+	cases := []struct {
+		name string
+		msg  types.ConversationMessage
+		want string
+	}{
+		{
+			name: "文本",
+			msg:  types.ConversationMessage{Type: "text", Sender: "user", Content: "银杏为什么会变黄？"},
+			want: "银杏为什么会变黄？",
+		},
+		{
+			name: "图片不带载荷",
+			msg:  types.ConversationMessage{Type: "image", Sender: "user", Content: "data:image/png;base64,iVBORw0KGgo="},
+			want: "[孩子发来一张图片]",
+		},
+		{
+			name: "语音识别文本",
+			msg:  types.ConversationMessage{Type: "voice", Sender: "user", Content: "这是什么树"},
+			want: "这是什么树",
+		},
+		{
+			name: "语音载荷",
+			msg:  types.ConversationMessage{Type: "voice", Sender: "user", Content: map[string]interface{}{"voice": "UklGR..."}},
+			want: "[语音]",
+		},
+		{
+			name: "卡片",
+			msg: types.ConversationMessage{Type: "card", Sender: "assistant", Content: map[string]interface{}{
+				"type":  "science",
+				"title": "银杏",
+				"content": map[string]interface{}{
+					"name":        "银杏",
+					"explanation": "银杏是古老的树种",
+					"facts":       []interface{}{"秋天叶子变黄"},
+				},
+			}},
+			want: "[知识卡片·science] 银杏：银杏是古老的树种；秋天叶子变黄；银杏",
+		},
+		{
+			name: "结构体卡片",
+			msg: types.ConversationMessage{Type: "card", Sender: "assistant", Content: types.CardContent{
+				Type: "poetry", Title: "咏银杏", Content: map[string]interface{}{"poem": "满地翻黄银杏叶"},
+			}},
+			want: "[知识卡片·poetry] 咏银杏：满地翻黄银杏叶",
+		},
+	}
+
+	for _, tc := range cases {
+		if got := RenderContent(tc.msg); got != tc.want {
+			t.Errorf("%s: RenderContent = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRenderContent_TruncatesCard(t *testing.T) {
+	msg := types.ConversationMessage{Type: "card", Sender: "assistant", Content: map[string]interface{}{
+		"type":    "science",
+		"title":   "银杏",
+		"content": map[string]interface{}{"explanation": strings.Repeat("很长的解释", 100)},
+	}}
+	if got := []rune(RenderContent(msg)); len(got) > maxCardTextRunes+20 {
+		t.Errorf("卡片内容应被截断, 实际长度: %d", len(got))
+	}
+}
+
+func TestToEinoMessages(t *testing.T) {
+	got := ToEinoMessages([]types.ConversationMessage{
+		{Type: "text", Sender: "user", Content: "你好"},
+		{Type: "text", Sender: "assistant", Content: ""},
+		{Type: "text", Sender: "assistant", Content: "你好呀"},
+	})
+	if len(got) != 2 || got[0].Role != schema.User || got[1].Role != schema.Assistant || got[1].Content != "你好呀" {
+		t.Errorf("ToEinoMessages = %+v", got)
+	}
+}
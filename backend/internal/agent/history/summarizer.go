@@ -0,0 +1,142 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// maxSummaryRunes 摘要的最大字数，超出时保留最近的部分
+	maxSummaryRunes = 600
+	// maxMockPointRunes Mock摘要中每个问题的最大字数
+	maxMockPointRunes = 30
+)
+
+// summarySystemPrompt 摘要模型的系统提示词
+const summarySystemPrompt = `你负责压缩儿童AI对话的历史记录。
+请把已有摘要和新的对话合并成一段简洁的中文摘要，保留：孩子问过的问题、讨论过的事物和知识点、孩子感兴趣或没弄懂的地方。
+不超过300字，不要编造对话中没有的内容，只输出摘要本身。`
+
+// Summarizer 把较早的对话压缩为摘要
+type Summarizer interface {
+	// Summarize 将已有摘要和新的对话合并为新的摘要
+	Summarize(ctx context.Context, previous string, messages []types.ConversationMessage) (string, error)
+}
+
+// NewSummarizer 根据配置创建摘要器
+// 文本角色配置了可用的模型服务时使用文本模型，否则使用抽取式的Mock摘要
+func NewSummarizer(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) Summarizer {
+	if !models.Enabled(llm.RoleText) {
+		logger.Infow("未配置可用的模型服务，上下文摘要将使用Mock模式")
+		return &mockSummarizer{}
+	}
+
+	modelName := cfg.ContextSummaryModel
+	if modelName == "" {
+		if textModels := models.Models(llm.RoleText); len(textModels) > 0 {
+			modelName = textModels[0]
+		}
+	}
+
+	// 优先使用摘要模型，失败时切换到其他文本模型
+	logger.Infow("上下文摘要模型已初始化", logx.Field("model", modelName))
+	return NewModelSummarizer(models.RoleModel(llm.RoleText).WithPreferred(modelName))
+}
+
+// modelSummarizer 使用文本模型生成摘要
+type modelSummarizer struct {
+	chatModel model.BaseChatModel
+}
+
+// NewModelSummarizer 创建使用指定模型的摘要器
+func NewModelSummarizer(chatModel model.BaseChatModel) Summarizer {
+	return &modelSummarizer{chatModel: chatModel}
+}
+
+// Summarize 调用模型合并摘要
+func (s *modelSummarizer) Summarize(ctx context.Context, previous string, messages []types.ConversationMessage) (string, error) {
+	if previous == "" {
+		previous = "无"
+	}
+	var dialog strings.Builder
+	for _, msg := range messages {
+		text := strings.TrimSpace(RenderContent(msg))
+		if text == "" {
+			continue
+		}
+		dialog.WriteString(speakerName(msg.Sender))
+		dialog.WriteString("：")
+		dialog.WriteString(text)
+		dialog.WriteString("\n")
+	}
+
+	result, err := s.chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(summarySystemPrompt),
+		schema.UserMessage(fmt.Sprintf("已有摘要：%s\n\n新的对话：\n%s", previous, dialog.String())),
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成上下文摘要失败: %w", err)
+	}
+
+	summary := strings.TrimSpace(result.Content)
+	if summary == "" {
+		return "", fmt.Errorf("生成上下文摘要失败: 模型返回空内容")
+	}
+	return truncateSummary(summary), nil
+}
+
+// mockSummarizer 抽取式摘要：记录孩子问过的问题
+type mockSummarizer struct{}
+
+// Summarize 把孩子的提问追加到已有摘要
+func (s *mockSummarizer) Summarize(ctx context.Context, previous string, messages []types.ConversationMessage) (string, error) {
+	var questions []string
+	for _, msg := range messages {
+		if msg.Sender != "user" {
+			continue
+		}
+		text, ok := msg.Content.(string)
+		if !ok || strings.TrimSpace(text) == "" {
+			continue
+		}
+		runes := []rune(strings.TrimSpace(text))
+		if len(runes) > maxMockPointRunes {
+			runes = append(runes[:maxMockPointRunes], '…')
+		}
+		questions = append(questions, string(runes))
+	}
+
+	if len(questions) == 0 {
+		return previous, nil
+	}
+	points := "孩子问过：" + strings.Join(questions, "；")
+	if previous == "" {
+		return truncateSummary(points), nil
+	}
+	return truncateSummary(previous + "\n" + points), nil
+}
+
+// speakerName 摘要中的说话人名称
+func speakerName(sender string) string {
+	if sender == "user" {
+		return "孩子"
+	}
+	return "AI"
+}
+
+// truncateSummary 限制摘要长度，超出时保留最近的部分
+func truncateSummary(summary string) string {
+	runes := []rune(summary)
+	if len(runes) <= maxSummaryRunes {
+		return summary
+	}
+	return "…" + string(runes[len(runes)-maxSummaryRunes:])
+}
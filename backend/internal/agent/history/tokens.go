@@ -0,0 +1,64 @@
+package history
+
+import (
+	"strings"
+	"unicode"
+)
+
+// messageOverheadTokens 每条消息的角色、分隔符等固定开销
+const messageOverheadTokens = 4
+
+// Estimator 按模型的分词特点粗略估算token数
+// 中日韩字符和其余字符分别按每字符的平均token数计算
+type Estimator struct {
+	CJKTokensPerRune   float64
+	OtherTokensPerRune float64
+}
+
+// defaultEstimator 未知模型使用的保守估算：中文每字1个token，其余每4个字符1个token
+var defaultEstimator = Estimator{CJKTokensPerRune: 1, OtherTokensPerRune: 0.25}
+
+// modelEstimators 按模型名前缀匹配的估算参数
+// 针对中文优化过词表的模型，一个token通常能覆盖1~2个汉字
+var modelEstimators = []struct {
+	prefix    string
+	estimator Estimator
+}{
+	{"doubao", Estimator{CJKTokensPerRune: 0.7, OtherTokensPerRune: 0.3}},
+	{"deepseek", Estimator{CJKTokensPerRune: 0.7, OtherTokensPerRune: 0.3}},
+	{"qwen", Estimator{CJKTokensPerRune: 0.7, OtherTokensPerRune: 0.3}},
+	{"glm", Estimator{CJKTokensPerRune: 0.7, OtherTokensPerRune: 0.3}},
+	{"gemini", Estimator{CJKTokensPerRune: 0.8, OtherTokensPerRune: 0.25}},
+	{"gpt", Estimator{CJKTokensPerRune: 1, OtherTokensPerRune: 0.25}},
+}
+
+// EstimatorFor 返回模型对应的token估算器，未知模型返回保守估算
+func EstimatorFor(model string) Estimator {
+	name := strings.ToLower(model)
+	for _, item := range modelEstimators {
+		if strings.HasPrefix(name, item.prefix) {
+			return item.estimator
+		}
+	}
+	return defaultEstimator
+}
+
+// Count 估算文本的token数
+func (e Estimator) Count(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	tokens := e.CJKTokensPerRune*float64(cjk) + e.OtherTokensPerRune*float64(other)
+	return int(tokens + 0.999)
+}
+
+// EstimateTokens 按保守估算计算文本的token数
+func EstimateTokens(text string) int {
+	return defaultEstimator.Count(text)
+}
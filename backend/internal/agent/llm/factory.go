@@ -0,0 +1,384 @@
+// Package llm 模型工厂：按角色（意图识别、图片识别、文本、卡片、图片生成）创建模型客户端，
+// 每个角色可以独立配置提供方（Ark、OpenAI兼容接口、Ollama）、凭证、服务地址、超时和生成参数
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Role 模型角色
+type Role string
+
+// 模型角色
+const (
+	RoleIntent   Role = "intent"    // 意图识别
+	RoleVision   Role = "vision"    // 图片识别
+	RoleText     Role = "text"      // 对话、多Agent节点、上下文摘要
+	RoleCard     Role = "card"      // 知识卡片生成
+	RoleImageGen Role = "image_gen" // 卡片配图生成
+)
+
+// Roles 所有模型角色
+var Roles = []Role{RoleIntent, RoleVision, RoleText, RoleCard, RoleImageGen}
+
+// 模型提供方类型
+const (
+	ProviderArk    = "ark"    // 火山方舟（eino ark 组件），默认提供方
+	ProviderOpenAI = "openai" // OpenAI兼容的 /chat/completions 接口（如 llama.cpp、vLLM、各类网关）
+	ProviderOllama = "ollama" // 本地Ollama（通过其OpenAI兼容接口访问）
+)
+
+// Settings 解析后的角色配置（已补全默认值）
+type Settings struct {
+	Provider    string
+	BaseURL     string
+	APIKey      string
+	Models      []string
+	Timeout     time.Duration
+	Temperature float64
+	TopP        float64
+	MaxTokens   int
+	Policy      string             // 路由策略：weighted、priority
+	Weights     map[string]float64 // 模型权重，未声明的模型权重为1
+}
+
+// weight 返回模型的路由权重
+func (s Settings) weight(modelName string) float64 {
+	if w, ok := s.Weights[modelName]; ok {
+		return w
+	}
+	return 1
+}
+
+// ModelFactory 模型工厂，按角色创建模型客户端，可并发使用
+// 服务启动时创建一次，客户端按（角色, 模型）缓存在池中供所有请求复用，见 ChatModel
+type ModelFactory struct {
+	settings   map[Role]Settings
+	httpClient *http.Client // OpenAI兼容客户端共享的HTTP客户端（复用连接）
+	router     *Router      // 健康感知的模型路由（按角色和模型统计成功率、延迟并熔断）
+
+	mu          sync.Mutex
+	chatModels  map[clientKey]model.ToolCallingChatModel
+	imageModels map[string]model.BaseChatModel
+}
+
+// NewModelFactory 根据AI配置创建模型工厂
+// 角色未单独配置时使用 EinoBaseURL + AppID:AppKey 访问Ark，模型列表沿用 INTENT_MODELS 等原有配置
+func NewModelFactory(cfg config.AIConfig) *ModelFactory {
+	text := cfg.Models.Text
+	roles := map[Role]config.ModelRoleConfig{
+		RoleIntent:   cfg.Models.Intent,
+		RoleVision:   cfg.Models.Vision,
+		RoleText:     text,
+		RoleCard:     cfg.Models.Card.Inherit(text),
+		RoleImageGen: cfg.Models.ImageGen,
+	}
+
+	f := &ModelFactory{
+		settings:    make(map[Role]Settings, len(roles)),
+		httpClient:  &http.Client{},
+		router:      NewRouter(routerConfig(cfg)),
+		chatModels:  make(map[clientKey]model.ToolCallingChatModel),
+		imageModels: make(map[string]model.BaseChatModel),
+	}
+	for role, roleCfg := range roles {
+		f.settings[role] = resolveSettings(role, roleCfg, cfg)
+	}
+	return f
+}
+
+// routerConfig 补全路由和熔断参数的默认值
+func routerConfig(cfg config.AIConfig) RouterConfig {
+	rc := RouterConfig{
+		FailureThreshold: cfg.ModelFailureThreshold,
+		Cooldown:         time.Duration(cfg.ModelCircuitCooldownSeconds) * time.Second,
+		MaxAttempts:      cfg.ModelMaxAttempts,
+	}
+	if rc.FailureThreshold == 0 {
+		rc.FailureThreshold = config.DefaultModelFailureThreshold
+	}
+	if rc.Cooldown <= 0 {
+		rc.Cooldown = time.Duration(config.DefaultModelCircuitCooldownSeconds) * time.Second
+	}
+	if rc.MaxAttempts <= 0 {
+		rc.MaxAttempts = config.DefaultModelMaxAttempts
+	}
+	return rc
+}
+
+// resolveSettings 补全角色配置的默认值
+func resolveSettings(role Role, roleCfg config.ModelRoleConfig, cfg config.AIConfig) Settings {
+	s := Settings{
+		Provider:    strings.ToLower(strings.TrimSpace(roleCfg.Provider)),
+		BaseURL:     roleCfg.BaseURL,
+		APIKey:      roleCfg.APIKey,
+		Models:      roleCfg.Models,
+		Timeout:     time.Duration(roleCfg.TimeoutSeconds) * time.Second,
+		Temperature: roleCfg.Temperature,
+		TopP:        roleCfg.TopP,
+		MaxTokens:   roleCfg.MaxTokens,
+		Policy:      strings.ToLower(strings.TrimSpace(roleCfg.Policy)),
+		Weights:     parseWeights(roleCfg.Weights),
+	}
+	if s.Provider == "" {
+		s.Provider = ProviderArk
+	}
+	if s.Policy != PolicyPriority {
+		s.Policy = config.DefaultModelRoutingPolicy
+	}
+	if s.Timeout <= 0 {
+		s.Timeout = time.Duration(config.DefaultModelTimeoutSeconds) * time.Second
+	}
+
+	switch s.Provider {
+	case ProviderOllama:
+		if s.BaseURL == "" {
+			s.BaseURL = config.DefaultOllamaBaseURL
+		}
+	default:
+		// Ark和OpenAI兼容接口未单独配置时沿用eino的服务地址和 AppID:AppKey 认证
+		if s.BaseURL == "" {
+			s.BaseURL = cfg.EinoBaseURL
+		}
+		if s.APIKey == "" {
+			s.APIKey = defaultAPIKey(cfg)
+		}
+	}
+
+	if len(s.Models) == 0 {
+		s.Models = defaultModels(role, cfg)
+	}
+	return s
+}
+
+// parseWeights 解析 "模型=权重,模型=权重" 格式的模型权重，忽略无效项
+func parseWeights(s string) map[string]float64 {
+	weights := map[string]float64{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || strings.TrimSpace(name) == "" || weight < 0 {
+			logx.Errorw("忽略无效的模型权重配置", logx.Field("item", item))
+			continue
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights
+}
+
+// defaultAPIKey 使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
+func defaultAPIKey(cfg config.AIConfig) string {
+	if cfg.AppID != "" && cfg.AppKey != "" {
+		return cfg.AppID + ":" + cfg.AppKey
+	} else if cfg.AppKey != "" {
+		return cfg.AppKey
+	}
+	return cfg.AppID
+}
+
+// defaultModels 角色未配置模型列表时使用的原有配置和默认值
+func defaultModels(role Role, cfg config.AIConfig) []string {
+	var models []string
+	fallback := config.DefaultTextGenerationModel
+	switch role {
+	case RoleIntent:
+		models, fallback = cfg.IntentModels, config.DefaultIntentModel
+		if len(models) == 0 {
+			models = config.GetDefaultIntentModels()
+		}
+	case RoleVision:
+		models, fallback = cfg.ImageRecognitionModels, config.DefaultImageRecognitionModel1
+		if len(models) == 0 {
+			models = config.GetDefaultImageRecognitionModels()
+		}
+	case RoleImageGen:
+		if cfg.ImageGenerationModel != "" {
+			models = []string{cfg.ImageGenerationModel}
+		}
+		fallback = config.DefaultImageGenerationModel
+	default:
+		models = cfg.TextGenerationModels
+		if len(models) == 0 {
+			models = config.GetDefaultTextGenerationModels()
+		}
+	}
+	if len(models) == 0 {
+		models = []string{fallback}
+	}
+	return models
+}
+
+// Settings 返回角色配置
+func (f *ModelFactory) Settings(role Role) (Settings, bool) {
+	if f == nil {
+		return Settings{}, false
+	}
+	s, ok := f.settings[role]
+	return s, ok
+}
+
+// Enabled 角色是否配置了可用的模型服务（未配置时调用方应使用Mock模式）
+// Ark需要服务地址和API Key，OpenAI兼容接口需要服务地址，Ollama默认访问本机
+func (f *ModelFactory) Enabled(role Role) bool {
+	s, ok := f.Settings(role)
+	if !ok {
+		return false
+	}
+	switch s.Provider {
+	case ProviderArk:
+		return s.BaseURL != "" && s.APIKey != ""
+	case ProviderOpenAI, ProviderOllama:
+		return s.BaseURL != ""
+	default:
+		return false
+	}
+}
+
+// Provider 返回角色使用的提供方
+func (f *ModelFactory) Provider(role Role) string {
+	s, _ := f.Settings(role)
+	return s.Provider
+}
+
+// Models 返回角色可用的模型列表
+func (f *ModelFactory) Models(role Role) []string {
+	s, _ := f.Settings(role)
+	return s.Models
+}
+
+// SelectModel 按路由策略为角色选择一个模型（跳过熔断中的模型）
+// 所有模型都不可用时随机返回一个，由调用结果决定是否恢复
+func (f *ModelFactory) SelectModel(role Role) string {
+	s, _ := f.Settings(role)
+	if len(s.Models) == 0 {
+		return ""
+	}
+	if candidates := f.router.Candidates(role, s, ""); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return s.Models[rand.Intn(len(s.Models))]
+}
+
+// Stats 返回所有角色各模型的路由状态（按角色和模型列表顺序）
+func (f *ModelFactory) Stats() []ModelStats {
+	if f == nil {
+		return nil
+	}
+	var stats []ModelStats
+	for _, role := range Roles {
+		s := f.settings[role]
+		for _, modelName := range s.Models {
+			stat := f.router.stats(role, modelName)
+			stat.Provider = s.Provider
+			stat.Policy = s.Policy
+			stat.Weight = s.weight(modelName)
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
+
+// NewChatModel 为角色创建指定模型的新ChatModel客户端，modelName为空时随机选择
+// 处理请求时应使用 ChatModel 从池中取共享客户端，避免每次请求重建HTTP客户端
+func (f *ModelFactory) NewChatModel(ctx context.Context, role Role, modelName string) (model.ToolCallingChatModel, error) {
+	s, ok := f.Settings(role)
+	if !ok {
+		return nil, fmt.Errorf("未知的模型角色: %s", role)
+	}
+	if !f.Enabled(role) {
+		return nil, fmt.Errorf("模型角色 %s 未配置可用的模型服务（提供方: %s）", role, s.Provider)
+	}
+	if modelName == "" {
+		modelName = f.SelectModel(role)
+	}
+
+	switch s.Provider {
+	case ProviderArk:
+		return ark.NewChatModel(ctx, &ark.ChatModelConfig{
+			BaseURL:     s.BaseURL,
+			APIKey:      s.APIKey,
+			Model:       modelName,
+			Timeout:     &s.Timeout,
+			Temperature: float32Ptr(s.Temperature),
+			TopP:        float32Ptr(s.TopP),
+			MaxTokens:   intPtr(s.MaxTokens),
+		})
+	case ProviderOpenAI, ProviderOllama:
+		return NewOpenAIChatModel(s.openAIConfig(modelName, f.httpClient))
+	default:
+		return nil, fmt.Errorf("不支持的模型提供方: %s", s.Provider)
+	}
+}
+
+// NewImageModel 创建新的图片生成模型客户端，modelName为空时使用图片生成角色的第一个模型
+// 生成的图片放在返回消息的 AssistantGenMultiContent 中（URL或base64）
+func (f *ModelFactory) NewImageModel(ctx context.Context, modelName string) (model.BaseChatModel, error) {
+	s, ok := f.Settings(RoleImageGen)
+	if !ok || !f.Enabled(RoleImageGen) {
+		return nil, fmt.Errorf("图片生成未配置可用的模型服务")
+	}
+	if modelName == "" && len(s.Models) > 0 {
+		modelName = s.Models[0]
+	}
+
+	switch s.Provider {
+	case ProviderArk:
+		return ark.NewImageGenerationModel(ctx, &ark.ImageGenerationConfig{
+			BaseURL: s.BaseURL,
+			APIKey:  s.APIKey,
+			Model:   modelName,
+			Timeout: &s.Timeout,
+		})
+	case ProviderOpenAI:
+		return NewOpenAIImageModel(s.openAIConfig(modelName, f.httpClient))
+	default:
+		return nil, fmt.Errorf("模型提供方 %s 不支持图片生成", s.Provider)
+	}
+}
+
+// openAIConfig 转换为OpenAI兼容客户端配置
+func (s Settings) openAIConfig(modelName string, client *http.Client) OpenAIConfig {
+	return OpenAIConfig{
+		HTTPClient:  client,
+		BaseURL:     s.BaseURL,
+		APIKey:      s.APIKey,
+		Model:       modelName,
+		Timeout:     s.Timeout,
+		Temperature: s.Temperature,
+		TopP:        s.TopP,
+		MaxTokens:   s.MaxTokens,
+	}
+}
+
+// float32Ptr 未设置（0）时返回nil，使用模型默认值
+func float32Ptr(v float64) *float32 {
+	if v == 0 {
+		return nil
+	}
+	f := float32(v)
+	return &f
+}
+
+// intPtr 未设置（0）时返回nil，使用模型默认值
+func intPtr(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
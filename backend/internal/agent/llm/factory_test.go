@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+)
+
+func TestNewModelFactory_Defaults(t *testing.T) {
+	f := NewModelFactory(config.AIConfig{
+		EinoBaseURL:          "https://eino.example.com/v1",
+		AppID:                "app",
+		AppKey:               "key",
+		TextGenerationModels: []string{"text-a", "text-b"},
+		ImageGenerationModel: "image-a",
+	})
+
+	for _, role := range Roles {
+		if !f.Enabled(role) {
+			t.Errorf("角色 %s 应使用eino配置启用", role)
+		}
+		if got := f.Provider(role); got != ProviderArk {
+			t.Errorf("角色 %s 默认提供方 = %q, want %q", role, got, ProviderArk)
+		}
+	}
+
+	text, _ := f.Settings(RoleText)
+	if text.BaseURL != "https://eino.example.com/v1" || text.APIKey != "app:key" {
+		t.Errorf("文本角色应沿用eino服务地址和 AppID:AppKey，got %+v", text)
+	}
+	if text.Timeout != time.Duration(config.DefaultModelTimeoutSeconds)*time.Second {
+		t.Errorf("默认超时 = %v", text.Timeout)
+	}
+	// 卡片角色未配置模型时沿用文本模型
+	if got := f.Models(RoleCard); !reflect.DeepEqual(got, []string{"text-a", "text-b"}) {
+		t.Errorf("卡片模型 = %v", got)
+	}
+	if got := f.Models(RoleImageGen); !reflect.DeepEqual(got, []string{"image-a"}) {
+		t.Errorf("图片生成模型 = %v", got)
+	}
+	if got := f.Models(RoleIntent); len(got) == 0 {
+		t.Error("意图识别未配置模型时应使用默认模型列表")
+	}
+}
+
+func TestNewModelFactory_PerRoleProviders(t *testing.T) {
+	f := NewModelFactory(config.AIConfig{
+		EinoBaseURL: "https://eino.example.com/v1",
+		AppID:       "app",
+		AppKey:      "key",
+		Models: config.ModelRolesConfig{
+			Vision: config.ModelRoleConfig{Provider: ProviderOllama, Models: []string{"llava"}},
+			Text: config.ModelRoleConfig{
+				Provider:       ProviderOpenAI,
+				BaseURL:        "http://127.0.0.1:8080/v1",
+				APIKey:         "local-key",
+				Models:         []string{"qwen"},
+				TimeoutSeconds: 30,
+				Temperature:    0.7,
+			},
+			Card: config.ModelRoleConfig{MaxTokens: 800},
+		},
+	})
+
+	vision, _ := f.Settings(RoleVision)
+	if vision.Provider != ProviderOllama || vision.BaseURL != config.DefaultOllamaBaseURL || vision.APIKey != "" {
+		t.Errorf("Ollama应默认访问本机且不使用eino凭证，got %+v", vision)
+	}
+	if !f.Enabled(RoleVision) {
+		t.Error("Ollama角色应启用")
+	}
+
+	// 卡片角色未设置的字段沿用文本角色
+	card, _ := f.Settings(RoleCard)
+	if card.Provider != ProviderOpenAI || card.BaseURL != "http://127.0.0.1:8080/v1" || card.APIKey != "local-key" {
+		t.Errorf("卡片角色应沿用文本角色的提供方，got %+v", card)
+	}
+	if card.Timeout != 30*time.Second || card.Temperature != 0.7 || card.MaxTokens != 800 {
+		t.Errorf("卡片角色生成参数 = %+v", card)
+	}
+	if !reflect.DeepEqual(card.Models, []string{"qwen"}) {
+		t.Errorf("卡片模型 = %v", card.Models)
+	}
+
+	// 未单独配置的角色仍使用Ark
+	if got := f.Provider(RoleIntent); got != ProviderArk {
+		t.Errorf("意图识别提供方 = %q", got)
+	}
+}
+
+func TestModelRoleConfig_InheritKeepsCredentialsPerProvider(t *testing.T) {
+	text := config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: "https://api.example.com/v1", APIKey: "secret"}
+	card := config.ModelRoleConfig{Provider: ProviderOllama}.Inherit(text)
+	if card.BaseURL != "" || card.APIKey != "" {
+		t.Errorf("提供方不同时不应沿用服务地址和凭证，got %+v", card)
+	}
+}
+
+func TestModelFactory_Disabled(t *testing.T) {
+	var nilFactory *ModelFactory
+	if nilFactory.Enabled(RoleText) {
+		t.Error("nil工厂不应启用任何角色")
+	}
+	if _, err := nilFactory.NewChatModel(context.Background(), RoleText, ""); err == nil {
+		t.Error("nil工厂创建模型应返回错误")
+	}
+
+	f := NewModelFactory(config.AIConfig{})
+	for _, role := range Roles {
+		if f.Enabled(role) {
+			t.Errorf("未配置服务时角色 %s 不应启用", role)
+		}
+	}
+	if _, err := f.NewChatModel(context.Background(), RoleText, ""); err == nil {
+		t.Error("未配置服务时创建模型应返回错误")
+	}
+
+	unknown := NewModelFactory(config.AIConfig{Models: config.ModelRolesConfig{
+		Text: config.ModelRoleConfig{Provider: "unknown", BaseURL: "http://127.0.0.1"},
+	}})
+	if unknown.Enabled(RoleText) {
+		t.Error("不支持的提供方不应启用")
+	}
+}
+
+func TestModelFactory_NewChatModel(t *testing.T) {
+	f := NewModelFactory(config.AIConfig{Models: config.ModelRolesConfig{
+		Text:     config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: "http://127.0.0.1:8080/v1", Models: []string{"qwen"}},
+		ImageGen: config.ModelRoleConfig{Provider: ProviderOllama},
+	}})
+
+	chatModel, err := f.NewChatModel(context.Background(), RoleText, "")
+	if err != nil {
+		t.Fatalf("NewChatModel() error = %v", err)
+	}
+	openAI, ok := chatModel.(*OpenAIChatModel)
+	if !ok {
+		t.Fatalf("OpenAI兼容提供方应返回 *OpenAIChatModel，got %T", chatModel)
+	}
+	if openAI.config.Model != "qwen" || openAI.endpoint != "http://127.0.0.1:8080/v1/chat/completions" {
+		t.Errorf("客户端配置 = %+v, endpoint = %s", openAI.config, openAI.endpoint)
+	}
+
+	if _, err := f.NewImageModel(context.Background(), ""); err == nil {
+		t.Error("Ollama不支持图片生成，应返回错误")
+	}
+}
@@ -0,0 +1,471 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// OpenAIConfig OpenAI兼容接口配置
+type OpenAIConfig struct {
+	BaseURL string // 服务地址，如 https://api.openai.com/v1、http://localhost:11434/v1
+	APIKey  string // 为空时不发送 Authorization 头（如本地Ollama）
+	Model   string
+	Timeout time.Duration // 非流式调用的超时，流式调用只受ctx控制
+	// 生成参数，0表示使用模型默认值
+	Temperature float64
+	TopP        float64
+	MaxTokens   int
+	// HTTPClient 自定义HTTP客户端（可选）
+	HTTPClient *http.Client
+}
+
+// APIError 模型服务返回的错误
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("模型服务返回HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// OpenAIChatModel OpenAI兼容的 /chat/completions 客户端，支持多模态输入、工具调用和流式输出
+type OpenAIChatModel struct {
+	config   OpenAIConfig
+	endpoint string
+	client   *http.Client
+	tools    []*schema.ToolInfo
+}
+
+// NewOpenAIChatModel 创建OpenAI兼容的ChatModel
+func NewOpenAIChatModel(cfg OpenAIConfig) (*OpenAIChatModel, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("未配置模型服务地址")
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("未配置模型名称")
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &OpenAIChatModel{
+		config:   cfg,
+		endpoint: strings.TrimSuffix(cfg.BaseURL, "/") + "/chat/completions",
+		client:   client,
+	}, nil
+}
+
+// Generate 调用模型生成完整回复
+func (m *OpenAIChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	req, err := m.buildRequest(in, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if m.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.config.Timeout)
+		defer cancel()
+	}
+
+	resp, err := m.post(ctx, m.endpoint, req, req.Stream)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析模型响应失败: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("模型响应为空")
+	}
+	choice := result.Choices[0]
+	msg := choice.Message.toMessage()
+	msg.ResponseMeta = &schema.ResponseMeta{
+		FinishReason: choice.FinishReason,
+		Usage:        result.Usage.toTokenUsage(),
+	}
+	return msg, nil
+}
+
+// Stream 调用模型流式生成回复
+func (m *OpenAIChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	req, err := m.buildRequest(in, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.post(ctx, m.endpoint, req, req.Stream)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](16)
+	go func() {
+		defer resp.Body.Close()
+		defer sw.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				return
+			}
+
+			var chunk chatResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				sw.Send(nil, fmt.Errorf("解析模型流式响应失败: %w", err))
+				return
+			}
+			if chunk.Error != nil {
+				sw.Send(nil, &APIError{StatusCode: resp.StatusCode, Message: chunk.Error.Message})
+				return
+			}
+
+			msg := &schema.Message{Role: schema.Assistant}
+			if len(chunk.Choices) > 0 {
+				choice := chunk.Choices[0]
+				msg = choice.Delta.toMessage()
+				if choice.FinishReason != "" {
+					msg.ResponseMeta = &schema.ResponseMeta{FinishReason: choice.FinishReason}
+				}
+			}
+			if usage := chunk.Usage.toTokenUsage(); usage != nil {
+				if msg.ResponseMeta == nil {
+					msg.ResponseMeta = &schema.ResponseMeta{}
+				}
+				msg.ResponseMeta.Usage = usage
+			}
+			if closed := sw.Send(msg, nil); closed {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			sw.Send(nil, fmt.Errorf("读取模型流式响应失败: %w", err))
+		}
+	}()
+	return sr, nil
+}
+
+// BindTools 绑定工具（之后的调用都会带上这些工具）
+func (m *OpenAIChatModel) BindTools(tools []*schema.ToolInfo) error {
+	if len(tools) == 0 {
+		return errors.New("工具列表为空")
+	}
+	m.tools = tools
+	return nil
+}
+
+// WithTools 返回绑定了工具的新实例，不修改当前实例
+func (m *OpenAIChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("工具列表为空")
+	}
+	clone := *m
+	clone.tools = tools
+	return &clone, nil
+}
+
+// post 发送JSON请求，非200响应转换为 APIError
+func (m *OpenAIChatModel) post(ctx context.Context, endpoint string, body interface{}, stream bool) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("构造模型请求失败: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建模型请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if m.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.config.APIKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("调用模型服务失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: errorMessage(respBody)}
+	}
+	return resp, nil
+}
+
+// buildRequest 构造 /chat/completions 请求体
+func (m *OpenAIChatModel) buildRequest(in []*schema.Message, stream bool, opts ...model.Option) (*chatRequest, error) {
+	options := model.GetCommonOptions(&model.Options{
+		Model:       &m.config.Model,
+		Temperature: float32Ptr(m.config.Temperature),
+		TopP:        float32Ptr(m.config.TopP),
+		MaxTokens:   intPtr(m.config.MaxTokens),
+		Tools:       m.tools,
+	}, opts...)
+
+	req := &chatRequest{
+		Model:       *options.Model,
+		Messages:    make([]chatMessage, 0, len(in)),
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+		MaxTokens:   options.MaxTokens,
+		Stop:        options.Stop,
+		Stream:      stream,
+	}
+	for _, msg := range in {
+		if msg == nil {
+			continue
+		}
+		req.Messages = append(req.Messages, toChatMessage(msg))
+	}
+
+	for _, tool := range options.Tools {
+		fn := chatFunction{Name: tool.Name, Description: tool.Desc}
+		if tool.ParamsOneOf != nil {
+			params, err := tool.ParamsOneOf.ToJSONSchema()
+			if err != nil {
+				return nil, fmt.Errorf("转换工具 %s 的参数定义失败: %w", tool.Name, err)
+			}
+			fn.Parameters = params
+		}
+		req.Tools = append(req.Tools, chatTool{Type: "function", Function: fn})
+	}
+	if len(req.Tools) > 0 && options.ToolChoice != nil {
+		switch *options.ToolChoice {
+		case schema.ToolChoiceForbidden:
+			req.ToolChoice = "none"
+		case schema.ToolChoiceForced:
+			req.ToolChoice = "required"
+		default:
+			req.ToolChoice = "auto"
+		}
+	}
+	return req, nil
+}
+
+// chatRequest /chat/completions 请求体
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+	Tools       []chatTool    `json:"tools,omitempty"`
+	ToolChoice  string        `json:"tool_choice,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+// chatMessage 请求消息，Content 为字符串或多模态内容数组
+type chatMessage struct {
+	Role       string         `json:"role"`
+	Content    interface{}    `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type chatContentPart struct {
+	Type     string        `json:"type"`
+	Text     string        `json:"text,omitempty"`
+	ImageURL *chatImageURL `json:"image_url,omitempty"`
+}
+
+type chatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type chatToolCall struct {
+	Index    *int             `json:"index,omitempty"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function chatFunctionCall `json:"function"`
+}
+
+type chatFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+type chatFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"`
+}
+
+// chatResponse 响应体（流式响应的每个分片格式相同，内容在 delta 中）
+type chatResponse struct {
+	Choices []struct {
+		Message      responseMessage `json:"message"`
+		Delta        responseMessage `json:"delta"`
+		FinishReason string          `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type responseMessage struct {
+	Content          string         `json:"content"`
+	ReasoningContent string         `json:"reasoning_content"`
+	ToolCalls        []chatToolCall `json:"tool_calls"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u *chatUsage) toTokenUsage() *schema.TokenUsage {
+	if u == nil {
+		return nil
+	}
+	return &schema.TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+func (r responseMessage) toMessage() *schema.Message {
+	msg := &schema.Message{
+		Role:             schema.Assistant,
+		Content:          r.Content,
+		ReasoningContent: r.ReasoningContent,
+	}
+	for _, call := range r.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			Index: call.Index,
+			ID:    call.ID,
+			Type:  call.Type,
+			Function: schema.FunctionCall{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		})
+	}
+	return msg
+}
+
+// toChatMessage 转换eino消息，图片使用URL或 data URL
+func toChatMessage(msg *schema.Message) chatMessage {
+	out := chatMessage{
+		Role:       string(msg.Role),
+		Content:    msg.Content,
+		ToolCallID: msg.ToolCallID,
+	}
+	for _, call := range msg.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, chatToolCall{
+			ID:   call.ID,
+			Type: "function",
+			Function: chatFunctionCall{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		})
+	}
+
+	var parts []chatContentPart
+	for _, part := range msg.UserInputMultiContent {
+		switch {
+		case part.Type == schema.ChatMessagePartTypeText:
+			parts = append(parts, chatContentPart{Type: "text", Text: part.Text})
+		case part.Type == schema.ChatMessagePartTypeImageURL && part.Image != nil:
+			if url := imageURL(part.Image.MessagePartCommon); url != "" {
+				parts = append(parts, chatContentPart{
+					Type:     "image_url",
+					ImageURL: &chatImageURL{URL: url, Detail: string(part.Image.Detail)},
+				})
+			}
+		}
+	}
+	for _, part := range msg.MultiContent {
+		switch {
+		case part.Type == schema.ChatMessagePartTypeText:
+			parts = append(parts, chatContentPart{Type: "text", Text: part.Text})
+		case part.Type == schema.ChatMessagePartTypeImageURL && part.ImageURL != nil:
+			parts = append(parts, chatContentPart{
+				Type:     "image_url",
+				ImageURL: &chatImageURL{URL: part.ImageURL.URL, Detail: string(part.ImageURL.Detail)},
+			})
+		}
+	}
+	if len(parts) > 0 {
+		if msg.Content != "" {
+			parts = append([]chatContentPart{{Type: "text", Text: msg.Content}}, parts...)
+		}
+		out.Content = parts
+	}
+	return out
+}
+
+// imageURL 返回图片URL，只有base64数据时转换为 data URL
+func imageURL(common schema.MessagePartCommon) string {
+	if common.URL != nil && *common.URL != "" {
+		return *common.URL
+	}
+	if common.Base64Data != nil && *common.Base64Data != "" {
+		mimeType := common.MIMEType
+		if mimeType == "" {
+			mimeType = "image/jpeg"
+		}
+		return "data:" + mimeType + ";base64," + *common.Base64Data
+	}
+	return ""
+}
+
+// errorMessage 从错误响应中提取错误信息
+func errorMessage(body []byte) string {
+	var payload struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && len(payload.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(payload.Error, &detail); err == nil && detail.Message != "" {
+			return detail.Message
+		}
+		var message string
+		if err := json.Unmarshal(payload.Error, &message); err == nil && message != "" {
+			return message
+		}
+	}
+	return truncate(strings.TrimSpace(string(body)), 200)
+}
+
+// truncate 截断过长的字符串（用于错误信息）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
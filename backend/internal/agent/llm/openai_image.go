@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// OpenAIImageModel OpenAI兼容的 /images/generations 客户端
+// 以最后一条用户消息作为提示词，生成的图片放在返回消息的 AssistantGenMultiContent 中
+type OpenAIImageModel struct {
+	chat     *OpenAIChatModel
+	endpoint string
+}
+
+// NewOpenAIImageModel 创建OpenAI兼容的图片生成模型
+func NewOpenAIImageModel(cfg OpenAIConfig) (*OpenAIImageModel, error) {
+	chat, err := NewOpenAIChatModel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAIImageModel{
+		chat:     chat,
+		endpoint: strings.TrimSuffix(cfg.BaseURL, "/") + "/images/generations",
+	}, nil
+}
+
+// imageRequest /images/generations 请求体
+type imageRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	N      int    `json:"n"`
+}
+
+// imageResponse /images/generations 响应体
+type imageResponse struct {
+	Data []struct {
+		URL     string `json:"url"`
+		B64JSON string `json:"b64_json"`
+	} `json:"data"`
+}
+
+// Generate 生成图片
+func (m *OpenAIImageModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	options := model.GetCommonOptions(&model.Options{Model: &m.chat.config.Model}, opts...)
+	prompt := ""
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i] != nil && in[i].Role == schema.User {
+			prompt = in[i].Content
+			break
+		}
+	}
+	if prompt == "" {
+		return nil, fmt.Errorf("图片生成提示词为空")
+	}
+
+	if m.chat.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.chat.config.Timeout)
+		defer cancel()
+	}
+	resp, err := m.chat.post(ctx, m.endpoint, &imageRequest{Model: *options.Model, Prompt: prompt, N: 1}, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result imageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析图片生成响应失败: %w", err)
+	}
+
+	msg := &schema.Message{Role: schema.Assistant}
+	for _, image := range result.Data {
+		common := schema.MessagePartCommon{MIMEType: "image/png"}
+		switch {
+		case image.URL != "":
+			url := image.URL
+			common.URL = &url
+		case image.B64JSON != "":
+			data := image.B64JSON
+			common.Base64Data = &data
+		default:
+			continue
+		}
+		msg.AssistantGenMultiContent = append(msg.AssistantGenMultiContent, schema.MessageOutputPart{
+			Type:  schema.ChatMessagePartTypeImageURL,
+			Image: &schema.MessageOutputImage{MessagePartCommon: common},
+		})
+	}
+	if len(msg.AssistantGenMultiContent) == 0 {
+		return nil, fmt.Errorf("图片生成响应中没有图片")
+	}
+	return msg, nil
+}
+
+// Stream 图片生成不支持增量输出，一次性返回完整结果
+func (m *OpenAIImageModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
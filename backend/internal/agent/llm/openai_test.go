@@ -0,0 +1,189 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestOpenAIChatModel_Generate(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("请求体不是JSON: %v", err)
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{\"q\":\"银杏\"}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`)
+	}))
+	defer server.Close()
+
+	chatModel, err := NewOpenAIChatModel(OpenAIConfig{BaseURL: server.URL + "/v1/", APIKey: "secret", Model: "qwen", Temperature: 0.5})
+	if err != nil {
+		t.Fatalf("NewOpenAIChatModel() error = %v", err)
+	}
+	if err := chatModel.BindTools([]*schema.ToolInfo{{
+		Name: "lookup",
+		Desc: "查询知识",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"q": {Type: schema.String, Required: true},
+		}),
+	}}); err != nil {
+		t.Fatalf("BindTools() error = %v", err)
+	}
+
+	image := "data:image/png;base64,AAAA"
+	msg, err := chatModel.Generate(context.Background(), []*schema.Message{
+		schema.SystemMessage("你是科普助手"),
+		{
+			Role: schema.User,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "这是什么？"},
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+					MessagePartCommon: schema.MessagePartCommon{URL: &image},
+					Detail:            schema.ImageURLDetailAuto,
+				}},
+			},
+		},
+	}, model.WithMaxTokens(256))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// 请求体：模型、生成参数、多模态内容和工具
+	if got["model"] != "qwen" || got["temperature"] != 0.5 || got["max_tokens"] != float64(256) {
+		t.Errorf("请求参数 = %v", got)
+	}
+	messages := got["messages"].([]interface{})
+	parts := messages[1].(map[string]interface{})["content"].([]interface{})
+	imagePart := parts[1].(map[string]interface{})
+	if imagePart["type"] != "image_url" || imagePart["image_url"].(map[string]interface{})["url"] != image {
+		t.Errorf("图片内容 = %v", imagePart)
+	}
+	tools := got["tools"].([]interface{})
+	function := tools[0].(map[string]interface{})["function"].(map[string]interface{})
+	if function["name"] != "lookup" || function["parameters"] == nil {
+		t.Errorf("工具定义 = %v", function)
+	}
+
+	// 响应：工具调用、结束原因和token用量
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "lookup" || msg.ToolCalls[0].Function.Arguments != `{"q":"银杏"}` {
+		t.Errorf("ToolCalls = %+v", msg.ToolCalls)
+	}
+	if msg.ResponseMeta == nil || msg.ResponseMeta.FinishReason != "tool_calls" || msg.ResponseMeta.Usage.TotalTokens != 17 {
+		t.Errorf("ResponseMeta = %+v", msg.ResponseMeta)
+	}
+}
+
+func TestOpenAIChatModel_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["stream"] != true {
+			t.Errorf("流式请求应设置 stream=true，got %v", req["stream"])
+		}
+		if _, ok := r.Header["Authorization"]; ok {
+			t.Error("未配置API Key时不应发送 Authorization 头")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"delta":{"role":"assistant","content":"银杏"}}]}`,
+			`{"choices":[{"delta":{"content":"是活化石"}}]}`,
+			`{"choices":[{"delta":{},"finish_reason":"stop"}]}`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	chatModel, err := NewOpenAIChatModel(OpenAIConfig{BaseURL: server.URL, Model: "llama3"})
+	if err != nil {
+		t.Fatalf("NewOpenAIChatModel() error = %v", err)
+	}
+	stream, err := chatModel.Stream(context.Background(), []*schema.Message{schema.UserMessage("银杏是什么")})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var chunks []*schema.Message
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages() error = %v", err)
+	}
+	if msg.Content != "银杏是活化石" {
+		t.Errorf("Content = %q", msg.Content)
+	}
+	if msg.ResponseMeta == nil || msg.ResponseMeta.FinishReason != "stop" {
+		t.Errorf("ResponseMeta = %+v", msg.ResponseMeta)
+	}
+}
+
+func TestOpenAIChatModel_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"message":"model is loading"}}`)
+	}))
+	defer server.Close()
+
+	chatModel, _ := NewOpenAIChatModel(OpenAIConfig{BaseURL: server.URL, Model: "llama3"})
+	_, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("你好")})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("应返回 *APIError，got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != "model is loading" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestOpenAIImageModel_Generate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/generations" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var req imageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Prompt != "一片银杏叶" || req.Model != "dall-e-3" {
+			t.Errorf("请求 = %+v", req)
+		}
+		fmt.Fprint(w, `{"data":[{"url":"https://img.example.com/1.png"}]}`)
+	}))
+	defer server.Close()
+
+	imageModel, err := NewOpenAIImageModel(OpenAIConfig{BaseURL: server.URL, Model: "dall-e-3"})
+	if err != nil {
+		t.Fatalf("NewOpenAIImageModel() error = %v", err)
+	}
+	msg, err := imageModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("一片银杏叶")})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(msg.AssistantGenMultiContent) != 1 || *msg.AssistantGenMultiContent[0].Image.URL != "https://img.example.com/1.png" {
+		t.Errorf("AssistantGenMultiContent = %+v", msg.AssistantGenMultiContent)
+	}
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// clientKey 模型池中客户端的键（角色 + 模型名称）
+type clientKey struct {
+	role  Role
+	model string
+}
+
+// ChatModel 返回角色指定模型的共享客户端，modelName为空时按路由策略选择
+// 每个（角色, 模型）的客户端只创建一次，之后所有请求复用（包括底层HTTP连接）
+// 返回的客户端可并发使用；需要工具调用时使用 WithTools 得到绑定工具的副本，不要调用 BindTools 修改共享实例
+func (f *ModelFactory) ChatModel(ctx context.Context, role Role, modelName string) (model.ToolCallingChatModel, error) {
+	if f == nil {
+		return nil, fmt.Errorf("未配置模型工厂")
+	}
+	if modelName == "" {
+		modelName = f.SelectModel(role)
+	}
+	key := clientKey{role: role, model: modelName}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if chatModel, ok := f.chatModels[key]; ok {
+		return chatModel, nil
+	}
+	chatModel, err := f.NewChatModel(ctx, role, modelName)
+	if err != nil {
+		return nil, err
+	}
+	f.chatModels[key] = chatModel
+	return chatModel, nil
+}
+
+// Preload 预先创建角色所有模型的客户端，任一模型创建失败时返回错误
+func (f *ModelFactory) Preload(ctx context.Context, role Role) error {
+	if !f.Enabled(role) {
+		return fmt.Errorf("模型角色 %s 未配置可用的模型服务（提供方: %s）", role, f.Provider(role))
+	}
+	for _, modelName := range f.Models(role) {
+		if _, err := f.ChatModel(ctx, role, modelName); err != nil {
+			return fmt.Errorf("创建模型 %s 的客户端失败: %w", modelName, err)
+		}
+	}
+	return nil
+}
+
+// ImageModel 返回图片生成模型的共享客户端，modelName为空时使用图片生成角色的第一个模型
+func (f *ModelFactory) ImageModel(ctx context.Context, modelName string) (model.BaseChatModel, error) {
+	if f == nil {
+		return nil, fmt.Errorf("未配置模型工厂")
+	}
+	if modelName == "" {
+		if models := f.Models(RoleImageGen); len(models) > 0 {
+			modelName = models[0]
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if imageModel, ok := f.imageModels[modelName]; ok {
+		return imageModel, nil
+	}
+	imageModel, err := f.NewImageModel(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+	f.imageModels[modelName] = imageModel
+	return imageModel, nil
+}
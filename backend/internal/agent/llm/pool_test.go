@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/config"
+)
+
+func TestModelFactory_ChatModelReusesClients(t *testing.T) {
+	f := NewModelFactory(config.AIConfig{Models: config.ModelRolesConfig{
+		Text: config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: "http://127.0.0.1:8080/v1", Models: []string{"qwen", "llama3"}},
+	}})
+	ctx := context.Background()
+
+	first, err := f.ChatModel(ctx, RoleText, "qwen")
+	if err != nil {
+		t.Fatalf("ChatModel() error = %v", err)
+	}
+	second, _ := f.ChatModel(ctx, RoleText, "qwen")
+	if first != second {
+		t.Error("同一角色和模型应复用同一个客户端")
+	}
+	other, _ := f.ChatModel(ctx, RoleText, "llama3")
+	if other == first {
+		t.Error("不同模型应使用不同的客户端")
+	}
+	// 卡片角色沿用文本角色的配置，但客户端按角色分开（生成参数可能不同）
+	card, _ := f.ChatModel(ctx, RoleCard, "qwen")
+	if card == first {
+		t.Error("不同角色应使用不同的客户端")
+	}
+
+	// 并发获取不会重复创建客户端
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.ChatModel(ctx, RoleText, ""); err != nil {
+				t.Errorf("ChatModel() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(f.chatModels) != 3 {
+		t.Errorf("池中客户端数量 = %d, want 3", len(f.chatModels))
+	}
+}
+
+func TestModelFactory_Preload(t *testing.T) {
+	f := NewModelFactory(config.AIConfig{Models: config.ModelRolesConfig{
+		Vision: config.ModelRoleConfig{Provider: ProviderOllama, Models: []string{"llava", "qwen2.5vl"}},
+	}})
+	if err := f.Preload(context.Background(), RoleVision); err != nil {
+		t.Fatalf("Preload() error = %v", err)
+	}
+	if len(f.chatModels) != 2 {
+		t.Errorf("预加载后客户端数量 = %d, want 2", len(f.chatModels))
+	}
+	if err := f.Preload(context.Background(), RoleText); err == nil {
+		t.Error("未配置模型服务的角色预加载应返回错误")
+	}
+}
+
+func TestRoleChatModel_WithTools(t *testing.T) {
+	var mu sync.Mutex
+	toolCounts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string            `json:"model"`
+			Tools []json.RawMessage `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		toolCounts[req.Model] += len(req.Tools)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"%s"},"finish_reason":"stop"}]}`, req.Model)
+	}))
+	defer server.Close()
+
+	f := NewModelFactory(config.AIConfig{Models: config.ModelRolesConfig{
+		Text: config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: server.URL, Models: []string{"qwen"}},
+	}})
+	roleModel := f.RoleModel(RoleText)
+	withTools, err := roleModel.WithTools([]*schema.ToolInfo{{Name: "lookup", Desc: "查询知识"}})
+	if err != nil {
+		t.Fatalf("WithTools() error = %v", err)
+	}
+
+	ctx := context.Background()
+	msg, err := roleModel.Generate(ctx, []*schema.Message{schema.UserMessage("你好")})
+	if err != nil || msg.Content != "qwen" {
+		t.Fatalf("Generate() = %+v, %v", msg, err)
+	}
+	if toolCounts["qwen"] != 0 {
+		t.Error("WithTools 不应修改原实例和共享客户端")
+	}
+	if _, err := withTools.Generate(ctx, []*schema.Message{schema.UserMessage("你好")}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if toolCounts["qwen"] != 1 {
+		t.Errorf("绑定工具的副本应带上工具，got %d", toolCounts["qwen"])
+	}
+	if len(f.chatModels) != 1 {
+		t.Errorf("绑定工具不应创建新的池客户端，got %d", len(f.chatModels))
+	}
+}
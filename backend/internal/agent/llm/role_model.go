@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// RoleChatModel 按角色路由的ChatModel：每次调用按路由策略给出候选模型，使用模型池中的共享客户端，
+// 模型调用失败时在同一次调用内切换到下一个候选模型，并把结果计入该模型的健康度
+// 本身不保存请求状态，可以在节点间共享并发使用；WithTools、WithPreferred 返回副本
+type RoleChatModel struct {
+	factory   *ModelFactory
+	role      Role
+	tools     []*schema.ToolInfo
+	preferred string
+}
+
+// RoleModel 返回角色的ChatModel
+func (f *ModelFactory) RoleModel(role Role) *RoleChatModel {
+	return &RoleChatModel{factory: f, role: role}
+}
+
+// WithPreferred 返回优先使用指定模型的副本（模型熔断或失败时仍会切换到其他候选模型）
+func (m *RoleChatModel) WithPreferred(modelName string) *RoleChatModel {
+	clone := *m
+	clone.preferred = modelName
+	return &clone
+}
+
+// Generate 生成完整回复，失败时切换模型重试
+func (m *RoleChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	var result *schema.Message
+	err := m.route(ctx, func(chatModel model.BaseChatModel) error {
+		msg, err := chatModel.Generate(ctx, in, opts...)
+		result = msg
+		return err
+	})
+	return result, err
+}
+
+// Stream 流式生成回复，建立流失败时切换模型重试（流开始输出后的错误通过流返回，不再切换）
+func (m *RoleChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	var result *schema.StreamReader[*schema.Message]
+	err := m.route(ctx, func(chatModel model.BaseChatModel) error {
+		stream, err := chatModel.Stream(ctx, in, opts...)
+		result = stream
+		return err
+	})
+	return result, err
+}
+
+// WithTools 返回绑定了工具的副本，不修改当前实例
+func (m *RoleChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("工具列表为空")
+	}
+	clone := *m
+	clone.tools = tools
+	return &clone, nil
+}
+
+// route 依次尝试候选模型，直到调用成功、请求本身出错或调用方结束
+func (m *RoleChatModel) route(ctx context.Context, call func(chatModel model.BaseChatModel) error) error {
+	f := m.factory
+	s, ok := f.Settings(m.role)
+	if !ok || !f.Enabled(m.role) {
+		return fmt.Errorf("模型角色 %s 未配置可用的模型服务", m.role)
+	}
+
+	var lastErr error
+	for _, modelName := range f.router.Candidates(m.role, s, m.preferred) {
+		if !f.router.Acquire(m.role, modelName) {
+			continue
+		}
+		chatModel, err := m.client(ctx, modelName)
+		if err != nil {
+			f.router.Record(m.role, modelName, 0, err)
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		err = call(chatModel)
+		switch {
+		case err == nil:
+			f.router.Record(m.role, modelName, time.Since(start), nil)
+			return nil
+		case isCallerCanceled(ctx), isRequestError(err):
+			f.router.Release(m.role, modelName)
+			return err
+		}
+
+		f.router.Record(m.role, modelName, time.Since(start), err)
+		lastErr = fmt.Errorf("模型 %s 调用失败: %w", modelName, err)
+		if ctx.Err() != nil {
+			return lastErr
+		}
+		logx.WithContext(ctx).Errorw("模型调用失败，切换到下一个候选模型",
+			logx.Field("role", m.role),
+			logx.Field("model", modelName),
+			logx.Field("error", err),
+		)
+	}
+	if lastErr == nil {
+		return fmt.Errorf("模型角色 %s: %w", m.role, ErrNoAvailableModel)
+	}
+	return lastErr
+}
+
+// client 取出模型的共享客户端并按需绑定工具
+func (m *RoleChatModel) client(ctx context.Context, modelName string) (model.BaseChatModel, error) {
+	chatModel, err := m.factory.ChatModel(ctx, m.role, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.tools) == 0 {
+		return chatModel, nil
+	}
+	return chatModel.WithTools(m.tools)
+}
@@ -0,0 +1,290 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	arkmodel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+// 路由策略
+const (
+	PolicyWeighted = "weighted" // 按权重和近期成功率随机排序候选模型（默认）
+	PolicyPriority = "priority" // 按模型列表顺序，前面的模型熔断或失败时才使用后面的
+)
+
+// 熔断器状态
+const (
+	CircuitClosed   = "closed"    // 正常
+	CircuitOpen     = "open"      // 熔断中，冷却结束前不使用
+	CircuitHalfOpen = "half_open" // 冷却结束，允许一次探测请求
+)
+
+// healthAlpha 成功率和延迟的指数移动平均系数（越大越看重最近的调用）
+const healthAlpha = 0.2
+
+// minHealthFactor 成功率很低的模型在加权排序中保留的最低比例，避免完全不被选中而无法恢复
+const minHealthFactor = 0.05
+
+// ErrNoAvailableModel 角色的所有模型都已熔断或被权重禁用
+var ErrNoAvailableModel = errors.New("没有可用的模型（均已熔断或被禁用）")
+
+// RouterConfig 路由和熔断参数
+type RouterConfig struct {
+	FailureThreshold int           // 连续失败次数达到该值时熔断，<=0 表示不熔断
+	Cooldown         time.Duration // 熔断冷却时间
+	MaxAttempts      int           // 单次调用最多尝试的模型数
+}
+
+// modelHealth 单个（角色, 模型）的健康状态
+type modelHealth struct {
+	requests            int64
+	successes           int64
+	failures            int64
+	consecutiveFailures int
+	successRate         float64       // 成功率（指数移动平均）
+	latency             time.Duration // 成功调用的延迟（指数移动平均）
+	state               string
+	openedAt            time.Time
+	probing             bool // 半开状态下的探测请求是否在进行中
+	lastError           string
+	lastErrorAt         time.Time
+}
+
+// ModelStats 模型的路由状态快照
+type ModelStats struct {
+	Role                Role
+	Model               string
+	Provider            string
+	Policy              string
+	Weight              float64
+	State               string
+	Requests            int64
+	Successes           int64
+	Failures            int64
+	ConsecutiveFailures int
+	SuccessRate         float64
+	Latency             time.Duration
+	LastError           string
+	LastErrorAt         time.Time
+	RetryAt             time.Time // 熔断中的模型允许探测的时间
+}
+
+// Router 健康感知的模型路由器：记录每个模型的成功率和延迟，连续失败时熔断，
+// 为每次调用给出候选模型顺序（调用失败时依次切换到下一个候选模型）
+type Router struct {
+	config RouterConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	health map[clientKey]*modelHealth
+	rand   *rand.Rand
+}
+
+// NewRouter 创建路由器
+func NewRouter(cfg RouterConfig) *Router {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	return &Router{
+		config: cfg,
+		now:    time.Now,
+		health: make(map[clientKey]*modelHealth),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// getHealth 返回模型的健康状态，调用方需持有锁
+func (r *Router) getHealth(key clientKey) *modelHealth {
+	h, ok := r.health[key]
+	if !ok {
+		h = &modelHealth{successRate: 1, state: CircuitClosed}
+		r.health[key] = h
+	}
+	return h
+}
+
+// refreshState 冷却结束的熔断模型转为半开状态，调用方需持有锁
+func (r *Router) refreshState(h *modelHealth) {
+	if h.state == CircuitOpen && !r.now().Before(h.openedAt.Add(r.config.Cooldown)) {
+		h.state = CircuitHalfOpen
+		h.probing = false
+	}
+}
+
+// Candidates 按策略给出本次调用的候选模型顺序（已排除熔断中和权重为0的模型）
+// preferred 不为空且可用时排在第一位
+func (r *Router) Candidates(role Role, s Settings, preferred string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type candidate struct {
+		model string
+		key   float64
+	}
+	candidates := make([]candidate, 0, len(s.Models))
+	for i, modelName := range s.Models {
+		weight := s.weight(modelName)
+		if weight <= 0 {
+			continue
+		}
+		h := r.getHealth(clientKey{role: role, model: modelName})
+		r.refreshState(h)
+		if h.state == CircuitOpen || (h.state == CircuitHalfOpen && h.probing) {
+			continue
+		}
+
+		var key float64
+		switch s.Policy {
+		case PolicyPriority:
+			key = -float64(i)
+		default:
+			// 加权随机排序（Efraimidis-Spirakis）：key = u^(1/w)，key越大越靠前
+			w := weight * math.Max(h.successRate, minHealthFactor)
+			key = math.Pow(r.rand.Float64(), 1/w)
+		}
+		if modelName == preferred {
+			key = math.Inf(1)
+		}
+		candidates = append(candidates, candidate{model: modelName, key: key})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+	if len(candidates) > r.config.MaxAttempts {
+		candidates = candidates[:r.config.MaxAttempts]
+	}
+	models := make([]string, len(candidates))
+	for i, c := range candidates {
+		models[i] = c.model
+	}
+	return models
+}
+
+// Acquire 调用模型前确认模型仍可用；半开状态的模型只放行一个探测请求
+func (r *Router) Acquire(role Role, modelName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.getHealth(clientKey{role: role, model: modelName})
+	r.refreshState(h)
+	switch h.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if h.probing {
+			return false
+		}
+		h.probing = true
+	}
+	return true
+}
+
+// Record 记录一次调用结果；err 为 nil 表示成功
+func (r *Router) Record(role Role, modelName string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.getHealth(clientKey{role: role, model: modelName})
+	h.requests++
+	h.probing = false
+	if err == nil {
+		h.successes++
+		h.consecutiveFailures = 0
+		h.successRate = h.successRate*(1-healthAlpha) + healthAlpha
+		if h.latency == 0 {
+			h.latency = latency
+		} else {
+			h.latency = time.Duration(float64(h.latency)*(1-healthAlpha) + float64(latency)*healthAlpha)
+		}
+		h.state = CircuitClosed
+		return
+	}
+
+	h.failures++
+	h.consecutiveFailures++
+	h.successRate *= 1 - healthAlpha
+	h.lastError = truncate(err.Error(), 200)
+	h.lastErrorAt = r.now()
+	// 半开状态的探测失败时重新熔断；正常状态连续失败达到阈值时熔断
+	if h.state == CircuitHalfOpen || (r.config.FailureThreshold > 0 && h.consecutiveFailures >= r.config.FailureThreshold) {
+		h.state = CircuitOpen
+		h.openedAt = r.now()
+	}
+}
+
+// Release 调用被放弃（如调用方取消）时释放探测名额，不计入成功或失败
+func (r *Router) Release(role Role, modelName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.getHealth(clientKey{role: role, model: modelName}).probing = false
+}
+
+// stats 返回模型的状态快照
+func (r *Router) stats(role Role, modelName string) ModelStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.getHealth(clientKey{role: role, model: modelName})
+	r.refreshState(h)
+	stats := ModelStats{
+		Role:                role,
+		Model:               modelName,
+		State:               h.state,
+		Requests:            h.requests,
+		Successes:           h.successes,
+		Failures:            h.failures,
+		ConsecutiveFailures: h.consecutiveFailures,
+		SuccessRate:         h.successRate,
+		Latency:             h.latency,
+		LastError:           h.lastError,
+		LastErrorAt:         h.lastErrorAt,
+	}
+	if h.state == CircuitOpen {
+		stats.RetryAt = h.openedAt.Add(r.config.Cooldown)
+	}
+	return stats
+}
+
+// isRequestError 请求本身有问题（参数错误、内容过长、内容审核未通过等），换模型也无济于事，不计入模型健康度
+// 识别本包的 APIError 以及 Ark SDK 返回的 APIError、RequestError
+func isRequestError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRequestErrorStatus(apiErr.StatusCode)
+	}
+	var arkAPIErr *arkmodel.APIError
+	if errors.As(err, &arkAPIErr) {
+		return isRequestErrorStatus(arkAPIErr.HTTPStatusCode)
+	}
+	var arkRequestErr *arkmodel.RequestError
+	if errors.As(err, &arkRequestErr) {
+		return isRequestErrorStatus(arkRequestErr.HTTPStatusCode)
+	}
+	return false
+}
+
+// isRequestErrorStatus 4xx 状态码表示请求本身有问题；
+// 鉴权失败、模型不存在、请求超时和限流与模型服务有关，仍计入模型健康度并切换模型
+func isRequestErrorStatus(code int) bool {
+	if code < http.StatusBadRequest || code >= http.StatusInternalServerError {
+		return false
+	}
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// isCallerCanceled 调用方主动取消（如客户端断开），不计入模型健康度
+func isCallerCanceled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
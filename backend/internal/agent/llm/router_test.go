@@ -0,0 +1,203 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/config"
+	arkmodel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+func TestRouter_CircuitBreaker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewRouter(RouterConfig{FailureThreshold: 2, Cooldown: 30 * time.Second, MaxAttempts: 3})
+	r.now = func() time.Time { return now }
+	s := Settings{Policy: PolicyPriority, Models: []string{"a", "b"}}
+	failed := errors.New("服务不可用")
+
+	r.Record(RoleText, "a", 0, failed)
+	if got := r.Candidates(RoleText, s, ""); len(got) != 2 {
+		t.Fatalf("未达到阈值不应熔断，候选 = %v", got)
+	}
+	r.Record(RoleText, "a", 0, failed)
+	if got := r.Candidates(RoleText, s, "a"); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("连续失败达到阈值应熔断，候选 = %v", got)
+	}
+	if st := r.stats(RoleText, "a"); st.State != CircuitOpen || !st.RetryAt.Equal(now.Add(30*time.Second)) {
+		t.Errorf("stats = %+v, want open", st)
+	}
+
+	// 冷却结束后半开，只放行一个探测请求
+	now = now.Add(31 * time.Second)
+	if !r.Acquire(RoleText, "a") {
+		t.Fatal("冷却结束后应允许探测")
+	}
+	if r.Acquire(RoleText, "a") {
+		t.Error("半开状态只允许一个探测请求")
+	}
+	if got := r.Candidates(RoleText, s, ""); len(got) != 1 {
+		t.Errorf("探测进行中的模型不应作为候选，候选 = %v", got)
+	}
+
+	// 探测失败重新熔断
+	r.Record(RoleText, "a", 0, failed)
+	if st := r.stats(RoleText, "a"); st.State != CircuitOpen {
+		t.Errorf("探测失败应重新熔断，state = %s", st.State)
+	}
+
+	// 探测成功恢复
+	now = now.Add(31 * time.Second)
+	r.Acquire(RoleText, "a")
+	r.Record(RoleText, "a", 100*time.Millisecond, nil)
+	st := r.stats(RoleText, "a")
+	if st.State != CircuitClosed || st.ConsecutiveFailures != 0 {
+		t.Errorf("探测成功应恢复，stats = %+v", st)
+	}
+	if st.Requests != 4 || st.Failures != 3 || st.Successes != 1 {
+		t.Errorf("计数错误，stats = %+v", st)
+	}
+}
+
+func TestRouter_Candidates(t *testing.T) {
+	r := NewRouter(RouterConfig{FailureThreshold: 3, Cooldown: time.Minute, MaxAttempts: 2})
+	s := Settings{Policy: PolicyPriority, Models: []string{"a", "b", "c"}}
+
+	got := r.Candidates(RoleText, s, "")
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("priority 策略应按列表顺序并限制尝试次数，候选 = %v", got)
+	}
+	got = r.Candidates(RoleText, s, "c")
+	if got[0] != "c" {
+		t.Errorf("优先模型应排在第一位，候选 = %v", got)
+	}
+
+	s = Settings{Policy: PolicyWeighted, Models: []string{"a", "b"}, Weights: map[string]float64{"a": 0}}
+	for i := 0; i < 20; i++ {
+		if got := r.Candidates(RoleText, s, "a"); len(got) != 1 || got[0] != "b" {
+			t.Fatalf("权重为0的模型不应被选中，候选 = %v", got)
+		}
+	}
+}
+
+func TestRoleChatModel_Failover(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	status := map[string]int{"broken": http.StatusServiceUnavailable, "bad-request": http.StatusBadRequest}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		calls[req.Model]++
+		mu.Unlock()
+		if code, ok := status[req.Model]; ok {
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"error":{"message":"出错了"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"%s"},"finish_reason":"stop"}]}`, req.Model)
+	}))
+	defer server.Close()
+
+	f := NewModelFactory(config.AIConfig{
+		ModelFailureThreshold: 2,
+		Models: config.ModelRolesConfig{
+			Text: config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: server.URL, Policy: PolicyPriority, Models: []string{"broken", "healthy"}},
+			Card: config.ModelRoleConfig{Provider: ProviderOpenAI, BaseURL: server.URL, Policy: PolicyPriority, Models: []string{"bad-request", "healthy"}},
+		},
+	})
+	ctx := context.Background()
+	in := []*schema.Message{schema.UserMessage("你好")}
+
+	for i := 0; i < 3; i++ {
+		msg, err := f.RoleModel(RoleText).Generate(ctx, in)
+		if err != nil || msg.Content != "healthy" {
+			t.Fatalf("Generate() = %+v, %v, want 切换到 healthy", msg, err)
+		}
+	}
+	if calls["broken"] != 2 {
+		t.Errorf("熔断后不应再调用故障模型，calls = %d", calls["broken"])
+	}
+	if st := f.router.stats(RoleText, "broken"); st.State != CircuitOpen {
+		t.Errorf("故障模型应熔断，state = %s", st.State)
+	}
+
+	// 请求本身出错时不切换模型，也不计入健康度
+	var apiErr *APIError
+	if _, err := f.RoleModel(RoleCard).Generate(ctx, in); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Generate() error = %v, want 400", err)
+	}
+	if calls["healthy"] != 3 {
+		t.Errorf("请求错误不应切换模型，healthy calls = %d", calls["healthy"])
+	}
+	if st := f.router.stats(RoleCard, "bad-request"); st.Failures != 0 {
+		t.Errorf("请求错误不应计入失败，failures = %d", st.Failures)
+	}
+}
+
+func TestRoleChatModel_ArkRequestError(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		calls[req.Model]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":"InvalidParameter","message":"图片URL无效","type":"BadRequest"}}`)
+	}))
+	defer server.Close()
+
+	f := NewModelFactory(config.AIConfig{
+		ModelFailureThreshold: 1,
+		Models: config.ModelRolesConfig{
+			Vision: config.ModelRoleConfig{Provider: ProviderArk, BaseURL: server.URL, APIKey: "test-key", Policy: PolicyPriority, Models: []string{"vision-a", "vision-b"}},
+		},
+	})
+
+	// Ark 返回的 400 是请求本身的问题：不切换模型，也不计入健康度
+	var arkErr *arkmodel.APIError
+	_, err := f.RoleModel(RoleVision).Generate(context.Background(), []*schema.Message{schema.UserMessage("识别图片")})
+	if !errors.As(err, &arkErr) || arkErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("Generate() error = %v, want Ark 400", err)
+	}
+	if calls["vision-b"] != 0 {
+		t.Errorf("请求错误不应切换模型，vision-b calls = %d", calls["vision-b"])
+	}
+	if st := f.router.stats(RoleVision, "vision-a"); st.Failures != 0 || st.ConsecutiveFailures != 0 || st.State != CircuitClosed {
+		t.Errorf("请求错误不应计入失败: %+v", st)
+	}
+}
+
+func TestIsRequestError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&APIError{StatusCode: http.StatusBadRequest}, true},
+		{&APIError{StatusCode: http.StatusServiceUnavailable}, false},
+		{fmt.Errorf("failed to create chat completion: %w", &arkmodel.APIError{HTTPStatusCode: http.StatusBadRequest}), true},
+		{&arkmodel.RequestError{HTTPStatusCode: http.StatusRequestEntityTooLarge, Err: errors.New("too large")}, true},
+		{&arkmodel.APIError{HTTPStatusCode: http.StatusTooManyRequests}, false},
+		{&arkmodel.RequestError{HTTPStatusCode: http.StatusInternalServerError, Err: errors.New("boom")}, false},
+		{errors.New("network error"), false},
+	}
+	for _, tt := range tests {
+		if got := isRequestError(tt.err); got != tt.want {
+			t.Errorf("isRequestError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
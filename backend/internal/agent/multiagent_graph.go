@@ -2,11 +2,19 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/agent/nodes"
 	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/speech"
 	"github.com/tango/explore/internal/storage"
 	"github.com/tango/explore/internal/tools"
 	"github.com/tango/explore/internal/tools/base"
@@ -35,36 +43,58 @@ type MultiAgentGraph struct {
 
 	// 存储
 	memoryStorage *storage.MemoryAgentStorage
+	learnerStore  storage.LearnerProfileStore // 可选：孩子的学习档案（跨会话）
+
+	// 语音识别
+	recognizer   *speech.Recognizer
+	sessionStore storage.SessionStore      // 可选：保存识别出的用户消息
+	onTranscript func(*speech.Recognition) // 可选：语音识别完成回调
+
+	// 后台任务（回答发送后的Reflection和Memory）
+	background sync.WaitGroup
+
+	// 执行轨迹（每次执行对话时重新创建）
+	trace *executionTrace
 }
 
-// NewMultiAgentGraph 创建MultiAgentGraph实例
-func NewMultiAgentGraph(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*MultiAgentGraph, error) {
+// NewMultiAgentGraph 创建MultiAgentGraph实例，models 为空时按配置创建模型工厂
+func NewMultiAgentGraph(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*MultiAgentGraph, error) {
 	graph := &MultiAgentGraph{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
 	}
+	if models == nil {
+		models = llm.NewModelFactory(cfg)
+	}
 
 	// 初始化Memory存储
 	graph.memoryStorage = storage.NewMemoryAgentStorage()
 
+	// 初始化语音识别（可通过SetASRProvider复用服务级的提供方）
+	asrProvider, asrErr := speech.NewASRProvider(cfg, logger)
+	if asrErr != nil {
+		logger.Errorw("语音识别初始化失败，语音消息将无法识别", logx.Field("error", asrErr))
+	}
+	graph.recognizer = speech.NewRecognizer(cfg, asrProvider)
+
 	// 初始化各个Agent节点
 	var err error
 
 	// 1. Intent Agent
-	graph.intentAgentNode, err = nodes.NewIntentAgentNode(ctx, cfg, logger)
+	graph.intentAgentNode, err = nodes.NewIntentAgentNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Intent Agent失败: %w", err)
 	}
 
 	// 2. Cognitive Load Agent
-	graph.cognitiveLoadNode, err = nodes.NewCognitiveLoadNode(ctx, cfg, logger)
+	graph.cognitiveLoadNode, err = nodes.NewCognitiveLoadNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Cognitive Load Agent失败: %w", err)
 	}
 
 	// 3. Learning Planner Agent
-	graph.learningPlannerNode, err = nodes.NewLearningPlannerNode(ctx, cfg, logger)
+	graph.learningPlannerNode, err = nodes.NewLearningPlannerNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Learning Planner Agent失败: %w", err)
 	}
@@ -97,29 +127,29 @@ func NewMultiAgentGraph(ctx context.Context, cfg config.AIConfig, logger logx.Lo
 	}
 
 	// 5. Domain Agents（传递工具注册表）
-	graph.scienceAgentNode, err = nodes.NewScienceAgentNode(ctx, cfg, logger, toolRegistry)
+	graph.scienceAgentNode, err = nodes.NewScienceAgentNode(ctx, cfg, models, logger, toolRegistry)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Science Agent失败: %w", err)
 	}
 
-	graph.languageAgentNode, err = nodes.NewLanguageAgentNode(ctx, cfg, logger, toolRegistry)
+	graph.languageAgentNode, err = nodes.NewLanguageAgentNode(ctx, cfg, models, logger, toolRegistry)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Language Agent失败: %w", err)
 	}
 
-	graph.humanitiesAgentNode, err = nodes.NewHumanitiesAgentNode(ctx, cfg, logger)
+	graph.humanitiesAgentNode, err = nodes.NewHumanitiesAgentNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Humanities Agent失败: %w", err)
 	}
 
 	// 6. Interaction Agent
-	graph.interactionAgentNode, err = nodes.NewInteractionAgentNode(ctx, cfg, logger)
+	graph.interactionAgentNode, err = nodes.NewInteractionAgentNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Interaction Agent失败: %w", err)
 	}
 
 	// 7. Reflection Agent
-	graph.reflectionAgentNode, err = nodes.NewReflectionAgentNode(ctx, cfg, logger)
+	graph.reflectionAgentNode, err = nodes.NewReflectionAgentNode(ctx, cfg, models, logger)
 	if err != nil {
 		return nil, fmt.Errorf("初始化Reflection Agent失败: %w", err)
 	}
@@ -134,14 +164,175 @@ func NewMultiAgentGraph(ctx context.Context, cfg config.AIConfig, logger logx.Lo
 	return graph, nil
 }
 
-// ExecuteMultiAgentConversation 执行多Agent对话流程
-func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
+// SetASRProvider 设置语音识别提供方
+func (g *MultiAgentGraph) SetASRProvider(provider speech.ASRProvider) {
+	g.recognizer = speech.NewRecognizer(g.config, provider)
+}
+
+// SetSessionStore 设置会话存储，语音消息识别后作为用户消息保存
+func (g *MultiAgentGraph) SetSessionStore(store storage.SessionStore) {
+	g.sessionStore = store
+}
+
+// SetMemoryStorage 设置记忆存储，复用服务级的存储使记忆记录在请求之间保留（可随会话一起删除）
+func (g *MultiAgentGraph) SetMemoryStorage(memoryStorage *storage.MemoryAgentStorage) {
+	g.memoryStorage = memoryStorage
+	g.memoryAgentNode.SetMemoryStorage(memoryStorage)
+}
+
+// SetLearnerProfileStore 设置学习档案存储，请求携带learnerId时读取档案个性化回答，并在每轮结束后合并记忆
+func (g *MultiAgentGraph) SetLearnerProfileStore(store storage.LearnerProfileStore) {
+	g.learnerStore = store
+	g.memoryAgentNode.SetLearnerProfileStore(store)
+}
+
+// SetTranscriptHandler 设置语音识别完成回调（如推送voice_recognized事件）
+func (g *MultiAgentGraph) SetTranscriptHandler(fn func(*speech.Recognition)) {
+	g.onTranscript = fn
+}
+
+// resolveUserMessage 获取用户消息文本
+// 语音消息且未提供识别文本时，调用语音识别流程，将识别文本保存为用户消息
+func (g *MultiAgentGraph) resolveUserMessage(ctx context.Context, req *types.UnifiedStreamConversationRequest) (string, error) {
+	if req.MessageType != "voice" || req.Message != "" {
+		return req.Message, nil
+	}
+
+	endStep := nodes.StartStep(ctx, "VoiceRecognition")
+	recognition, err := g.recognizer.Recognize(ctx, req.Audio)
+	if err != nil {
+		endStep(nil, err)
+		return "", fmt.Errorf("语音识别失败: %w", err)
+	}
+	endStep(map[string]interface{}{
+		"text":       recognition.Text,
+		"confidence": recognition.Confidence,
+		"provider":   recognition.Provider,
+	}, nil)
+
+	g.logger.Infow("语音消息识别完成",
+		logx.Field("sessionId", req.SessionId),
+		logx.Field("provider", recognition.Provider),
+		logx.Field("format", string(recognition.Info.Format)),
+		logx.Field("confidence", recognition.Confidence),
+	)
+
+	if g.sessionStore != nil {
+		g.sessionStore.AddMessage(req.SessionId, types.ConversationMessage{
+			Id:        uuid.New().String(),
+			Type:      "voice",
+			Sender:    "user",
+			Content:   recognition.Text,
+			Timestamp: time.Now().Format(time.RFC3339),
+			SessionId: req.SessionId,
+		})
+	}
+	if g.onTranscript != nil {
+		g.onTranscript(recognition)
+	}
+	return recognition.Text, nil
+}
+
+// postProcessTimeout 回答发送后异步执行Reflection和Memory的超时时间
+const postProcessTimeout = 30 * time.Second
+
+// streamBufferSize 流式管道缓冲的消息块数量
+const streamBufferSize = 16
+
+// conversationTurn 一轮对话中Supervisor协调后的结果，供同步和流式流程共用
+type conversationTurn struct {
+	message      string
+	state        *types.SupervisorState
+	decision     *types.LearningPlanDecision
+	maxSentences int
+}
+
+// ConversationStream 多Agent流式对话：逐块读取回答，读取完成后调用Finish执行反思和记忆
+type ConversationStream struct {
+	*schema.StreamReader[*schema.Message]
+	DomainAgent string // 本轮回答的领域Agent
+
+	graph       *MultiAgentGraph
+	sessionId   string
+	learnerId   string
+	userAge     int
+	objectName  string
+	chatHistory []*schema.Message
+	result      *types.InteractionOptimization // 流读取结束后由Interaction Agent填充
+}
+
+// Finish 回答发送完成后异步执行Reflection和Memory，不阻塞客户端
+// 流未完整读取（出错或提前关闭）时不记录
+func (s *ConversationStream) Finish(ctx context.Context) {
+	if s.result == nil {
+		s.graph.logger.Infow("流式回答未完整读取，跳过反思和记忆", logx.Field("sessionId", s.sessionId))
+		s.graph.finishTrace(s.sessionId)
+		return
+	}
+
+	result := s.result
+	s.graph.background.Add(1)
+	go func() {
+		defer s.graph.background.Done()
+		// 请求结束后上下文会被取消，后台处理使用独立的超时
+		bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), postProcessTimeout)
+		defer cancel()
+		bgCtx = nodes.WithStepRecorder(bgCtx, s.graph.trace)
+		s.graph.reflectAndRemember(bgCtx, s.sessionId, s.learnerId, s.userAge, result.OptimizedContent, s.objectName, s.chatHistory)
+		s.graph.finishTrace(s.sessionId)
+	}()
+}
+
+// Wait 等待后台的Reflection和Memory处理完成
+func (g *MultiAgentGraph) Wait() {
+	g.background.Wait()
+}
+
+// ExecutionState 返回最近一次对话的执行状态（执行路径、各节点决策和耗时）
+func (g *MultiAgentGraph) ExecutionState() types.GraphExecutionState {
+	if g.trace == nil {
+		return newExecutionTrace().snapshot()
+	}
+	return g.trace.snapshot()
+}
+
+// DrainSteps 取出尚未推送的执行步骤（用于agent_step事件）
+// 流式对话中节点可能在后台goroutine中结束，调用方在发送事件的goroutine中定期取出，避免并发写响应
+func (g *MultiAgentGraph) DrainSteps() []types.AgentStep {
+	if g.trace == nil {
+		return nil
+	}
+	return g.trace.drain()
+}
+
+// startTrace 开始记录一次对话的执行轨迹，返回携带步骤记录器的上下文
+func (g *MultiAgentGraph) startTrace(ctx context.Context) context.Context {
+	g.trace = newExecutionTrace()
+	return nodes.WithStepRecorder(ctx, g.trace)
+}
+
+// finishTrace 结束执行轨迹并记录日志，供内容团队排查孩子得到某个回答的原因
+func (g *MultiAgentGraph) finishTrace(sessionId string) {
+	g.trace.finish()
+	state := g.trace.snapshot()
+	g.logger.Infow("多Agent执行轨迹",
+		logx.Field("sessionId", sessionId),
+		logx.Field("executionPath", state.ExecutionPath),
+		logx.Field("intermediateResults", state.IntermediateResults),
+		logx.Field("errorState", state.ErrorState),
+		logx.Field("durationMs", state.EndTime.Sub(state.StartTime).Milliseconds()),
+	)
+}
+
+// prepareTurn 识别用户消息并由Supervisor协调（Intent、Cognitive Load、Learning Planner）
+func (g *MultiAgentGraph) prepareTurn(
 	ctx context.Context,
 	req *types.UnifiedStreamConversationRequest,
 	chatHistory []*schema.Message,
-) (string, error) {
+) (*conversationTurn, error) {
 	g.logger.Infow("开始执行多Agent对话流程",
 		logx.Field("sessionId", req.SessionId),
+		logx.Field("learnerId", req.LearnerId),
 		logx.Field("messageType", req.MessageType),
 		logx.Field("userAge", req.UserAge),
 	)
@@ -153,15 +344,10 @@ func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
 		Cards:              []types.CardContent{},
 		UserAge:            req.UserAge,
 		ConversationRounds: len(chatHistory) / 2, // 简单估算对话轮数
-		RecentOutputLength: 0,                      // TODO: 从chatHistory计算
+		RecentOutputLength: 0,                    // TODO: 从chatHistory计算
 		AgentResults:       make(map[string]interface{}),
 		SessionId:          req.SessionId,
 	}
-	
-	// 确保AgentResults已初始化
-	if state.AgentResults == nil {
-		state.AgentResults = make(map[string]interface{})
-	}
 
 	// 从IdentificationContext获取对象信息
 	if req.IdentificationContext != nil {
@@ -169,42 +355,87 @@ func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
 		state.ObjectCategory = req.IdentificationContext.ObjectCategory
 	}
 
-	// 获取用户消息
-	message := req.Message
-	if req.MessageType == "voice" && req.Audio != "" {
-		// TODO: 语音识别
-		message = "语音消息（待识别）"
+	// 读取孩子的学习档案（跨会话），请求未提供年龄时使用档案中的年龄
+	if req.LearnerId != "" && g.learnerStore != nil {
+		if profile, ok := g.learnerStore.Get(req.LearnerId); ok {
+			state.LearnerProfile = profile
+			if state.UserAge == 0 {
+				state.UserAge = profile.UserAge
+			}
+		}
+	}
+
+	// 获取用户消息（语音消息先识别为文本）
+	message, err := g.resolveUserMessage(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// 2. Supervisor协调：调用Intent、Cognitive Load、Learning Planner
 	decision, err := g.supervisorNode.Coordinate(ctx, state, message, chatHistory)
 	if err != nil {
-		return "", fmt.Errorf("Supervisor协调失败: %w", err)
+		return nil, fmt.Errorf("Supervisor协调失败: %w", err)
 	}
 
-	// 3. 根据决策选择Domain Agent
-	var domainResponse *types.DomainAgentResponse
 	maxSentences := 5 // 默认值
 	if cognitiveLoadAdvice, ok := state.AgentResults["cognitiveLoad"].(*types.CognitiveLoadAdvice); ok && cognitiveLoadAdvice != nil {
 		maxSentences = cognitiveLoadAdvice.MaxSentences
 	}
-	
+
+	return &conversationTurn{
+		message:      message,
+		state:        state,
+		decision:     decision,
+		maxSentences: maxSentences,
+	}, nil
+}
+
+// ExecuteMultiAgentConversation 执行多Agent对话流程（同步，等待全部Agent完成后返回完整回答）
+func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
+	ctx context.Context,
+	req *types.UnifiedStreamConversationRequest,
+	chatHistory []*schema.Message,
+) (string, error) {
+	ctx = g.startTrace(ctx)
+	turn, err := g.prepareTurn(ctx, req, chatHistory)
+	if err != nil {
+		g.finishTrace(req.SessionId)
+		return "", err
+	}
+	message, state, decision := turn.message, turn.state, turn.decision
+
+	// 3. 根据决策选择Domain Agent
+	var domainResponse *types.DomainAgentResponse
+	endDomain := nodes.StartStep(ctx, decision.DomainAgent)
 	switch decision.DomainAgent {
 	case "Science":
-		domainResponse, err = g.scienceAgentNode.GenerateScienceAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, maxSentences, decision.Tools)
+		domainResponse, err = g.scienceAgentNode.GenerateScienceAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, turn.maxSentences, decision.Tools, state.LearnerProfile)
 	case "Language":
-		domainResponse, err = g.languageAgentNode.GenerateLanguageAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, decision.Tools)
+		domainResponse, err = g.languageAgentNode.GenerateLanguageAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, decision.Tools, state.LearnerProfile)
 	case "Humanities":
-		domainResponse, err = g.humanitiesAgentNode.GenerateHumanitiesAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory)
+		domainResponse, err = g.humanitiesAgentNode.GenerateHumanitiesAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, state.LearnerProfile)
 	default:
-		return "", fmt.Errorf("未知的领域Agent: %s", decision.DomainAgent)
+		err = fmt.Errorf("未知的领域Agent: %s", decision.DomainAgent)
+		endDomain(nil, err)
+		g.finishTrace(req.SessionId)
+		return "", err
 	}
 	if err != nil {
+		endDomain(nil, err)
+		g.finishTrace(req.SessionId)
 		return "", fmt.Errorf("Domain Agent生成回答失败: %w", err)
 	}
+	endDomain(map[string]interface{}{
+		"domainAgent":      decision.DomainAgent,
+		"recommendedTools": decision.Tools,
+		"toolsUsed":        domainResponse.ToolsUsed,
+		"contentLength":    len(domainResponse.Content),
+	}, nil)
 
 	// 4. Interaction Agent优化交互
+	endInteraction := nodes.StartStep(ctx, "Interaction")
 	interactionResult, err := g.interactionAgentNode.OptimizeInteraction(ctx, domainResponse.Content)
+	endInteraction(interactionDecision(interactionResult), err)
 	if err != nil {
 		g.logger.Errorw("Interaction Agent优化失败，使用原始回答", logx.Field("error", err))
 		interactionResult = &types.InteractionOptimization{
@@ -213,8 +444,101 @@ func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
 		}
 	}
 
-	// 5. Reflection Agent反思判断
-	reflectionResult, err := g.reflectionAgentNode.Reflect(ctx, interactionResult.OptimizedContent, chatHistory)
+	// 5-6. Reflection Agent反思判断，Memory Agent记录学习状态
+	g.reflectAndRemember(ctx, req.SessionId, req.LearnerId, state.UserAge, interactionResult.OptimizedContent, state.ObjectName, chatHistory)
+	g.finishTrace(req.SessionId)
+
+	g.logger.Infow("多Agent对话流程完成",
+		logx.Field("domainAgent", decision.DomainAgent),
+		logx.Field("contentLength", len(interactionResult.OptimizedContent)),
+	)
+
+	return interactionResult.OptimizedContent, nil
+}
+
+// StreamMultiAgentConversation 流式执行多Agent对话流程
+// Supervisor协调完成后，领域Agent的文本块经Interaction Agent后处理立即返回，首字延迟不再包含后续Agent
+// 调用方读取完流并发送完成事件后，调用 ConversationStream.Finish 异步执行Reflection和Memory
+func (g *MultiAgentGraph) StreamMultiAgentConversation(
+	ctx context.Context,
+	req *types.UnifiedStreamConversationRequest,
+	chatHistory []*schema.Message,
+) (*ConversationStream, error) {
+	ctx = g.startTrace(ctx)
+	turn, err := g.prepareTurn(ctx, req, chatHistory)
+	if err != nil {
+		g.finishTrace(req.SessionId)
+		return nil, err
+	}
+	message, state, decision := turn.message, turn.state, turn.decision
+
+	// 3. 根据决策选择Domain Agent（流式）
+	var domainStream *schema.StreamReader[*schema.Message]
+	endDomain := nodes.StartStep(ctx, decision.DomainAgent)
+	switch decision.DomainAgent {
+	case "Science":
+		domainStream, err = g.scienceAgentNode.StreamScienceAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, turn.maxSentences, decision.Tools, state.LearnerProfile)
+	case "Language":
+		domainStream, err = g.languageAgentNode.StreamLanguageAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, decision.Tools, state.LearnerProfile)
+	case "Humanities":
+		domainStream, err = g.humanitiesAgentNode.StreamHumanitiesAnswer(ctx, message, state.ObjectName, state.ObjectCategory, state.UserAge, chatHistory, state.LearnerProfile)
+	default:
+		err = fmt.Errorf("未知的领域Agent: %s", decision.DomainAgent)
+		endDomain(nil, err)
+		g.finishTrace(req.SessionId)
+		return nil, err
+	}
+	if err != nil {
+		endDomain(nil, err)
+		g.finishTrace(req.SessionId)
+		return nil, fmt.Errorf("Domain Agent生成回答失败: %w", err)
+	}
+
+	// 领域Agent的流结束时记录其步骤，随后Interaction Agent补充结尾
+	var endInteraction func(map[string]interface{}, error)
+	domainStream = traceStream(domainStream, func(content string, firstChunk time.Duration, err error) {
+		if err != nil {
+			endDomain(nil, err)
+			return
+		}
+		endDomain(map[string]interface{}{
+			"domainAgent":      decision.DomainAgent,
+			"recommendedTools": decision.Tools,
+			"toolsUsed":        g.trace.toolsCalled(),
+			"contentLength":    len(content),
+			"firstChunkMs":     firstChunk.Milliseconds(),
+		}, nil)
+		endInteraction = nodes.StartStep(ctx, "Interaction")
+	})
+
+	// 4. Interaction Agent作为流式后处理，回答结束后补充结尾
+	stream := &ConversationStream{
+		DomainAgent: decision.DomainAgent,
+		graph:       g,
+		sessionId:   req.SessionId,
+		learnerId:   req.LearnerId,
+		userAge:     state.UserAge,
+		objectName:  state.ObjectName,
+		chatHistory: chatHistory,
+	}
+	stream.StreamReader = g.interactionAgentNode.StreamOptimizeInteraction(ctx, domainStream, func(result *types.InteractionOptimization) {
+		stream.result = result
+		if endInteraction != nil {
+			endInteraction(interactionDecision(result), nil)
+		}
+		g.logger.Infow("多Agent流式回答完成",
+			logx.Field("domainAgent", decision.DomainAgent),
+			logx.Field("contentLength", len(result.OptimizedContent)),
+		)
+	})
+	return stream, nil
+}
+
+// reflectAndRemember Reflection Agent反思判断，Memory Agent记录学习状态
+// learnerId 非空时将本会话的记忆合并到孩子的学习档案
+func (g *MultiAgentGraph) reflectAndRemember(ctx context.Context, sessionId string, learnerId string, userAge int, content string, objectName string, chatHistory []*schema.Message) {
+	endReflection := nodes.StartStep(ctx, "Reflection")
+	reflectionResult, err := g.reflectionAgentNode.Reflect(ctx, content, chatHistory)
 	if err != nil {
 		g.logger.Errorw("Reflection Agent反思失败", logx.Field("error", err))
 		reflectionResult = &types.ReflectionResult{
@@ -223,18 +547,67 @@ func (g *MultiAgentGraph) ExecuteMultiAgentConversation(
 			Relax:     false,
 		}
 	}
-
-	// 6. Memory Agent记录学习状态
-	err = g.memoryAgentNode.RecordMemory(ctx, req.SessionId, reflectionResult, interactionResult.OptimizedContent, state.ObjectName)
+	endReflection(map[string]interface{}{
+		"interest":  reflectionResult.Interest,
+		"confusion": reflectionResult.Confusion,
+		"relax":     reflectionResult.Relax,
+	}, err)
+
+	endMemory := nodes.StartStep(ctx, "Memory")
+	err = g.memoryAgentNode.RecordMemory(ctx, sessionId, reflectionResult, content, objectName)
 	if err != nil {
 		g.logger.Errorw("Memory Agent记录失败", logx.Field("error", err))
+	} else if learnerId != "" {
+		err = g.memoryAgentNode.UpdateLearnerProfile(ctx, learnerId, sessionId, userAge, reflectionResult, objectName)
+		if err != nil {
+			g.logger.Errorw("学习档案更新失败", logx.Field("learnerId", learnerId), logx.Field("error", err))
+		}
 	}
+	endMemory(map[string]interface{}{"objectName": objectName, "learnerId": learnerId}, err)
+}
 
-	g.logger.Infow("多Agent对话流程完成",
-		logx.Field("domainAgent", decision.DomainAgent),
-		logx.Field("contentLength", len(interactionResult.OptimizedContent)),
-	)
-
-	return interactionResult.OptimizedContent, nil
+// interactionDecision Interaction Agent的决策（用于执行轨迹）
+func interactionDecision(result *types.InteractionOptimization) map[string]interface{} {
+	if result == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"endingAction":  result.EndingAction,
+		"contentLength": len(result.OptimizedContent),
+	}
 }
 
+// traceStream 转发流，并在流结束时回调完整内容、首个文本块的耗时和错误
+func traceStream(stream *schema.StreamReader[*schema.Message], onEnd func(content string, firstChunk time.Duration, err error)) *schema.StreamReader[*schema.Message] {
+	start := time.Now()
+	sr, sw := schema.Pipe[*schema.Message](streamBufferSize)
+	go func() {
+		defer sw.Close()
+		defer stream.Close()
+
+		var builder strings.Builder
+		var firstChunk time.Duration
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				onEnd(builder.String(), firstChunk, err)
+				sw.Send(nil, err)
+				return
+			}
+			if chunk != nil && chunk.Content != "" && builder.Len() == 0 {
+				firstChunk = time.Since(start)
+			}
+			if chunk != nil {
+				builder.WriteString(chunk.Content)
+			}
+			if sw.Send(chunk, nil) {
+				return
+			}
+		}
+		onEnd(builder.String(), firstChunk, nil)
+	}()
+	return sr
+}
@@ -2,10 +2,17 @@ package agent
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/tango/explore/internal/agent/nodes"
 	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/speech"
+	"github.com/tango/explore/internal/storage"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -19,7 +26,7 @@ func TestMultiAgentGraph_ExecuteMultiAgentConversation(t *testing.T) {
 		AppKey:      "",
 	}
 
-	graph, err := NewMultiAgentGraph(ctx, cfg, logger)
+	graph, err := NewMultiAgentGraph(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
 	}
@@ -57,6 +64,127 @@ func TestMultiAgentGraph_ExecuteMultiAgentConversation(t *testing.T) {
 	t.Logf("MultiAgent conversation completed in %v, answer length: %d", duration, len(answer))
 }
 
+func TestMultiAgentGraph_StreamMultiAgentConversation(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+
+	graph, err := NewMultiAgentGraph(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
+	}
+
+	req := &types.UnifiedStreamConversationRequest{
+		MessageType: "text",
+		Message:     "这是什么？",
+		SessionId:   "stream-session",
+		UserAge:     10,
+		IdentificationContext: &types.IdentificationContext{
+			ObjectName:     "银杏",
+			ObjectCategory: "自然类",
+		},
+	}
+
+	stream, err := graph.StreamMultiAgentConversation(ctx, req, nil)
+	if err != nil {
+		t.Fatalf("StreamMultiAgentConversation failed: %v", err)
+	}
+	if stream.DomainAgent == "" {
+		t.Error("DomainAgent should not be empty")
+	}
+
+	chunks := 0
+	answer := ""
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取流失败: %v", err)
+		}
+		chunks++
+		answer += chunk.Content
+	}
+	stream.Close()
+	if chunks < 2 || answer == "" {
+		t.Fatalf("回答应分块返回, chunks=%d answer=%q", chunks, answer)
+	}
+
+	// 读取完成前不记录记忆，Finish后在后台执行Reflection和Memory
+	if _, exists := graph.memoryStorage.GetMemoryRecord(req.SessionId); exists {
+		t.Error("Finish之前不应记录记忆")
+	}
+	stream.Finish(ctx)
+	graph.Wait()
+
+	record, exists := graph.memoryStorage.GetMemoryRecord(req.SessionId)
+	if !exists {
+		t.Fatal("Finish后应记录记忆")
+	}
+	if record.SessionId != req.SessionId {
+		t.Errorf("记忆记录sessionId = %s", record.SessionId)
+	}
+}
+
+func TestMultiAgentGraph_ExecutionState(t *testing.T) {
+	ctx := context.Background()
+	graph, err := NewMultiAgentGraph(ctx, config.AIConfig{}, nil, logx.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
+	}
+
+	req := &types.UnifiedStreamConversationRequest{
+		MessageType: "text",
+		Message:     "这是什么？",
+		SessionId:   "trace-session",
+		UserAge:     8,
+		IdentificationContext: &types.IdentificationContext{
+			ObjectName:     "银杏",
+			ObjectCategory: "自然类",
+		},
+	}
+	stream, err := graph.StreamMultiAgentConversation(ctx, req, nil)
+	if err != nil {
+		t.Fatalf("StreamMultiAgentConversation failed: %v", err)
+	}
+
+	// Supervisor协调在返回流之前完成
+	steps := graph.DrainSteps()
+	if len(steps) == 0 || steps[0].Node != "Supervisor" || steps[0].Status != StepStarted {
+		t.Fatalf("第一个步骤应为Supervisor开始, 实际: %+v", steps)
+	}
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+	stream.Finish(ctx)
+	graph.Wait()
+
+	state := graph.ExecutionState()
+	want := []string{"Supervisor", "Intent", "CognitiveLoad", "LearningPlanner", stream.DomainAgent, "Interaction", "Reflection", "Memory"}
+	if strings.Join(state.ExecutionPath, ",") != strings.Join(want, ",") {
+		t.Errorf("执行路径 = %v, 期望 %v", state.ExecutionPath, want)
+	}
+	if state.EndTime == nil || state.CurrentNode != "" {
+		t.Errorf("执行结束后应记录结束时间: %+v", state)
+	}
+	if len(state.Steps) != 2*len(want) {
+		t.Errorf("每个节点应有开始和结束两个步骤, 实际: %d", len(state.Steps))
+	}
+
+	supervisor, _ := state.IntermediateResults["Supervisor"].(map[string]interface{})
+	if supervisor["domainAgent"] != stream.DomainAgent || supervisor["intent"] == "" || supervisor["strategy"] == "" {
+		t.Errorf("Supervisor决策错误: %+v", supervisor)
+	}
+	domain, _ := state.IntermediateResults[stream.DomainAgent].(map[string]interface{})
+	if _, ok := domain["toolsUsed"]; !ok {
+		t.Errorf("领域Agent决策应包含调用的工具: %+v", domain)
+	}
+}
+
 func TestMultiAgentGraph_ExecuteMultiAgentConversation_ErrorHandling(t *testing.T) {
 	ctx := context.Background()
 	logger := logx.WithContext(ctx)
@@ -66,7 +194,7 @@ func TestMultiAgentGraph_ExecuteMultiAgentConversation_ErrorHandling(t *testing.
 		AppKey:      "",
 	}
 
-	graph, err := NewMultiAgentGraph(ctx, cfg, logger)
+	graph, err := NewMultiAgentGraph(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
 	}
@@ -86,3 +214,142 @@ func TestMultiAgentGraph_ExecuteMultiAgentConversation_ErrorHandling(t *testing.
 	}
 }
 
+
+// testWAV 构造1秒16kHz单声道16位PCM静音WAV
+func testWAV() []byte {
+	const dataSize = 32000
+	buf := make([]byte, 44+dataSize)
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], 36+dataSize)
+	copy(buf[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], 1)
+	binary.LittleEndian.PutUint16(buf[22:24], 1)
+	binary.LittleEndian.PutUint32(buf[24:28], 16000)
+	binary.LittleEndian.PutUint32(buf[28:32], 32000)
+	binary.LittleEndian.PutUint16(buf[32:34], 2)
+	binary.LittleEndian.PutUint16(buf[34:36], 16)
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], dataSize)
+	return buf
+}
+
+func TestMultiAgentGraph_ExecuteMultiAgentConversation_Voice(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+
+	graph, err := NewMultiAgentGraph(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
+	}
+
+	store := storage.NewMemoryStorage()
+	graph.SetSessionStore(store)
+	var transcript string
+	graph.SetTranscriptHandler(func(recognition *speech.Recognition) {
+		transcript = recognition.Text
+	})
+
+	req := &types.UnifiedStreamConversationRequest{
+		MessageType: "voice",
+		Audio:       "data:audio/wav;base64," + base64.StdEncoding.EncodeToString(testWAV()),
+		SessionId:   "voice-session",
+		UserAge:     6,
+	}
+	answer, err := graph.ExecuteMultiAgentConversation(ctx, req, nil)
+	if err != nil {
+		t.Fatalf("ExecuteMultiAgentConversation failed: %v", err)
+	}
+	if answer == "" {
+		t.Error("Answer should not be empty")
+	}
+
+	// USE_AI_MODEL=false 时使用Mock语音识别
+	if transcript == "" || transcript == "语音消息（待识别）" {
+		t.Fatalf("Transcript handler should receive recognized text, got %q", transcript)
+	}
+	messages := store.GetMessages("voice-session")
+	if len(messages) != 1 {
+		t.Fatalf("Recognized voice should be stored as one user message, got %d", len(messages))
+	}
+	msg := messages[0].(types.ConversationMessage)
+	if msg.Sender != "user" || msg.Type != "voice" || msg.Content != transcript {
+		t.Errorf("Unexpected stored message: %+v", msg)
+	}
+
+	// 无效音频返回语音识别错误，且不保存消息
+	req.Audio = base64.StdEncoding.EncodeToString([]byte("not audio"))
+	req.SessionId = "invalid-voice-session"
+	if _, err := graph.ExecuteMultiAgentConversation(ctx, req, nil); !speech.IsRecognitionError(err) {
+		t.Errorf("Invalid audio should return a recognition error, got %v", err)
+	}
+	if messages := store.GetMessages("invalid-voice-session"); len(messages) != 0 {
+		t.Errorf("Failed recognition should not store messages, got %d", len(messages))
+	}
+}
+
+func TestMultiAgentGraph_LearnerProfile(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+	learnerStore := storage.NewMemoryLearnerStore()
+
+	newRequest := func(sessionId string, userAge int) *types.UnifiedStreamConversationRequest {
+		return &types.UnifiedStreamConversationRequest{
+			MessageType: "text",
+			Message:     "这是什么？",
+			SessionId:   sessionId,
+			LearnerId:   "child-1",
+			UserAge:     userAge,
+			IdentificationContext: &types.IdentificationContext{
+				ObjectName:     "银杏",
+				ObjectCategory: "自然类",
+			},
+		}
+	}
+
+	// 第一次会话：生成学习档案
+	graph, err := NewMultiAgentGraph(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
+	}
+	graph.SetLearnerProfileStore(learnerStore)
+	if _, err := graph.ExecuteMultiAgentConversation(ctx, newRequest("session-1", 8), nil); err != nil {
+		t.Fatalf("ExecuteMultiAgentConversation failed: %v", err)
+	}
+
+	profile, ok := learnerStore.Get("child-1")
+	if !ok {
+		t.Fatal("Learner profile should be saved")
+	}
+	if profile.UserAge != 8 || len(profile.SessionIds) != 1 || profile.SessionIds[0] != "session-1" {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+
+	// 第二次会话（新的Graph、新的会话）：读取档案，不重复讲已理解的主题
+	graph, err = NewMultiAgentGraph(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create MultiAgentGraph: %v", err)
+	}
+	graph.SetLearnerProfileStore(learnerStore)
+	stream, err := graph.StreamMultiAgentConversation(ctx, newRequest("session-2", 0), nil)
+	if err != nil {
+		t.Fatalf("StreamMultiAgentConversation failed: %v", err)
+	}
+	answer, err := nodes.CollectStream(stream.StreamReader)
+	if err != nil {
+		t.Fatalf("读取流失败: %v", err)
+	}
+	if !strings.HasPrefix(answer, "我们之前聊过银杏") {
+		t.Errorf("Answer should build on previous session: %s", answer)
+	}
+	if stream.userAge != 8 {
+		t.Errorf("userAge should fall back to profile age, got %d", stream.userAge)
+	}
+	stream.Finish(ctx)
+	graph.Wait()
+
+	profile, _ = learnerStore.Get("child-1")
+	if len(profile.SessionIds) != 2 {
+		t.Errorf("Profile should merge both sessions: %v", profile.SessionIds)
+	}
+}
@@ -0,0 +1,102 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tango/explore/internal/cards"
+)
+
+// CardGenerator 知识卡片生成器，每种卡片类型对应一个
+type CardGenerator interface {
+	// Type 卡片类型，如 science、math
+	Type() string
+	// Generate 生成一张卡片，返回包含 type、title、content 的卡片数据
+	Generate(ctx context.Context, data *GraphData) (map[string]interface{}, error)
+}
+
+// CardGeneratorFunc 用函数实现 CardGenerator
+type CardGeneratorFunc struct {
+	CardType string
+	Fn       func(ctx context.Context, data *GraphData) (map[string]interface{}, error)
+}
+
+// Type 卡片类型
+func (g CardGeneratorFunc) Type() string {
+	return g.CardType
+}
+
+// Generate 生成卡片
+func (g CardGeneratorFunc) Generate(ctx context.Context, data *GraphData) (map[string]interface{}, error) {
+	return g.Fn(ctx, data)
+}
+
+// CardError 单张卡片的生成错误
+type CardError struct {
+	Index int    // 卡片在请求的卡片类型中的位置
+	Type  string // 卡片类型
+	Err   error
+}
+
+// CardRegistry 卡片生成器注册表，按卡片类型查找生成器，可并发使用
+type CardRegistry struct {
+	mu         sync.RWMutex
+	generators map[string]CardGenerator
+	order      []string // 注册顺序
+}
+
+// NewCardRegistry 创建空的卡片生成器注册表
+func NewCardRegistry() *CardRegistry {
+	return &CardRegistry{generators: make(map[string]CardGenerator)}
+}
+
+// Register 注册卡片生成器，同一类型重复注册时替换原有生成器
+func (r *CardRegistry) Register(g CardGenerator) error {
+	if g == nil || g.Type() == "" {
+		return fmt.Errorf("卡片生成器缺少卡片类型")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.generators[g.Type()]; !ok {
+		r.order = append(r.order, g.Type())
+	}
+	r.generators[g.Type()] = g
+	return nil
+}
+
+// Get 返回卡片类型的生成器
+func (r *CardRegistry) Get(cardType string) (CardGenerator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	g, ok := r.generators[cardType]
+	return g, ok
+}
+
+// Types 返回已注册的卡片类型（按注册顺序）
+func (r *CardRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// RegisterCardGenerators 把文本生成节点支持的所有卡片类型注册到注册表
+func (n *TextGenerationNode) RegisterCardGenerators(registry *CardRegistry) {
+	builtin := []CardGenerator{
+		CardGeneratorFunc{CardType: cards.TypeScience, Fn: n.GenerateScienceCard},
+		CardGeneratorFunc{CardType: cards.TypePoetry, Fn: n.GeneratePoetryCard},
+		CardGeneratorFunc{CardType: cards.TypeEnglish, Fn: n.GenerateEnglishCard},
+	}
+	for _, cardType := range []string{cards.TypeMath, cards.TypeHistory, cards.TypeArt, cards.TypeSafety, cards.TypeCharacter} {
+		cardType := cardType
+		builtin = append(builtin, CardGeneratorFunc{
+			CardType: cardType,
+			Fn: func(ctx context.Context, data *GraphData) (map[string]interface{}, error) {
+				return n.GenerateCard(ctx, cardType, data)
+			},
+		})
+	}
+	for _, g := range builtin {
+		registry.Register(g)
+	}
+}
@@ -0,0 +1,56 @@
+package nodes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tango/explore/internal/cards"
+)
+
+func TestCardRegistry(t *testing.T) {
+	registry := NewCardRegistry()
+	(&TextGenerationNode{}).RegisterCardGenerators(registry)
+
+	types := registry.Types()
+	if strings.Join(types[:3], ",") != "science,poetry,english" || len(types) != 8 {
+		t.Fatalf("Types() = %v", types)
+	}
+
+	// 重复注册替换原有生成器，不改变顺序
+	custom := CardGeneratorFunc{CardType: cards.TypePoetry, Fn: func(ctx context.Context, data *GraphData) (map[string]interface{}, error) {
+		return map[string]interface{}{"type": cards.TypePoetry, "title": "自定义"}, nil
+	}}
+	if err := registry.Register(custom); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	g, _ := registry.Get(cards.TypePoetry)
+	if card, _ := g.Generate(context.Background(), &GraphData{}); card["title"] != "自定义" {
+		t.Errorf("重复注册应替换生成器，card = %v", card)
+	}
+	if len(registry.Types()) != 8 {
+		t.Errorf("重复注册不应新增类型，Types() = %v", registry.Types())
+	}
+
+	if err := registry.Register(CardGeneratorFunc{}); err == nil {
+		t.Error("缺少卡片类型的生成器应注册失败")
+	}
+	if _, ok := registry.Get("unknown"); ok {
+		t.Error("未注册的类型不应找到生成器")
+	}
+}
+
+func TestMockCard(t *testing.T) {
+	for cardType := range cardTitles {
+		card := MockCard(cardType, "银杏")
+		if card["type"] != cardType || card["title"] == "" {
+			t.Errorf("MockCard(%s) = %v", cardType, card)
+		}
+		if _, err := cards.Validate(cardType, card["content"].(map[string]interface{})); err != nil {
+			t.Errorf("MockCard(%s) 内容不符合Schema: %v", cardType, err)
+		}
+	}
+	if MockCard("unknown", "银杏") != nil {
+		t.Error("未知卡片类型应返回 nil")
+	}
+}
@@ -3,14 +3,12 @@ package nodes
 import (
 	"context"
 	"encoding/json"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -21,22 +19,24 @@ type CognitiveLoadNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例（可选，用于复杂判断）
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（可选，用于复杂判断；每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewCognitiveLoadNode 创建Cognitive Load Agent节点
-func NewCognitiveLoadNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*CognitiveLoadNode, error) {
+func NewCognitiveLoadNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*CognitiveLoadNode, error) {
 	node := &CognitiveLoadNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
 	// Cognitive Load Agent主要使用规则判断，ChatModel作为辅助
-	// 如果配置了 eino 相关参数，初始化 ChatModel（用于复杂场景）
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	// 如果配置了可用的模型服务，初始化 ChatModel（用于复杂场景）
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将仅使用规则判断", logx.Field("error", err))
 		} else {
@@ -44,7 +44,7 @@ func NewCognitiveLoadNode(ctx context.Context, cfg config.AIConfig, logger logx.
 			logger.Info("✅ Cognitive Load Agent节点已初始化ChatModel，将使用规则+模型判断")
 		}
 	} else {
-		logger.Info("未配置eino参数，Cognitive Load Agent节点将仅使用规则判断")
+		logger.Info("未配置可用的模型服务，Cognitive Load Agent节点将仅使用规则判断")
 	}
 
 	// 创建消息模板（用于复杂场景）
@@ -55,56 +55,14 @@ func NewCognitiveLoadNode(ctx context.Context, cfg config.AIConfig, logger logx.
 
 // initChatModel 初始化 ChatModel（可选）
 func (n *CognitiveLoadNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *CognitiveLoadNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板（用于复杂场景）
 func (n *CognitiveLoadNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -152,7 +110,7 @@ func (n *CognitiveLoadNode) AssessCognitiveLoad(ctx context.Context, userAge int
 	advice := n.assessByRules(userAge, conversationRounds, recentOutputLength)
 
 	// 如果ChatModel已初始化，可以用于复杂场景的二次验证
-	if n.initialized && n.chatModel != nil && (conversationRounds > 3 || recentOutputLength > 300) {
+	if n.initialized && (conversationRounds > 3 || recentOutputLength > 300) {
 		// 复杂场景使用ChatModel辅助判断
 		modelAdvice, err := n.assessByModel(ctx, userAge, conversationRounds, recentOutputLength)
 		if err == nil && modelAdvice != nil {
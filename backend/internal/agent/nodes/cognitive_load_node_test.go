@@ -17,7 +17,7 @@ func TestCognitiveLoadNode_AssessCognitiveLoad(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewCognitiveLoadNode(ctx, cfg, logger)
+	node, err := NewCognitiveLoadNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create CognitiveLoadNode: %v", err)
 	}
@@ -3,13 +3,10 @@ package nodes
 import (
 	"context"
 	"fmt"
-	"math/rand"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
-	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -19,31 +16,26 @@ type ConversationNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（共享的模型客户端池）
 	template    prompt.ChatTemplate // 对话模板
 	initialized bool
 }
 
 // NewConversationNode 创建对话节点
-func NewConversationNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*ConversationNode, error) {
+func NewConversationNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*ConversationNode, error) {
 	node := &ConversationNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel
-	hasEinoBaseURL := cfg.EinoBaseURL != ""
-	hasAppID := cfg.AppID != ""
-	hasAppKey := cfg.AppKey != ""
-
-	if hasEinoBaseURL && hasAppID && hasAppKey {
-		logger.Infow("检测到eino配置，尝试初始化对话ChatModel",
-			logx.Field("einoBaseURL", cfg.EinoBaseURL),
-			logx.Field("appID", hasAppID),
-			logx.Field("hasAppKey", hasAppKey),
+	// 如果配置了可用的模型服务，预先创建模型池中的客户端
+	if models.Enabled(llm.RoleText) {
+		logger.Infow("检测到模型配置，尝试初始化对话ChatModel",
+			logx.Field("provider", models.Provider(llm.RoleText)),
 		)
-		if err := node.initChatModel(ctx); err != nil {
+		if err := models.Preload(ctx, llm.RoleText); err != nil {
 			logger.Errorw("初始化对话ChatModel失败，将使用Mock模式",
 				logx.Field("error", err),
 			)
@@ -52,10 +44,8 @@ func NewConversationNode(ctx context.Context, cfg config.AIConfig, logger logx.L
 			logger.Info("✅ 对话节点已初始化ChatModel，将使用真实模型")
 		}
 	} else {
-		logger.Errorw("未完整配置eino参数，对话节点将使用Mock模式",
-			logx.Field("hasEinoBaseURL", hasEinoBaseURL),
-			logx.Field("hasAppID", hasAppID),
-			logx.Field("hasAppKey", hasAppKey),
+		logger.Errorw("未配置可用的模型服务，对话节点将使用Mock模式",
+			logx.Field("provider", models.Provider(llm.RoleText)),
 		)
 	}
 
@@ -65,59 +55,13 @@ func NewConversationNode(ctx context.Context, cfg config.AIConfig, logger logx.L
 	return node, nil
 }
 
-// initChatModel 初始化 ChatModel（使用随机选择的模型）
-func (n *ConversationNode) initChatModel(ctx context.Context) error {
-	// 从配置中随机选择一个文本生成模型
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil // 返回 nil，使用 Mock 模式
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
-		return err
-	}
-
-	n.chatModel = chatModel
-	n.logger.Infow("对话模型已初始化", logx.Field("model", modelName))
-	return nil
-}
-
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *ConversationNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
+// SelectModel 按路由策略为本次对话选择一个文本生成模型（Mock模式为空）
+// 调用方可先按所选模型组装上下文，再把模型名称传给 StreamConversation
+func (n *ConversationNode) SelectModel() string {
+	if !n.initialized {
 		return ""
 	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
+	return n.models.SelectModel(llm.RoleText)
 }
 
 // initTemplate 初始化对话模板
@@ -187,22 +131,12 @@ func (n *ConversationNode) StreamConversation(
 	objectName string,
 	objectCategory string,
 	imageURL string, // 新增：图片URL参数，支持多模态输入
+	modelName string, // 优先使用的模型（SelectModel 的结果），失败时切换到其他模型
 ) (*schema.StreamReader[*schema.Message], error) {
 	if !n.initialized {
 		return nil, fmt.Errorf("ChatModel未初始化，无法进行流式对话")
 	}
-
-	// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-	if err := n.initChatModel(ctx); err != nil {
-		n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-			logx.Field("error", err),
-		)
-		// 如果重新初始化失败，继续使用已初始化的模型
-	}
-
-	if n.chatModel == nil {
-		return nil, fmt.Errorf("ChatModel未初始化，无法进行流式对话")
-	}
+	chatModel := n.models.RoleModel(llm.RoleText).WithPreferred(modelName)
 
 	// 根据用户年级生成系统prompt
 	systemPrompt := n.generateSystemPrompt(userAge, objectName, objectCategory)
@@ -264,7 +198,7 @@ func (n *ConversationNode) StreamConversation(
 	)
 
 	// 调用Eino ChatModel的Stream接口
-	streamReader, err := n.chatModel.Stream(ctx, messages)
+	streamReader, err := chatModel.Stream(ctx, messages)
 	if err != nil {
 		n.logger.Errorw("调用Eino Stream接口失败",
 			logx.Field("error", err),
@@ -289,18 +223,8 @@ func (n *ConversationNode) GenerateText(
 		return fmt.Sprintf("这是一个Mock响应。待接入真实AI模型后，将根据您的问题和识别结果（%s）生成相应的回答。", objectName), nil
 	}
 
-	// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-	if err := n.initChatModel(ctx); err != nil {
-		n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-			logx.Field("error", err),
-		)
-		// 如果重新初始化失败，继续使用已初始化的模型
-	}
-
-	if n.chatModel == nil {
-		// Mock响应
-		return fmt.Sprintf("这是一个Mock响应。待接入真实AI模型后，将根据您的问题和识别结果（%s）生成相应的回答。", objectName), nil
-	}
+	// 按路由策略选择模型，失败时切换到其他模型
+	chatModel := n.models.RoleModel(llm.RoleText)
 
 	// 根据用户年级生成系统prompt
 	systemPrompt := n.generateSystemPrompt(userAge, objectName, objectCategory)
@@ -319,7 +243,7 @@ func (n *ConversationNode) GenerateText(
 	messages = append(messages, schema.UserMessage(message))
 
 	// 调用Eino ChatModel的Generate接口
-	result, err := n.chatModel.Generate(ctx, messages)
+	result, err := chatModel.Generate(ctx, messages)
 	if err != nil {
 		n.logger.Errorw("调用Eino Generate接口失败",
 			logx.Field("error", err),
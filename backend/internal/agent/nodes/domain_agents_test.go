@@ -2,10 +2,13 @@ package nodes
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/cloudwego/eino/schema"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/tools"
+	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -19,12 +22,12 @@ func TestScienceAgentNode_GenerateScienceAnswer(t *testing.T) {
 	}
 
 	toolRegistry := tools.GetDefaultRegistry(logger)
-	node, err := NewScienceAgentNode(ctx, cfg, logger, toolRegistry)
+	node, err := NewScienceAgentNode(ctx, cfg, nil, logger, toolRegistry)
 	if err != nil {
 		t.Fatalf("Failed to create ScienceAgentNode: %v", err)
 	}
 
-	response, err := node.GenerateScienceAnswer(ctx, "这是什么？", "银杏", "自然类", 10, nil, 4, []string{})
+	response, err := node.GenerateScienceAnswer(ctx, "这是什么？", "银杏", "自然类", 10, nil, 4, []string{}, nil)
 	if err != nil {
 		t.Errorf("GenerateScienceAnswer failed: %v", err)
 		return
@@ -49,12 +52,12 @@ func TestLanguageAgentNode_GenerateLanguageAnswer(t *testing.T) {
 	}
 
 	toolRegistry := tools.GetDefaultRegistry(logger)
-	node, err := NewLanguageAgentNode(ctx, cfg, logger, toolRegistry)
+	node, err := NewLanguageAgentNode(ctx, cfg, nil, logger, toolRegistry)
 	if err != nil {
 		t.Fatalf("Failed to create LanguageAgentNode: %v", err)
 	}
 
-	response, err := node.GenerateLanguageAnswer(ctx, "用英语怎么说？", "银杏", "自然类", 10, nil, []string{})
+	response, err := node.GenerateLanguageAnswer(ctx, "用英语怎么说？", "银杏", "自然类", 10, nil, []string{}, nil)
 	if err != nil {
 		t.Errorf("GenerateLanguageAnswer failed: %v", err)
 		return
@@ -78,12 +81,12 @@ func TestHumanitiesAgentNode_GenerateHumanitiesAnswer(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewHumanitiesAgentNode(ctx, cfg, logger)
+	node, err := NewHumanitiesAgentNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create HumanitiesAgentNode: %v", err)
 	}
 
-	response, err := node.GenerateHumanitiesAnswer(ctx, "有什么故事吗？", "银杏", "自然类", 10, nil)
+	response, err := node.GenerateHumanitiesAnswer(ctx, "有什么故事吗？", "银杏", "自然类", 10, nil, nil)
 	if err != nil {
 		t.Errorf("GenerateHumanitiesAnswer failed: %v", err)
 		return
@@ -98,3 +101,78 @@ func TestHumanitiesAgentNode_GenerateHumanitiesAnswer(t *testing.T) {
 	}
 }
 
+
+func TestDomainAgents_Stream(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+	cfg := config.AIConfig{}
+	toolRegistry := tools.GetDefaultRegistry(logger)
+
+	science, _ := NewScienceAgentNode(ctx, cfg, nil, logger, toolRegistry)
+	language, _ := NewLanguageAgentNode(ctx, cfg, nil, logger, toolRegistry)
+	humanities, _ := NewHumanitiesAgentNode(ctx, cfg, nil, logger)
+
+	scienceStream, err := science.StreamScienceAnswer(ctx, "这是什么？", "银杏", "自然类", 10, nil, 4, nil, nil)
+	if err != nil {
+		t.Fatalf("StreamScienceAnswer failed: %v", err)
+	}
+	languageStream, err := language.StreamLanguageAnswer(ctx, "用英语怎么说？", "银杏", "自然类", 10, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("StreamLanguageAnswer failed: %v", err)
+	}
+	humanitiesStream, err := humanities.StreamHumanitiesAnswer(ctx, "有什么故事吗？", "银杏", "自然类", 10, nil, nil)
+	if err != nil {
+		t.Fatalf("StreamHumanitiesAnswer failed: %v", err)
+	}
+
+	// Mock模式下流式内容与非流式回答一致
+	scienceAnswer, _ := science.GenerateScienceAnswer(ctx, "这是什么？", "银杏", "自然类", 10, nil, 4, nil, nil)
+	languageAnswer, _ := language.GenerateLanguageAnswer(ctx, "用英语怎么说？", "银杏", "自然类", 10, nil, nil, nil)
+	humanitiesAnswer, _ := humanities.GenerateHumanitiesAnswer(ctx, "有什么故事吗？", "银杏", "自然类", 10, nil, nil)
+
+	for name, tc := range map[string]struct {
+		stream *schema.StreamReader[*schema.Message]
+		want   string
+	}{
+		"Science":    {scienceStream, scienceAnswer.Content},
+		"Language":   {languageStream, languageAnswer.Content},
+		"Humanities": {humanitiesStream, humanitiesAnswer.Content},
+	} {
+		content, err := CollectStream(tc.stream)
+		if err != nil {
+			t.Errorf("%s: 读取流失败: %v", name, err)
+			continue
+		}
+		if content != tc.want {
+			t.Errorf("%s: 流式内容 = %q, 期望 %q", name, content, tc.want)
+		}
+	}
+}
+
+func TestDomainAgents_LearnerProfile(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+	science, _ := NewScienceAgentNode(ctx, config.AIConfig{}, nil, logger, tools.GetDefaultRegistry(logger))
+	profile := &types.LearnerProfile{LearnerId: "child-1", UnderstoodTopics: []string{"银杏"}}
+
+	// 已经理解的主题换个角度讲
+	response, err := science.GenerateScienceAnswer(ctx, "这是什么？", "银杏", "自然类", 10, nil, 4, nil, profile)
+	if err != nil {
+		t.Fatalf("GenerateScienceAnswer failed: %v", err)
+	}
+	if !strings.HasPrefix(response.Content, "我们之前聊过银杏") {
+		t.Errorf("Content should mention previous conversation: %s", response.Content)
+	}
+
+	// 新主题不受影响
+	response, _ = science.GenerateScienceAnswer(ctx, "这是什么？", "蝴蝶", "自然类", 10, nil, 4, nil, profile)
+	if strings.HasPrefix(response.Content, "我们之前聊过") {
+		t.Errorf("Content should not mention previous conversation: %s", response.Content)
+	}
+
+	// 学习档案加入系统提示词
+	messages := science.buildMessages("这是什么？", "银杏", "自然类", nil, nil, profile)
+	if !strings.Contains(messages[0].Content, "已经理解的主题：银杏") {
+		t.Errorf("System message should include learner profile: %s", messages[0].Content)
+	}
+}
@@ -2,13 +2,12 @@ package nodes
 
 import (
 	"context"
-	"math/rand"
-	"time"
+	"fmt"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -19,20 +18,22 @@ type HumanitiesAgentNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewHumanitiesAgentNode 创建Humanities Agent节点
-func NewHumanitiesAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*HumanitiesAgentNode, error) {
+func NewHumanitiesAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*HumanitiesAgentNode, error) {
 	node := &HumanitiesAgentNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -40,7 +41,7 @@ func NewHumanitiesAgentNode(ctx context.Context, cfg config.AIConfig, logger log
 			logger.Info("✅ Humanities Agent节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，Humanities Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Humanities Agent节点将使用Mock模式")
 	}
 
 	node.initTemplate()
@@ -49,56 +50,14 @@ func NewHumanitiesAgentNode(ctx context.Context, cfg config.AIConfig, logger log
 
 // initChatModel 初始化 ChatModel
 func (n *HumanitiesAgentNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *HumanitiesAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *HumanitiesAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -120,7 +79,7 @@ func (n *HumanitiesAgentNode) initTemplate() {
 }
 
 // GenerateHumanitiesAnswer 生成人文回答
-func (n *HumanitiesAgentNode) GenerateHumanitiesAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message) (*types.DomainAgentResponse, error) {
+func (n *HumanitiesAgentNode) GenerateHumanitiesAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	n.logger.Infow("执行Humanities Agent回答生成",
 		logx.Field("message", message),
 		logx.Field("objectName", objectName),
@@ -128,15 +87,15 @@ func (n *HumanitiesAgentNode) GenerateHumanitiesAnswer(ctx context.Context, mess
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	if n.initialized && n.chatModel != nil {
-		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory)
+	if n.initialized {
+		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory, profile)
 	}
 
-	return n.executeMock(message, objectName, userAge)
+	return n.executeMock(message, objectName, userAge, profile)
 }
 
 // executeMock Mock实现
-func (n *HumanitiesAgentNode) executeMock(message string, objectName string, userAge int) (*types.DomainAgentResponse, error) {
+func (n *HumanitiesAgentNode) executeMock(message string, objectName string, userAge int, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	content := "关于" + objectName + "，有一句古诗说得很美。"
 	if userAge <= 6 {
 		content = "看到" + objectName + "，我想起了一个有趣的故事。"
@@ -145,6 +104,7 @@ func (n *HumanitiesAgentNode) executeMock(message string, objectName string, use
 	} else {
 		content = objectName + "承载着丰富的文化内涵，让我们一起来探索。"
 	}
+	content = personalizeMock(content, profile, objectName)
 
 	return &types.DomainAgentResponse{
 		DomainType:  "Humanities",
@@ -154,18 +114,45 @@ func (n *HumanitiesAgentNode) executeMock(message string, objectName string, use
 	}, nil
 }
 
-// executeReal 真实eino实现
-func (n *HumanitiesAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message) (*types.DomainAgentResponse, error) {
+// StreamHumanitiesAnswer 流式生成人文回答，文本一生成就写入返回的流
+// 模板格式化或模型调用失败时降级为Mock回答
+func (n *HumanitiesAgentNode) StreamHumanitiesAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, profile *types.LearnerProfile) (*schema.StreamReader[*schema.Message], error) {
+	n.logger.Infow("执行Humanities Agent流式回答生成",
+		logx.Field("message", message),
+		logx.Field("objectName", objectName),
+		logx.Field("userAge", userAge),
+		logx.Field("useRealModel", n.initialized),
+	)
+
+	if n.initialized {
+		messages, err := n.buildMessages(ctx, message, objectName, objectCategory, userAge, chatHistory, profile)
+		if err == nil {
+			var stream *schema.StreamReader[*schema.Message]
+			if stream, err = n.chatModel.Stream(ctx, messages); err == nil {
+				return stream, nil
+			}
+		}
+		n.logger.Errorw("ChatModel流式调用失败，降级到Mock模式", logx.Field("error", err))
+	}
+
+	response, err := n.executeMock(message, objectName, userAge, profile)
+	if err != nil {
+		return nil, err
+	}
+	return mockStream(response.Content), nil
+}
+
+// buildMessages 格式化消息模板，构建发送给ChatModel的消息列表
+func (n *HumanitiesAgentNode) buildMessages(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, profile *types.LearnerProfile) ([]*schema.Message, error) {
 	messages, err := n.template.Format(ctx, map[string]any{
-		"message":       message,
-		"objectName":    objectName,
+		"message":        message,
+		"objectName":     objectName,
 		"objectCategory": objectCategory,
-		"userAge":       userAge,
-		"chat_history":  chatHistory,
+		"userAge":        userAge,
+		"chat_history":   chatHistory,
 	})
 	if err != nil {
-		n.logger.Errorw("模板格式化失败", logx.Field("error", err))
-		return n.executeMock(message, objectName, userAge)
+		return nil, fmt.Errorf("模板格式化失败: %w", err)
 	}
 
 	// 确保消息格式正确，移除任何可能导致工具调用错误的字段
@@ -180,10 +167,26 @@ func (n *HumanitiesAgentNode) executeReal(ctx context.Context, message string, o
 		}
 	}
 
+	// 在系统提示词后附加孩子的学习档案
+	if len(cleanMessages) > 0 && cleanMessages[0].Role == schema.System {
+		cleanMessages[0].Content += learnerProfilePrompt(profile)
+	}
+
+	return cleanMessages, nil
+}
+
+// executeReal 真实eino实现
+func (n *HumanitiesAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
+	cleanMessages, err := n.buildMessages(ctx, message, objectName, objectCategory, userAge, chatHistory, profile)
+	if err != nil {
+		n.logger.Errorw("模板格式化失败", logx.Field("error", err))
+		return n.executeMock(message, objectName, userAge, profile)
+	}
+
 	result, err := n.chatModel.Generate(ctx, cleanMessages)
 	if err != nil {
 		n.logger.Errorw("ChatModel调用失败", logx.Field("error", err))
-		return n.executeMock(message, objectName, userAge)
+		return n.executeMock(message, objectName, userAge, profile)
 	}
 
 	return &types.DomainAgentResponse{
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
-	configpkg "github.com/tango/explore/internal/config"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -17,20 +17,22 @@ type ImageGenerationNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	imageModel  *ark.ImageGenerationModel // eino ImageGenerationModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	imageModel  model.BaseChatModel // 图片生成模型（模型池中的共享客户端）
 	initialized bool
 }
 
 // NewImageGenerationNode 创建图片生成节点
-func NewImageGenerationNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*ImageGenerationNode, error) {
+func NewImageGenerationNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*ImageGenerationNode, error) {
 	node := &ImageGenerationNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ImageGenerationModel
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	// 如果配置了可用的模型服务，初始化 ImageGenerationModel
+	if models.Enabled(llm.RoleImageGen) {
 		if err := node.initImageModel(ctx); err != nil {
 			logger.Errorw("初始化ImageGenerationModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -38,7 +40,7 @@ func NewImageGenerationNode(ctx context.Context, cfg config.AIConfig, logger log
 			logger.Info("图片生成节点已初始化ImageGenerationModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，图片生成节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，图片生成节点将使用Mock模式")
 	}
 
 	return node, nil
@@ -46,31 +48,7 @@ func NewImageGenerationNode(ctx context.Context, cfg config.AIConfig, logger log
 
 // initImageModel 初始化 ImageGenerationModel
 func (n *ImageGenerationNode) initImageModel(ctx context.Context) error {
-	modelName := n.config.ImageGenerationModel
-	if modelName == "" {
-		modelName = configpkg.DefaultImageGenerationModel
-	}
-
-	cfg := &ark.ImageGenerationConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil // 返回 nil，使用 Mock 模式
-	}
-
-	imageModel, err := ark.NewImageGenerationModel(ctx, cfg)
+	imageModel, err := n.models.ImageModel(ctx, "")
 	if err != nil {
 		return err
 	}
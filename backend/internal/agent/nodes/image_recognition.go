@@ -5,58 +5,93 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
+	"math"
 	"math/rand"
-	"net/http"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
-	configpkg "github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/taxonomy"
 	"github.com/tango/explore/internal/utils"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+// imageFetcher 远程图片下载器（imageproc.Fetcher）
+type imageFetcher interface {
+	Fetch(ctx context.Context, rawURL string) ([]byte, string, error)
+}
+
 // ImageRecognitionNode 图片识别节点
 type ImageRecognitionNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例（支持 Vision）
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（支持 Vision，每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
+	cache       *RecognitionCache   // 识别结果缓存（nil表示不缓存）
+	fetcher     imageFetcher        // 远程图片下载器（模型无法访问图片URL时下载后转为base64）
+	storePrefix string              // 本服务图片存储的URL前缀（这些URL按内容命名，识别结果按URL缓存）
+	taxonomy    *taxonomy.Taxonomy  // 类别树（识别结果的类别归一化到类别树上）
 	initialized bool
 }
 
+// maxRecognizedObjects 最多返回的候选对象数
+const maxRecognizedObjects = 5
+
 // ImageRecognitionResult 图片识别结果
+// ObjectName 等字段为主对象；Objects 为图片中的全部候选对象（按置信度从高到低，第一个即主对象）
 type ImageRecognitionResult struct {
 	ObjectName     string
 	ObjectCategory string
+	CategoryPath   []string // 归一化后的类别路径（从顶层类别到子类）
 	Keywords       []string
 	Confidence     float64
+	Objects        []RecognizedObject
+	mocked         bool // Mock结果（包括真实模型失败后的回退），不写入缓存
+}
+
+// RecognizedObject 图片中识别出的候选对象
+type RecognizedObject struct {
+	ObjectName     string       `json:"objectName"`
+	ObjectCategory string       `json:"objectCategory"`
+	CategoryPath   []string     `json:"-"`
+	Confidence     float64      `json:"confidence"`
+	Keywords       []string     `json:"keywords"`
+	BoundingBox    *BoundingBox `json:"boundingBox"`
+}
+
+// BoundingBox 归一化边界框（相对图片宽高的比例 0-1，X、Y 为左上角）
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // NewImageRecognitionNode 创建图片识别节点
-func NewImageRecognitionNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*ImageRecognitionNode, error) {
+func NewImageRecognitionNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*ImageRecognitionNode, error) {
 	node := &ImageRecognitionNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
+		cache:  NewRecognitionCache(cfg),
+		// 默认按10MB限制下载，svc 会按上传配置替换（大小限制、允许的主机）
+		fetcher:  imageproc.NewFetcher(config.UploadConfig{}),
+		taxonomy: taxonomy.Default(),
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel（Vision 模型）
-	hasEinoBaseURL := cfg.EinoBaseURL != ""
-	hasAppID := cfg.AppID != ""
-	hasAppKey := cfg.AppKey != ""
-
-	if hasEinoBaseURL && hasAppID && hasAppKey {
-		logger.Infow("检测到eino配置，尝试初始化Vision ChatModel",
-			logx.Field("einoBaseURL", cfg.EinoBaseURL),
-			logx.Field("appID", cfg.AppID),
-			logx.Field("hasAppKey", hasAppKey),
+	// 如果配置了可用的模型服务，初始化 ChatModel（Vision 模型）
+	if models.Enabled(llm.RoleVision) {
+		logger.Infow("检测到模型配置，尝试初始化Vision ChatModel",
+			logx.Field("provider", models.Provider(llm.RoleVision)),
 		)
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化Vision ChatModel失败，将使用Mock模式",
@@ -68,12 +103,10 @@ func NewImageRecognitionNode(ctx context.Context, cfg config.AIConfig, logger lo
 			logger.Info("✅ 图片识别节点已初始化Vision ChatModel，将使用真实模型")
 		}
 	} else {
-		logger.Errorw("未完整配置eino参数，图片识别节点将使用Mock模式",
-			logx.Field("hasEinoBaseURL", hasEinoBaseURL),
-			logx.Field("hasAppID", hasAppID),
-			logx.Field("hasAppKey", hasAppKey),
+		logger.Errorw("未配置可用的模型服务，图片识别节点将使用Mock模式",
+			logx.Field("provider", models.Provider(llm.RoleVision)),
 		)
-		logger.Info("提示：需要同时配置 EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY 才能使用真实模型")
+		logger.Info("提示：需要同时配置 EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY，或通过 VISION_MODEL_PROVIDER 等配置其他模型服务，才能使用真实模型")
 	}
 
 	// 创建消息模板
@@ -84,70 +117,14 @@ func NewImageRecognitionNode(ctx context.Context, cfg config.AIConfig, logger lo
 
 // initChatModel 初始化 Vision ChatModel（使用随机选择的模型）
 func (n *ImageRecognitionNode) initChatModel(ctx context.Context) error {
-	// 从配置中随机选择一个图片识别模型
-	modelName := n.selectRandomModel(n.config.ImageRecognitionModels)
-	if modelName == "" {
-		models := configpkg.GetDefaultImageRecognitionModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = configpkg.DefaultImageRecognitionModel1
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用与其他节点一致的格式
-	// eino 框架的 APIKey 字段会自动处理 Bearer Token 格式
-	// 使用 AppID:AppKey 格式，框架会在内部添加 Bearer 前缀
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		// 使用 AppID:AppKey 格式（与其他节点一致）
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-		n.logger.Infow("使用 AppID:AppKey 作为 APIKey",
-			logx.Field("appIDLength", len(n.config.AppID)),
-			logx.Field("appKeyLength", len(n.config.AppKey)),
-		)
-	} else if n.config.AppKey != "" {
-		// 如果只有 AppKey，直接使用（可能是完整的认证 token）
-		cfg.APIKey = n.config.AppKey
-		n.logger.Infow("使用 AppKey 作为 APIKey")
-	} else if n.config.AppID != "" {
-		// 如果只有 AppID，使用 AppID
-		cfg.APIKey = n.config.AppID
-		n.logger.Infow("使用 AppID 作为 APIKey")
-	} else {
-		return nil // 返回 nil，使用 Mock 模式
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleVision); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
-	n.logger.Infow("图片识别模型已初始化", logx.Field("model", modelName))
+	n.chatModel = n.models.RoleModel(llm.RoleVision)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *ImageRecognitionNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *ImageRecognitionNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -160,13 +137,18 @@ func (n *ImageRecognitionNode) initTemplate() {
 2. 判断对象类别：自然类、生活类、人文类
 3. 提取3-5个相关关键词
 4. 评估识别置信度（0.0-1.0）
+5. 如果图片中有多个明显的对象（例如沙发上的小猫），在 objects 中列出最多5个候选对象，按置信度从高到低排列，第一个与主对象相同；
+   boundingBox 为对象在图片中的位置，坐标是相对图片宽高的比例（0.0-1.0），x、y 为左上角
 
 请严格按照以下JSON格式返回：
 {
   "objectName": "对象名称（中文）",
   "objectCategory": "自然类/生活类/人文类",
   "keywords": ["关键词1", "关键词2", "关键词3"],
-  "confidence": 0.0-1.0之间的浮点数
+  "confidence": 0.0-1.0之间的浮点数,
+  "objects": [
+    {"objectName": "对象名称", "objectCategory": "自然类/生活类/人文类", "confidence": 0.0-1.0, "keywords": ["关键词"], "boundingBox": {"x": 0.1, "y": 0.2, "width": 0.5, "height": 0.6}}
+  ]
 }`),
 		schema.UserMessage("请识别这张图片中的对象。"),
 	)
@@ -181,9 +163,33 @@ func (n *ImageRecognitionNode) Execute(data *GraphData) (*ImageRecognitionResult
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	// 如果 ChatModel 已初始化，使用真实模型
+	// 如果 ChatModel 已初始化，使用真实模型（先查识别结果缓存）
 	if n.initialized && n.chatModel != nil {
-		return n.executeReal(data)
+		if n.cache == nil {
+			return n.executeReal(data)
+		}
+		key, image, ok := n.cacheKeyFor(data.Image)
+		if !ok {
+			return n.executeReal(data)
+		}
+		if cached, hit := n.cache.Get(key); hit {
+			n.logger.Infow("命中识别结果缓存，跳过模型调用",
+				logx.Field("objectName", cached.ObjectName),
+				logx.Field("category", cached.ObjectCategory),
+			)
+			return cached, nil
+		}
+		if image != data.Image {
+			// 计算缓存键时已下载外部图片，直接使用下载的内容
+			downloaded := *data
+			downloaded.Image = image
+			data = &downloaded
+		}
+		result, err := n.executeReal(data)
+		if err == nil && !result.mocked {
+			n.cache.Put(key, result)
+		}
+		return result, err
 	}
 
 	// 否则使用 Mock 实现
@@ -219,7 +225,9 @@ func (n *ImageRecognitionNode) executeMock(data *GraphData) (*ImageRecognitionRe
 		ObjectCategory: selected.category,
 		Keywords:       selected.keywords,
 		Confidence:     confidence,
+		mocked:         true,
 	}
+	n.normalizeResult(result)
 
 	// 优化：减少日志详细程度
 	n.logger.Debugw("图片识别完成（Mock）",
@@ -233,14 +241,6 @@ func (n *ImageRecognitionNode) executeMock(data *GraphData) (*ImageRecognitionRe
 
 // executeReal 真实eino实现
 func (n *ImageRecognitionNode) executeReal(data *GraphData) (*ImageRecognitionResult, error) {
-	// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-	if err := n.initChatModel(n.ctx); err != nil {
-		n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-			logx.Field("error", err),
-		)
-		// 如果重新初始化失败，继续使用已初始化的模型
-	}
-
 	// 优化：调整超时时间到45秒（原来60秒可能过长）
 	// 如果模型调用需要更长时间，可以根据实际情况调整
 	ctx, cancel := context.WithTimeout(n.ctx, 45*time.Second)
@@ -258,13 +258,18 @@ func (n *ImageRecognitionNode) executeReal(data *GraphData) (*ImageRecognitionRe
 2. 判断对象类别：自然类、生活类、人文类
 3. 提取3-5个相关关键词
 4. 评估识别置信度（0.0-1.0）
+5. 如果图片中有多个明显的对象（例如沙发上的小猫），在 objects 中列出最多5个候选对象，按置信度从高到低排列，第一个与主对象相同；
+   boundingBox 为对象在图片中的位置，坐标是相对图片宽高的比例（0.0-1.0），x、y 为左上角
 
 请严格按照以下JSON格式返回：
 {
   "objectName": "对象名称（中文）",
   "objectCategory": "自然类/生活类/人文类",
   "keywords": ["关键词1", "关键词2", "关键词3"],
-  "confidence": 0.0-1.0之间的浮点数
+  "confidence": 0.0-1.0之间的浮点数,
+  "objects": [
+    {"objectName": "对象名称", "objectCategory": "自然类/生活类/人文类", "confidence": 0.0-1.0, "keywords": ["关键词"], "boundingBox": {"x": 0.1, "y": 0.2, "width": 0.5, "height": 0.6}}
+  ]
 }`),
 	}
 
@@ -486,68 +491,129 @@ func (n *ImageRecognitionNode) executeReal(data *GraphData) (*ImageRecognitionRe
 		)
 		return n.executeMock(data)
 	}
+	n.normalizeResult(&recognitionResult)
 
 	// 优化：减少日志详细程度，使用Info级别但减少字段
 	n.logger.Infow("图片识别完成（真实模型）",
 		logx.Field("objectName", recognitionResult.ObjectName),
 		logx.Field("category", recognitionResult.ObjectCategory),
 		logx.Field("confidence", recognitionResult.Confidence),
+		logx.Field("objectCount", len(recognitionResult.Objects)),
 	)
 
 	return &recognitionResult, nil
 }
 
-// downloadImageAsBase64 从 URL 下载图片并转换为 base64
-func (n *ImageRecognitionNode) downloadImageAsBase64(ctx context.Context, url string) (base64Data string, mimeType string, err error) {
-	// 创建 HTTP 请求，使用传入的 context
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+// SetImageStorePrefix 设置本服务图片存储的URL前缀
+func (n *ImageRecognitionNode) SetImageStorePrefix(prefix string) {
+	n.storePrefix = prefix
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", "", fmt.Errorf("创建请求失败: %w", err)
+// normalizeResult 整理候选对象，并把主对象和候选对象的类别归一化到类别树上
+func (n *ImageRecognitionNode) normalizeResult(result *ImageRecognitionResult) {
+	normalizeRecognizedObjects(result)
+	path := n.taxonomy.Classify(result.ObjectCategory, result.ObjectName, result.Keywords)
+	result.ObjectCategory = path.Top()
+	result.CategoryPath = path
+	for i := range result.Objects {
+		obj := &result.Objects[i]
+		path := n.taxonomy.Classify(obj.ObjectCategory, obj.ObjectName, obj.Keywords)
+		obj.ObjectCategory = path.Top()
+		obj.CategoryPath = path
 	}
+}
 
-	// 设置 User-Agent，避免某些服务器拒绝请求
-	req.Header.Set("User-Agent", "TanGo-ImageRecognition/1.0")
+// normalizeRecognizedObjects 整理候选对象：去掉无名称的对象，置信度和边界框限制在0-1，
+// 按置信度从高到低排序并截断；模型未返回候选对象时用主对象补齐，主对象缺失时取置信度最高的候选对象
+func normalizeRecognizedObjects(result *ImageRecognitionResult) {
+	objects := make([]RecognizedObject, 0, len(result.Objects)+1)
+	for _, obj := range result.Objects {
+		obj.ObjectName = strings.TrimSpace(obj.ObjectName)
+		if obj.ObjectName == "" {
+			continue
+		}
+		obj.Confidence = clamp01(obj.Confidence)
+		obj.BoundingBox = normalizeBoundingBox(obj.BoundingBox)
+		objects = append(objects, obj)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].Confidence > objects[j].Confidence
+	})
+	if len(objects) > maxRecognizedObjects {
+		objects = objects[:maxRecognizedObjects]
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", "", fmt.Errorf("下载图片失败: %w", err)
+	result.Confidence = clamp01(result.Confidence)
+	if result.ObjectName == "" && len(objects) > 0 {
+		top := objects[0]
+		result.ObjectName = top.ObjectName
+		result.ObjectCategory = top.ObjectCategory
+		result.Confidence = top.Confidence
+		if len(result.Keywords) == 0 {
+			result.Keywords = top.Keywords
+		}
+	}
+	if len(objects) == 0 && result.ObjectName != "" {
+		objects = append(objects, RecognizedObject{
+			ObjectName:     result.ObjectName,
+			ObjectCategory: result.ObjectCategory,
+			Confidence:     result.Confidence,
+			Keywords:       result.Keywords,
+		})
 	}
-	defer resp.Body.Close()
+	result.Objects = objects
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("下载图片失败，状态码: %d", resp.StatusCode)
+// normalizeBoundingBox 将边界框限制在图片范围内，无效的边界框返回nil
+func normalizeBoundingBox(box *BoundingBox) *BoundingBox {
+	if box == nil {
+		return nil
+	}
+	x, y := clamp01(box.X), clamp01(box.Y)
+	normalized := &BoundingBox{
+		X:      x,
+		Y:      y,
+		Width:  math.Min(clamp01(box.Width), 1-x),
+		Height: math.Min(clamp01(box.Height), 1-y),
+	}
+	if normalized.Width <= 0 || normalized.Height <= 0 {
+		return nil
 	}
+	return normalized
+}
 
-	// 读取图片数据
-	imageData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", fmt.Errorf("读取图片数据失败: %w", err)
+func clamp01(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
 	}
+	return math.Max(0, math.Min(1, v))
+}
 
-	// 获取 MIME 类型
-	mimeType = resp.Header.Get("Content-Type")
-	if mimeType == "" {
-		// 根据 URL 扩展名推断 MIME 类型
-		urlLower := strings.ToLower(url)
-		if strings.HasSuffix(urlLower, ".png") {
-			mimeType = "image/png"
-		} else if strings.HasSuffix(urlLower, ".jpg") || strings.HasSuffix(urlLower, ".jpeg") {
-			mimeType = "image/jpeg"
-		} else if strings.HasSuffix(urlLower, ".gif") {
-			mimeType = "image/gif"
-		} else if strings.HasSuffix(urlLower, ".webp") {
-			mimeType = "image/webp"
-		} else {
-			mimeType = "image/jpeg" // 默认类型
-		}
+// Enabled 是否使用真实的视觉模型（未配置模型时返回Mock结果）
+func (n *ImageRecognitionNode) Enabled() bool {
+	return n.initialized && n.chatModel != nil
+}
+
+// SetTaxonomy 设置类别树
+func (n *ImageRecognitionNode) SetTaxonomy(t *taxonomy.Taxonomy) {
+	if t != nil {
+		n.taxonomy = t
 	}
+}
 
-	// 转换为 base64
-	base64Data = base64.StdEncoding.EncodeToString(imageData)
+// SetFetcher 设置远程图片下载器
+func (n *ImageRecognitionNode) SetFetcher(fetcher *imageproc.Fetcher) {
+	if fetcher != nil {
+		n.fetcher = fetcher
+	}
+}
 
-	return base64Data, mimeType, nil
+// downloadImageAsBase64 从 URL 下载图片并转换为 base64
+// 使用安全下载器：拒绝内网地址、限制大小并校验文件头，MIME类型按文件头识别
+func (n *ImageRecognitionNode) downloadImageAsBase64(ctx context.Context, url string) (base64Data string, mimeType string, err error) {
+	imageData, mimeType, err := n.fetcher.Fetch(ctx, url)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(imageData), mimeType, nil
 }
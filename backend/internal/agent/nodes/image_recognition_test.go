@@ -0,0 +1,66 @@
+package nodes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImageRecognitionNode_MultipleObjects(t *testing.T) {
+	chatModel := &countingVisionModel{reply: "识别结果如下：\n" + `{
+  "objectName": "小猫",
+  "objectCategory": "自然类",
+  "keywords": ["动物"],
+  "confidence": 0.55,
+  "objects": [
+    {"objectName": "沙发", "objectCategory": "生活类", "confidence": 0.5, "boundingBox": {"x": 0.0, "y": 0.4, "width": 1.2, "height": 0.6}},
+    {"objectName": "小猫", "objectCategory": "自然类", "confidence": 0.55, "keywords": ["动物"], "boundingBox": {"x": 0.3, "y": 0.2, "width": 0.3, "height": 0.4}},
+    {"objectName": "", "confidence": 0.9}
+  ]
+}`}
+	node := newCachedRecognitionNode(chatModel)
+
+	result, err := node.Execute(&GraphData{Image: "https://cdn.example.com/cat.jpg"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.ObjectName != "小猫" || len(result.Objects) != 2 {
+		t.Fatalf("识别结果错误: %+v", result)
+	}
+	if result.Objects[0].ObjectName != "小猫" || result.Objects[1].ObjectName != "沙发" {
+		t.Errorf("候选对象应按置信度从高到低排列: %+v", result.Objects)
+	}
+	// 类别归一化到类别树上
+	if got := strings.Join(result.CategoryPath, "/"); got != "自然类/动物" {
+		t.Errorf("CategoryPath = %s", got)
+	}
+	// 超出图片范围的边界框被截断
+	if box := result.Objects[1].BoundingBox; box == nil || box.Width != 1 || box.Height != 0.6 {
+		t.Errorf("边界框未归一化: %+v", box)
+	}
+}
+
+func TestNormalizeRecognizedObjects(t *testing.T) {
+	// 未返回候选对象时用主对象补齐
+	result := &ImageRecognitionResult{ObjectName: "苹果", ObjectCategory: "生活类", Confidence: 1.3}
+	normalizeRecognizedObjects(result)
+	if result.Confidence != 1 || len(result.Objects) != 1 || result.Objects[0].ObjectName != "苹果" {
+		t.Errorf("主对象补齐错误: %+v", result)
+	}
+
+	// 主对象缺失时取置信度最高的候选对象，并截断到最多5个
+	result = &ImageRecognitionResult{}
+	for i := 0; i < 7; i++ {
+		result.Objects = append(result.Objects, RecognizedObject{
+			ObjectName:  string(rune('A' + i)),
+			Confidence:  float64(i) / 10,
+			BoundingBox: &BoundingBox{X: 0.5, Y: 0.5, Width: 0, Height: 0.1},
+		})
+	}
+	normalizeRecognizedObjects(result)
+	if result.ObjectName != "G" || result.Confidence != 0.6 || len(result.Objects) != maxRecognizedObjects {
+		t.Errorf("候选对象整理错误: %+v", result)
+	}
+	if result.Objects[0].BoundingBox != nil {
+		t.Error("宽度为0的边界框应丢弃")
+	}
+}
@@ -3,14 +3,12 @@ package nodes
 import (
 	"context"
 	"encoding/json"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -21,21 +19,23 @@ type IntentAgentNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewIntentAgentNode 创建Intent Agent节点
-func NewIntentAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*IntentAgentNode, error) {
+func NewIntentAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*IntentAgentNode, error) {
 	node := &IntentAgentNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	// 如果配置了可用的模型服务，初始化 ChatModel
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -43,7 +43,7 @@ func NewIntentAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Lo
 			logger.Info("✅ Intent Agent节点已初始化ChatModel，将使用真实模型")
 		}
 	} else {
-		logger.Info("未配置eino参数，Intent Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Intent Agent节点将使用Mock模式")
 	}
 
 	// 创建消息模板
@@ -54,59 +54,14 @@ func NewIntentAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Lo
 
 // initChatModel 初始化 ChatModel（使用随机选择的模型）
 func (n *IntentAgentNode) initChatModel(ctx context.Context) error {
-	// 从配置中随机选择一个文本生成模型
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil // 返回 nil，使用 Mock 模式
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
-	n.logger.Infow("Intent Agent模型已初始化", logx.Field("model", modelName))
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *IntentAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *IntentAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -145,7 +100,7 @@ func (n *IntentAgentNode) RecognizeIntent(ctx context.Context, message string, c
 	)
 
 	// 如果 ChatModel 已初始化，使用真实模型
-	if n.initialized && n.chatModel != nil {
+	if n.initialized {
 		return n.executeReal(ctx, message, chatHistory)
 	}
 
@@ -17,7 +17,7 @@ func TestIntentAgentNode_RecognizeIntent(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewIntentAgentNode(ctx, cfg, logger)
+	node, err := NewIntentAgentNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create IntentAgentNode: %v", err)
 	}
@@ -75,7 +75,7 @@ func TestIntentAgentNode_RecognizeIntent_EdgeCases(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewIntentAgentNode(ctx, cfg, logger)
+	node, err := NewIntentAgentNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create IntentAgentNode: %v", err)
 	}
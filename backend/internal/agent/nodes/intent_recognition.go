@@ -3,16 +3,13 @@ package nodes
 import (
 	"context"
 	"encoding/json"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
-	configpkg "github.com/tango/explore/internal/config"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -21,7 +18,8 @@ type IntentRecognitionNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
@@ -34,15 +32,16 @@ type IntentRecognitionResult struct {
 }
 
 // NewIntentRecognitionNode 创建意图识别节点
-func NewIntentRecognitionNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*IntentRecognitionNode, error) {
+func NewIntentRecognitionNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*IntentRecognitionNode, error) {
 	node := &IntentRecognitionNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	// 如果配置了可用的模型服务，初始化 ChatModel
+	if models.Enabled(llm.RoleIntent) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 			// 继续使用 Mock 模式
@@ -51,7 +50,7 @@ func NewIntentRecognitionNode(ctx context.Context, cfg config.AIConfig, logger l
 			logger.Info("意图识别节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，意图识别节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，意图识别节点将使用Mock模式")
 	}
 
 	// 创建消息模板
@@ -62,67 +61,14 @@ func NewIntentRecognitionNode(ctx context.Context, cfg config.AIConfig, logger l
 
 // initChatModel 初始化 ChatModel（使用随机选择的模型）
 func (n *IntentRecognitionNode) initChatModel(ctx context.Context) error {
-	// 从配置中随机选择一个意图识别模型
-	modelName := n.selectRandomModel(n.config.IntentModels)
-	if modelName == "" {
-		models := configpkg.GetDefaultIntentModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = configpkg.DefaultIntentModel
-		}
-	}
-
-	// 构建配置：使用 AppID 作为 APIKey（根据实际认证方式调整）
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	// 如果配置了 BaseURL，使用自定义地址
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
-	// 注意：eino 框架的 APIKey 字段可能已经处理了 Bearer Token 格式
-	// 如果框架不支持，需要手动构造 Bearer Token
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		// 使用 AppID:AppKey 格式作为 APIKey（eino 框架可能内部处理 Bearer Token）
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		// 如果只有 AppKey，使用 AppKey
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		// 如果只有 AppID，使用 AppID
-		cfg.APIKey = n.config.AppID
-	} else {
-		// 如果没有配置认证信息，无法初始化
-		return nil // 返回 nil，使用 Mock 模式
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleIntent); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
-	n.logger.Infow("意图识别模型已初始化", logx.Field("model", modelName))
+	n.chatModel = n.models.RoleModel(llm.RoleIntent)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *IntentRecognitionNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *IntentRecognitionNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -152,7 +98,7 @@ func (n *IntentRecognitionNode) Execute(data *GraphData, context []interface{})
 	)
 
 	// 如果 ChatModel 已初始化，使用真实模型
-	if n.initialized && n.chatModel != nil {
+	if n.initialized {
 		return n.executeReal(data, context)
 	}
 
@@ -202,14 +148,6 @@ func (n *IntentRecognitionNode) executeMock(data *GraphData, context []interface
 
 // executeReal 真实eino实现
 func (n *IntentRecognitionNode) executeReal(data *GraphData, context []interface{}) (*IntentRecognitionResult, error) {
-	// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-	if err := n.initChatModel(n.ctx); err != nil {
-		n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-			logx.Field("error", err),
-		)
-		// 如果重新初始化失败，继续使用已初始化的模型
-	}
-
 	// 转换上下文为 eino Message 格式
 	chatHistory := make([]*schema.Message, 0)
 	for _, ctxItem := range context {
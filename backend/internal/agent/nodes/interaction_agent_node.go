@@ -2,14 +2,15 @@ package nodes
 
 import (
 	"context"
+	"errors"
+	"io"
 	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -20,20 +21,22 @@ type InteractionAgentNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewInteractionAgentNode 创建Interaction Agent节点
-func NewInteractionAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*InteractionAgentNode, error) {
+func NewInteractionAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*InteractionAgentNode, error) {
 	node := &InteractionAgentNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -41,7 +44,7 @@ func NewInteractionAgentNode(ctx context.Context, cfg config.AIConfig, logger lo
 			logger.Info("✅ Interaction Agent节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，Interaction Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Interaction Agent节点将使用Mock模式")
 	}
 
 	node.initTemplate()
@@ -50,56 +53,14 @@ func NewInteractionAgentNode(ctx context.Context, cfg config.AIConfig, logger lo
 
 // initChatModel 初始化 ChatModel
 func (n *InteractionAgentNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *InteractionAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *InteractionAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -126,24 +87,109 @@ func (n *InteractionAgentNode) OptimizeInteraction(ctx context.Context, content
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	if n.initialized && n.chatModel != nil {
+	if n.initialized {
 		return n.executeReal(ctx, content)
 	}
 
 	return n.executeMock(content)
 }
 
+// StreamOptimizeInteraction 流式交互优化：领域Agent的文本块原样转发，回答结束后补充轻松友好的结尾
+// 不再等待完整回答后整体改写，孩子可以立即看到回答；回答已以问句结尾时不追加
+// onDone 在流结束前回调完整的优化结果（可选），用于后续的反思和记忆
+func (n *InteractionAgentNode) StreamOptimizeInteraction(
+	ctx context.Context,
+	content *schema.StreamReader[*schema.Message],
+	onDone func(*types.InteractionOptimization),
+) *schema.StreamReader[*schema.Message] {
+	sr, sw := schema.Pipe[*schema.Message](streamBufferSize)
+	go func() {
+		defer sw.Close()
+		defer content.Close()
+
+		var builder strings.Builder
+		for {
+			chunk, err := content.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if chunk == nil || chunk.Content == "" {
+				continue
+			}
+			builder.WriteString(chunk.Content)
+			if sw.Send(chunk, nil) {
+				return
+			}
+		}
+
+		optimizedContent := builder.String()
+		ending := ""
+		if strings.TrimSpace(optimizedContent) != "" && !endsWithQuestion(optimizedContent) {
+			ending = n.generateEnding(ctx, optimizedContent)
+			optimizedContent += " " + ending
+			if sw.Send(&schema.Message{Role: schema.Assistant, Content: " " + ending}, nil) {
+				return
+			}
+		}
+
+		n.logger.Infow("Interaction Agent流式交互优化完成",
+			logx.Field("contentLength", len(optimizedContent)),
+			logx.Field("endingAction", ending),
+		)
+		if onDone != nil {
+			onDone(&types.InteractionOptimization{
+				OptimizedContent: optimizedContent,
+				EndingAction:     ending,
+			})
+		}
+	}()
+	return sr
+}
+
+// generateEnding 生成回答的结尾：真实模式由模型根据回答生成一句结尾，失败或Mock模式使用预设结尾
+func (n *InteractionAgentNode) generateEnding(ctx context.Context, content string) string {
+	if n.initialized {
+		result, err := n.chatModel.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(`你是 Interaction Agent，负责给回答添加轻松友好的结尾。
+
+重要规则：
+- 只输出一句结尾，不要重复原回答
+- 给孩子一个可选动作，不制造学习压力
+- 常用的结尾方式：你想不想试试？我们下一步看什么？要不要换个角度？
+- 不要使用任何工具`),
+			schema.UserMessage("回答: " + content),
+		})
+		if err == nil && strings.TrimSpace(result.Content) != "" {
+			return strings.TrimSpace(result.Content)
+		}
+		n.logger.Errorw("生成结尾失败，使用预设结尾", logx.Field("error", err))
+	}
+	return interactionEndings[rand.Intn(len(interactionEndings))]
+}
+
+// interactionEndings 预设的轻松结尾
+var interactionEndings = []string{
+	"你想不想试试？",
+	"我们下一步看什么？",
+	"要不要换个角度？",
+}
+
+// endsWithQuestion 回答是否已以问句结尾
+func endsWithQuestion(content string) bool {
+	content = strings.TrimSpace(content)
+	return strings.HasSuffix(content, "？") || strings.HasSuffix(content, "?")
+}
+
 // executeMock Mock实现
 func (n *InteractionAgentNode) executeMock(content string) (*types.InteractionOptimization, error) {
-	endings := []string{
-		"你想不想试试？",
-		"我们下一步看什么？",
-		"要不要换个角度？",
-	}
-	ending := endings[rand.Intn(len(endings))]
+	ending := interactionEndings[rand.Intn(len(interactionEndings))]
 
 	optimizedContent := content
-	if !strings.HasSuffix(content, "？") && !strings.HasSuffix(content, "?") {
+	if !endsWithQuestion(content) {
 		optimizedContent = content + " " + ending
 	}
 
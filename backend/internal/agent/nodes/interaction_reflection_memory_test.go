@@ -19,7 +19,7 @@ func TestInteractionAgentNode_OptimizeInteraction(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewInteractionAgentNode(ctx, cfg, logger)
+	node, err := NewInteractionAgentNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create InteractionAgentNode: %v", err)
 	}
@@ -36,6 +36,44 @@ func TestInteractionAgentNode_OptimizeInteraction(t *testing.T) {
 	}
 }
 
+func TestInteractionAgentNode_StreamOptimizeInteraction(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+	node, err := NewInteractionAgentNode(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create InteractionAgentNode: %v", err)
+	}
+
+	var result *types.InteractionOptimization
+	stream := node.StreamOptimizeInteraction(ctx, mockStream("银杏是活化石。"), func(r *types.InteractionOptimization) {
+		result = r
+	})
+
+	first, err := stream.Recv()
+	if err != nil || first.Content != "银" {
+		t.Fatalf("领域回答的文本块应原样转发, 实际: %v %v", first, err)
+	}
+	rest, err := CollectStream(stream)
+	if err != nil {
+		t.Fatalf("读取流失败: %v", err)
+	}
+	content := first.Content + rest
+	if result == nil || result.OptimizedContent != content || result.EndingAction == "" {
+		t.Fatalf("完成回调结果错误: %+v, 流式内容: %q", result, content)
+	}
+	if content != "银杏是活化石。 "+result.EndingAction {
+		t.Errorf("回答结束后应追加结尾, 实际: %q", content)
+	}
+
+	// 已以问句结尾时不追加
+	stream = node.StreamOptimizeInteraction(ctx, mockStream("你知道银杏吗？"), func(r *types.InteractionOptimization) {
+		result = r
+	})
+	if content, _ := CollectStream(stream); content != "你知道银杏吗？" || result.EndingAction != "" {
+		t.Errorf("问句结尾不应追加结尾, 实际: %q %+v", content, result)
+	}
+}
+
 func TestReflectionAgentNode_Reflect(t *testing.T) {
 	ctx := context.Background()
 	logger := logx.WithContext(ctx)
@@ -45,7 +83,7 @@ func TestReflectionAgentNode_Reflect(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewReflectionAgentNode(ctx, cfg, logger)
+	node, err := NewReflectionAgentNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create ReflectionAgentNode: %v", err)
 	}
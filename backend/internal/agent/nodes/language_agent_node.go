@@ -3,14 +3,12 @@ package nodes
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/tools"
 	"github.com/tango/explore/internal/types"
@@ -22,22 +20,24 @@ type LanguageAgentNode struct {
 	ctx          context.Context
 	config       config.AIConfig
 	logger       logx.Logger
-	chatModel    model.ChatModel     // eino ChatModel 实例
-	template     prompt.ChatTemplate // 消息模板
-	toolRegistry *tools.ToolRegistry // 工具注册表
+	models       *llm.ModelFactory          // 模型工厂（按角色创建模型客户端）
+	chatModel    model.ToolCallingChatModel // eino ChatModel（已绑定工具，每次调用从模型池中选择模型）
+	template     prompt.ChatTemplate        // 消息模板
+	toolRegistry *tools.ToolRegistry        // 工具注册表
 	initialized  bool
 }
 
 // NewLanguageAgentNode 创建Language Agent节点
-func NewLanguageAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger, toolRegistry *tools.ToolRegistry) (*LanguageAgentNode, error) {
+func NewLanguageAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger, toolRegistry *tools.ToolRegistry) (*LanguageAgentNode, error) {
 	node := &LanguageAgentNode{
 		ctx:          ctx,
 		config:       cfg,
 		logger:       logger,
+		models:       models,
 		toolRegistry: toolRegistry,
 	}
 
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -45,7 +45,7 @@ func NewLanguageAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.
 			logger.Info("✅ Language Agent节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，Language Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Language Agent节点将使用Mock模式")
 	}
 
 	node.initTemplate()
@@ -54,39 +54,11 @@ func NewLanguageAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.
 
 // initChatModel 初始化 ChatModel（支持工具调用）
 func (n *LanguageAgentNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	// 预先创建模型池中的客户端，每次调用时从池中随机选择模型
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
+	var chatModel model.ToolCallingChatModel = n.models.RoleModel(llm.RoleText)
 
 	// 注册工具到ChatModel
 	if n.toolRegistry != nil {
@@ -98,10 +70,11 @@ func (n *LanguageAgentNode) initChatModel(ctx context.Context) error {
 			if err != nil {
 				n.logger.Errorw("转换工具信息失败", logx.Field("error", err))
 			} else if len(toolInfos) > 0 {
-				// 绑定工具到ChatModel
-				if err := chatModel.BindTools(toolInfos); err != nil {
+				// 绑定工具到ChatModel（WithTools 返回副本，不修改共享的客户端）
+				if withTools, err := chatModel.WithTools(toolInfos); err != nil {
 					n.logger.Errorw("绑定工具到ChatModel失败", logx.Field("error", err))
 				} else {
+					chatModel = withTools
 					n.logger.Infow("✅ 注册工具到Language Agent ChatModel",
 						logx.Field("tool_count", len(toolInfos)),
 						logx.Field("tools", func() []string {
@@ -121,18 +94,6 @@ func (n *LanguageAgentNode) initChatModel(ctx context.Context) error {
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *LanguageAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *LanguageAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -160,7 +121,7 @@ func (n *LanguageAgentNode) initTemplate() {
 }
 
 // GenerateLanguageAnswer 生成语言回答
-func (n *LanguageAgentNode) GenerateLanguageAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, recommendedTools []string) (*types.DomainAgentResponse, error) {
+func (n *LanguageAgentNode) GenerateLanguageAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, recommendedTools []string, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	n.logger.Infow("执行Language Agent回答生成",
 		logx.Field("message", message),
 		logx.Field("objectName", objectName),
@@ -169,19 +130,20 @@ func (n *LanguageAgentNode) GenerateLanguageAnswer(ctx context.Context, message
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	if n.initialized && n.chatModel != nil {
-		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory, recommendedTools)
+	if n.initialized {
+		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory, recommendedTools, profile)
 	}
 
-	return n.executeMock(message, objectName, userAge)
+	return n.executeMock(message, objectName, userAge, profile)
 }
 
 // executeMock Mock实现
-func (n *LanguageAgentNode) executeMock(message string, objectName string, userAge int) (*types.DomainAgentResponse, error) {
+func (n *LanguageAgentNode) executeMock(message string, objectName string, userAge int, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	content := "用英语说" + objectName + "是 \"" + objectName + "\"。你可以说：This is " + objectName + "."
 	if userAge <= 6 {
 		content = "这个叫" + objectName + "，你可以说：这是" + objectName + "。"
 	}
+	content = personalizeMock(content, profile, objectName)
 
 	return &types.DomainAgentResponse{
 		DomainType:  "Language",
@@ -191,25 +153,56 @@ func (n *LanguageAgentNode) executeMock(message string, objectName string, userA
 	}, nil
 }
 
-// executeReal 真实eino实现（支持工具调用）
-func (n *LanguageAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, recommendedTools []string) (*types.DomainAgentResponse, error) {
+// StreamLanguageAnswer 流式生成语言回答，文本一生成就写入返回的流
+// 模型请求工具调用时，执行工具后继续流式输出整合后的回答；模型调用失败时降级为Mock回答
+func (n *LanguageAgentNode) StreamLanguageAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, recommendedTools []string, profile *types.LearnerProfile) (*schema.StreamReader[*schema.Message], error) {
+	n.logger.Infow("执行Language Agent流式回答生成",
+		logx.Field("message", message),
+		logx.Field("objectName", objectName),
+		logx.Field("userAge", userAge),
+		logx.Field("recommendedTools", recommendedTools),
+		logx.Field("useRealModel", n.initialized),
+	)
+
+	if n.initialized {
+		messages, chatModel := n.prepareMessages(ctx, message, chatHistory, recommendedTools, profile)
+		toolChain := NewToolChain(n.toolRegistry, n.logger)
+		stream, err := toolChain.StreamToolChain(ctx, messages, chatModel, func(toolsUsed []string, _ map[string]interface{}) {
+			n.logger.Infow("Language Agent流式回答使用了工具", logx.Field("toolsUsed", toolsUsed))
+		})
+		if err == nil {
+			return stream, nil
+		}
+		n.logger.Errorw("ChatModel流式调用失败，降级到Mock模式", logx.Field("error", err))
+	}
+
+	response, err := n.executeMock(message, objectName, userAge, profile)
+	if err != nil {
+		return nil, err
+	}
+	return mockStream(response.Content), nil
+}
+
+// prepareMessages 构建发送给ChatModel的消息列表，并返回本次请求使用的ChatModel（动态绑定推荐的工具，不修改节点共享的实例）
+func (n *LanguageAgentNode) prepareMessages(ctx context.Context, message string, chatHistory []*schema.Message, recommendedTools []string, profile *types.LearnerProfile) ([]*schema.Message, model.ToolCallingChatModel) {
 	// 根据推荐的工具动态构建SystemMessage
-	systemMessage := n.buildSystemMessageWithTools(recommendedTools)
-	
+	systemMessage := n.buildSystemMessageWithTools(recommendedTools) + learnerProfilePrompt(profile)
+
 	// 构建消息列表
 	messages := []*schema.Message{
 		schema.SystemMessage(systemMessage),
 	}
-	
+
 	// 添加对话历史
 	if len(chatHistory) > 0 {
 		messages = append(messages, chatHistory...)
 	}
-	
+
 	// 添加用户消息
 	messages = append(messages, schema.UserMessage(message))
 
 	// 如果有关键工具推荐，动态注册（补充到已注册的工具）
+	chatModel := n.chatModel
 	if len(recommendedTools) > 0 && n.toolRegistry != nil {
 		// 获取已注册的工具
 		existingTools := n.toolRegistry.GetToolsForAgent("Language")
@@ -234,9 +227,10 @@ func (n *LanguageAgentNode) executeReal(ctx context.Context, message string, obj
 		if len(allTools) > 0 {
 			toolInfos, err := tools.ConvertToEinoTools(allTools, ctx)
 			if err == nil && len(toolInfos) > 0 {
-				if err := n.chatModel.BindTools(toolInfos); err != nil {
+				if withTools, err := n.chatModel.WithTools(toolInfos); err != nil {
 					n.logger.Errorw("动态绑定工具失败", logx.Field("error", err))
 				} else {
+					chatModel = withTools
 					n.logger.Infow("🔄 动态注册推荐工具",
 						logx.Field("recommended_tools", recommendedTools),
 						logx.Field("total_tools", len(toolInfos)),
@@ -258,20 +252,27 @@ func (n *LanguageAgentNode) executeReal(ctx context.Context, message string, obj
 		}
 	}
 
+	return cleanMessages, chatModel
+}
+
+// executeReal 真实eino实现（支持工具调用）
+func (n *LanguageAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, recommendedTools []string, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
+	cleanMessages, chatModel := n.prepareMessages(ctx, message, chatHistory, recommendedTools, profile)
+
 	// 使用工具调用链处理工具调用
 	toolChain := NewToolChain(n.toolRegistry, n.logger)
-	finalMessages, toolsUsed, toolResults, err := toolChain.ExecuteToolChain(ctx, cleanMessages, n.chatModel, recommendedTools)
+	finalMessages, toolsUsed, toolResults, err := toolChain.ExecuteToolChain(ctx, cleanMessages, chatModel, recommendedTools)
 	if err != nil {
 		n.logger.Errorw("工具调用链执行失败", logx.Field("error", err))
 		// 降级：直接调用ChatModel
-		result, err := n.chatModel.Generate(ctx, cleanMessages)
+		result, err := chatModel.Generate(ctx, cleanMessages)
 		if err != nil {
 			n.logger.Errorw("ChatModel调用失败，降级到Mock模式",
 				logx.Field("error", err),
 				logx.Field("message", message),
 				logx.Field("objectName", objectName),
 			)
-			return n.executeMock(message, objectName, userAge)
+			return n.executeMock(message, objectName, userAge, profile)
 		}
 		return &types.DomainAgentResponse{
 			DomainType:  "Language",
@@ -287,7 +288,7 @@ func (n *LanguageAgentNode) executeReal(ctx context.Context, message string, obj
 		result = finalMessages[len(finalMessages)-1]
 	} else {
 		// 如果没有结果，降级处理
-		return n.executeMock(message, objectName, userAge)
+		return n.executeMock(message, objectName, userAge, profile)
 	}
 
 	return &types.DomainAgentResponse{
@@ -0,0 +1,71 @@
+package nodes
+
+import (
+	"strings"
+
+	"github.com/tango/explore/internal/types"
+)
+
+// maxProfileSummaryItems 学习档案摘要中每类最多列出的条数（取最近的）
+const maxProfileSummaryItems = 5
+
+// learnerProfileSummary 将学习档案整理为提示词中的一段描述，没有档案时返回"暂无"
+func learnerProfileSummary(profile *types.LearnerProfile) string {
+	if profile == nil {
+		return "暂无"
+	}
+
+	parts := []string{}
+	if topics := recentItems(profile.UnderstoodTopics); len(topics) > 0 {
+		parts = append(parts, "已经理解的主题："+strings.Join(topics, "、"))
+	}
+	if topics := recentItems(profile.InterestedTopics); len(topics) > 0 {
+		parts = append(parts, "感兴趣的主题："+strings.Join(topics, "、"))
+	}
+	if points := recentItems(profile.UnunderstoodPoints); len(points) > 0 {
+		parts = append(parts, "还没理解的内容："+strings.Join(points, "；"))
+	}
+	if len(parts) == 0 {
+		return "暂无"
+	}
+	return strings.Join(parts, "\n")
+}
+
+// learnerProfilePrompt 追加到领域Agent系统提示词中的学习档案说明，没有档案时返回空
+func learnerProfilePrompt(profile *types.LearnerProfile) string {
+	summary := learnerProfileSummary(profile)
+	if summary == "暂无" {
+		return ""
+	}
+	return "\n\n孩子的学习档案（来自之前的对话）：\n" + summary +
+		"\n请结合孩子的兴趣来讲解；孩子已经理解的主题不要重复讲基础知识，换一个新的角度或更进一步；还没理解的内容可以换一种更简单的方式解释。"
+}
+
+// profileUnderstands 孩子是否已经理解过该主题
+func profileUnderstands(profile *types.LearnerProfile, objectName string) bool {
+	if profile == nil || objectName == "" {
+		return false
+	}
+	for _, topic := range profile.UnderstoodTopics {
+		if topic == objectName {
+			return true
+		}
+	}
+	return false
+}
+
+// personalizeMock Mock模式下按学习档案调整回答：已理解的主题提示换个角度
+func personalizeMock(content string, profile *types.LearnerProfile, objectName string) string {
+	if !profileUnderstands(profile, objectName) {
+		return content
+	}
+	return "我们之前聊过" + objectName + "，这次说点新的：" + content
+}
+
+// recentItems 取最近的若干条
+func recentItems(items []string) []string {
+	if len(items) > maxProfileSummaryItems {
+		return items[len(items)-maxProfileSummaryItems:]
+	}
+	return items
+}
@@ -3,14 +3,12 @@ package nodes
 import (
 	"context"
 	"encoding/json"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -21,21 +19,23 @@ type LearningPlannerNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewLearningPlannerNode 创建Learning Planner Agent节点
-func NewLearningPlannerNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*LearningPlannerNode, error) {
+func NewLearningPlannerNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*LearningPlannerNode, error) {
 	node := &LearningPlannerNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	// 如果配置了可用的模型服务，初始化 ChatModel
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -43,7 +43,7 @@ func NewLearningPlannerNode(ctx context.Context, cfg config.AIConfig, logger log
 			logger.Info("✅ Learning Planner Agent节点已初始化ChatModel，将使用真实模型")
 		}
 	} else {
-		logger.Info("未配置eino参数，Learning Planner Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Learning Planner Agent节点将使用Mock模式")
 	}
 
 	// 创建消息模板
@@ -54,56 +54,14 @@ func NewLearningPlannerNode(ctx context.Context, cfg config.AIConfig, logger log
 
 // initChatModel 初始化 ChatModel
 func (n *LearningPlannerNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *LearningPlannerNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *LearningPlannerNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -114,6 +72,7 @@ func (n *LearningPlannerNode) initTemplate() {
 - 认知负载建议（简短讲解、类比讲解、深入讲解、反问引导、暂停探索）
 - 当前识别对象
 - 孩子年龄段
+- 孩子的学习档案（之前对话中理解和感兴趣的主题）
 
 你需要决定：
 - 本轮是否继续深入
@@ -122,6 +81,7 @@ func (n *LearningPlannerNode) initTemplate() {
 
 重要规则：
 - 你的输出是【下一步教学动作】，而不是知识本身
+- 孩子已经理解的主题不要重复讲基础知识，优先"问一个问题"或换一个领域Agent拓展
 - 必须严格按照JSON格式返回
 - 不要使用任何工具，只返回JSON结果
 
@@ -134,31 +94,34 @@ func (n *LearningPlannerNode) initTemplate() {
 		schema.UserMessage(`意图判断: {intent}
 认知负载建议: {cognitiveLoadAdvice}
 识别对象: {objectName}（{objectCategory}）
-孩子年龄: {userAge}岁`),
+孩子年龄: {userAge}岁
+学习档案: {learnerProfile}`),
 	)
 }
 
 // PlanLearning 制定学习计划
-func (n *LearningPlannerNode) PlanLearning(ctx context.Context, intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int) (*types.LearningPlanDecision, error) {
+// profile 为孩子的学习档案（可为nil），用于避免重复讲解孩子已经理解的主题
+func (n *LearningPlannerNode) PlanLearning(ctx context.Context, intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int, profile *types.LearnerProfile) (*types.LearningPlanDecision, error) {
 	n.logger.Infow("执行学习计划制定",
 		logx.Field("intent", intentResult.Intent),
 		logx.Field("strategy", cognitiveLoadAdvice.Strategy),
 		logx.Field("objectName", objectName),
 		logx.Field("userAge", userAge),
+		logx.Field("hasProfile", profile != nil),
 		logx.Field("useRealModel", n.initialized),
 	)
 
 	// 如果 ChatModel 已初始化，使用真实模型
-	if n.initialized && n.chatModel != nil {
-		return n.executeReal(ctx, intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+	if n.initialized {
+		return n.executeReal(ctx, intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	// 否则使用 Mock 实现
-	return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+	return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 }
 
 // executeMock Mock实现
-func (n *LearningPlannerNode) executeMock(intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int) (*types.LearningPlanDecision, error) {
+func (n *LearningPlannerNode) executeMock(intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int, profile *types.LearnerProfile) (*types.LearningPlanDecision, error) {
 	// 根据意图选择领域Agent
 	var domainAgent string
 	switch intentResult.Intent {
@@ -177,6 +140,10 @@ func (n *LearningPlannerNode) executeMock(intentResult *types.FollowUpIntentResu
 	if cognitiveLoadAdvice.Strategy == "反问引导" || cognitiveLoadAdvice.Strategy == "暂停探索" {
 		action = "问一个问题"
 	}
+	// 孩子已经理解过该主题，不再重复讲解，改为提问引导
+	if profileUnderstands(profile, objectName) {
+		action = "问一个问题"
+	}
 
 	return &types.LearningPlanDecision{
 		Continue:    true,
@@ -186,17 +153,18 @@ func (n *LearningPlannerNode) executeMock(intentResult *types.FollowUpIntentResu
 }
 
 // executeReal 真实eino实现
-func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int) (*types.LearningPlanDecision, error) {
+func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *types.FollowUpIntentResult, cognitiveLoadAdvice *types.CognitiveLoadAdvice, objectName string, objectCategory string, userAge int, profile *types.LearnerProfile) (*types.LearningPlanDecision, error) {
 	messages, err := n.template.Format(ctx, map[string]any{
 		"intent":              intentResult.Intent,
 		"cognitiveLoadAdvice": cognitiveLoadAdvice.Strategy,
 		"objectName":          objectName,
 		"objectCategory":      objectCategory,
 		"userAge":             userAge,
+		"learnerProfile":      learnerProfileSummary(profile),
 	})
 	if err != nil {
 		n.logger.Errorw("模板格式化失败", logx.Field("error", err))
-		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	// 确保消息格式正确，移除任何可能导致工具调用错误的字段
@@ -216,7 +184,7 @@ func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *typ
 	result, err := n.chatModel.Generate(ctx, cleanMessages)
 	if err != nil {
 		n.logger.Errorw("ChatModel调用失败", logx.Field("error", err))
-		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	// 解析 JSON 结果
@@ -229,10 +197,10 @@ func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *typ
 		jsonStr := text[jsonStart : jsonEnd+1]
 		if err := json.Unmarshal([]byte(jsonStr), &decision); err != nil {
 			n.logger.Errorw("解析JSON失败", logx.Field("error", err), logx.Field("text", text))
-			return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+			return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 		}
 	} else {
-		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	// 验证领域Agent类型
@@ -245,7 +213,7 @@ func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *typ
 		}
 	}
 	if !isValidDomain {
-		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	// 验证动作类型
@@ -258,7 +226,7 @@ func (n *LearningPlannerNode) executeReal(ctx context.Context, intentResult *typ
 		}
 	}
 	if !isValidAction {
-		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge)
+		return n.executeMock(intentResult, cognitiveLoadAdvice, objectName, objectCategory, userAge, profile)
 	}
 
 	n.logger.Infow("学习计划制定完成（真实模型）",
@@ -18,7 +18,7 @@ func TestLearningPlannerNode_PlanLearning(t *testing.T) {
 		AppKey:      "",
 	}
 
-	node, err := NewLearningPlannerNode(ctx, cfg, logger)
+	node, err := NewLearningPlannerNode(ctx, cfg, nil, logger)
 	if err != nil {
 		t.Fatalf("Failed to create LearningPlannerNode: %v", err)
 	}
@@ -59,7 +59,7 @@ func TestLearningPlannerNode_PlanLearning(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			decision, err := node.PlanLearning(ctx, tc.intentResult, tc.cognitiveLoadAdvice, tc.objectName, tc.objectCategory, tc.userAge)
+			decision, err := node.PlanLearning(ctx, tc.intentResult, tc.cognitiveLoadAdvice, tc.objectName, tc.objectCategory, tc.userAge, nil)
 			if err != nil {
 				t.Errorf("PlanLearning failed: %v", err)
 				return
@@ -102,3 +102,35 @@ func TestLearningPlannerNode_PlanLearning(t *testing.T) {
 	}
 }
 
+
+func TestLearningPlannerNode_PlanLearning_LearnerProfile(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.WithContext(ctx)
+
+	node, err := NewLearningPlannerNode(ctx, config.AIConfig{}, nil, logger)
+	if err != nil {
+		t.Fatalf("Failed to create LearningPlannerNode: %v", err)
+	}
+
+	intent := &types.FollowUpIntentResult{Intent: "认知型", Confidence: 0.9}
+	advice := &types.CognitiveLoadAdvice{Strategy: "类比讲解", MaxSentences: 5}
+	profile := &types.LearnerProfile{LearnerId: "child-1", UnderstoodTopics: []string{"银杏"}}
+
+	// 已经理解的主题改为提问引导
+	decision, err := node.PlanLearning(ctx, intent, advice, "银杏", "自然类", 8, profile)
+	if err != nil {
+		t.Fatalf("PlanLearning failed: %v", err)
+	}
+	if decision.Action != "问一个问题" {
+		t.Errorf("Action = %s, want 问一个问题", decision.Action)
+	}
+
+	// 新主题正常讲解
+	decision, err = node.PlanLearning(ctx, intent, advice, "蝴蝶", "自然类", 8, profile)
+	if err != nil {
+		t.Fatalf("PlanLearning failed: %v", err)
+	}
+	if decision.Action != "讲一点" {
+		t.Errorf("Action = %s, want 讲一点", decision.Action)
+	}
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/tango/explore/internal/config"
@@ -16,6 +17,7 @@ type MemoryAgentNode struct {
 	config      config.AIConfig
 	logger      logx.Logger
 	memoryStorage *storage.MemoryAgentStorage
+	learnerStore  storage.LearnerProfileStore // 可选：孩子的学习档案（跨会话）
 }
 
 // NewMemoryAgentNode 创建Memory Agent节点
@@ -31,6 +33,16 @@ func NewMemoryAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Lo
 	return node, nil
 }
 
+// SetMemoryStorage 设置记忆存储（替换创建时传入的存储）
+func (n *MemoryAgentNode) SetMemoryStorage(memoryStorage *storage.MemoryAgentStorage) {
+	n.memoryStorage = memoryStorage
+}
+
+// SetLearnerProfileStore 设置学习档案存储
+func (n *MemoryAgentNode) SetLearnerProfileStore(store storage.LearnerProfileStore) {
+	n.learnerStore = store
+}
+
 // RecordMemory 记录学习状态
 func (n *MemoryAgentNode) RecordMemory(ctx context.Context, sessionId string, reflectionResult *types.ReflectionResult, content string, objectName string) error {
 	n.logger.Infow("执行Memory Agent记忆记录",
@@ -82,3 +94,32 @@ func (n *MemoryAgentNode) GetMemory(ctx context.Context, sessionId string) (*typ
 	return n.memoryStorage.GetMemoryRecord(sessionId)
 }
 
+
+// UpdateLearnerProfile 将会话的记忆记录合并到孩子的学习档案
+// 孩子没有困惑时，把当前对象记为已理解的主题，之后的对话不再重复讲基础知识
+func (n *MemoryAgentNode) UpdateLearnerProfile(ctx context.Context, learnerId string, sessionId string, userAge int, reflectionResult *types.ReflectionResult, objectName string) error {
+	if n.learnerStore == nil || learnerId == "" {
+		return nil
+	}
+
+	record, _ := n.memoryStorage.GetMemoryRecord(sessionId)
+	// 读取、合并和保存由存储原子完成，同一孩子的多个会话在后台同时合并时不会丢失更新
+	profile, err := n.learnerStore.Update(learnerId, func(profile *types.LearnerProfile) {
+		if userAge > 0 {
+			profile.UserAge = userAge
+		}
+		storage.MergeMemoryRecord(profile, record)
+		storage.MarkTopic(profile, objectName, !reflectionResult.Confusion)
+	})
+	if err != nil {
+		return fmt.Errorf("保存学习档案失败: %w", err)
+	}
+
+	n.logger.Infow("学习档案已更新",
+		logx.Field("learnerId", learnerId),
+		logx.Field("sessionId", sessionId),
+		logx.Field("understoodTopics", profile.UnderstoodTopics),
+		logx.Field("interestedTopics", profile.InterestedTopics),
+	)
+	return nil
+}
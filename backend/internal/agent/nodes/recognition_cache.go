@@ -0,0 +1,196 @@
+package nodes
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/utils"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// recognitionCacheTTL 识别结果缓存的有效期
+const recognitionCacheTTL = 24 * time.Hour
+
+// recognitionCacheFetchTimeout 计算缓存键时下载外部图片的超时时间
+const recognitionCacheFetchTimeout = 10 * time.Second
+
+// RecognitionCache 图片识别结果缓存
+// 图片按感知哈希匹配（汉明距离不超过阈值即视为同一物体）；本服务图片存储中的URL按URL精确匹配
+// （上传的图片按内容命名，相同内容的URL相同）。孩子反复拍同一个玩具时不再重复调用模型
+type RecognitionCache struct {
+	mu          sync.Mutex
+	entries     []*recognitionCacheEntry // 按写入顺序排列，超出容量时淘汰最早的
+	size        int
+	maxDistance int
+	now         func() time.Time
+}
+
+type recognitionCacheEntry struct {
+	url       string // 本服务图片存储的URL（按感知哈希匹配时为空）
+	hash      uint64 // 感知哈希（按URL匹配时为0）
+	result    ImageRecognitionResult
+	expiresAt time.Time
+}
+
+// NewRecognitionCache 根据配置创建识别结果缓存，配置为负数时返回nil（不缓存）
+func NewRecognitionCache(cfg config.AIConfig) *RecognitionCache {
+	size := cfg.RecognitionCacheSize
+	if size < 0 {
+		return nil
+	}
+	if size == 0 {
+		size = config.DefaultRecognitionCacheSize
+	}
+	maxDistance := cfg.RecognitionCacheDistance
+	if maxDistance <= 0 {
+		maxDistance = config.DefaultRecognitionCacheDistance
+	}
+	return &RecognitionCache{
+		size:        size,
+		maxDistance: maxDistance,
+		now:         time.Now,
+	}
+}
+
+// recognitionCacheKey 缓存键：本服务图片存储的URL，或图片内容的感知哈希
+type recognitionCacheKey struct {
+	url  string
+	hash uint64
+}
+
+// cacheKeyFor 计算图片的缓存键，图片无法下载或解码时返回false
+// 本服务图片存储的URL内容不变，按URL匹配；其他URL的内容可能变化（如同一地址的摄像头快照），
+// 下载后按内容的感知哈希匹配，同时返回下载内容的data URL，未命中缓存时直接交给模型，不再重复下载
+func (n *ImageRecognitionNode) cacheKeyFor(image string) (recognitionCacheKey, string, bool) {
+	if n.storePrefix != "" && strings.HasPrefix(image, n.storePrefix) {
+		return recognitionCacheKey{url: image}, image, true
+	}
+	if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		if n.fetcher == nil {
+			return recognitionCacheKey{}, image, false
+		}
+		ctx, cancel := context.WithTimeout(n.ctx, recognitionCacheFetchTimeout)
+		defer cancel()
+		data, mimeType, err := n.fetcher.Fetch(ctx, image)
+		if err != nil {
+			n.logger.Debugw("下载图片失败，跳过识别结果缓存",
+				logx.Field("url", image),
+				logx.Field("error", err),
+			)
+			return recognitionCacheKey{}, image, false
+		}
+		key, ok := perceptualCacheKey(data)
+		if !ok {
+			return recognitionCacheKey{}, image, false
+		}
+		if mimeType == "" {
+			mimeType = "image/jpeg"
+		}
+		return key, "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), true
+	}
+
+	payload := image
+	if strings.HasPrefix(image, "data:") {
+		comma := strings.Index(image, ",")
+		if comma < 0 {
+			return recognitionCacheKey{}, image, false
+		}
+		payload = image[comma+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(utils.CleanBase64String(payload))
+	if err != nil {
+		return recognitionCacheKey{}, image, false
+	}
+	key, ok := perceptualCacheKey(data)
+	return key, image, ok
+}
+
+// perceptualCacheKey 按图片内容的感知哈希生成缓存键
+func perceptualCacheKey(data []byte) (recognitionCacheKey, bool) {
+	hash, err := imageproc.PerceptualHash(data)
+	if err != nil {
+		return recognitionCacheKey{}, false
+	}
+	return recognitionCacheKey{hash: hash}, true
+}
+
+// Get 查找缓存的识别结果，按感知哈希匹配时返回汉明距离最小的结果
+func (c *RecognitionCache) Get(key recognitionCacheKey) (*ImageRecognitionResult, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	var best *recognitionCacheEntry
+	bestDistance := c.maxDistance + 1
+	for _, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			continue
+		}
+		if key.url != "" || entry.url != "" {
+			if entry.url == key.url {
+				best = entry
+				break
+			}
+			continue
+		}
+		if distance := imageproc.HammingDistance(entry.hash, key.hash); distance < bestDistance {
+			best, bestDistance = entry, distance
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return copyRecognitionResult(&best.result), true
+}
+
+// Put 写入识别结果，超出容量时淘汰最早的条目
+func (c *RecognitionCache) Put(key recognitionCacheKey, result *ImageRecognitionResult) {
+	if c == nil || result == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entries := c.entries[:0]
+	for _, entry := range c.entries {
+		if now.Before(entry.expiresAt) && !(entry.url == key.url && entry.hash == key.hash) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) >= c.size {
+		entries = entries[len(entries)-c.size+1:]
+	}
+	c.entries = append(entries, &recognitionCacheEntry{
+		url:       key.url,
+		hash:      key.hash,
+		result:    *copyRecognitionResult(result),
+		expiresAt: now.Add(recognitionCacheTTL),
+	})
+}
+
+// copyRecognitionResult 复制识别结果，避免调用方修改缓存中的关键词和候选对象
+func copyRecognitionResult(result *ImageRecognitionResult) *ImageRecognitionResult {
+	copied := *result
+	copied.Keywords = append([]string(nil), result.Keywords...)
+	copied.CategoryPath = append([]string(nil), result.CategoryPath...)
+	copied.Objects = make([]RecognizedObject, len(result.Objects))
+	for i, obj := range result.Objects {
+		obj.Keywords = append([]string(nil), obj.Keywords...)
+		obj.CategoryPath = append([]string(nil), obj.CategoryPath...)
+		if obj.BoundingBox != nil {
+			box := *obj.BoundingBox
+			obj.BoundingBox = &box
+		}
+		copied.Objects[i] = obj
+	}
+	return &copied
+}
@@ -0,0 +1,236 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// countingVisionModel 返回固定识别结果并统计调用次数的ChatModel
+type countingVisionModel struct {
+	calls     int
+	err       error
+	reply     string // 模型回复，为空时返回默认识别结果
+	lastImage string // 最近一次调用收到的图片URL
+}
+
+func (m *countingVisionModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.calls++
+	for _, msg := range input {
+		for _, part := range msg.UserInputMultiContent {
+			if part.Image != nil && part.Image.URL != nil {
+				m.lastImage = *part.Image.URL
+			}
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.reply != "" {
+		return schema.AssistantMessage(m.reply, nil), nil
+	}
+	return schema.AssistantMessage(`{"objectName":"小熊玩偶","objectCategory":"生活类","keywords":["玩具","毛绒"],"confidence":0.93}`, nil), nil
+}
+
+func (m *countingVisionModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *countingVisionModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+// fakeFetcher 按URL返回固定图片数据并统计下载次数的下载器
+type fakeFetcher struct {
+	images map[string][]byte
+	calls  int
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	f.calls++
+	data, ok := f.images[rawURL]
+	if !ok {
+		return nil, "", errors.New("not found")
+	}
+	return data, "image/png", nil
+}
+
+// gradientDataURL 生成渐变图片的data URL，shift用于制造轻微差异
+func gradientDataURL(t *testing.T, width, height, shift int) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(gradientPNG(t, width, height, shift))
+}
+
+// gradientPNG 生成渐变图片，shift用于制造轻微差异
+func gradientPNG(t *testing.T, width, height, shift int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(min(255, x*255/width+shift))
+			img.Set(x, y, color.RGBA{R: v, G: uint8(y * 255 / height), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// checkerPNG 生成棋盘格图片，与渐变图片差异明显
+func checkerPNG(t *testing.T, width, height int) []byte {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x/(width/4)+y/(height/4))%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newCachedRecognitionNode(chatModel model.ChatModel) *ImageRecognitionNode {
+	return &ImageRecognitionNode{
+		ctx:         context.Background(),
+		logger:      logx.WithContext(context.Background()),
+		chatModel:   chatModel,
+		cache:       NewRecognitionCache(config.AIConfig{}),
+		initialized: true,
+	}
+}
+
+func TestImageRecognitionNode_Cache(t *testing.T) {
+	chatModel := &countingVisionModel{}
+	node := newCachedRecognitionNode(chatModel)
+
+	first, err := node.Execute(&GraphData{Image: gradientDataURL(t, 120, 80, 0)})
+	if err != nil || first.ObjectName != "小熊玩偶" {
+		t.Fatalf("Execute = %+v, %v", first, err)
+	}
+	first.Keywords[0] = "被修改"
+
+	// 同一物体再拍一次（尺寸和颜色略有不同），直接返回缓存结果
+	second, err := node.Execute(&GraphData{Image: gradientDataURL(t, 90, 60, 4)})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if chatModel.calls != 1 {
+		t.Errorf("相似图片不应再次调用模型, calls = %d", chatModel.calls)
+	}
+	if second.ObjectName != "小熊玩偶" || second.Keywords[0] != "玩具" {
+		t.Errorf("缓存结果错误: %+v", second)
+	}
+
+	// 本服务图片存储的URL按URL精确匹配，不下载图片
+	fetcher := &fakeFetcher{images: map[string][]byte{}}
+	node.fetcher = fetcher
+	node.SetImageStorePrefix("https://explore.example.com/api/images/")
+	node.Execute(&GraphData{Image: "https://explore.example.com/api/images/a.jpg"})
+	node.Execute(&GraphData{Image: "https://explore.example.com/api/images/a.jpg"})
+	if chatModel.calls != 2 || fetcher.calls != 0 {
+		t.Errorf("图片存储中的相同URL应命中缓存, calls = %d, fetches = %d", chatModel.calls, fetcher.calls)
+	}
+}
+
+func TestImageRecognitionNode_CacheExternalURLByContent(t *testing.T) {
+	chatModel := &countingVisionModel{}
+	node := newCachedRecognitionNode(chatModel)
+	fetcher := &fakeFetcher{images: map[string][]byte{}}
+	node.fetcher = fetcher
+	node.SetImageStorePrefix("https://explore.example.com/api/images/")
+	url := "https://camera.example.com/snapshot.jpg"
+
+	fetcher.images[url] = gradientPNG(t, 120, 80, 0)
+	node.Execute(&GraphData{Image: url})
+
+	// 计算缓存键时下载的内容直接交给模型，不再重复下载
+	if fetcher.calls != 1 {
+		t.Errorf("外部图片应只下载一次, fetches = %d", fetcher.calls)
+	}
+	if want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(fetcher.images[url]); chatModel.lastImage != want {
+		t.Errorf("模型应收到下载内容的data URL, got %.40q", chatModel.lastImage)
+	}
+
+	// 外部URL的内容变化后，不能沿用同一URL的旧识别结果
+	fetcher.images[url] = checkerPNG(t, 120, 80)
+	node.Execute(&GraphData{Image: url})
+	if chatModel.calls != 2 {
+		t.Errorf("外部URL内容变化后应重新识别, calls = %d", chatModel.calls)
+	}
+
+	// 其他URL上的相同内容按感知哈希命中
+	fetcher.images["https://cdn.example.com/copy.png"] = gradientPNG(t, 120, 80, 0)
+	node.Execute(&GraphData{Image: "https://cdn.example.com/copy.png"})
+	if chatModel.calls != 2 {
+		t.Errorf("内容相同的外部图片应命中缓存, calls = %d", chatModel.calls)
+	}
+
+	// 下载失败时不缓存
+	node.Execute(&GraphData{Image: "https://cdn.example.com/missing.png"})
+	node.Execute(&GraphData{Image: "https://cdn.example.com/missing.png"})
+	if chatModel.calls != 4 {
+		t.Errorf("无法下载的图片不应命中缓存, calls = %d", chatModel.calls)
+	}
+}
+
+func TestImageRecognitionNode_CacheSkipsMock(t *testing.T) {
+	chatModel := &countingVisionModel{err: errors.New("model unavailable")}
+	node := newCachedRecognitionNode(chatModel)
+	image := gradientDataURL(t, 120, 80, 0)
+
+	node.Execute(&GraphData{Image: image})
+	node.Execute(&GraphData{Image: image})
+	if chatModel.calls != 2 {
+		t.Errorf("模型失败回退的Mock结果不应缓存, calls = %d", chatModel.calls)
+	}
+}
+
+func TestRecognitionCache_EvictAndExpire(t *testing.T) {
+	cache := NewRecognitionCache(config.AIConfig{RecognitionCacheSize: 2})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	result := &ImageRecognitionResult{ObjectName: "苹果"}
+	cache.Put(recognitionCacheKey{url: "a"}, result)
+	cache.Put(recognitionCacheKey{url: "b"}, result)
+	cache.Put(recognitionCacheKey{url: "c"}, result)
+	if _, ok := cache.Get(recognitionCacheKey{url: "a"}); ok {
+		t.Error("超出容量时应淘汰最早的条目")
+	}
+	if _, ok := cache.Get(recognitionCacheKey{url: "c"}); !ok {
+		t.Error("新条目应命中")
+	}
+
+	// 感知哈希超过阈值不命中
+	cache.Put(recognitionCacheKey{hash: 0}, result)
+	if _, ok := cache.Get(recognitionCacheKey{hash: 0xFF}); ok {
+		t.Error("汉明距离超过阈值不应命中")
+	}
+	if _, ok := cache.Get(recognitionCacheKey{hash: 0x3}); !ok {
+		t.Error("汉明距离在阈值内应命中")
+	}
+
+	now = now.Add(recognitionCacheTTL + time.Minute)
+	if _, ok := cache.Get(recognitionCacheKey{url: "c"}); ok {
+		t.Error("过期条目不应命中")
+	}
+
+	if NewRecognitionCache(config.AIConfig{RecognitionCacheSize: -1}) != nil {
+		t.Error("缓存大小为负数时应关闭缓存")
+	}
+}
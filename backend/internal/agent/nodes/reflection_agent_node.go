@@ -3,14 +3,12 @@ package nodes
 import (
 	"context"
 	"encoding/json"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -21,20 +19,22 @@ type ReflectionAgentNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
+	models      *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel   model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	template    prompt.ChatTemplate // 消息模板
 	initialized bool
 }
 
 // NewReflectionAgentNode 创建Reflection Agent节点
-func NewReflectionAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*ReflectionAgentNode, error) {
+func NewReflectionAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*ReflectionAgentNode, error) {
 	node := &ReflectionAgentNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -42,7 +42,7 @@ func NewReflectionAgentNode(ctx context.Context, cfg config.AIConfig, logger log
 			logger.Info("✅ Reflection Agent节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，Reflection Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Reflection Agent节点将使用Mock模式")
 	}
 
 	node.initTemplate()
@@ -51,56 +51,14 @@ func NewReflectionAgentNode(ctx context.Context, cfg config.AIConfig, logger log
 
 // initChatModel 初始化 ChatModel
 func (n *ReflectionAgentNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleText)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *ReflectionAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *ReflectionAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -136,7 +94,7 @@ func (n *ReflectionAgentNode) Reflect(ctx context.Context, content string, conve
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	if n.initialized && n.chatModel != nil {
+	if n.initialized {
 		return n.executeReal(ctx, content, conversationHistory)
 	}
 
@@ -4,14 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/tools"
 	"github.com/tango/explore/internal/types"
@@ -23,22 +21,24 @@ type ScienceAgentNode struct {
 	ctx         context.Context
 	config      config.AIConfig
 	logger      logx.Logger
-	chatModel   model.ChatModel     // eino ChatModel 实例
-	template    prompt.ChatTemplate // 消息模板
+	models      *llm.ModelFactory          // 模型工厂（按角色创建模型客户端）
+	chatModel   model.ToolCallingChatModel // eino ChatModel（已绑定工具，每次调用从模型池中选择模型）
+	template    prompt.ChatTemplate        // 消息模板
 	toolRegistry *tools.ToolRegistry // 工具注册表
 	initialized bool
 }
 
 // NewScienceAgentNode 创建Science Agent节点
-func NewScienceAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger, toolRegistry *tools.ToolRegistry) (*ScienceAgentNode, error) {
+func NewScienceAgentNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger, toolRegistry *tools.ToolRegistry) (*ScienceAgentNode, error) {
 	node := &ScienceAgentNode{
 		ctx:          ctx,
 		config:       cfg,
 		logger:       logger,
+		models:       models,
 		toolRegistry: toolRegistry,
 	}
 
-	if cfg.EinoBaseURL != "" && cfg.AppID != "" && cfg.AppKey != "" {
+	if models.Enabled(llm.RoleText) {
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式", logx.Field("error", err))
 		} else {
@@ -46,7 +46,7 @@ func NewScienceAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.L
 			logger.Info("✅ Science Agent节点已初始化ChatModel")
 		}
 	} else {
-		logger.Info("未配置eino参数，Science Agent节点将使用Mock模式")
+		logger.Info("未配置可用的模型服务，Science Agent节点将使用Mock模式")
 	}
 
 	node.initTemplate()
@@ -55,39 +55,11 @@ func NewScienceAgentNode(ctx context.Context, cfg config.AIConfig, logger logx.L
 
 // initChatModel 初始化 ChatModel（支持工具调用）
 func (n *ScienceAgentNode) initChatModel(ctx context.Context) error {
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		return nil
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	// 预先创建模型池中的客户端，每次调用时从池中随机选择模型
+	if err := n.models.Preload(ctx, llm.RoleText); err != nil {
 		return err
 	}
+	var chatModel model.ToolCallingChatModel = n.models.RoleModel(llm.RoleText)
 
 	// 注册工具到ChatModel
 	if n.toolRegistry != nil {
@@ -99,10 +71,11 @@ func (n *ScienceAgentNode) initChatModel(ctx context.Context) error {
 			if err != nil {
 				n.logger.Errorw("转换工具信息失败", logx.Field("error", err))
 			} else if len(toolInfos) > 0 {
-				// 绑定工具到ChatModel
-				if err := chatModel.BindTools(toolInfos); err != nil {
+				// 绑定工具到ChatModel（WithTools 返回副本，不修改共享的客户端）
+				if withTools, err := chatModel.WithTools(toolInfos); err != nil {
 					n.logger.Errorw("绑定工具到ChatModel失败", logx.Field("error", err))
 				} else {
+					chatModel = withTools
 					n.logger.Infow("✅ 注册工具到Science Agent ChatModel",
 						logx.Field("tool_count", len(toolInfos)),
 						logx.Field("tools", func() []string {
@@ -122,18 +95,6 @@ func (n *ScienceAgentNode) initChatModel(ctx context.Context) error {
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *ScienceAgentNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // initTemplate 初始化消息模板
 func (n *ScienceAgentNode) initTemplate() {
 	n.template = prompt.FromMessages(schema.FString,
@@ -162,7 +123,7 @@ func (n *ScienceAgentNode) initTemplate() {
 }
 
 // GenerateScienceAnswer 生成科学回答
-func (n *ScienceAgentNode) GenerateScienceAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, maxSentences int, recommendedTools []string) (*types.DomainAgentResponse, error) {
+func (n *ScienceAgentNode) GenerateScienceAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, maxSentences int, recommendedTools []string, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	n.logger.Infow("执行Science Agent回答生成",
 		logx.Field("message", message),
 		logx.Field("objectName", objectName),
@@ -172,15 +133,15 @@ func (n *ScienceAgentNode) GenerateScienceAnswer(ctx context.Context, message st
 		logx.Field("useRealModel", n.initialized),
 	)
 
-	if n.initialized && n.chatModel != nil {
-		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory, maxSentences, recommendedTools)
+	if n.initialized {
+		return n.executeReal(ctx, message, objectName, objectCategory, userAge, chatHistory, maxSentences, recommendedTools, profile)
 	}
 
-	return n.executeMock(message, objectName, userAge, maxSentences)
+	return n.executeMock(message, objectName, userAge, maxSentences, profile)
 }
 
 // executeMock Mock实现
-func (n *ScienceAgentNode) executeMock(message string, objectName string, userAge int, maxSentences int) (*types.DomainAgentResponse, error) {
+func (n *ScienceAgentNode) executeMock(message string, objectName string, userAge int, maxSentences int, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
 	content := "关于" + objectName + "的科学知识很有趣。"
 	if userAge <= 6 {
 		content = objectName + "就像我们身边的朋友一样，有很多有趣的特点。"
@@ -189,6 +150,7 @@ func (n *ScienceAgentNode) executeMock(message string, objectName string, userAg
 	} else {
 		content = objectName + "涉及的科学知识可以深入探索，让我们一起来了解。"
 	}
+	content = personalizeMock(content, profile, objectName)
 
 	return &types.DomainAgentResponse{
 		DomainType:  "Science",
@@ -198,21 +160,51 @@ func (n *ScienceAgentNode) executeMock(message string, objectName string, userAg
 	}, nil
 }
 
-// executeReal 真实eino实现（支持工具调用）
-func (n *ScienceAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, maxSentences int, recommendedTools []string) (*types.DomainAgentResponse, error) {
+// StreamScienceAnswer 流式生成科学回答，文本一生成就写入返回的流
+// 模型请求工具调用时，执行工具后继续流式输出整合后的回答；模型调用失败时降级为Mock回答
+func (n *ScienceAgentNode) StreamScienceAnswer(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, maxSentences int, recommendedTools []string, profile *types.LearnerProfile) (*schema.StreamReader[*schema.Message], error) {
+	n.logger.Infow("执行Science Agent流式回答生成",
+		logx.Field("message", message),
+		logx.Field("objectName", objectName),
+		logx.Field("userAge", userAge),
+		logx.Field("recommendedTools", recommendedTools),
+		logx.Field("useRealModel", n.initialized),
+	)
+
+	if n.initialized {
+		messages := n.buildMessages(message, objectName, objectCategory, chatHistory, recommendedTools, profile)
+		toolChain := NewToolChain(n.toolRegistry, n.logger)
+		stream, err := toolChain.StreamToolChain(ctx, messages, n.chatModel, func(toolsUsed []string, _ map[string]interface{}) {
+			n.logger.Infow("Science Agent流式回答使用了工具", logx.Field("toolsUsed", toolsUsed))
+		})
+		if err == nil {
+			return stream, nil
+		}
+		n.logger.Errorw("ChatModel流式调用失败，降级到Mock模式", logx.Field("error", err))
+	}
+
+	response, err := n.executeMock(message, objectName, userAge, maxSentences, profile)
+	if err != nil {
+		return nil, err
+	}
+	return mockStream(response.Content), nil
+}
+
+// buildMessages 构建发送给ChatModel的消息列表（SystemMessage、对话历史、包含对象信息的用户消息）
+func (n *ScienceAgentNode) buildMessages(message string, objectName string, objectCategory string, chatHistory []*schema.Message, recommendedTools []string, profile *types.LearnerProfile) []*schema.Message {
 	// 根据推荐的工具动态构建SystemMessage
-	systemMessage := n.buildSystemMessageWithTools(recommendedTools)
-	
+	systemMessage := n.buildSystemMessageWithTools(recommendedTools) + learnerProfilePrompt(profile)
+
 	// 构建消息列表
 	messages := []*schema.Message{
 		schema.SystemMessage(systemMessage),
 	}
-	
+
 	// 添加对话历史
 	if len(chatHistory) > 0 {
 		messages = append(messages, chatHistory...)
 	}
-	
+
 	// 添加用户消息（包含对象信息）
 	userMsg := message
 	if objectName != "" {
@@ -232,11 +224,18 @@ func (n *ScienceAgentNode) executeReal(ctx context.Context, message string, obje
 		}
 	}
 
+	return cleanMessages
+}
+
+// executeReal 真实eino实现（支持工具调用）
+func (n *ScienceAgentNode) executeReal(ctx context.Context, message string, objectName string, objectCategory string, userAge int, chatHistory []*schema.Message, maxSentences int, recommendedTools []string, profile *types.LearnerProfile) (*types.DomainAgentResponse, error) {
+	cleanMessages := n.buildMessages(message, objectName, objectCategory, chatHistory, recommendedTools, profile)
+
 	// 调用ChatModel，可能返回工具调用请求
 	result, err := n.chatModel.Generate(ctx, cleanMessages)
 	if err != nil {
 		n.logger.Errorw("ChatModel调用失败", logx.Field("error", err))
-		return n.executeMock(message, objectName, userAge, maxSentences)
+		return n.executeMock(message, objectName, userAge, maxSentences, profile)
 	}
 
 	// 检查是否有工具调用请求
@@ -281,7 +280,9 @@ func (n *ScienceAgentNode) executeReal(ctx context.Context, message string, obje
 			}
 
 			// 执行工具
+			endTool := StartStep(ctx, "Tool")
 			toolResult, err := tool.Execute(ctx, params)
+			endTool(map[string]interface{}{"tool": toolName, "arguments": params}, err)
 			if err != nil {
 				n.logger.Errorw("工具调用失败",
 					logx.Field("tool", toolName),
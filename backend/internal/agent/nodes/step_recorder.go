@@ -0,0 +1,25 @@
+package nodes
+
+import "context"
+
+// StepRecorder 记录Agent节点的执行步骤（开始、结束、决策和耗时）
+type StepRecorder interface {
+	// StartStep 记录节点开始执行，返回结束记录函数
+	// decision 为节点的决策（如意图、策略、选择的领域Agent），err 非nil表示节点失败
+	StartStep(node string) func(decision map[string]interface{}, err error)
+}
+
+type stepRecorderKey struct{}
+
+// WithStepRecorder 将执行步骤记录器放入上下文，节点通过上下文记录自己的执行步骤
+func WithStepRecorder(ctx context.Context, recorder StepRecorder) context.Context {
+	return context.WithValue(ctx, stepRecorderKey{}, recorder)
+}
+
+// StartStep 记录节点开始执行，上下文中没有记录器时返回空操作
+func StartStep(ctx context.Context, node string) func(decision map[string]interface{}, err error) {
+	if recorder, ok := ctx.Value(stepRecorderKey{}).(StepRecorder); ok && recorder != nil {
+		return recorder.StartStep(node)
+	}
+	return func(map[string]interface{}, error) {}
+}
@@ -0,0 +1,41 @@
+package nodes
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// streamBufferSize 流式管道缓冲的消息块数量
+const streamBufferSize = 16
+
+// mockStream 将Mock回答按字拆分为流（用于Mock模式或降级）
+func mockStream(content string) *schema.StreamReader[*schema.Message] {
+	runes := []rune(content)
+	chunks := make([]*schema.Message, 0, len(runes))
+	for _, r := range runes {
+		chunks = append(chunks, &schema.Message{Role: schema.Assistant, Content: string(r)})
+	}
+	return schema.StreamReaderFromArray(chunks)
+}
+
+// CollectStream 读取完整的流并拼接文本内容，读取结束后关闭流
+func CollectStream(stream *schema.StreamReader[*schema.Message]) (string, error) {
+	defer stream.Close()
+
+	var builder strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return builder.String(), nil
+		}
+		if err != nil {
+			return builder.String(), err
+		}
+		if chunk != nil {
+			builder.WriteString(chunk.Content)
+		}
+	}
+}
@@ -47,7 +47,10 @@ func (n *SupervisorNode) Coordinate(ctx context.Context, state *types.Supervisor
 		state.AgentResults = make(map[string]interface{})
 	}
 
+	endSupervisor := StartStep(ctx, "Supervisor")
+
 	// 1. 调用Intent Agent识别意图
+	endIntent := StartStep(ctx, "Intent")
 	intentResult, err := n.intentAgent.RecognizeIntent(ctx, message, chatHistory)
 	if err != nil {
 		n.logger.Errorw("Intent Agent调用失败", logx.Field("error", err))
@@ -58,9 +61,15 @@ func (n *SupervisorNode) Coordinate(ctx context.Context, state *types.Supervisor
 			Reason:     "Intent Agent调用失败，使用默认意图",
 		}
 	}
+	endIntent(map[string]interface{}{
+		"intent":     intentResult.Intent,
+		"confidence": intentResult.Confidence,
+		"reason":     intentResult.Reason,
+	}, err)
 	state.AgentResults["intent"] = intentResult
 
 	// 2. 调用Cognitive Load Agent判断认知负载
+	endCognitiveLoad := StartStep(ctx, "CognitiveLoad")
 	cognitiveLoadAdvice, err := n.cognitiveLoadAgent.AssessCognitiveLoad(ctx, state.UserAge, state.ConversationRounds, state.RecentOutputLength)
 	if err != nil {
 		n.logger.Errorw("Cognitive Load Agent调用失败", logx.Field("error", err))
@@ -71,14 +80,27 @@ func (n *SupervisorNode) Coordinate(ctx context.Context, state *types.Supervisor
 			MaxSentences: 5,
 		}
 	}
+	endCognitiveLoad(map[string]interface{}{
+		"strategy":     cognitiveLoadAdvice.Strategy,
+		"maxSentences": cognitiveLoadAdvice.MaxSentences,
+		"reason":       cognitiveLoadAdvice.Reason,
+	}, err)
 	state.AgentResults["cognitiveLoad"] = cognitiveLoadAdvice
 
 	// 3. 调用Learning Planner Agent制定学习计划
-	decision, err := n.learningPlannerAgent.PlanLearning(ctx, intentResult, cognitiveLoadAdvice, state.ObjectName, state.ObjectCategory, state.UserAge)
+	endLearningPlanner := StartStep(ctx, "LearningPlanner")
+	decision, err := n.learningPlannerAgent.PlanLearning(ctx, intentResult, cognitiveLoadAdvice, state.ObjectName, state.ObjectCategory, state.UserAge, state.LearnerProfile)
 	if err != nil {
 		n.logger.Errorw("Learning Planner Agent调用失败", logx.Field("error", err))
+		endLearningPlanner(nil, err)
+		endSupervisor(nil, err)
 		return nil, err
 	}
+	endLearningPlanner(map[string]interface{}{
+		"continue":    decision.Continue,
+		"domainAgent": decision.DomainAgent,
+		"action":      decision.Action,
+	}, nil)
 	state.AgentResults["learningPlan"] = decision
 
 	// 4. 智能工具选择：根据意图、问题内容和领域Agent选择工具
@@ -94,6 +116,15 @@ func (n *SupervisorNode) Coordinate(ctx context.Context, state *types.Supervisor
 	decision.Tools = selectedTools
 	decision.ToolStrategy = string(toolStrategy)
 
+	endSupervisor(map[string]interface{}{
+		"intent":       intentResult.Intent,
+		"strategy":     cognitiveLoadAdvice.Strategy,
+		"domainAgent":  decision.DomainAgent,
+		"action":       decision.Action,
+		"tools":        selectedTools,
+		"toolStrategy": string(toolStrategy),
+	}, nil)
+
 	n.logger.Infow("Supervisor协调完成",
 		logx.Field("intent", intentResult.Intent),
 		logx.Field("strategy", cognitiveLoadAdvice.Strategy),
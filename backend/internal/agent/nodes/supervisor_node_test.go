@@ -19,9 +19,9 @@ func TestSupervisorNode_Coordinate(t *testing.T) {
 	}
 
 	// 创建子Agent节点
-	intentAgent, _ := NewIntentAgentNode(ctx, cfg, logger)
-	cognitiveLoadAgent, _ := NewCognitiveLoadNode(ctx, cfg, logger)
-	learningPlannerAgent, _ := NewLearningPlannerNode(ctx, cfg, logger)
+	intentAgent, _ := NewIntentAgentNode(ctx, cfg, nil, logger)
+	cognitiveLoadAgent, _ := NewCognitiveLoadNode(ctx, cfg, nil, logger)
+	learningPlannerAgent, _ := NewLearningPlannerNode(ctx, cfg, nil, logger)
 
 	supervisor, err := NewSupervisorNode(ctx, cfg, logger, intentAgent, cognitiveLoadAgent, learningPlannerAgent)
 	if err != nil {
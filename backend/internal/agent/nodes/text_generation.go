@@ -3,16 +3,16 @@ package nodes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/agent/llm"
+	"github.com/tango/explore/internal/cards"
 	"github.com/tango/explore/internal/config"
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -22,32 +22,29 @@ type TextGenerationNode struct {
 	ctx             context.Context
 	config          config.AIConfig
 	logger          logx.Logger
-	chatModel       model.ChatModel     // eino ChatModel 实例
+	models          *llm.ModelFactory   // 模型工厂（按角色创建模型客户端）
+	chatModel       model.BaseChatModel // eino ChatModel（每次调用从模型池中选择模型）
 	scienceTemplate prompt.ChatTemplate // 科学认知卡模板
 	poetryTemplate  prompt.ChatTemplate // 古诗词卡模板
 	englishTemplate prompt.ChatTemplate // 英语表达卡模板
+	cardTemplate    prompt.ChatTemplate // 其他卡片类型的通用模板（字段说明由卡片Schema生成）
 	textTemplate    prompt.ChatTemplate // 文本回答模板
 	initialized     bool
 }
 
 // NewTextGenerationNode 创建文本生成节点
-func NewTextGenerationNode(ctx context.Context, cfg config.AIConfig, logger logx.Logger) (*TextGenerationNode, error) {
+func NewTextGenerationNode(ctx context.Context, cfg config.AIConfig, models *llm.ModelFactory, logger logx.Logger) (*TextGenerationNode, error) {
 	node := &TextGenerationNode{
 		ctx:    ctx,
 		config: cfg,
 		logger: logger,
+		models: models,
 	}
 
-	// 如果配置了 eino 相关参数，初始化 ChatModel
-	hasEinoBaseURL := cfg.EinoBaseURL != ""
-	hasAppID := cfg.AppID != ""
-	hasAppKey := cfg.AppKey != ""
-
-	if hasEinoBaseURL && hasAppID && hasAppKey {
-		logger.Infow("检测到eino配置，尝试初始化ChatModel",
-			logx.Field("einoBaseURL", cfg.EinoBaseURL),
-			logx.Field("appID", hasAppID),
-			logx.Field("hasAppKey", hasAppKey),
+	// 如果配置了可用的模型服务，初始化 ChatModel
+	if models.Enabled(llm.RoleCard) {
+		logger.Infow("检测到模型配置，尝试初始化ChatModel",
+			logx.Field("provider", models.Provider(llm.RoleCard)),
 		)
 		if err := node.initChatModel(ctx); err != nil {
 			logger.Errorw("初始化ChatModel失败，将使用Mock模式",
@@ -60,12 +57,10 @@ func NewTextGenerationNode(ctx context.Context, cfg config.AIConfig, logger logx
 			logger.Info("✅ 文本生成节点已初始化ChatModel，将使用真实模型")
 		}
 	} else {
-		logger.Errorw("未完整配置eino参数，文本生成节点将使用Mock模式",
-			logx.Field("hasEinoBaseURL", hasEinoBaseURL),
-			logx.Field("hasAppID", hasAppID),
-			logx.Field("hasAppKey", hasAppKey),
+		logger.Errorw("未配置可用的模型服务，文本生成节点将使用Mock模式",
+			logx.Field("provider", models.Provider(llm.RoleCard)),
 		)
-		logger.Info("提示：需要同时配置 EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY 才能使用真实模型")
+		logger.Info("提示：需要同时配置 EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY，或通过 CARD_MODEL_PROVIDER 等配置其他模型服务，才能使用真实模型")
 	}
 
 	// 检查配置中的UseAIModel设置
@@ -86,69 +81,23 @@ func NewTextGenerationNode(ctx context.Context, cfg config.AIConfig, logger logx
 
 // initChatModel 初始化 ChatModel（使用随机选择的模型）
 func (n *TextGenerationNode) initChatModel(ctx context.Context) error {
-	// 从配置中随机选择一个文本生成模型
-	modelName := n.selectRandomModel(n.config.TextGenerationModels)
-	if modelName == "" {
-		models := config.GetDefaultTextGenerationModels()
-		if len(models) > 0 {
-			modelName = n.selectRandomModel(models)
-		}
-		if modelName == "" {
-			modelName = config.DefaultTextGenerationModel
-		}
-	}
-
-	cfg := &ark.ChatModelConfig{
-		Model: modelName,
-	}
-
-	if n.config.EinoBaseURL != "" {
-		cfg.BaseURL = n.config.EinoBaseURL
-	}
-
-	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
-	if n.config.AppID != "" && n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppID + ":" + n.config.AppKey
-	} else if n.config.AppKey != "" {
-		cfg.APIKey = n.config.AppKey
-	} else if n.config.AppID != "" {
-		cfg.APIKey = n.config.AppID
-	} else {
-		// 如果缺少认证信息，返回错误而不是nil，让调用方知道初始化失败
-		return fmt.Errorf("缺少认证信息：需要配置 AppID 或 AppKey")
-	}
-
-	chatModel, err := ark.NewChatModel(ctx, cfg)
-	if err != nil {
+	if err := n.models.Preload(ctx, llm.RoleCard); err != nil {
 		n.logger.Errorw("创建ChatModel失败",
 			logx.Field("error", err),
-			logx.Field("modelName", modelName),
-			logx.Field("baseURL", cfg.BaseURL),
+			logx.Field("models", n.models.Models(llm.RoleCard)),
+			logx.Field("provider", n.models.Provider(llm.RoleCard)),
 		)
 		return fmt.Errorf("创建ChatModel失败: %w", err)
 	}
 
-	n.chatModel = chatModel
+	n.chatModel = n.models.RoleModel(llm.RoleCard)
 	n.logger.Infow("ChatModel初始化成功",
-		logx.Field("modelName", modelName),
-		logx.Field("baseURL", cfg.BaseURL),
+		logx.Field("models", n.models.Models(llm.RoleCard)),
+		logx.Field("provider", n.models.Provider(llm.RoleCard)),
 	)
 	return nil
 }
 
-// selectRandomModel 从模型列表中随机选择一个模型
-func (n *TextGenerationNode) selectRandomModel(models []string) string {
-	if len(models) == 0 {
-		return ""
-	}
-	if len(models) == 1 {
-		return models[0]
-	}
-	// 使用当前时间作为随机种子
-	rand.Seed(time.Now().UnixNano())
-	return models[rand.Intn(len(models))]
-}
-
 // getAgePrompt 根据年龄生成对应的prompt要求
 func (n *TextGenerationNode) getAgePrompt(age int, cardType string) string {
 	var agePrompt string
@@ -241,9 +190,62 @@ func (n *TextGenerationNode) getAgePrompt(age int, cardType string) string {
 		}
 	}
 
+	// 其他卡片类型：按类型的内容要求加上年龄段的整体要求
+	if agePrompt == "" {
+		if requirements, ok := cardRequirements[cardType]; ok {
+			agePrompt = "要求：\n" + requirements + "\n" + ageLevelRequirement(age)
+		}
+	}
+
 	return agePrompt
 }
 
+// cardRequirements 通用模板生成的卡片类型的内容要求
+var cardRequirements = map[string]string{
+	cards.TypeMath: `1. 从{objectName}中找到一个数学概念（如数量、形状、大小比较、规律、比例）
+2. 用生活化的语言解释这个概念
+3. 出一道围绕{objectName}的数学小题，难度符合孩子的年级
+4. 给出答案，并一步一步说明解题思路`,
+	cards.TypeHistory: `1. 讲述与{objectName}相关的一段真实历史（如起源、发明、在古代的用途）
+2. 标注大致的年代或朝代
+3. 提供2-3个历史小知识
+4. 说明这段历史对今天的影响
+5. 内容必须真实，不要编造人物和事件`,
+	cards.TypeArt: `1. 找到与{objectName}相关的艺术作品或艺术形式（绘画、音乐、雕塑、民间艺术等）
+2. 能确定时标注作者或出处，不确定时不要编造
+3. 用孩子能理解的语言赏析作品的颜色、形状或情感
+4. 设计一个可以在家动手尝试的创作活动`,
+	cards.TypeSafety: `1. 说明接触或使用{objectName}时需要注意的危险；{objectName}本身很安全时，说明相关场景中的安全注意事项
+2. 给出2-4条具体、可执行的安全要点
+3. 说明遇到危险时应该怎么做（如马上告诉大人、拨打急救电话）
+4. 语气要温和，不要吓唬孩子`,
+	cards.TypeCharacter: `1. 从{objectName}的名称中选一个适合学习的汉字
+2. 标注带声调的拼音
+3. 解释字义，并说明字形来源或造字方法（象形、会意、形声等）
+4. 提供3-4个常用组词`,
+}
+
+// ageLevelRequirement 通用模板卡片按年龄段的整体要求
+func ageLevelRequirement(age int) string {
+	switch {
+	case age <= 6:
+		return "整体要求：内容符合3-6岁孩子的认知水平，用最简单、最生动的语言，避免专业术语，可以适当使用emoji但不要过多"
+	case age <= 12:
+		return "整体要求：内容符合7-12岁孩子的认知水平，结合生活实际，可以使用基础术语，可以适当使用emoji但不要过多"
+	default:
+		return "整体要求：内容符合13-18岁学生的认知水平，讲解准确、有深度，可以引导思考，可以适当使用emoji但不要过多"
+	}
+}
+
+// cardTitles 通用模板生成的卡片类型的标题
+var cardTitles = map[string]func(objectName string) string{
+	cards.TypeMath:      func(objectName string) string { return objectName + "里的数学" },
+	cards.TypeHistory:   func(objectName string) string { return objectName + "的历史故事" },
+	cards.TypeArt:       func(objectName string) string { return "艺术中的" + objectName },
+	cards.TypeSafety:    func(objectName string) string { return objectName + "安全小知识" },
+	cards.TypeCharacter: func(objectName string) string { return "从" + objectName + "认汉字" },
+}
+
 // initTemplates 初始化所有消息模板
 func (n *TextGenerationNode) initTemplates() {
 	// 科学认知卡模板（使用动态prompt，根据年龄调整）
@@ -293,6 +295,19 @@ func (n *TextGenerationNode) initTemplates() {
 		schema.UserMessage("请为{objectName}生成英语表达卡片内容，适合{age}岁孩子。"),
 	)
 
+	// 其他卡片类型的通用模板（卡片名称、字段说明按卡片类型填入）
+	n.cardTemplate = prompt.FromMessages(schema.FString,
+		schema.SystemMessage(`你是一个K12教育内容生成助手，专门为{age}岁的孩子生成{cardName}内容。
+
+{agePrompt}
+
+请返回JSON格式，包含以下字段：
+{fields}
+
+注意：emoji要适量使用，不要过多，保持内容的可读性。`),
+		schema.UserMessage("请为{objectName}生成{cardName}内容，适合{age}岁孩子。"),
+	)
+
 	// 文本回答模板
 	n.textTemplate = prompt.FromMessages(schema.FString,
 		schema.SystemMessage("你是一个友好的K12教育助手，用简单易懂的语言回答孩子的问题。适当使用emoji表情符号（如 🌟 ✨ 💡 🔍 📚 🎨 🌈 🦋 🌸 ⭐ 等）让回答更生动有趣，适合小朋友阅读。注意：emoji要适量，不要过多，避免影响阅读体验。"),
@@ -310,13 +325,6 @@ func (n *TextGenerationNode) GenerateText(data *GraphData, context []interface{}
 	)
 
 	if n.initialized {
-		// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-		if err := n.initChatModel(n.ctx); err != nil {
-			n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-				logx.Field("error", err),
-			)
-			// 如果重新初始化失败，继续使用已初始化的模型
-		}
 		if n.chatModel != nil {
 			return n.generateTextReal(data, context)
 		}
@@ -335,13 +343,6 @@ func (n *TextGenerationNode) GenerateScienceCard(ctx context.Context, data *Grap
 	)
 
 	if n.initialized {
-		// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-		if err := n.initChatModel(ctx); err != nil {
-			n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-				logx.Field("error", err),
-			)
-			// 如果重新初始化失败，继续使用已初始化的模型
-		}
 		if n.chatModel != nil {
 			n.logger.Infow("尝试使用真实模型生成科学认知卡",
 				logx.Field("objectName", data.ObjectName),
@@ -380,13 +381,6 @@ func (n *TextGenerationNode) GeneratePoetryCard(ctx context.Context, data *Graph
 	)
 
 	if n.initialized {
-		// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-		if err := n.initChatModel(ctx); err != nil {
-			n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-				logx.Field("error", err),
-			)
-			// 如果重新初始化失败，继续使用已初始化的模型
-		}
 		if n.chatModel != nil {
 			n.logger.Infow("尝试使用真实模型生成古诗词卡",
 				logx.Field("objectName", data.ObjectName),
@@ -425,13 +419,6 @@ func (n *TextGenerationNode) GenerateEnglishCard(ctx context.Context, data *Grap
 	)
 
 	if n.initialized {
-		// 每次调用时重新初始化 ChatModel，使用随机选择的模型
-		if err := n.initChatModel(ctx); err != nil {
-			n.logger.Errorw("重新初始化ChatModel失败，使用已初始化的模型",
-				logx.Field("error", err),
-			)
-			// 如果重新初始化失败，继续使用已初始化的模型
-		}
 		if n.chatModel != nil {
 			n.logger.Infow("尝试使用真实模型生成英语表达卡",
 				logx.Field("objectName", data.ObjectName),
@@ -460,6 +447,113 @@ func (n *TextGenerationNode) GenerateEnglishCard(ctx context.Context, data *Grap
 	return n.generateEnglishCardMock(data)
 }
 
+// GenerateCard 使用通用模板生成卡片（数学、历史、艺术、安全知识、汉字等）
+func (n *TextGenerationNode) GenerateCard(ctx context.Context, cardType string, data *GraphData) (map[string]interface{}, error) {
+	title, ok := cardTitles[cardType]
+	if !ok {
+		return nil, fmt.Errorf("未知的卡片类型: %s", cardType)
+	}
+	n.logger.Infow("生成卡片",
+		logx.Field("cardType", cardType),
+		logx.Field("objectName", data.ObjectName),
+		logx.Field("age", data.Age),
+		logx.Field("useRealModel", n.initialized),
+	)
+
+	if !n.initialized || n.chatModel == nil {
+		n.logger.Errorw("使用Mock模式生成卡片（ChatModel未初始化）",
+			logx.Field("cardType", cardType),
+			logx.Field("objectName", data.ObjectName),
+		)
+		return MockCard(cardType, data.ObjectName), nil
+	}
+
+	cardContent, err := n.generateCardWithRetry(ctx, cardType, data, n.cardTemplate, 1)
+	if err != nil {
+		n.logger.Errorw("真实模型生成卡片失败，返回错误",
+			logx.Field("cardType", cardType),
+			logx.Field("objectName", data.ObjectName),
+			logx.Field("error", err),
+		)
+		return nil, err
+	}
+	n.logger.Infow("✅ 卡片生成完成（真实模型）",
+		logx.Field("cardType", cardType),
+		logx.Field("objectName", data.ObjectName),
+	)
+	return map[string]interface{}{
+		"type":    cardType,
+		"title":   title(data.ObjectName),
+		"content": cardContent,
+	}, nil
+}
+
+// MockCard 通用模板卡片类型的Mock卡片（type、title、content），未知类型返回 nil
+func MockCard(cardType, objectName string) map[string]interface{} {
+	title, ok := cardTitles[cardType]
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		"type":    cardType,
+		"title":   title(objectName),
+		"content": mockCardContent(cardType, objectName),
+	}
+}
+
+// mockCardContent 通用模板卡片类型的Mock内容
+func mockCardContent(cardType, objectName string) map[string]interface{} {
+	var content interface{}
+	switch cardType {
+	case cards.TypeMath:
+		content = cards.MathCard{
+			SchemaVersion: cards.MathSchemaVersion,
+			Concept:       "数一数 🔢",
+			Explanation:   "我们可以数一数身边的" + objectName + "，数数是最基础的数学本领 ✨。",
+			Problem:       "桌上有3个" + objectName + "，又拿来2个，一共有几个？",
+			Answer:        "3 + 2 = 5，一共有5个 🎉。",
+		}
+	case cards.TypeHistory:
+		content = cards.HistoryCard{
+			SchemaVersion: cards.HistorySchemaVersion,
+			Era:           "很久很久以前",
+			Story:         "人们很早就认识了" + objectName + "，关于它的故事一代代流传下来 📜。",
+			Facts:         []string{"关于" + objectName + "的历史小知识 🏛️"},
+			Significance:  "了解" + objectName + "的历史，能帮我们更好地认识今天的生活 ✨。",
+		}
+	case cards.TypeArt:
+		content = cards.ArtCard{
+			SchemaVersion: cards.ArtSchemaVersion,
+			Artwork:       "以" + objectName + "为主题的画作 🎨",
+			Description:   "画家用明亮的颜色表现" + objectName + "的样子 🌈。",
+			Activity:      "拿起画笔，画一画你眼中的" + objectName + " 🖍️。",
+		}
+	case cards.TypeSafety:
+		content = cards.SafetyCard{
+			SchemaVersion: cards.SafetySchemaVersion,
+			Risks:         []string{"不认识的东西不要放进嘴里 ⚠️"},
+			Tips:          []string{"观察" + objectName + "时要有大人陪同 👨‍👩‍👧", "看完记得洗手 🧼"},
+			Emergency:     "感觉不舒服时，马上告诉身边的大人 🆘。",
+		}
+	case cards.TypeCharacter:
+		character := objectName
+		if runes := []rune(objectName); len(runes) > 0 {
+			character = string(runes[0])
+		}
+		content = cards.CharacterCard{
+			SchemaVersion: cards.CharacterSchemaVersion,
+			Character:     character,
+			Pinyin:        "pīn yīn",
+			Meaning:       "“" + character + "”是" + objectName + "名字里的字 📝。",
+			Origin:        "这个字的字形来源等待我们去探索 🔍。",
+			Words:         []string{objectName},
+		}
+	default:
+		return nil
+	}
+	return cards.ToMap(content)
+}
+
 // generateTextMock Mock实现（待替换为真实eino调用）
 func (n *TextGenerationNode) generateTextMock(data *GraphData, context []interface{}) (string, error) {
 	// Mock文本响应
@@ -489,15 +583,16 @@ func (n *TextGenerationNode) generateScienceCardMock(data *GraphData) (map[strin
 	card := map[string]interface{}{
 		"type":  "science",
 		"title": data.ObjectName + "的科学知识",
-		"content": map[string]interface{}{
-			"name":        data.ObjectName,
-			"explanation": explanation,
-			"facts": []string{
+		"content": cards.ToMap(cards.ScienceCard{
+			SchemaVersion: cards.ScienceSchemaVersion,
+			Name:          data.ObjectName,
+			Explanation:   explanation,
+			Facts: []string{
 				"关于" + data.ObjectName + "的有趣事实1 💡",
 				"关于" + data.ObjectName + "的有趣事实2 🔍",
 			},
-			"funFact": "关于" + data.ObjectName + "的趣味知识 🎉！",
-		},
+			FunFact: "关于" + data.ObjectName + "的趣味知识 🎉！",
+		}),
 	}
 
 	n.logger.Info("科学认知卡生成完成（Mock）")
@@ -532,12 +627,13 @@ func (n *TextGenerationNode) generatePoetryCardMock(data *GraphData) (map[string
 	card := map[string]interface{}{
 		"type":  "poetry",
 		"title": "古人怎么看" + data.ObjectName,
-		"content": map[string]interface{}{
-			"poem":        poem,
-			"poemSource":  "古诗词",
-			"explanation": "这句诗描写了" + data.ObjectName + "的美丽景象 🌸，让我们感受到古人的智慧和情感 ✨。",
-			"context":     "看到" + data.ObjectName + "，我们可以联想到相关的文化和历史 🏛️，丰富我们的认知 📚。",
-		},
+		"content": cards.ToMap(cards.PoetryCard{
+			SchemaVersion: cards.PoetrySchemaVersion,
+			Poem:          poem,
+			PoemSource:    "古诗词",
+			Explanation:   "这句诗描写了" + data.ObjectName + "的美丽景象 🌸，让我们感受到古人的智慧和情感 ✨。",
+			Context:       "看到" + data.ObjectName + "，我们可以联想到相关的文化和历史 🏛️，丰富我们的认知 📚。",
+		}),
 	}
 
 	n.logger.Infow("古诗词卡生成完成（Mock）",
@@ -569,14 +665,15 @@ func (n *TextGenerationNode) generateEnglishCardMock(data *GraphData) (map[strin
 	card := map[string]interface{}{
 		"type":  "english",
 		"title": "用英语说" + data.ObjectName,
-		"content": map[string]interface{}{
-			"keywords": kw,
-			"expressions": []string{
+		"content": cards.ToMap(cards.EnglishCard{
+			SchemaVersion: cards.EnglishSchemaVersion,
+			Keywords:      kw,
+			Expressions: []string{
 				"This is " + kw[0] + ".",
 				"I like " + kw[0] + ".",
 			},
-			"pronunciation": kw[0] + ": /pronunciation/",
-		},
+			Pronunciation: kw[0] + ": /pronunciation/",
+		}),
 	}
 
 	n.logger.Info("英语表达卡生成完成（Mock）")
@@ -628,11 +725,12 @@ func (n *TextGenerationNode) parseJSONFromResponse(text string) (map[string]inte
 	return nil, fmt.Errorf("未找到有效的JSON内容")
 }
 
-// generateCardWithRetry 生成卡片并支持JSON解析失败时的快速重试
-// maxRetries: 最大重试次数（不包括首次调用）
+// generateCardWithRetry 生成卡片内容并按卡片结构校验
+// 返回内容无法解析或不符合结构时，把上次的输出和具体问题发给模型，要求按Schema修正（不是原样重试）
+// maxRetries: 最大修正次数（不包括首次调用）
 func (n *TextGenerationNode) generateCardWithRetry(
 	ctx context.Context,
-	cardType string, // "science", "poetry", "english"
+	cardType string, // "science", "poetry", "english", "math" 等
 	data *GraphData,
 	template prompt.ChatTemplate,
 	maxRetries int,
@@ -640,54 +738,43 @@ func (n *TextGenerationNode) generateCardWithRetry(
 	var lastErr error
 	var lastContent string
 
-	// 根据卡片类型获取年龄prompt
-	var agePrompt string
-	switch cardType {
-	case "science":
-		agePrompt = n.getAgePrompt(data.Age, "science")
-	case "poetry":
-		agePrompt = n.getAgePrompt(data.Age, "poetry")
-	case "english":
-		agePrompt = n.getAgePrompt(data.Age, "english")
-	default:
+	// 根据卡片类型获取年龄prompt（模板只替换一层变量，要求中的对象名称在这里替换）
+	agePrompt := n.getAgePrompt(data.Age, cardType)
+	if agePrompt == "" {
 		return nil, fmt.Errorf("未知的卡片类型: %s", cardType)
 	}
+	agePrompt = strings.ReplaceAll(agePrompt, "{objectName}", data.ObjectName)
+	fields, err := cards.FieldsPrompt(cardType)
+	if err != nil {
+		return nil, err
+	}
 
-	// 重试逻辑：首次调用 + 最多maxRetries次重试
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// 每次重试都重新构建消息，避免消息累积
-		messages, err := template.Format(ctx, map[string]any{
-			"objectName": data.ObjectName,
-			"age":        strconv.Itoa(data.Age),
-			"agePrompt":  agePrompt,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("模板格式化失败: %w", err)
-		}
+	messages, err := template.Format(ctx, map[string]any{
+		"objectName": data.ObjectName,
+		"age":        strconv.Itoa(data.Age),
+		"agePrompt":  agePrompt,
+		"cardName":   cards.Name(cardType),
+		"fields":     fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("模板格式化失败: %w", err)
+	}
 
+	// 首次调用 + 最多maxRetries次修正
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			// 重试时，在用户消息中添加强调JSON格式的提示
-			// 在最后一条用户消息后添加强调
-			if len(messages) > 0 {
-				lastMsg := messages[len(messages)-1]
-				if lastMsg.Role == schema.User {
-					// 创建新的强调消息
-					emphasisMsg := &schema.Message{
-						Role:    schema.User,
-						Content: "重要：请严格按照JSON格式返回，不要包含任何其他文本说明。",
-					}
-					messages = append(messages, emphasisMsg)
-				}
+			repairMsgs, err := n.cardRepairMessages(cardType, lastContent, lastErr)
+			if err != nil {
+				return nil, err
 			}
-			n.logger.Infow("JSON解析失败，快速重试模型调用",
+			messages = append(messages, repairMsgs...)
+			n.logger.Infow("卡片内容不符合要求，要求模型修正",
 				logx.Field("cardType", cardType),
 				logx.Field("objectName", data.ObjectName),
 				logx.Field("attempt", attempt),
 				logx.Field("maxRetries", maxRetries),
 				logx.Field("lastError", lastErr),
 			)
-			// 短暂延迟，避免立即重试
-			time.Sleep(100 * time.Millisecond)
 		}
 
 		// 调用模型
@@ -699,21 +786,18 @@ func (n *TextGenerationNode) generateCardWithRetry(
 				logx.Field("attempt", attempt),
 				logx.Field("error", err),
 			)
-			// 如果是模型调用错误（非JSON解析错误），直接返回
-			if attempt == 0 {
-				return nil, fmt.Errorf("ChatModel调用失败: %w", err)
-			}
-			// 重试时的模型调用错误，继续重试
-			lastErr = fmt.Errorf("ChatModel调用失败: %w", err)
-			continue
+			// 模型调用错误（模型池已经切换过候选模型）不再修正，直接返回
+			return nil, fmt.Errorf("ChatModel调用失败: %w", err)
 		}
 
-		// 尝试解析JSON
+		// 解析JSON并按卡片结构校验
 		cardContent, parseErr := n.parseJSONFromResponse(result.Content)
 		if parseErr == nil {
-			// 解析成功
+			cardContent, parseErr = cards.Validate(cardType, cardContent)
+		}
+		if parseErr == nil {
 			if attempt > 0 {
-				n.logger.Infow("重试成功，JSON解析通过",
+				n.logger.Infow("修正成功，卡片内容校验通过",
 					logx.Field("cardType", cardType),
 					logx.Field("objectName", data.ObjectName),
 					logx.Field("attempt", attempt),
@@ -722,10 +806,10 @@ func (n *TextGenerationNode) generateCardWithRetry(
 			return cardContent, nil
 		}
 
-		// JSON解析失败，记录错误并准备重试
+		// 解析或校验失败，记录错误并准备修正
 		lastErr = parseErr
 		lastContent = result.Content
-		n.logger.Infow("JSON解析失败，准备重试",
+		n.logger.Infow("卡片内容解析或校验失败，准备修正",
 			logx.Field("cardType", cardType),
 			logx.Field("objectName", data.ObjectName),
 			logx.Field("attempt", attempt),
@@ -739,8 +823,8 @@ func (n *TextGenerationNode) generateCardWithRetry(
 		)
 	}
 
-	// 所有重试都失败
-	n.logger.Errorw("所有重试均失败，JSON解析失败",
+	// 所有修正都失败
+	n.logger.Errorw("卡片内容修正均失败",
 		logx.Field("cardType", cardType),
 		logx.Field("objectName", data.ObjectName),
 		logx.Field("maxRetries", maxRetries),
@@ -752,7 +836,36 @@ func (n *TextGenerationNode) generateCardWithRetry(
 			return lastContent
 		}()),
 	)
-	return nil, fmt.Errorf("JSON解析失败（已重试%d次）: %w, 最后返回内容: %s", maxRetries, lastErr, lastContent)
+	return nil, fmt.Errorf("卡片内容不符合要求（已修正%d次）: %w, 最后返回内容: %s", maxRetries, lastErr, lastContent)
+}
+
+// cardRepairMessages 构建修正消息：上次的输出作为助手消息，随后列出具体问题和卡片的JSON Schema
+func (n *TextGenerationNode) cardRepairMessages(cardType, lastContent string, lastErr error) ([]*schema.Message, error) {
+	contentSchema, err := cards.SchemaJSON(cardType)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems string
+	var validationErr *cards.ValidationError
+	if errors.As(lastErr, &validationErr) {
+		problems = "- " + strings.Join(validationErr.Issues, "\n- ")
+	} else {
+		problems = "- 无法解析为JSON对象：" + lastErr.Error()
+	}
+
+	repair := fmt.Sprintf(`你上次返回的内容不符合卡片格式要求，问题如下：
+%s
+
+请只修正上述问题，其他内容保持不变，严格按照下面的JSON Schema返回完整的JSON对象，不要包含任何其他文本说明：
+%s`, problems, contentSchema)
+	if strings.TrimSpace(lastContent) == "" {
+		return []*schema.Message{schema.UserMessage(repair)}, nil
+	}
+	return []*schema.Message{
+		schema.AssistantMessage(lastContent, nil),
+		schema.UserMessage(repair),
+	}, nil
 }
 
 // generateScienceCardReal 真实eino实现科学认知卡
@@ -0,0 +1,118 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// scriptedCardModel 按顺序返回预设回复并记录每次调用的输入
+type scriptedCardModel struct {
+	replies []string
+	inputs  [][]*schema.Message
+}
+
+func (m *scriptedCardModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.inputs = append(m.inputs, input)
+	if len(m.inputs) > len(m.replies) {
+		return nil, errors.New("没有更多预设回复")
+	}
+	return schema.AssistantMessage(m.replies[len(m.inputs)-1], nil), nil
+}
+
+func (m *scriptedCardModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func newScriptedTextNode(chatModel model.BaseChatModel) *TextGenerationNode {
+	node := &TextGenerationNode{
+		ctx:         context.Background(),
+		logger:      logx.WithContext(context.Background()),
+		chatModel:   chatModel,
+		initialized: true,
+	}
+	node.initTemplates()
+	return node
+}
+
+func TestGenerateCardWithRetry_RepairsInvalidCard(t *testing.T) {
+	chatModel := &scriptedCardModel{replies: []string{
+		`{"name":"银杏","explanation":"银杏是古老的植物。","facts":"叶子像扇子"}`,
+		`{"name":"银杏","explanation":"银杏是古老的植物。","facts":["叶子像扇子"],"funFact":"银杏是活化石！"}`,
+	}}
+	node := newScriptedTextNode(chatModel)
+
+	content, err := node.generateCardWithRetry(context.Background(), "science", &GraphData{ObjectName: "银杏", Age: 8}, node.scienceTemplate, 1)
+	if err != nil {
+		t.Fatalf("generateCardWithRetry() error = %v", err)
+	}
+	if content["schemaVersion"] != float64(1) || content["funFact"] != "银杏是活化石！" {
+		t.Errorf("content = %v", content)
+	}
+
+	if len(chatModel.inputs) != 2 {
+		t.Fatalf("模型调用次数 = %d, want 2", len(chatModel.inputs))
+	}
+	repair := chatModel.inputs[1]
+	if len(repair) != len(chatModel.inputs[0])+2 {
+		t.Fatalf("修正请求应在原对话后追加上次输出和修正要求，got %d 条消息", len(repair))
+	}
+	if repair[len(repair)-2].Role != schema.Assistant || repair[len(repair)-2].Content != chatModel.replies[0] {
+		t.Error("修正请求应包含上次的输出")
+	}
+	prompt := repair[len(repair)-1].Content
+	for _, want := range []string{"缺少字段 funFact", "字段 facts 应为数组", `"required"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("修正提示缺少 %q: %s", want, prompt)
+		}
+	}
+}
+
+func TestGenerateCardWithRetry_GivesUpAfterRetries(t *testing.T) {
+	chatModel := &scriptedCardModel{replies: []string{"抱歉，我不知道。", `{"poem":""}`}}
+	node := newScriptedTextNode(chatModel)
+
+	_, err := node.generateCardWithRetry(context.Background(), "poetry", &GraphData{ObjectName: "月亮", Age: 8}, node.poetryTemplate, 1)
+	if err == nil {
+		t.Fatal("修正后仍不符合要求时应返回错误")
+	}
+	if prompt := chatModel.inputs[1][len(chatModel.inputs[1])-1].Content; !strings.Contains(prompt, "无法解析为JSON对象") {
+		t.Errorf("无法解析时修正提示应说明原因: %s", prompt)
+	}
+}
+
+func TestGenerateCard_GenericTemplate(t *testing.T) {
+	chatModel := &scriptedCardModel{replies: []string{
+		`{"concept":"数数","explanation":"一片一片地数。","problem":"树上有3片银杏叶，又长出2片，一共几片？","answer":"5片"}`,
+	}}
+	node := newScriptedTextNode(chatModel)
+
+	card, err := node.GenerateCard(context.Background(), "math", &GraphData{ObjectName: "银杏", Age: 6})
+	if err != nil {
+		t.Fatalf("GenerateCard() error = %v", err)
+	}
+	if card["type"] != "math" || card["title"] != "银杏里的数学" {
+		t.Errorf("card = %v", card)
+	}
+	var prompt string
+	for _, msg := range chatModel.inputs[0] {
+		prompt += msg.Content + "\n"
+	}
+	for _, want := range []string{"数学卡", "- problem:", "银杏"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("提示词缺少 %q: %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "{objectName}") {
+		t.Errorf("提示词中的占位符未替换: %s", prompt)
+	}
+
+	if _, err := node.GenerateCard(context.Background(), "unknown", &GraphData{ObjectName: "银杏", Age: 6}); err == nil {
+		t.Error("未知卡片类型应返回错误")
+	}
+}
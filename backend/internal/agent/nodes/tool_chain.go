@@ -3,7 +3,9 @@ package nodes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/cloudwego/eino/components/model"
@@ -36,7 +38,7 @@ func NewToolChain(toolRegistry *tools.ToolRegistry, logger logx.Logger) *ToolCha
 func (tc *ToolChain) ExecuteToolChain(
 	ctx context.Context,
 	messages []*schema.Message,
-	chatModel model.ChatModel,
+	chatModel model.BaseChatModel,
 	initialTools []string, // 初始推荐的工具列表（可选）
 ) ([]*schema.Message, []string, map[string]interface{}, error) {
 	// 设置超时
@@ -167,6 +169,124 @@ func (tc *ToolChain) ExecuteToolChain(
 	return currentMessages, toolsUsed, toolResults, nil
 }
 
+// StreamToolChain 流式执行工具调用链
+// 文本内容一生成就写入返回的流；模型请求工具调用时，执行工具后继续流式生成，直到达到最大深度
+// 第一轮流式调用失败时直接返回错误，便于调用方降级；后续错误通过流返回
+// 工具执行受超时控制，文本生成不受限制（长回答不会被截断）
+func (tc *ToolChain) StreamToolChain(
+	ctx context.Context,
+	messages []*schema.Message,
+	chatModel model.BaseChatModel,
+	onToolsUsed func(toolsUsed []string, toolResults map[string]interface{}), // 可选：工具调用完成回调
+) (*schema.StreamReader[*schema.Message], error) {
+	stream, err := chatModel.Stream(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](streamBufferSize)
+	go func() {
+		defer sw.Close()
+
+		currentMessages := messages
+		toolsUsed := []string{}
+		toolResults := make(map[string]interface{})
+		defer func() {
+			if onToolsUsed != nil && len(toolsUsed) > 0 {
+				onToolsUsed(toolsUsed, toolResults)
+			}
+		}()
+
+		for depth := 0; ; depth++ {
+			result, closed, err := tc.forwardStream(stream, sw)
+			if closed {
+				return
+			}
+			if err != nil {
+				tc.logger.Errorw("流式工具调用链读取失败",
+					logx.Field("depth", depth),
+					logx.Field("error", err),
+				)
+				sw.Send(nil, err)
+				return
+			}
+
+			if result == nil || len(result.ToolCalls) == 0 {
+				return
+			}
+			if depth >= tc.maxDepth || tc.toolRegistry == nil {
+				tc.logger.Errorw("流式工具调用链中断：达到最大深度或未配置工具注册表",
+					logx.Field("depth", depth),
+				)
+				return
+			}
+
+			toolCtx, cancel := context.WithTimeout(ctx, tc.timeout)
+			toolMessages, roundTools, roundResults := tc.executeToolRound(toolCtx, result.ToolCalls)
+			cancel()
+			if len(toolMessages) == 0 {
+				tc.logger.Errorw("流式工具调用链中断：没有成功执行的工具",
+					logx.Field("depth", depth),
+				)
+				return
+			}
+
+			toolsUsed = append(toolsUsed, roundTools...)
+			for k, v := range roundResults {
+				toolResults[k] = v
+			}
+			currentMessages = append(currentMessages, result)
+			currentMessages = append(currentMessages, toolMessages...)
+
+			tc.logger.Infow("🔄 流式工具调用链继续（等待ChatModel整合工具结果）",
+				logx.Field("depth", depth+1),
+				logx.Field("toolsUsed", roundTools),
+			)
+
+			stream, err = chatModel.Stream(ctx, currentMessages)
+			if err != nil {
+				tc.logger.Errorw("流式工具调用链整合工具结果失败", logx.Field("error", err))
+				sw.Send(nil, err)
+				return
+			}
+		}
+	}()
+
+	return sr, nil
+}
+
+// forwardStream 将模型输出的文本块转发到管道，返回拼接后的完整消息（含工具调用请求）
+// closed 为true表示读取方已关闭流，无需继续生成
+func (tc *ToolChain) forwardStream(stream *schema.StreamReader[*schema.Message], sw *schema.StreamWriter[*schema.Message]) (result *schema.Message, closed bool, err error) {
+	defer stream.Close()
+
+	chunks := []*schema.Message{}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if chunk == nil {
+			continue
+		}
+		chunks = append(chunks, chunk)
+		if chunk.Content != "" {
+			if sw.Send(&schema.Message{Role: schema.Assistant, Content: chunk.Content}, nil) {
+				return nil, true, nil
+			}
+		}
+	}
+
+	if len(chunks) == 0 {
+		return nil, false, nil
+	}
+	result, err = schema.ConcatMessages(chunks)
+	return result, false, err
+}
+
 // executeToolRound 执行一轮工具调用
 func (tc *ToolChain) executeToolRound(ctx context.Context, toolCalls []schema.ToolCall) ([]*schema.Message, []string, map[string]interface{}) {
 	toolMessages := make([]*schema.Message, 0, len(toolCalls))
@@ -218,7 +338,9 @@ func (tc *ToolChain) executeToolRound(ctx context.Context, toolCalls []schema.To
 		}
 
 		// 执行工具
+		endTool := StartStep(ctx, "Tool")
 		toolResult, err := tool.Execute(ctx, params)
+		endTool(map[string]interface{}{"tool": toolName, "arguments": params}, err)
 		if err != nil {
 			tc.logger.Errorw("❌ 工具调用失败",
 				logx.Field("tool", toolName),
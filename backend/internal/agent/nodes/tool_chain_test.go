@@ -0,0 +1,83 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tango/explore/internal/tools"
+	"github.com/tango/explore/internal/tools/base"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// scriptedChatModel 按顺序返回预设流式结果的ChatModel，并记录每轮收到的消息
+type scriptedChatModel struct {
+	rounds   [][]*schema.Message
+	received [][]*schema.Message
+}
+
+func (m *scriptedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *scriptedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if len(m.received) >= len(m.rounds) {
+		return nil, errors.New("unexpected call")
+	}
+	m.received = append(m.received, input)
+	return schema.StreamReaderFromArray(m.rounds[len(m.received)-1]), nil
+}
+
+func (m *scriptedChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestToolChain_StreamToolChain(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+	registry := tools.NewToolRegistry(logger)
+	registry.Register(base.NewGetCurrentTimeTool(logger))
+
+	chatModel := &scriptedChatModel{rounds: [][]*schema.Message{
+		{
+			schema.AssistantMessage("我看看", nil),
+			schema.AssistantMessage("时间。", []schema.ToolCall{{
+				ID:       "call-1",
+				Function: schema.FunctionCall{Name: "get_current_time", Arguments: "{}"},
+			}}),
+		},
+		{
+			schema.AssistantMessage("现在是", nil),
+			schema.AssistantMessage("下午三点。", nil),
+		},
+	}}
+
+	var toolsUsed []string
+	stream, err := NewToolChain(registry, logger).StreamToolChain(context.Background(),
+		[]*schema.Message{schema.UserMessage("几点了？")}, chatModel,
+		func(used []string, _ map[string]interface{}) { toolsUsed = used })
+	if err != nil {
+		t.Fatalf("StreamToolChain failed: %v", err)
+	}
+
+	content, err := CollectStream(stream)
+	if err != nil {
+		t.Fatalf("读取流失败: %v", err)
+	}
+	if content != "我看看时间。现在是下午三点。" {
+		t.Errorf("流式内容 = %q", content)
+	}
+	if len(toolsUsed) != 1 || toolsUsed[0] != "get_current_time" {
+		t.Errorf("toolsUsed = %v", toolsUsed)
+	}
+
+	// 第二轮应带上工具调用请求和工具结果
+	if len(chatModel.received) != 2 {
+		t.Fatalf("ChatModel应调用2次, 实际: %d", len(chatModel.received))
+	}
+	second := chatModel.received[1]
+	if len(second) != 3 || len(second[1].ToolCalls) != 1 || second[2].Role != schema.Tool || second[2].ToolCallID != "call-1" {
+		t.Errorf("第二轮消息错误: %+v", second)
+	}
+}
@@ -9,12 +9,16 @@ type GraphData struct {
 	Keywords []string // 关键词
 
 	// 中间数据
-	ObjectName     string // 识别的对象名称
-	ObjectCategory string // 对象类别
-	Intent         string // 识别的意图
+	ObjectName     string             // 识别的对象名称
+	ObjectCategory string             // 对象类别
+	CategoryPath   []string           // 归一化后的类别路径（从顶层类别到子类）
+	Confidence     float64            // 识别置信度
+	Objects        []RecognizedObject // 图片中的候选对象（按置信度从高到低）
+	Intent         string             // 识别的意图
 
 	// 输出数据
-	Cards      []interface{} // 生成的卡片
+	Cards      []interface{} // 生成的卡片（与请求的卡片类型一一对应，生成失败的位置为nil）
+	CardErrors []CardError   // 生成失败的卡片
 	TextResult string        // 文本生成结果
 	ImageURL   string        // 生成的图片URL
 }
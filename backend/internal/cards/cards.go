@@ -0,0 +1,233 @@
+// Package cards 知识卡片的内容结构：每种卡片类型对应一个带版本号的Go结构体，
+// 由结构体生成JSON Schema（用于提示模型修正输出），并按Schema校验和规整模型返回的卡片内容
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/eino-contrib/jsonschema"
+)
+
+// 卡片类型
+const (
+	TypeScience   = "science"   // 科学认知卡
+	TypePoetry    = "poetry"    // 古诗词卡
+	TypeEnglish   = "english"   // 英语表达卡
+	TypeMath      = "math"      // 数学卡
+	TypeHistory   = "history"   // 历史卡
+	TypeArt       = "art"       // 艺术卡
+	TypeSafety    = "safety"    // 安全知识卡
+	TypeCharacter = "character" // 汉字卡
+)
+
+// DefaultTypes 请求未指定卡片类型时生成的卡片
+var DefaultTypes = []string{TypeScience, TypePoetry, TypeEnglish}
+
+// 各卡片类型当前的内容结构版本，字段有不兼容的变化时递增
+const (
+	ScienceSchemaVersion   = 1
+	PoetrySchemaVersion    = 1
+	EnglishSchemaVersion   = 1
+	MathSchemaVersion      = 1
+	HistorySchemaVersion   = 1
+	ArtSchemaVersion       = 1
+	SafetySchemaVersion    = 1
+	CharacterSchemaVersion = 1
+)
+
+// ScienceCard 科学认知卡内容
+type ScienceCard struct {
+	SchemaVersion int      `json:"schemaVersion" jsonschema:"-"`
+	Name          string   `json:"name" jsonschema:"minLength=1" jsonschema_description:"对象名称"`
+	Explanation   string   `json:"explanation" jsonschema:"minLength=1" jsonschema_description:"科学解释"`
+	Facts         []string `json:"facts" jsonschema:"minItems=1,maxItems=5" jsonschema_description:"有趣的事实列表"`
+	FunFact       string   `json:"funFact" jsonschema:"minLength=1" jsonschema_description:"趣味知识"`
+}
+
+// PoetryCard 古诗词卡内容
+type PoetryCard struct {
+	SchemaVersion int    `json:"schemaVersion" jsonschema:"-"`
+	Poem          string `json:"poem" jsonschema:"minLength=1" jsonschema_description:"古诗词内容"`
+	PoemSource    string `json:"poemSource" jsonschema:"minLength=1" jsonschema_description:"作者和诗名，格式：作者 - 诗名"`
+	Explanation   string `json:"explanation" jsonschema:"minLength=1" jsonschema_description:"诗词解释"`
+	Context       string `json:"context" jsonschema:"minLength=1" jsonschema_description:"文化背景"`
+}
+
+// EnglishCard 英语表达卡内容
+type EnglishCard struct {
+	SchemaVersion int      `json:"schemaVersion" jsonschema:"-"`
+	Keywords      []string `json:"keywords" jsonschema:"minItems=1,maxItems=8" jsonschema_description:"英语关键词列表"`
+	Expressions   []string `json:"expressions" jsonschema:"minItems=1,maxItems=5" jsonschema_description:"英语表达句子列表"`
+	Pronunciation string   `json:"pronunciation" jsonschema:"minLength=1" jsonschema_description:"发音指导"`
+}
+
+// MathCard 数学卡内容
+type MathCard struct {
+	SchemaVersion int    `json:"schemaVersion" jsonschema:"-"`
+	Concept       string `json:"concept" jsonschema:"minLength=1" jsonschema_description:"与对象相关的数学概念（如数数、形状、比例）"`
+	Explanation   string `json:"explanation" jsonschema:"minLength=1" jsonschema_description:"概念解释"`
+	Problem       string `json:"problem" jsonschema:"minLength=1" jsonschema_description:"围绕对象的一道数学小题"`
+	Answer        string `json:"answer" jsonschema:"minLength=1" jsonschema_description:"答案和解题思路"`
+}
+
+// HistoryCard 历史卡内容
+type HistoryCard struct {
+	SchemaVersion int      `json:"schemaVersion" jsonschema:"-"`
+	Era           string   `json:"era" jsonschema:"minLength=1" jsonschema_description:"相关的年代或朝代"`
+	Story         string   `json:"story" jsonschema:"minLength=1" jsonschema_description:"历史故事"`
+	Facts         []string `json:"facts" jsonschema:"minItems=1,maxItems=5" jsonschema_description:"历史小知识列表"`
+	Significance  string   `json:"significance" jsonschema:"minLength=1" jsonschema_description:"对今天的影响或意义"`
+}
+
+// ArtCard 艺术卡内容
+type ArtCard struct {
+	SchemaVersion int    `json:"schemaVersion" jsonschema:"-"`
+	Artwork       string `json:"artwork" jsonschema:"minLength=1" jsonschema_description:"与对象相关的艺术作品或艺术形式"`
+	Artist        string `json:"artist,omitempty" jsonschema_description:"作者或出处（可选）"`
+	Description   string `json:"description" jsonschema:"minLength=1" jsonschema_description:"作品赏析"`
+	Activity      string `json:"activity" jsonschema:"minLength=1" jsonschema_description:"可以动手尝试的创作活动"`
+}
+
+// SafetyCard 安全知识卡内容
+type SafetyCard struct {
+	SchemaVersion int      `json:"schemaVersion" jsonschema:"-"`
+	Risks         []string `json:"risks" jsonschema:"minItems=1,maxItems=5" jsonschema_description:"需要注意的危险"`
+	Tips          []string `json:"tips" jsonschema:"minItems=1,maxItems=5" jsonschema_description:"安全要点"`
+	Emergency     string   `json:"emergency" jsonschema:"minLength=1" jsonschema_description:"遇到危险时怎么办"`
+}
+
+// CharacterCard 汉字卡内容
+type CharacterCard struct {
+	SchemaVersion int      `json:"schemaVersion" jsonschema:"-"`
+	Character     string   `json:"character" jsonschema:"minLength=1" jsonschema_description:"对象名称中的一个汉字"`
+	Pinyin        string   `json:"pinyin" jsonschema:"minLength=1" jsonschema_description:"带声调的拼音"`
+	Meaning       string   `json:"meaning" jsonschema:"minLength=1" jsonschema_description:"字义"`
+	Origin        string   `json:"origin" jsonschema:"minLength=1" jsonschema_description:"字形来源或造字方法"`
+	Words         []string `json:"words" jsonschema:"minItems=1,maxItems=6" jsonschema_description:"常用组词"`
+}
+
+// spec 卡片类型的内容结构定义
+type spec struct {
+	name    string
+	version int
+	content reflect.Type
+}
+
+var specs = map[string]spec{
+	TypeScience:   {name: "科学认知卡", version: ScienceSchemaVersion, content: reflect.TypeOf(ScienceCard{})},
+	TypePoetry:    {name: "古诗词卡", version: PoetrySchemaVersion, content: reflect.TypeOf(PoetryCard{})},
+	TypeEnglish:   {name: "英语表达卡", version: EnglishSchemaVersion, content: reflect.TypeOf(EnglishCard{})},
+	TypeMath:      {name: "数学卡", version: MathSchemaVersion, content: reflect.TypeOf(MathCard{})},
+	TypeHistory:   {name: "历史卡", version: HistorySchemaVersion, content: reflect.TypeOf(HistoryCard{})},
+	TypeArt:       {name: "艺术卡", version: ArtSchemaVersion, content: reflect.TypeOf(ArtCard{})},
+	TypeSafety:    {name: "安全知识卡", version: SafetySchemaVersion, content: reflect.TypeOf(SafetyCard{})},
+	TypeCharacter: {name: "汉字卡", version: CharacterSchemaVersion, content: reflect.TypeOf(CharacterCard{})},
+}
+
+// Known 是否为已定义内容结构的卡片类型
+func Known(cardType string) bool {
+	_, ok := specs[cardType]
+	return ok
+}
+
+// Name 返回卡片类型的中文名称，未知类型返回类型本身
+func Name(cardType string) string {
+	if s, ok := specs[cardType]; ok {
+		return s.name
+	}
+	return cardType
+}
+
+var (
+	schemaMu sync.Mutex
+	schemas  = map[string]*jsonschema.Schema{}
+)
+
+// Version 返回卡片类型当前的内容结构版本，未知类型返回 0
+func Version(cardType string) int {
+	return specs[cardType].version
+}
+
+// Schema 返回卡片类型内容的JSON Schema（不包含由服务端填写的 schemaVersion）
+func Schema(cardType string) (*jsonschema.Schema, error) {
+	s, ok := specs[cardType]
+	if !ok {
+		return nil, fmt.Errorf("未知的卡片类型: %s", cardType)
+	}
+
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	if cached, ok := schemas[cardType]; ok {
+		return cached, nil
+	}
+	reflector := &jsonschema.Reflector{
+		DoNotReference:            true,
+		ExpandedStruct:            true,
+		AllowAdditionalProperties: true,
+	}
+	generated := reflector.ReflectFromType(s.content)
+	// 只用于提示模型，不需要 $schema 和 $id
+	generated.Version = ""
+	generated.ID = ""
+	schemas[cardType] = generated
+	return generated, nil
+}
+
+// SchemaJSON 返回卡片类型内容的JSON Schema文本，用于写入提示词
+func SchemaJSON(cardType string) (string, error) {
+	s, err := Schema(cardType)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("序列化卡片Schema失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// FieldsPrompt 按Schema列出卡片内容的字段说明（每行一个字段），用于生成卡片的提示词
+func FieldsPrompt(cardType string) (string, error) {
+	s, err := Schema(cardType)
+	if err != nil {
+		return "", err
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	var lines []string
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		field := pair.Value
+		kind := "字符串"
+		if field.Type == "array" {
+			kind = "字符串数组"
+			if field.MinItems != nil && field.MaxItems != nil {
+				kind += fmt.Sprintf("，%d-%d个", *field.MinItems, *field.MaxItems)
+			}
+		}
+		if !required[pair.Key] {
+			kind += "，可选"
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s（%s）", pair.Key, field.Description, kind))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ToMap 把卡片内容结构体转换为 CardContent.Content 使用的 map
+func ToMap(content interface{}) map[string]interface{} {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
@@ -0,0 +1,86 @@
+package cards
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	content, err := Validate(TypeScience, map[string]interface{}{
+		"name":        "银杏",
+		"explanation": "银杏是非常古老的植物。",
+		"facts":       []interface{}{"银杏叶子像小扇子"},
+		"funFact":     "银杏被称为活化石！",
+		"extra":       "多余字段",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if content["schemaVersion"] != float64(ScienceSchemaVersion) {
+		t.Errorf("schemaVersion = %v, want %d", content["schemaVersion"], ScienceSchemaVersion)
+	}
+	if _, ok := content["extra"]; ok {
+		t.Error("结构之外的字段应被去掉")
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	tests := []struct {
+		name     string
+		cardType string
+		content  map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "缺少字段和空字符串",
+			cardType: TypePoetry,
+			content:  map[string]interface{}{"poem": "床前明月光", "poemSource": " ", "explanation": "描写月光"},
+			want:     []string{"缺少字段 context", "字段 poemSource 不能为空"},
+		},
+		{
+			name:     "类型错误和数量超限",
+			cardType: TypeEnglish,
+			content: map[string]interface{}{
+				"keywords":      "moon",
+				"expressions":   []interface{}{"a", "b", "c", "d", "e", "f"},
+				"pronunciation": "/muːn/",
+			},
+			want: []string{"字段 keywords 应为数组", "字段 expressions 最多5项，实际6项"},
+		},
+		{
+			name:     "数组元素类型错误",
+			cardType: TypeScience,
+			content:  map[string]interface{}{"name": "月亮", "explanation": "卫星", "facts": []interface{}{"事实", 2.0}, "funFact": "潮汐"},
+			want:     []string{"字段 facts[1] 应为字符串"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validate(tt.cardType, tt.content)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+			if strings.Join(validationErr.Issues, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Issues = %q, want %q", validationErr.Issues, tt.want)
+			}
+		})
+	}
+
+	if _, err := Validate("unknown", map[string]interface{}{}); err == nil {
+		t.Error("未知卡片类型应返回错误")
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	for _, cardType := range []string{TypeScience, TypePoetry, TypeEnglish} {
+		s, err := SchemaJSON(cardType)
+		if err != nil {
+			t.Fatalf("SchemaJSON(%s) error = %v", cardType, err)
+		}
+		if strings.Contains(s, "schemaVersion") || !strings.Contains(s, `"required"`) {
+			t.Errorf("SchemaJSON(%s) = %s", cardType, s)
+		}
+	}
+}
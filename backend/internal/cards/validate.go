@@ -0,0 +1,116 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eino-contrib/jsonschema"
+)
+
+// ValidationError 卡片内容不符合卡片类型的结构要求，Issues 逐条列出问题（可直接写入修正提示）
+type ValidationError struct {
+	CardType string
+	Issues   []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s卡片内容不符合结构要求: %s", e.CardType, strings.Join(e.Issues, "；"))
+}
+
+// Validate 按卡片类型的Schema校验模型返回的内容，通过后返回规整后的内容：
+// 去掉结构之外的字段，并填写当前的 schemaVersion
+func Validate(cardType string, content map[string]interface{}) (map[string]interface{}, error) {
+	s, ok := specs[cardType]
+	if !ok {
+		return nil, fmt.Errorf("未知的卡片类型: %s", cardType)
+	}
+	contentSchema, err := Schema(cardType)
+	if err != nil {
+		return nil, err
+	}
+	if issues := check(contentSchema, content, ""); len(issues) > 0 {
+		return nil, &ValidationError{CardType: cardType, Issues: issues}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("序列化卡片内容失败: %w", err)
+	}
+	typed := reflect.New(s.content)
+	if err := json.Unmarshal(data, typed.Interface()); err != nil {
+		return nil, &ValidationError{CardType: cardType, Issues: []string{err.Error()}}
+	}
+	typed.Elem().FieldByName("SchemaVersion").SetInt(int64(s.version))
+	return ToMap(typed.Interface()), nil
+}
+
+// check 按Schema检查值，返回发现的问题（只支持卡片结构用到的关键字）
+func check(s *jsonschema.Schema, value interface{}, path string) []string {
+	var issues []string
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fieldName(path) + "应为对象"}
+		}
+		for _, name := range s.Required {
+			if _, exists := obj[name]; !exists {
+				issues = append(issues, "缺少字段 "+joinPath(path, name))
+			}
+		}
+		if s.Properties == nil {
+			return issues
+		}
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if v, exists := obj[pair.Key]; exists {
+				issues = append(issues, check(pair.Value, v, joinPath(path, pair.Key))...)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fieldName(path) + "应为数组"}
+		}
+		if s.MinItems != nil && uint64(len(items)) < *s.MinItems {
+			issues = append(issues, fmt.Sprintf("%s至少需要%d项，实际%d项", fieldName(path), *s.MinItems, len(items)))
+		}
+		if s.MaxItems != nil && uint64(len(items)) > *s.MaxItems {
+			issues = append(issues, fmt.Sprintf("%s最多%d项，实际%d项", fieldName(path), *s.MaxItems, len(items)))
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				issues = append(issues, check(s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fieldName(path) + "应为字符串"}
+		}
+		if s.MinLength != nil && uint64(utf8.RuneCountInString(strings.TrimSpace(str))) < *s.MinLength {
+			issues = append(issues, fieldName(path)+"不能为空")
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return []string{fieldName(path) + "应为数字"}
+		}
+	}
+	return issues
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldName(path string) string {
+	if path == "" {
+		return "内容"
+	}
+	return "字段 " + path + " "
+}
@@ -0,0 +1,7 @@
+package config
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	// 管理接口令牌，请求头 X-Admin-Token 与之一致才能访问；未设置时管理接口不可用
+	Token string `json:",optional,env=ADMIN_TOKEN"`
+}
@@ -10,6 +10,16 @@ type Config struct {
 	Upload UploadConfig
 	// MCP配置
 	MCP MCPConfig
+	// 会话存储配置
+	Session SessionConfig
+	// 分享链接配置
+	Share ShareConfig
+	// 学习档案配置
+	Learner LearnerConfig
+	// 对象类别树配置
+	Taxonomy TaxonomyConfig
+	// 管理接口配置
+	Admin AdminConfig
 }
 
 // AIConfig AI模型配置
@@ -40,16 +50,108 @@ type AIConfig struct {
 	// true: 使用AI模型调用，禁止使用Mock数据（默认值）
 	// false: 使用Mock数据作为降级方案（仅用于开发测试场景）
 	UseAIModel bool `json:",optional,env=USE_AI_MODEL"`
+
+	// 按角色划分的模型提供方配置（意图识别、图片识别、文本、卡片、图片生成）
+	// 环境变量由 explore.go 手动读取，未配置的角色使用 EinoBaseURL + AppID:AppKey 访问Ark
+	Models ModelRolesConfig `json:",optional"`
+
+	// 模型熔断：同一模型连续失败次数达到该值后暂停使用，默认3次，负数表示关闭熔断
+	ModelFailureThreshold int `json:",optional,env=MODEL_FAILURE_THRESHOLD"`
+	// 熔断冷却时间（秒），之后允许一次探测请求，成功则恢复使用，默认30秒
+	ModelCircuitCooldownSeconds int `json:",optional,env=MODEL_CIRCUIT_COOLDOWN_SECONDS"`
+	// 单次调用最多尝试的模型数（模型失败时切换到下一个候选模型），默认3个
+	ModelMaxAttempts int `json:",optional,env=MODEL_MAX_ATTEMPTS"`
+
+	// 语音识别提供方：whisper（OpenAI兼容的 /audio/transcriptions 接口）、mock
+	// 未设置时：配置了服务地址则使用whisper，USE_AI_MODEL=false时使用mock
+	ASRProvider string `json:",optional,env=ASR_PROVIDER"`
+	// 语音识别服务地址（未设置时使用EinoBaseURL）
+	ASRBaseURL string `json:",optional,env=ASR_BASE_URL"`
+	// 语音识别API Key（未设置时使用 AppID:AppKey）
+	ASRAPIKey string `json:",optional,env=ASR_API_KEY"`
+	// 语音识别模型（未设置时使用默认值）
+	ASRModel string `json:",optional,env=ASR_MODEL"`
+	// 语音识别语言提示（如 zh，可选）
+	ASRLanguage string `json:",optional,env=ASR_LANGUAGE"`
+	// 音频大小限制（字节），默认 10MB
+	ASRMaxAudioBytes int `json:",optional,env=ASR_MAX_AUDIO_BYTES"`
+	// 音频时长限制（秒），默认 60 秒
+	ASRMaxDurationSeconds int `json:",optional,env=ASR_MAX_DURATION_SECONDS"`
+
+	// 语音合成提供方：openai（OpenAI兼容的 /audio/speech 接口）、espeak（本地espeak-ng命令）、mock
+	// 未设置时：配置了服务地址则使用openai，USE_AI_MODEL=false时使用mock
+	TTSProvider string `json:",optional,env=TTS_PROVIDER"`
+	// 语音合成服务地址（未设置时使用EinoBaseURL）
+	TTSBaseURL string `json:",optional,env=TTS_BASE_URL"`
+	// 语音合成API Key（未设置时使用 AppID:AppKey）
+	TTSAPIKey string `json:",optional,env=TTS_API_KEY"`
+	// 语音合成模型（未设置时使用默认值）
+	TTSModel string `json:",optional,env=TTS_MODEL"`
+	// 发音人（openai如 alloy，espeak如 cmn，未设置时使用各提供方默认值）
+	TTSVoice string `json:",optional,env=TTS_VOICE"`
+	// 语速倍率（1.0为正常语速，未设置时使用默认值）
+	TTSSpeed float64 `json:",optional,env=TTS_SPEED"`
+	// espeak命令路径（未设置时使用 espeak-ng）
+	TTSCommand string `json:",optional,env=TTS_COMMAND"`
+	// 单次合成最大字数，默认 500 字
+	TTSMaxChars int `json:",optional,env=TTS_MAX_CHARS"`
+	// 对话自动朗读的最大年龄（请求未指定tts时，不超过该年龄自动附带语音），默认 6 岁，负数表示关闭
+	TTSAutoMaxAge int `json:",optional,env=TTS_AUTO_MAX_AGE"`
+
+	// 对话上下文token预算，超出后较早的对话压缩为摘要，默认 3000，负数表示关闭摘要（只按轮数截断）
+	ContextTokenBudget int `json:",optional,env=CONTEXT_TOKEN_BUDGET"`
+	// 压缩摘要时原文保留的最近消息数，默认 6 条
+	ContextKeepMessages int `json:",optional,env=CONTEXT_KEEP_MESSAGES"`
+	// 按模型声明的上下文token预算，格式为 "模型=token数,模型=token数"，未声明的模型使用 ContextTokenBudget
+	ContextModelBudgets string `json:",optional,env=CONTEXT_MODEL_BUDGETS"`
+	// 生成上下文摘要的模型（未设置时使用文本生成模型列表的第一个）
+	ContextSummaryModel string `json:",optional,env=CONTEXT_SUMMARY_MODEL"`
+	// 识别结果缓存条数（默认1000，负数关闭），相同或相似的图片直接返回缓存的识别结果
+	RecognitionCacheSize int `json:",optional,env=RECOGNITION_CACHE_SIZE"`
+	// 判定为相似图片的感知哈希最大汉明距离（0-64，默认6）
+	RecognitionCacheDistance int `json:",optional,env=RECOGNITION_CACHE_DISTANCE"`
+	// 图片中有多个候选对象且最高置信度低于该阈值时，询问孩子想了解哪一个（0-1，默认0.6，负数关闭）
+	DisambiguationThreshold float64 `json:",optional,env=DISAMBIGUATION_THRESHOLD"`
 }
 
+// 图片存储后端类型
+const (
+	ImageBackendGitHub = "github" // GitHub 仓库
+	ImageBackendLocal  = "local"  // 本地磁盘，通过 GET /api/images/:name 访问
+	ImageBackendS3     = "s3"     // S3兼容对象存储（如 MinIO）
+)
+
 // UploadConfig 图片上传配置
 type UploadConfig struct {
+	// 存储后端：github、local、s3
+	// 未设置时：配置了GitHub参数则使用github，否则返回base64 data URL
+	Backend string `json:",optional,env=UPLOAD_BACKEND"`
+
 	// GitHub 配置
 	GitHubToken  string `json:",optional,env=GITHUB_TOKEN"`  // GitHub Personal Access Token
 	GitHubOwner  string `json:",optional,env=GITHUB_OWNER"`  // GitHub 用户名或组织名
 	GitHubRepo   string `json:",optional,env=GITHUB_REPO"`   // GitHub 仓库名
 	GitHubBranch string `json:",optional,env=GITHUB_BRANCH"` // GitHub 分支名，默认 "main"
 	GitHubPath   string `json:",optional,env=GITHUB_PATH"`   // 图片存储路径，默认 "images/"
+	// 本地磁盘配置（Backend为local时使用）
+	LocalDir      string `json:",optional,env=UPLOAD_LOCAL_DIR"`       // 图片保存目录，默认 data/images
+	PublicBaseURL string `json:",optional,env=UPLOAD_PUBLIC_BASE_URL"` // 服务对外访问地址，如 https://explore.example.com，未设置时返回相对路径
+	// S3兼容存储配置（Backend为s3时使用）
+	S3Endpoint  string `json:",optional,env=S3_ENDPOINT"`   // 服务地址，如 http://127.0.0.1:9000
+	S3Region    string `json:",optional,env=S3_REGION"`     // 区域，默认 us-east-1
+	S3Bucket    string `json:",optional,env=S3_BUCKET"`     // 存储桶
+	S3AccessKey string `json:",optional,env=S3_ACCESS_KEY"` // Access Key
+	S3SecretKey string `json:",optional,env=S3_SECRET_KEY"` // Secret Key
+	S3PathStyle bool   `json:",optional,env=S3_PATH_STYLE"` // 使用路径风格访问（MinIO需要开启）
+	S3Prefix    string `json:",optional,env=S3_PREFIX"`     // 对象键前缀，默认 "images/"
+	S3PublicURL string `json:",optional,env=S3_PUBLIC_URL"` // 对外访问地址前缀（如CDN），默认按服务地址和存储桶拼接
 	// 图片大小限制（字节），默认 10MB
 	MaxImageSize int64 `json:",optional,env=MAX_IMAGE_SIZE"`
+	// 图片处理配置（上传和识别前统一按EXIF方向旋转、去除元数据、缩放并重新编码）
+	ImageMaxEdge     int `json:",optional,env=IMAGE_MAX_EDGE"`           // 最长边像素，默认 1600
+	ImageQuality     int `json:",optional,env=IMAGE_QUALITY"`            // JPEG质量（1-100），默认 85
+	ThumbnailMaxEdge int `json:",optional,env=IMAGE_THUMBNAIL_MAX_EDGE"` // 缩略图最长边像素，默认 320
+	// 允许下载图片的主机（逗号分隔，支持 *.example.com），为空时允许所有公网主机
+	// 无论是否配置，都不允许访问内网、回环、链路本地地址
+	FetchAllowedHosts string `json:",optional,env=IMAGE_FETCH_ALLOWED_HOSTS"`
 }
@@ -0,0 +1,16 @@
+package config
+
+// LearnerConfig 学习档案存储配置（按孩子标识跨会话保存）
+type LearnerConfig struct {
+	// 存储后端：memory（默认）、file、redis，取值同 SessionConfig.Backend
+	Backend string `json:",optional,env=LEARNER_BACKEND"`
+
+	// 文件存储路径（Backend为file时使用），默认 data/learners.db
+	FilePath string `json:",optional,env=LEARNER_FILE_PATH"`
+
+	// Redis配置（Backend为redis时使用）
+	RedisHost      string `json:",optional,env=LEARNER_REDIS_HOST"`       // 地址，如 127.0.0.1:6379
+	RedisType      string `json:",optional,env=LEARNER_REDIS_TYPE"`       // node（默认）或 cluster
+	RedisPass      string `json:",optional,env=LEARNER_REDIS_PASS"`       // 密码
+	RedisKeyPrefix string `json:",optional,env=LEARNER_REDIS_KEY_PREFIX"` // 键前缀，默认 "explore:"
+}
@@ -28,12 +28,18 @@ type MCPServerConfig struct {
 	// URL（用于SSE或HTTP类型的服务器）
 	URL string `json:"url,omitempty"`
 
+	// 传输方式（用于url类型的服务器）：sse、streamable-http，为空时自动探测
+	Transport string `json:"transport,omitempty"`
+
 	// 命令（用于command类型的服务器）
 	Command string `json:"command,omitempty"`
 
 	// 命令参数
 	Args []string `json:"args,omitempty"`
 
+	// 环境变量（用于command类型的服务器，追加到当前进程环境变量之后）
+	Env map[string]string `json:"env,omitempty"`
+
 	// HTTP请求头（用于需要认证的服务器）
 	Headers map[string]string `json:"headers,omitempty"`
 
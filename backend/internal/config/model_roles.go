@@ -0,0 +1,71 @@
+package config
+
+// ModelRoleConfig 单个模型角色的提供方配置，未设置的字段使用默认值
+type ModelRoleConfig struct {
+	// 提供方：ark（默认）、openai（OpenAI兼容接口，如 llama.cpp、vLLM）、ollama
+	Provider string `json:",optional"`
+	// 服务地址（ark/openai 未设置时使用EinoBaseURL，ollama 默认 http://localhost:11434/v1）
+	BaseURL string `json:",optional"`
+	// API Key（未设置时使用 AppID:AppKey，ollama 不需要）
+	APIKey string `json:",optional"`
+	// 模型列表（未设置时使用 INTENT_MODELS、IMAGE_RECOGNITION_MODELS 等原有配置）
+	Models []string `json:",optional" yaml:",omitempty"`
+	// 单次调用超时（秒），默认 120 秒
+	TimeoutSeconds int `json:",optional"`
+	// 生成参数，未设置（0）时使用模型默认值
+	Temperature float64 `json:",optional"`
+	TopP        float64 `json:",optional"`
+	MaxTokens   int     `json:",optional"`
+	// 路由策略：weighted（默认，按权重和近期成功率随机选择）、priority（按模型列表顺序，前面的模型熔断或失败时才使用后面的）
+	Policy string `json:",optional"`
+	// 模型权重（weighted 策略），格式为 "模型=权重,模型=权重"，未声明的模型权重为1，权重为0表示不使用该模型
+	Weights string `json:",optional"`
+}
+
+// ModelRolesConfig 按角色划分的模型配置，不同角色可以使用不同的提供方
+// 环境变量格式为 <角色>_MODEL_PROVIDER、<角色>_MODEL_BASE_URL 等，角色前缀见 README
+type ModelRolesConfig struct {
+	Intent   ModelRoleConfig `json:",optional"` // 意图识别
+	Vision   ModelRoleConfig `json:",optional"` // 图片识别
+	Text     ModelRoleConfig `json:",optional"` // 对话和多Agent节点
+	Card     ModelRoleConfig `json:",optional"` // 知识卡片生成，未设置的字段沿用 Text
+	ImageGen ModelRoleConfig `json:",optional"` // 图片生成
+}
+
+// Inherit 返回用 base 补全未设置字段后的配置
+// 提供方不同时不沿用服务地址和API Key，避免把一个厂商的凭证发给另一个厂商
+func (c ModelRoleConfig) Inherit(base ModelRoleConfig) ModelRoleConfig {
+	if c.Provider == "" {
+		c.Provider = base.Provider
+	}
+	if c.Provider == base.Provider {
+		if c.BaseURL == "" {
+			c.BaseURL = base.BaseURL
+		}
+		if c.APIKey == "" {
+			c.APIKey = base.APIKey
+		}
+	}
+	if len(c.Models) == 0 {
+		c.Models = base.Models
+	}
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = base.TimeoutSeconds
+	}
+	if c.Temperature == 0 {
+		c.Temperature = base.Temperature
+	}
+	if c.TopP == 0 {
+		c.TopP = base.TopP
+	}
+	if c.MaxTokens == 0 {
+		c.MaxTokens = base.MaxTokens
+	}
+	if c.Policy == "" {
+		c.Policy = base.Policy
+	}
+	if c.Weights == "" {
+		c.Weights = base.Weights
+	}
+	return c
+}
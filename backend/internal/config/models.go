@@ -16,14 +16,66 @@ const (
 
 	// 文本生成模型（默认值，兼容旧配置）
 	DefaultTextGenerationModel = "gpt-5-nano"
+
+	// 语音识别模型
+	DefaultASRModel = "whisper-1"
+
+	// 语音合成模型
+	DefaultTTSModel = "tts-1"
+)
+
+// 模型提供方默认值
+const (
+	DefaultModelTimeoutSeconds = 120
+	DefaultOllamaBaseURL       = "http://localhost:11434/v1"
+)
+
+// 语音识别音频限制默认值
+const (
+	DefaultASRMaxAudioBytes      = 10 * 1024 * 1024 // 10MB
+	DefaultASRMaxDurationSeconds = 60
+)
+
+// 语音合成默认值
+const (
+	DefaultTTSMaxChars   = 500
+	DefaultTTSAutoMaxAge = 6 // 3-6岁幼儿阅读能力有限，默认自动朗读
+)
+
+// 对话上下文默认值
+const (
+	DefaultContextTokenBudget  = 3000
+	DefaultContextKeepMessages = 6
+)
+
+// 图片处理默认值
+const (
+	DefaultImageMaxEdge     = 1600 // 最长边像素
+	DefaultImageQuality     = 85   // JPEG质量
+	DefaultThumbnailMaxEdge = 320  // 缩略图最长边像素
+)
+
+// 模型路由默认值
+const (
+	DefaultModelRoutingPolicy          = "weighted" // 按权重和健康度随机选择
+	DefaultModelFailureThreshold       = 3          // 连续失败次数达到该值时熔断
+	DefaultModelCircuitCooldownSeconds = 30         // 熔断后经过该时间允许一次探测请求
+	DefaultModelMaxAttempts            = 3          // 单次请求最多尝试的模型数
+)
+
+// 识别结果缓存默认值
+const (
+	DefaultRecognitionCacheSize     = 1000
+	DefaultRecognitionCacheDistance = 6 // 感知哈希最大汉明距离
 )
 
+// DefaultDisambiguationThreshold 多对象消歧的默认置信度阈值
+const DefaultDisambiguationThreshold = 0.6
+
 // GetDefaultIntentModels 获取默认意图识别模型列表
 func GetDefaultIntentModels() []string {
 	return []string{
-		"gemini-3-pro-image",
 		"gpt-5-nano",
-		"doubao-seededit-3-0-i2i",
 		"doubao-seed-1.6vision",
 		"glm-4.6v",
 		"gpt-4o",
@@ -36,9 +88,7 @@ func GetDefaultIntentModels() []string {
 // GetDefaultImageRecognitionModels 获取默认图片识别模型列表
 func GetDefaultImageRecognitionModels() []string {
 	return []string{
-		"gemini-3-pro-image",
 		"gpt-5-nano",
-		"doubao-seededit-3-0-i2i",
 		"doubao-seed-1.6vision",
 		"glm-4.6v",
 		"gpt-4o",
@@ -49,11 +99,10 @@ func GetDefaultImageRecognitionModels() []string {
 }
 
 // GetDefaultTextGenerationModels 获取默认文本生成模型列表
+// 只包含对话模型，图片生成模型（如 gemini-3-pro-image）不能回答文本请求
 func GetDefaultTextGenerationModels() []string {
 	return []string{
-		"gemini-3-pro-image",
 		"gpt-5-nano",
-		"doubao-seededit-3-0-i2i",
 		"doubao-seed-1.6vision",
 		"glm-4.6v",
 		"gpt-4o",
@@ -0,0 +1,26 @@
+package config
+
+// 存储后端类型（会话、分享等持久化存储共用）
+const (
+	StoreBackendMemory = "memory" // 内存存储（默认，重启丢失）
+	StoreBackendFile   = "file"   // 本地文件存储（bbolt）
+	StoreBackendRedis  = "redis"  // Redis存储（支持多副本共享）
+)
+
+// SessionConfig 会话存储配置
+type SessionConfig struct {
+	// 存储后端：memory（默认）、file、redis
+	Backend string `json:",optional,env=SESSION_BACKEND"`
+
+	// 会话过期时间（分钟），无活动超过该时间的会话将被清理，默认30分钟
+	TTLMinutes int `json:",optional,env=SESSION_TTL_MINUTES"`
+
+	// 文件存储路径（Backend为file时使用），默认 data/sessions.db
+	FilePath string `json:",optional,env=SESSION_FILE_PATH"`
+
+	// Redis配置（Backend为redis时使用）
+	RedisHost      string `json:",optional,env=SESSION_REDIS_HOST"`       // 地址，如 127.0.0.1:6379
+	RedisType      string `json:",optional,env=SESSION_REDIS_TYPE"`       // node（默认）或 cluster
+	RedisPass      string `json:",optional,env=SESSION_REDIS_PASS"`       // 密码
+	RedisKeyPrefix string `json:",optional,env=SESSION_REDIS_KEY_PREFIX"` // 键前缀，默认 "explore:"
+}
@@ -0,0 +1,22 @@
+package config
+
+// ShareConfig 分享链接存储配置
+type ShareConfig struct {
+	// 存储后端：memory（默认）、file、redis，取值同 SessionConfig.Backend
+	Backend string `json:",optional,env=SHARE_BACKEND"`
+
+	// 默认有效期（小时），创建分享时未指定则使用该值，默认168小时（7天）
+	DefaultTTLHours int `json:",optional,env=SHARE_DEFAULT_TTL_HOURS"`
+
+	// 最长有效期（小时），创建分享时指定的有效期不能超过该值，默认720小时（30天）
+	MaxTTLHours int `json:",optional,env=SHARE_MAX_TTL_HOURS"`
+
+	// 文件存储路径（Backend为file时使用），默认 data/shares.db
+	FilePath string `json:",optional,env=SHARE_FILE_PATH"`
+
+	// Redis配置（Backend为redis时使用）
+	RedisHost      string `json:",optional,env=SHARE_REDIS_HOST"`       // 地址，如 127.0.0.1:6379
+	RedisType      string `json:",optional,env=SHARE_REDIS_TYPE"`       // node（默认）或 cluster
+	RedisPass      string `json:",optional,env=SHARE_REDIS_PASS"`       // 密码
+	RedisKeyPrefix string `json:",optional,env=SHARE_REDIS_KEY_PREFIX"` // 键前缀，默认 "explore:"
+}
@@ -0,0 +1,7 @@
+package config
+
+// TaxonomyConfig 对象类别树配置
+type TaxonomyConfig struct {
+	// 类别树文件路径（JSON，格式同内置的 internal/taxonomy/default.json），未设置时使用内置类别树
+	FilePath string `json:",optional,env=TAXONOMY_FILE"`
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tango/explore/internal/logic"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// GetImageHandler 读取本地存储的图片（返回图片二进制内容）
+func GetImageHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GetImageRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewGetImageLogic(r.Context(), svcCtx)
+		file, err := l.GetImage(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		// 文件名由内容哈希生成，同一文件名的内容不会变化，可以长期缓存
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, req.Name, info.ModTime(), file)
+	}
+}
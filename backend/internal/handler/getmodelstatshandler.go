@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tango/explore/internal/logic"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetModelStatsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ModelStatsRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewGetModelStatsLogic(r.Context(), svcCtx)
+		resp, err := l.GetModelStats(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
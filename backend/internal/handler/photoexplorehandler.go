@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/tango/explore/internal/logic"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// PhotoExploreHandler 拍照探索Handler（SSE）
+// 一次请求完成识别和卡片生成：先发送 identified 事件，再按完成顺序发送 card 事件，最后发送 done 事件
+func PhotoExploreHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 限制请求体大小，与识别接口一致
+		r.Body = http.MaxBytesReader(w, r.Body, 10*1024*1024)
+
+		var req types.PhotoExploreRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		// 参数校验失败时尚未开始推送事件，按普通JSON错误返回
+		l := logic.NewPhotoLogic(r.Context(), svcCtx)
+		if err := l.ExplorePhoto(w, &req); err != nil {
+			httpx.Error(w, err)
+		}
+	}
+}
@@ -14,6 +14,11 @@ import (
 func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
 		[]rest.Route{
+			{
+				Method:  http.MethodGet,
+				Path:    "/api/admin/models",
+				Handler: GetModelStatsHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/api/badge/stats",
@@ -39,6 +44,21 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 			Path:    "/api/conversation/agent",
 			Handler: AgentConversationHandler(serverCtx),
 		},
+			{
+				Method:  http.MethodGet,
+				Path:    "/api/conversation/sessions",
+				Handler: ListSessionsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/api/conversation/sessions/:sessionId",
+				Handler: GetSessionHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/api/conversation/sessions/:sessionId",
+				Handler: DeleteSessionHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/api/explore/generate-cards",
@@ -49,16 +69,36 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/api/explore/identify",
 				Handler: IdentifyHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/api/explore/photo",
+				Handler: PhotoExploreHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/api/images/:name",
+				Handler: GetImageHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodGet,
 				Path:    "/api/share/:shareId",
 				Handler: GetShareHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/api/share/:shareId",
+				Handler: RevokeShareHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/api/share/create",
 				Handler: CreateShareHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/api/tts",
+				Handler: TTSHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/api/upload/image",
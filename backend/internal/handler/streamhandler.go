@@ -68,7 +68,7 @@ func StreamConversationHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Owner-Token")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 
 		// 处理OPTIONS预检请求
@@ -91,6 +91,8 @@ func StreamConversationHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
+		req.OwnerToken = r.Header.Get("X-Session-Owner-Token")
+
 		// 设置默认值
 		if req.MaxContextRounds <= 0 {
 			req.MaxContextRounds = 20
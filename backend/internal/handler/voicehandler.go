@@ -89,8 +89,12 @@ func VoiceStreamHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 
 		// 发送语音识别完成事件
 		recognizedEvent := types.StreamEvent{
-			Type:    "voice_recognized",
-			Content: map[string]interface{}{"text": voiceResp.Text},
+			Type: "voice_recognized",
+			Content: map[string]interface{}{
+				"text":       voiceResp.Text,
+				"confidence": voiceResp.Confidence,
+				"segments":   voiceResp.Segments,
+			},
 		}
 		recognizedJSON, _ := json.Marshal(recognizedEvent)
 		fmt.Fprintf(w, "event: voice_recognized\ndata: %s\n\n", string(recognizedJSON))
@@ -0,0 +1,136 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// exifOrientationTag EXIF方向标签
+const exifOrientationTag = 0x0112
+
+// exifOrientation 读取图片的EXIF方向（1-8），没有EXIF或无法解析时返回1（不旋转）
+// 支持 JPEG 的 APP1 段、PNG 的 eXIf 块和 WebP 的 EXIF 块
+func exifOrientation(data []byte) int {
+	tiff := findExif(data)
+	if tiff == nil {
+		return 1
+	}
+	orientation := parseOrientation(tiff)
+	if orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
+
+// findExif 查找图片中的EXIF（TIFF格式）数据
+func findExif(data []byte) []byte {
+	switch {
+	case len(data) > 2 && data[0] == 0xFF && data[1] == 0xD8:
+		return findJPEGExif(data)
+	case len(data) > 8 && bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return findPNGExif(data)
+	case len(data) > 12 && bytes.HasPrefix(data, []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return findWebPExif(data)
+	}
+	return nil
+}
+
+// findJPEGExif 在JPEG的APP1段中查找EXIF
+func findJPEGExif(data []byte) []byte {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 || marker == 0xFF {
+			i++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 { // 图像数据开始，之后不会再有元数据段
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		segment := data[i+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		i = end
+	}
+	return nil
+}
+
+// findPNGExif 在PNG的eXIf块中查找EXIF
+func findPNGExif(data []byte) []byte {
+	for i := 8; i+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 8 + length
+		if length < 0 || end > len(data) {
+			return nil
+		}
+		if chunkType == "eXIf" {
+			return data[i+8 : end]
+		}
+		if chunkType == "IDAT" || chunkType == "IEND" {
+			return nil
+		}
+		i = end + 4 // 跳过CRC
+	}
+	return nil
+}
+
+// findWebPExif 在WebP的EXIF块中查找EXIF
+func findWebPExif(data []byte) []byte {
+	for i := 12; i+8 <= len(data); {
+		fourCC := string(data[i : i+4])
+		length := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + length
+		if length < 0 || end > len(data) {
+			return nil
+		}
+		if fourCC == "EXIF" {
+			return bytes.TrimPrefix(data[i+8:end], []byte("Exif\x00\x00"))
+		}
+		i = end + length%2 // 块按偶数字节对齐
+	}
+	return nil
+}
+
+// parseOrientation 从TIFF结构的第0个IFD中读取方向标签
+func parseOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,227 @@
+package imageproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/utils"
+)
+
+const (
+	// fetchTimeout 下载远程图片的总超时
+	fetchTimeout = 30 * time.Second
+	// fetchMaxRedirects 最多跟随的重定向次数
+	fetchMaxRedirects = 5
+	// defaultMaxImageSize 未配置 MaxImageSize 时的默认大小限制
+	defaultMaxImageSize = 10 * 1024 * 1024
+)
+
+var (
+	// ErrFetchURLInvalid 图片URL无效（只允许http/https）
+	ErrFetchURLInvalid = errors.New("图片URL无效，只支持 http/https")
+	// ErrFetchHostNotAllowed 图片所在的主机不在允许列表中
+	ErrFetchHostNotAllowed = errors.New("图片所在的主机不在允许列表中")
+	// ErrFetchAddressBlocked 图片地址解析到内网、回环或链路本地地址
+	ErrFetchAddressBlocked = errors.New("不允许访问内网地址")
+	// ErrFetchNotImage 下载的内容不是图片
+	ErrFetchNotImage = errors.New("下载的内容不是有效的图片")
+)
+
+// blockedNetworks net.IP 方法未覆盖的保留地址段
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级NAT
+	"192.0.0.0/24",  // IETF协议分配
+	"198.18.0.0/15", // 基准测试
+	"240.0.0.0/4",   // 保留地址（含广播地址）
+	"64:ff9b::/96",  // NAT64（可映射到任意IPv4地址）
+	"2001:db8::/32", // 文档地址
+)
+
+// Fetcher 安全的远程图片下载器
+// 只允许 http/https，拒绝解析到内网、回环、链路本地等地址的请求（在建立连接时按实际IP检查，
+// 重定向和DNS重绑定同样适用），边读取边限制大小，并按文件头确认内容是图片
+type Fetcher struct {
+	client       *http.Client
+	maxSize      int64
+	allowedHosts []string
+	// blocked 判断IP是否禁止访问（测试中替换以访问本地服务）
+	blocked func(ip net.IP) bool
+}
+
+// NewFetcher 根据上传配置创建图片下载器
+// 大小限制使用 MaxImageSize；配置了 FetchAllowedHosts 时只允许下载这些主机上的图片
+func NewFetcher(cfg config.UploadConfig) *Fetcher {
+	f := &Fetcher{
+		maxSize:      cfg.MaxImageSize,
+		allowedHosts: parseAllowedHosts(cfg.FetchAllowedHosts),
+		blocked:      isBlockedIP,
+	}
+	if f.maxSize <= 0 {
+		f.maxSize = defaultMaxImageSize
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		// Control 在DNS解析之后、建立连接之前调用，address 是实际要连接的IP
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || f.blocked(ip) {
+				return fmt.Errorf("%w: %s", ErrFetchAddressBlocked, host)
+			}
+			return nil
+		},
+	}
+	f.client = &http.Client{
+		Timeout: fetchTimeout,
+		Transport: &http.Transport{
+			// 不使用代理：经代理访问时无法检查目标地址
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 15 * time.Second,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= fetchMaxRedirects {
+				return fmt.Errorf("重定向次数过多")
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+	return f
+}
+
+// Fetch 下载图片，返回图片数据和按文件头识别的MIME类型
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", ErrFetchURLInvalid
+	}
+	if err := f.checkURL(u); err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	// 设置 User-Agent，避免某些服务器拒绝请求
+	req.Header.Set("User-Agent", "TanGo-ImageRecognition/1.0")
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("下载图片失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("下载图片失败，状态码: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > f.maxSize {
+		return nil, "", ErrImageTooLarge
+	}
+
+	// 多读一个字节判断是否超过限制，不信任 Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("读取图片数据失败: %w", err)
+	}
+	if int64(len(data)) > f.maxSize {
+		return nil, "", ErrImageTooLarge
+	}
+	if !utils.IsValidImageFormat(data) {
+		return nil, "", ErrFetchNotImage
+	}
+
+	return data, http.DetectContentType(data), nil
+}
+
+// checkURL 检查协议和主机（首次请求和每次重定向都会检查）
+func (f *Fetcher) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrFetchURLInvalid
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return ErrFetchURLInvalid
+	}
+	if len(f.allowedHosts) > 0 && !hostAllowed(host, f.allowedHosts) {
+		return fmt.Errorf("%w: %s", ErrFetchHostNotAllowed, host)
+	}
+	// IP字面量直接检查，域名在建立连接时按解析结果检查
+	if ip := net.ParseIP(host); ip != nil && f.blocked(ip) {
+		return fmt.Errorf("%w: %s", ErrFetchAddressBlocked, host)
+	}
+	return nil
+}
+
+// parseAllowedHosts 解析逗号分隔的主机列表，支持 *.example.com 匹配子域名
+func parseAllowedHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// hostAllowed 判断主机是否在允许列表中
+func hostAllowed(host string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlockedIP 判断IP是否属于内网、回环、链路本地、组播或保留地址
+func isBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
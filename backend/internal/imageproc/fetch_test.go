@@ -0,0 +1,143 @@
+package imageproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tango/explore/internal/config"
+)
+
+// newLocalFetcher 创建允许访问回环地址的下载器（测试服务监听在127.0.0.1）
+func newLocalFetcher(cfg config.UploadConfig) *Fetcher {
+	f := NewFetcher(cfg)
+	f.blocked = func(ip net.IP) bool { return !ip.IsLoopback() && isBlockedIP(ip) }
+	return f
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/leaf.jpg":
+			// Content-Type 声明错误时以文件头为准
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(buf.Bytes())
+		case "/page.png":
+			w.Write([]byte("<html>not an image</html>"))
+		case "/big.png":
+			// 不声明 Content-Length，分块写入
+			w.Write(buf.Bytes())
+			w.(http.Flusher).Flush()
+			w.Write(bytes.Repeat([]byte{0}, 4096))
+		case "/redirect":
+			http.Redirect(w, r, "http://10.0.0.1/leaf.png", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	f := newLocalFetcher(config.UploadConfig{MaxImageSize: 1024})
+	ctx := context.Background()
+
+	data, mimeType, err := f.Fetch(ctx, server.URL+"/leaf.jpg")
+	if err != nil || mimeType != "image/png" || !bytes.Equal(data, buf.Bytes()) {
+		t.Errorf("Fetch = %d bytes, %s, %v", len(data), mimeType, err)
+	}
+	if _, _, err := f.Fetch(ctx, server.URL+"/page.png"); !errors.Is(err, ErrFetchNotImage) {
+		t.Errorf("非图片内容应返回 ErrFetchNotImage, got %v", err)
+	}
+	if _, _, err := f.Fetch(ctx, server.URL+"/big.png"); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("超过大小限制应返回 ErrImageTooLarge, got %v", err)
+	}
+	if _, _, err := f.Fetch(ctx, server.URL+"/redirect"); !errors.Is(err, ErrFetchAddressBlocked) {
+		t.Errorf("重定向到内网地址应被拒绝, got %v", err)
+	}
+	if _, _, err := f.Fetch(ctx, server.URL+"/missing.png"); err == nil {
+		t.Error("404应返回错误")
+	}
+}
+
+func TestFetcher_BlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("不应访问到内网服务")
+	}))
+	defer server.Close()
+
+	f := NewFetcher(config.UploadConfig{})
+	ctx := context.Background()
+
+	// IP字面量在请求前拒绝
+	if _, _, err := f.Fetch(ctx, server.URL+"/leaf.png"); !errors.Is(err, ErrFetchAddressBlocked) {
+		t.Errorf("回环地址应被拒绝, got %v", err)
+	}
+	// 域名在DNS解析后、建立连接时按实际IP拒绝
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+	if _, _, err := f.Fetch(ctx, "http://localhost"+port+"/leaf.png"); !errors.Is(err, ErrFetchAddressBlocked) {
+		t.Errorf("解析到回环地址的域名应被拒绝, got %v", err)
+	}
+
+	for _, rawURL := range []string{"file:///etc/passwd", "ftp://example.com/a.png", "http:///a.png", "://bad"} {
+		if _, _, err := f.Fetch(ctx, rawURL); !errors.Is(err, ErrFetchURLInvalid) {
+			t.Errorf("%s 应返回 ErrFetchURLInvalid, got %v", rawURL, err)
+		}
+	}
+}
+
+func TestFetcher_AllowedHosts(t *testing.T) {
+	f := NewFetcher(config.UploadConfig{FetchAllowedHosts: "cdn.example.com, *.githubusercontent.com"})
+	if _, _, err := f.Fetch(context.Background(), "https://evil.example.org/a.png"); !errors.Is(err, ErrFetchHostNotAllowed) {
+		t.Errorf("不在允许列表中的主机应被拒绝, got %v", err)
+	}
+
+	cases := map[string]bool{
+		"cdn.example.com":                true,
+		"img.cdn.example.com":            false,
+		"raw.githubusercontent.com":      true,
+		"githubusercontent.com":          false,
+		"evil-githubusercontent.com":     false,
+		"raw.githubusercontent.com.evil": false,
+	}
+	for host, want := range cases {
+		if got := hostAllowed(host, f.allowedHosts); got != want {
+			t.Errorf("hostAllowed(%s) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true, // 云服务元数据地址
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"255.255.255.255": true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"64:ff9b::a00:1":  true,
+		"8.8.8.8":         false,
+		"140.82.112.3":    false,
+		"2606:4700::1111": false,
+	}
+	for addr, want := range cases {
+		if got := isBlockedIP(net.ParseIP(addr)); got != want {
+			t.Errorf("isBlockedIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
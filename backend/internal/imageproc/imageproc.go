@@ -0,0 +1,218 @@
+// Package imageproc 图片处理：上传和识别前统一解码、按EXIF方向旋转、去除元数据、缩放并重新编码，
+// 以及安全地下载远程图片
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+
+	"github.com/tango/explore/internal/config"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册WebP解码器
+)
+
+const (
+	// maxPixels 允许解码的最大像素数，防止超大尺寸图片（解压炸弹）耗尽内存
+	maxPixels = 50_000_000
+	// thumbnailQuality 缩略图的JPEG质量
+	thumbnailQuality = 75
+)
+
+// 处理后的图片格式
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+)
+
+var (
+	// ErrUnsupportedImage 无法解码的图片
+	ErrUnsupportedImage = errors.New("无法解码图片，仅支持 JPEG/PNG/WebP/GIF")
+	// ErrImageTooLarge 图片尺寸超过限制
+	ErrImageTooLarge = errors.New("图片尺寸超过限制")
+)
+
+// Options 图片处理参数，为0时使用默认值
+type Options struct {
+	MaxEdge          int // 最长边像素
+	Quality          int // JPEG质量（1-100）
+	ThumbnailMaxEdge int // 缩略图最长边像素
+}
+
+// OptionsFromConfig 从上传配置读取图片处理参数
+func OptionsFromConfig(cfg config.UploadConfig) Options {
+	return Options{
+		MaxEdge:          cfg.ImageMaxEdge,
+		Quality:          cfg.ImageQuality,
+		ThumbnailMaxEdge: cfg.ThumbnailMaxEdge,
+	}
+}
+
+// Result 图片处理结果
+type Result struct {
+	Data           []byte // 处理后的图片（不含任何元数据）
+	ContentType    string // 处理后的格式：image/jpeg（不透明图片）或 image/png（带透明通道）
+	Width          int
+	Height         int
+	Thumbnail      []byte // 缩略图，格式与 ContentType 相同
+	OriginalFormat string // 原图格式：jpeg/png/webp/gif
+	OriginalSize   int    // 原图大小（字节）
+	OriginalWidth  int    // 原图宽度（按EXIF方向旋转后）
+	OriginalHeight int    // 原图高度（按EXIF方向旋转后）
+}
+
+// Ext 处理后图片的文件扩展名
+func (r *Result) Ext() string {
+	if r.ContentType == ContentTypePNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// Normalize 处理图片：解码 JPEG/PNG/WebP/GIF（GIF只取第一帧），缩放到最长边不超过 MaxEdge，
+// 按EXIF方向旋转，然后重新编码（重新编码不会写入EXIF、GPS等任何元数据），同时生成缩略图
+func Normalize(data []byte, opts Options) (*Result, error) {
+	opts = withDefaults(opts)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	orientation := exifOrientation(data)
+	// 先缩放再旋转：最长边不受旋转影响，旋转较小的图片更快
+	scaled := resize(src, opts.MaxEdge)
+	oriented := orient(scaled, orientation)
+
+	contentType := ContentTypeJPEG
+	if !oriented.Opaque() {
+		contentType = ContentTypePNG
+	}
+	encoded, err := encode(oriented, contentType, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := encode(resize(oriented, opts.ThumbnailMaxEdge), contentType, thumbnailQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	originalWidth, originalHeight := cfg.Width, cfg.Height
+	if orientation >= 5 {
+		originalWidth, originalHeight = originalHeight, originalWidth
+	}
+	return &Result{
+		Data:           encoded,
+		ContentType:    contentType,
+		Width:          oriented.Bounds().Dx(),
+		Height:         oriented.Bounds().Dy(),
+		Thumbnail:      thumbnail,
+		OriginalFormat: format,
+		OriginalSize:   len(data),
+		OriginalWidth:  originalWidth,
+		OriginalHeight: originalHeight,
+	}, nil
+}
+
+// withDefaults 补全默认参数
+func withDefaults(opts Options) Options {
+	if opts.MaxEdge <= 0 {
+		opts.MaxEdge = config.DefaultImageMaxEdge
+	}
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = config.DefaultImageQuality
+	}
+	if opts.ThumbnailMaxEdge <= 0 {
+		opts.ThumbnailMaxEdge = config.DefaultThumbnailMaxEdge
+	}
+	return opts
+}
+
+// resize 等比缩放到最长边不超过maxEdge，返回RGBA图片（不需要缩放时只转换格式）
+func resize(src image.Image, maxEdge int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxEdge || height > maxEdge {
+		if width >= height {
+			height = max(1, height*maxEdge/width)
+			width = maxEdge
+		} else {
+			width = max(1, width*maxEdge/height)
+			height = maxEdge
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	}
+	return dst
+}
+
+// orient 按EXIF方向变换图片，使其正向显示
+// 2:水平翻转 3:旋转180° 4:垂直翻转 5:转置 6:顺时针旋转90° 7:反转置 8:逆时针旋转90°
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			si := src.PixOffset(sx, sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// encode 按格式编码图片
+func encode(img image.Image, contentType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == ContentTypePNG {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
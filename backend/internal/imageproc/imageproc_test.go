@@ -0,0 +1,245 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// twoColorImage 左半红色、右半蓝色的图片
+func twoColorImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+// jpegWithOrientation 编码JPEG并插入带方向标签和GPS占位的EXIF段
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("jpeg.Encode failed: %v", err)
+	}
+
+	// TIFF（大端序）：IFD0 包含方向标签和一个GPS IFD指针
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 2)
+	tiff = append(tiff, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0x00, 0x00)
+	tiff = append(tiff, 0x88, 0x25, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	result := append([]byte{}, data[:2]...)
+	result = append(result, app1...)
+	return append(result, data[2:]...)
+}
+
+func TestNormalize_ExifOrientation(t *testing.T) {
+	data := jpegWithOrientation(t, twoColorImage(40, 20), 6)
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("exifOrientation = %d, want 6", got)
+	}
+
+	result, err := Normalize(data, Options{})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	// 顺时针旋转90°后变为20x40，原来的左半（红色）在上方
+	if result.Width != 20 || result.Height != 40 || result.OriginalWidth != 20 || result.OriginalHeight != 40 {
+		t.Errorf("尺寸错误: %dx%d (原图 %dx%d)", result.Width, result.Height, result.OriginalWidth, result.OriginalHeight)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(result.Data))
+	if err != nil {
+		t.Fatalf("处理后的图片无法解码: %v", err)
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Error("旋转后上方应为红色")
+	}
+	if r, _, b, _ := img.At(10, 35).RGBA(); b < r {
+		t.Error("旋转后下方应为蓝色")
+	}
+
+	// 重新编码后不应保留EXIF
+	if bytes.Contains(result.Data, []byte("Exif")) || exifOrientation(result.Data) != 1 {
+		t.Error("处理后的图片不应包含EXIF")
+	}
+	if result.ContentType != ContentTypeJPEG || result.Ext() != ".jpg" || result.OriginalFormat != "jpeg" {
+		t.Errorf("格式错误: %s %s %s", result.ContentType, result.Ext(), result.OriginalFormat)
+	}
+}
+
+func TestNormalize_Downscale(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, twoColorImage(2000, 500)); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+
+	result, err := Normalize(buf.Bytes(), Options{MaxEdge: 800, ThumbnailMaxEdge: 100})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if result.Width != 800 || result.Height != 200 || result.OriginalWidth != 2000 || result.OriginalSize != buf.Len() {
+		t.Errorf("缩放错误: %dx%d, original=%d/%d", result.Width, result.Height, result.OriginalWidth, result.OriginalSize)
+	}
+	// 不透明的PNG重新编码为JPEG
+	if result.ContentType != ContentTypeJPEG {
+		t.Errorf("不透明图片应编码为JPEG, got %s", result.ContentType)
+	}
+
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(result.Thumbnail))
+	if err != nil || thumb.Width != 100 || thumb.Height != 25 {
+		t.Errorf("缩略图错误: %+v, %v", thumb, err)
+	}
+}
+
+func TestNormalize_Transparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 128})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+
+	result, err := Normalize(buf.Bytes(), Options{})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if result.ContentType != ContentTypePNG || result.Ext() != ".png" {
+		t.Errorf("带透明通道的图片应保持PNG, got %s", result.ContentType)
+	}
+}
+
+func TestNormalize_Formats(t *testing.T) {
+	// 1x1 无损 WebP
+	webp, _ := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	result, err := Normalize(webp, Options{})
+	if err != nil || result.OriginalFormat != "webp" {
+		t.Errorf("WebP处理失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	palette := color.Palette{color.White, color.Black}
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 8, 8), palette), nil); err != nil {
+		t.Fatalf("gif.Encode failed: %v", err)
+	}
+	result, err = Normalize(buf.Bytes(), Options{})
+	if err != nil || result.OriginalFormat != "gif" || result.Width != 8 {
+		t.Errorf("GIF处理失败: %v", err)
+	}
+
+	if _, err := Normalize([]byte("not an image"), Options{}); !errors.Is(err, ErrUnsupportedImage) {
+		t.Errorf("无效图片应返回 ErrUnsupportedImage, got %v", err)
+	}
+}
+
+func TestNormalize_TooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	// 把IHDR中的宽高改为 20000x20000，并重新计算CRC
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:20], 20000)
+	binary.BigEndian.PutUint32(data[20:24], 20000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, err := Normalize(data, Options{}); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("超大尺寸应返回 ErrImageTooLarge, got %v", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// 2x1：左红右蓝
+	src := twoColorImage(2, 1)
+	red := color.RGBA{R: 255, A: 255}
+	cases := []struct {
+		orientation int
+		width       int
+		redAt       image.Point
+	}{
+		{1, 2, image.Pt(0, 0)},
+		{2, 2, image.Pt(1, 0)},
+		{3, 2, image.Pt(1, 0)},
+		{4, 2, image.Pt(0, 0)},
+		{5, 1, image.Pt(0, 0)},
+		{6, 1, image.Pt(0, 0)},
+		{7, 1, image.Pt(0, 1)},
+		{8, 1, image.Pt(0, 1)},
+	}
+	for _, tc := range cases {
+		dst := orient(src, tc.orientation)
+		if dst.Bounds().Dx() != tc.width {
+			t.Errorf("orientation %d: 宽度 = %d, want %d", tc.orientation, dst.Bounds().Dx(), tc.width)
+			continue
+		}
+		if got := dst.RGBAAt(tc.redAt.X, tc.redAt.Y); got != red {
+			t.Errorf("orientation %d: %v 处应为红色, got %v", tc.orientation, tc.redAt, got)
+		}
+	}
+}
+
+func TestPerceptualHash(t *testing.T) {
+	// 左右渐变的图片，缩放和重新压缩后感知哈希应基本不变
+	src := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			v := uint8((x*7 + y*3) % 256)
+			src.Set(x, y, color.RGBA{R: v, G: 255 - v, B: uint8(y % 256), A: 255})
+		}
+	}
+	var original bytes.Buffer
+	if err := png.Encode(&original, src); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	processed, err := Normalize(original.Bytes(), Options{MaxEdge: 200, Quality: 60})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	a, err := PerceptualHash(original.Bytes())
+	if err != nil {
+		t.Fatalf("PerceptualHash failed: %v", err)
+	}
+	b, err := PerceptualHash(processed.Data)
+	if err != nil {
+		t.Fatalf("PerceptualHash failed: %v", err)
+	}
+	if d := HammingDistance(a, b); d > 6 {
+		t.Errorf("相似图片的汉明距离 = %d, want <= 6", d)
+	}
+
+	var other bytes.Buffer
+	if err := png.Encode(&other, twoColorImage(400, 300)); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	c, _ := PerceptualHash(other.Bytes())
+	if d := HammingDistance(a, c); d <= 6 {
+		t.Errorf("不同图片的汉明距离 = %d, want > 6", d)
+	}
+
+	if _, err := PerceptualHash([]byte("not an image")); !errors.Is(err, ErrUnsupportedImage) {
+		t.Errorf("无效图片应返回 ErrUnsupportedImage, got %v", err)
+	}
+}
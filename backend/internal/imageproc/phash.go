@@ -0,0 +1,49 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"math/bits"
+
+	"golang.org/x/image/draw"
+)
+
+// PerceptualHash 计算图片的感知哈希（dHash，64位）
+// 缩小为9x8灰度图后比较相邻像素的亮度，缩放、重新压缩、轻微调色后哈希基本不变，
+// 两张图片哈希的汉明距离越小越相似
+func PerceptualHash(data []byte) (uint64, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return 0, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, ErrUnsupportedImage
+	}
+	return DifferenceHash(img), nil
+}
+
+// DifferenceHash 计算已解码图片的dHash
+func DifferenceHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance 两个感知哈希不同的位数（0-64）
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
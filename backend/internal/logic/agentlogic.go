@@ -3,14 +3,16 @@ package logic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/cloudwego/eino/schema"
 	"github.com/google/uuid"
 	"github.com/tango/explore/internal/agent"
+	"github.com/tango/explore/internal/speech"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
@@ -49,11 +51,12 @@ func (l *AgentLogic) StreamAgentConversation(
 		sessionId = uuid.New().String()
 	}
 
-	// 发送连接成功事件
+	// 发送连接成功事件（新会话附带所有者令牌）
 	connectedEvent := types.StreamEvent{
-		Type:      "connected",
-		Content:   map[string]interface{}{"sessionId": sessionId},
-		SessionId: sessionId,
+		Type:       "connected",
+		Content:    map[string]interface{}{"sessionId": sessionId},
+		SessionId:  sessionId,
+		OwnerToken: claimSession(l.svcCtx.Storage, sessionId, req.OwnerToken),
 	}
 	connectedJSON, _ := json.Marshal(connectedEvent)
 	fmt.Fprintf(w, "event: connected\ndata: %s\n\n", string(connectedJSON))
@@ -83,6 +86,13 @@ func (l *AgentLogic) StreamAgentConversation(
 		}
 	}
 
+	// 学习档案中记录的年龄
+	if userAge == 0 && req.LearnerId != "" && l.svcCtx.LearnerStore != nil {
+		if profile, ok := l.svcCtx.LearnerStore.Get(req.LearnerId); ok {
+			userAge = profile.UserAge
+		}
+	}
+
 	// 默认年龄
 	if userAge == 0 {
 		userAge = 8
@@ -135,35 +145,8 @@ func (l *AgentLogic) StreamAgentConversation(
 			w.(http.Flusher).Flush()
 			return fmt.Errorf("messageType为voice时，audio字段必填")
 		}
-		// 语音识别
-		voiceLogic := NewVoiceLogic(l.ctx, l.svcCtx)
-		voiceReq := &types.VoiceRequest{
-			Audio:     req.Audio,
-			SessionId: sessionId,
-		}
-		voiceResp, err := voiceLogic.RecognizeVoice(voiceReq)
-		if err != nil {
-			logger.Errorw("语音识别失败", logx.Field("error", err), logx.Field("sessionId", sessionId))
-			errorEvent := types.StreamEvent{
-				Type:      "error",
-				Content:   map[string]interface{}{"message": "语音识别失败: " + err.Error()},
-				SessionId: sessionId,
-			}
-			errorJSON, _ := json.Marshal(errorEvent)
-			fmt.Fprintf(w, "event: error\ndata: %s\n\n", string(errorJSON))
-			w.(http.Flusher).Flush()
-			return fmt.Errorf("语音识别失败: %w", err)
-		}
-		messageText = voiceResp.Text
+		// 语音识别由MultiAgentGraph完成，识别文本在回调中获取
 		messageType = "voice"
-		recognizedEvent := types.StreamEvent{
-			Type:      "voice_recognized",
-			Content:   map[string]interface{}{"text": voiceResp.Text},
-			SessionId: sessionId,
-		}
-		recognizedJSON, _ := json.Marshal(recognizedEvent)
-		fmt.Fprintf(w, "event: voice_recognized\ndata: %s\n\n", string(recognizedJSON))
-		w.(http.Flusher).Flush()
 	case "image":
 		if req.Image == "" {
 			logger.Errorw("messageType为image时，image字段必填", logx.Field("sessionId", sessionId))
@@ -228,18 +211,9 @@ func (l *AgentLogic) StreamAgentConversation(
 		return fmt.Errorf("不支持的messageType: %s", req.MessageType)
 	}
 
-	// 保存用户消息
-	userMessage := types.ConversationMessage{
-		Id:        uuid.New().String(),
-		Type:      messageType,
-		Sender:    "user",
-		Content:   messageText,
-		Timestamp: time.Now().Format(time.RFC3339),
-		SessionId: sessionId,
-	}
-	l.svcCtx.Storage.AddMessage(sessionId, userMessage)
-
 	// 获取对话历史（转换为eino Message格式）
+	// 历史只包含之前的对话，本轮消息由各Agent作为用户消息追加；
+	// 因此先读取历史再保存用户消息，文本、图片和语音消息（识别后由MultiAgentGraph保存）的历史一致
 	messagesRaw := l.svcCtx.Storage.GetMessages(sessionId)
 	messages := make([]types.ConversationMessage, 0, len(messagesRaw))
 	for _, msgRaw := range messagesRaw {
@@ -247,7 +221,23 @@ func (l *AgentLogic) StreamAgentConversation(
 			messages = append(messages, msg)
 		}
 	}
-	chatHistory := l.convertToEinoMessages(messages, maxContextRounds)
+	// 多Agent各节点随机选择文本模型，不指定模型时按配置的文本模型中最小的预算组装
+	chatHistory := l.svcCtx.ContextManager.Build(l.ctx, l.svcCtx.Storage, sessionId, messages, "", maxContextRounds*2)
+
+	// 保存用户消息（语音消息识别后由MultiAgentGraph保存）
+	userMessageSaved := false
+	if messageType != "voice" {
+		userMessage := types.ConversationMessage{
+			Id:        uuid.New().String(),
+			Type:      messageType,
+			Sender:    "user",
+			Content:   messageText,
+			Timestamp: time.Now().Format(time.RFC3339),
+			SessionId: sessionId,
+		}
+		l.svcCtx.Storage.AddMessage(sessionId, userMessage)
+		userMessageSaved = true
+	}
 
 	// 构建请求（用于MultiAgentGraph）
 	multiAgentReq := &types.UnifiedStreamConversationRequest{
@@ -259,42 +249,124 @@ func (l *AgentLogic) StreamAgentConversation(
 		IdentificationContext: req.IdentificationContext,
 		UserAge:               userAge,
 		MaxContextRounds:      maxContextRounds,
+		LearnerId:             req.LearnerId,
 	}
 
 	// 尝试调用MultiAgentGraph
-	multiAgentGraph, err := agent.NewMultiAgentGraph(l.ctx, l.svcCtx.Config.AI, logger)
+	multiAgentGraph, err := agent.NewMultiAgentGraph(l.ctx, l.svcCtx.Config.AI, l.svcCtx.Models, logger)
 	if err != nil {
 		logger.Errorw("MultiAgentGraph初始化失败，降级到单Agent模式", logx.Field("error", err))
-		// 降级到单Agent模式
+		// 降级到单Agent模式（用户消息已保存时不再重复保存）
 		streamLogic := NewStreamLogic(l.ctx, l.svcCtx)
-		return streamLogic.StreamConversationUnified(w, req)
+		return streamLogic.streamConversation(w, req, userMessageSaved)
+	}
+
+	// 语音识别复用服务级的提供方，识别完成后推送识别结果
+	multiAgentGraph.SetASRProvider(l.svcCtx.ASR)
+	multiAgentGraph.SetSessionStore(l.svcCtx.Storage)
+	if l.svcCtx.MemoryStorage != nil {
+		multiAgentGraph.SetMemoryStorage(l.svcCtx.MemoryStorage)
+	}
+	if l.svcCtx.LearnerStore != nil {
+		multiAgentGraph.SetLearnerProfileStore(l.svcCtx.LearnerStore)
 	}
+	multiAgentGraph.SetTranscriptHandler(func(recognition *speech.Recognition) {
+		messageText = recognition.Text
+		recognizedEvent := types.StreamEvent{
+			Type: "voice_recognized",
+			Content: map[string]interface{}{
+				"text":       recognition.Text,
+				"confidence": recognition.Confidence,
+				"segments":   voiceSegments(recognition),
+			},
+			SessionId: sessionId,
+		}
+		recognizedJSON, _ := json.Marshal(recognizedEvent)
+		fmt.Fprintf(w, "event: voice_recognized\ndata: %s\n\n", string(recognizedJSON))
+		w.(http.Flusher).Flush()
+	})
 
-	// 调用MultiAgentGraph执行对话
-	answer, err := multiAgentGraph.ExecuteMultiAgentConversation(l.ctx, multiAgentReq, chatHistory)
+	// 调用MultiAgentGraph流式执行对话
+	answerStream, err := multiAgentGraph.StreamMultiAgentConversation(l.ctx, multiAgentReq, chatHistory)
+	if err != nil && speech.IsRecognitionError(err) {
+		// 语音识别失败时降级也无法识别，直接返回错误
+		logger.Errorw("语音识别失败", logx.Field("error", err), logx.Field("sessionId", sessionId))
+		errorEvent := types.StreamEvent{
+			Type:      "error",
+			Content:   map[string]interface{}{"message": "语音识别失败: " + voiceAPIError(err).Error()},
+			SessionId: sessionId,
+		}
+		errorJSON, _ := json.Marshal(errorEvent)
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", string(errorJSON))
+		w.(http.Flusher).Flush()
+		return fmt.Errorf("语音识别失败: %w", err)
+	}
+	if req.Debug {
+		l.sendAgentSteps(w, multiAgentGraph, sessionId)
+	}
 	if err != nil {
 		logger.Errorw("MultiAgentGraph执行失败，降级到单Agent模式", logx.Field("error", err))
-		// 降级到单Agent模式
+		// 降级到单Agent模式（语音已识别时以文本继续，避免重复识别；识别结果已由MultiAgentGraph保存）
+		fallbackReq := req
+		if messageType == "voice" && messageText != "" {
+			fallbackReq.MessageType = "text"
+			fallbackReq.Message = messageText
+			fallbackReq.Audio = ""
+			userMessageSaved = true
+		}
 		streamLogic := NewStreamLogic(l.ctx, l.svcCtx)
-		return streamLogic.StreamConversationUnified(w, req)
+		return streamLogic.streamConversation(w, fallbackReq, userMessageSaved)
 	}
 
-	// 流式返回回答
+	// 流式返回回答：领域Agent生成的文本块到达即发送
 	messageId := uuid.New().String()
-	words := []rune(answer)
-	for i, word := range words {
-		event := types.StreamEvent{
-			Type:      "message",
-			Content:   string(word),
-			Index:     i,
-			SessionId: sessionId,
-			MessageId: messageId,
-			Markdown:  true,
+	var answerBuilder strings.Builder
+	index := 0
+	for {
+		chunk, err := answerStream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			answerStream.Close()
+			logger.Errorw("读取多Agent流式回答失败", logx.Field("error", err), logx.Field("sessionId", sessionId))
+			errorEvent := types.StreamEvent{
+				Type:      "error",
+				Content:   map[string]interface{}{"message": "流式读取失败: " + err.Error()},
+				SessionId: sessionId,
+			}
+			errorJSON, _ := json.Marshal(errorEvent)
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", string(errorJSON))
+			w.(http.Flusher).Flush()
+			return err
+		}
+		if chunk == nil || chunk.Content == "" {
+			continue
+		}
+
+		answerBuilder.WriteString(chunk.Content)
+		for _, char := range chunk.Content {
+			event := types.StreamEvent{
+				Type:      "message",
+				Content:   string(char),
+				Index:     index,
+				SessionId: sessionId,
+				MessageId: messageId,
+				Markdown:  true,
+			}
+			eventJSON, _ := json.Marshal(event)
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", string(eventJSON))
+			w.(http.Flusher).Flush()
+			index++
+		}
+		if req.Debug {
+			l.sendAgentSteps(w, multiAgentGraph, sessionId)
 		}
-		eventJSON, _ := json.Marshal(event)
-		fmt.Fprintf(w, "event: message\ndata: %s\n\n", string(eventJSON))
-		w.(http.Flusher).Flush()
-		time.Sleep(30 * time.Millisecond) // 打字机效果
+	}
+	answerStream.Close()
+	answer := answerBuilder.String()
+	if req.Debug {
+		l.sendAgentSteps(w, multiAgentGraph, sessionId)
 	}
 
 	// 保存助手消息
@@ -309,6 +381,12 @@ func (l *AgentLogic) StreamAgentConversation(
 	}
 	l.svcCtx.Storage.AddMessage(sessionId, assistantMessage)
 
+	// 语音回复（在完成事件之前发送）
+	ttsLogic := NewTTSLogic(l.ctx, l.svcCtx)
+	if ttsLogic.ShouldSpeak(req.Tts, userAge) {
+		ttsLogic.StreamAudio(w, sessionId, messageId, answer)
+	}
+
 	// 发送完成事件
 	doneEvent := types.StreamEvent{
 		Type:      "done",
@@ -320,32 +398,23 @@ func (l *AgentLogic) StreamAgentConversation(
 	fmt.Fprintf(w, "event: done\ndata: %s\n\n", string(doneJSON))
 	w.(http.Flusher).Flush()
 
+	// 回答已送达，异步执行Reflection和Memory
+	answerStream.Finish(l.ctx)
+
 	return nil
 }
 
-// convertToEinoMessages 转换对话消息为eino Message格式
-func (l *AgentLogic) convertToEinoMessages(messages []types.ConversationMessage, maxRounds int) []*schema.Message {
-	result := make([]*schema.Message, 0)
-	
-	// 限制消息数量
-	startIdx := 0
-	if len(messages) > maxRounds*2 {
-		startIdx = len(messages) - maxRounds*2
-	}
-
-	for i := startIdx; i < len(messages); i++ {
-		msg := messages[i]
-		var einoMsg *schema.Message
-		
-		if msg.Sender == "user" {
-			einoMsg = schema.UserMessage(fmt.Sprintf("%v", msg.Content))
-		} else {
-			einoMsg = schema.AssistantMessage(fmt.Sprintf("%v", msg.Content), nil)
+// sendAgentSteps 推送尚未发送的Agent执行步骤（agent_step事件，仅debug模式）
+// 回答发送后异步执行的Reflection和Memory只记录在执行轨迹日志中
+func (l *AgentLogic) sendAgentSteps(w http.ResponseWriter, graph *agent.MultiAgentGraph, sessionId string) {
+	for _, step := range graph.DrainSteps() {
+		event := types.StreamEvent{
+			Type:      "agent_step",
+			Content:   step,
+			SessionId: sessionId,
 		}
-		
-		result = append(result, einoMsg)
+		eventJSON, _ := json.Marshal(event)
+		fmt.Fprintf(w, "event: agent_step\ndata: %s\n\n", string(eventJSON))
+		w.(http.Flusher).Flush()
 	}
-	
-	return result
 }
-
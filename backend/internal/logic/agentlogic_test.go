@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/tango/explore/internal/config"
@@ -66,6 +67,60 @@ func TestAgentLogic_StreamAgentConversation(t *testing.T) {
 	}
 }
 
+func TestAgentLogic_StreamAgentConversation_Debug(t *testing.T) {
+	svcCtx := &svc.ServiceContext{Storage: storage.NewMemoryStorage()}
+	req := types.UnifiedStreamConversationRequest{
+		MessageType: "text",
+		Message:     "这是什么？",
+		SessionId:   "debug-session",
+		UserAge:     10,
+	}
+
+	w := httptest.NewRecorder()
+	if err := NewAgentLogic(context.Background(), svcCtx).StreamAgentConversation(w, req); err != nil {
+		t.Fatalf("StreamAgentConversation failed: %v", err)
+	}
+	if strings.Contains(w.Body.String(), "event: agent_step") {
+		t.Error("未开启debug时不应推送agent_step事件")
+	}
+
+	req.Debug = true
+	w = httptest.NewRecorder()
+	if err := NewAgentLogic(context.Background(), svcCtx).StreamAgentConversation(w, req); err != nil {
+		t.Fatalf("StreamAgentConversation failed: %v", err)
+	}
+
+	nodes := []string{}
+	firstMessage, lastStep := -1, -1
+	for i, block := range strings.Split(strings.TrimSpace(w.Body.String()), "\n\n") {
+		lines := strings.SplitN(block, "\n", 2)
+		switch lines[0] {
+		case "event: message":
+			if firstMessage < 0 {
+				firstMessage = i
+			}
+		case "event: agent_step":
+			var event struct {
+				Content types.AgentStep `json:"content"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &event); err != nil {
+				t.Fatalf("解析agent_step失败: %v", err)
+			}
+			if event.Content.Status != "started" {
+				nodes = append(nodes, event.Content.Node)
+			}
+			lastStep = i
+		}
+	}
+
+	if firstMessage < 0 || lastStep < 0 {
+		t.Fatalf("应同时推送message和agent_step事件: %s", w.Body.String())
+	}
+	if len(nodes) < 5 || nodes[0] != "Intent" || nodes[len(nodes)-1] != "Interaction" {
+		t.Errorf("完成的步骤 = %v", nodes)
+	}
+}
+
 func TestAgentLogic_StreamAgentConversation_ErrorHandling(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Config{
@@ -99,3 +154,42 @@ func TestAgentLogic_StreamAgentConversation_ErrorHandling(t *testing.T) {
 	}
 }
 
+
+// countUserMessages 会话中用户消息的数量
+func countUserMessages(store storage.SessionStore, sessionId string) int {
+	count := 0
+	for _, raw := range store.GetMessages(sessionId) {
+		if msg, ok := raw.(types.ConversationMessage); ok && msg.Sender == "user" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAgentLogic_StreamAgentConversation_SavesUserMessageOnce(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	svcCtx := &svc.ServiceContext{Storage: store}
+
+	req := types.UnifiedStreamConversationRequest{
+		MessageType: "text",
+		Message:     "银杏为什么会变黄？",
+		SessionId:   "session-once",
+		UserAge:     8,
+	}
+	if err := NewAgentLogic(context.Background(), svcCtx).StreamAgentConversation(httptest.NewRecorder(), req); err != nil {
+		t.Fatalf("StreamAgentConversation failed: %v", err)
+	}
+	if n := countUserMessages(store, "session-once"); n != 1 {
+		t.Errorf("用户消息应只保存一次, got %d", n)
+	}
+
+	// 多Agent降级到单Agent时，已保存的用户消息不再重复保存
+	req.SessionId = "session-fallback"
+	store.AddMessage(req.SessionId, types.ConversationMessage{Id: "u1", Type: "text", Sender: "user", Content: req.Message, SessionId: req.SessionId})
+	if err := NewStreamLogic(context.Background(), svcCtx).streamConversation(httptest.NewRecorder(), req, true); err != nil {
+		t.Fatalf("streamConversation failed: %v", err)
+	}
+	if n := countUserMessages(store, req.SessionId); n != 1 {
+		t.Errorf("降级时不应重复保存用户消息, got %d", n)
+	}
+}
@@ -135,9 +135,10 @@ func (l *ConversationLogic) ProcessMessage(req *types.ConversationRequest) (*typ
 	)
 
 	return &types.ConversationResponse{
-		Message:   assistantMessage,
-		SessionId: sessionId,
-		Type:      responseType,
+		Message:    assistantMessage,
+		SessionId:  sessionId,
+		Type:       responseType,
+		OwnerToken: claimSession(l.svcCtx.Storage, sessionId, req.OwnerToken),
 	}, nil
 }
 
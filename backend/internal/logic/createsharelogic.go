@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/tango/explore/internal/storage"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
@@ -33,28 +34,52 @@ func (l *CreateShareLogic) CreateShare(req *types.CreateShareRequest) (resp *typ
 		return nil, utils.NewAPIError(400, "探索记录和收藏卡片不能同时为空")
 	}
 
+	// 计算有效期
+	defaultTTL, maxTTL := shareTTLLimits(l.svcCtx.Config.Share)
+	ttl := defaultTTL
+	if req.ExpiresInHours < 0 {
+		return nil, utils.NewAPIError(400, "分享有效期不能为负数")
+	}
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+		if ttl > maxTTL {
+			return nil, utils.NewAPIError(400, fmt.Sprintf("分享有效期不能超过%d小时", int(maxTTL.Hours())))
+		}
+	}
+
+	// 生成所有者令牌（仅在创建时返回，用于撤销分享）
+	ownerToken, err := newOwnerToken()
+	if err != nil {
+		l.Errorw("生成分享所有者令牌失败", logx.Field("error", err))
+		return nil, utils.ErrInternalServer
+	}
+
 	// 生成分享ID
 	shareId := uuid.New().String()
 	now := time.Now()
-	expiresAt := now.Add(7 * 24 * time.Hour) // 7天后过期
+	expiresAt := now.Add(ttl)
 
-	// 保存到内存存储
-	store := GetShareStore()
-	store.Save(shareId, &ShareData{
-		ShareId:           shareId,
+	// 保存到分享存储
+	if err := l.svcCtx.ShareStore.Save(shareId, &storage.ShareData{
+		ShareId:            shareId,
 		ExplorationRecords: req.ExplorationRecords,
 		CollectedCards:     req.CollectedCards,
+		OwnerTokenHash:     hashOwnerToken(ownerToken),
 		CreatedAt:          now,
 		ExpiresAt:          expiresAt,
-	})
+	}); err != nil {
+		l.Errorw("保存分享数据失败", logx.Field("shareId", shareId), logx.Field("error", err))
+		return nil, utils.ErrInternalServer
+	}
 
 	// 生成分享URL（这里使用相对路径，实际部署时需要配置完整URL）
 	shareUrl := fmt.Sprintf("/api/share/%s", shareId)
 
 	resp = &types.CreateShareResponse{
-		ShareId:   shareId,
-		ShareUrl:  shareUrl,
-		ExpiresAt: expiresAt.Format(time.RFC3339),
+		ShareId:    shareId,
+		ShareUrl:   shareUrl,
+		ExpiresAt:  expiresAt.Format(time.RFC3339),
+		OwnerToken: ownerToken,
 	}
 
 	l.Infow("创建分享链接", logx.Field("shareId", shareId), logx.Field("recordCount", len(req.ExplorationRecords)), logx.Field("cardCount", len(req.CollectedCards)))
@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteSessionLogic {
+	return &DeleteSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteSession 删除会话及其Memory Agent记忆记录，仅会话所有者（持有所有者令牌）可以删除
+func (l *DeleteSessionLogic) DeleteSession(req *types.DeleteSessionRequest) (resp *types.DeleteSessionResponse, err error) {
+	if req.SessionId == "" {
+		return nil, utils.NewAPIError(400, "会话ID不能为空")
+	}
+	if req.OwnerToken == "" {
+		return nil, utils.ErrSessionForbidden
+	}
+
+	if _, ok := l.svcCtx.Storage.GetSession(req.SessionId); !ok {
+		return nil, utils.ErrSessionNotFound
+	}
+	if !sessionOwnedBy(l.svcCtx.Storage, req.SessionId, req.OwnerToken) {
+		l.Infow("删除会话被拒绝：所有者令牌不匹配", logx.Field("sessionId", req.SessionId))
+		return nil, utils.ErrSessionForbidden
+	}
+
+	l.svcCtx.Storage.DeleteSession(req.SessionId)
+	if l.svcCtx.MemoryStorage != nil {
+		l.svcCtx.MemoryStorage.DeleteMemoryRecord(req.SessionId)
+	}
+
+	l.Infow("删除会话", logx.Field("sessionId", req.SessionId))
+	return &types.DeleteSessionResponse{
+		SessionId: req.SessionId,
+		Deleted:   true,
+	}, nil
+}
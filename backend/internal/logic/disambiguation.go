@@ -0,0 +1,112 @@
+package logic
+
+import (
+	"strings"
+
+	"github.com/tango/explore/internal/agent/nodes"
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/types"
+)
+
+// disambiguationDataKey 会话中待孩子选择的候选对象
+const disambiguationDataKey = "disambiguation"
+
+// disambiguationThreshold 多对象消歧的置信度阈值，返回负数表示关闭
+func disambiguationThreshold(cfg config.AIConfig) float64 {
+	if cfg.DisambiguationThreshold == 0 {
+		return config.DefaultDisambiguationThreshold
+	}
+	return cfg.DisambiguationThreshold
+}
+
+// toDetectedObjects 将识别节点的候选对象转换为接口类型
+func toDetectedObjects(objects []nodes.RecognizedObject) []types.DetectedObject {
+	if len(objects) == 0 {
+		return nil
+	}
+	result := make([]types.DetectedObject, 0, len(objects))
+	for _, obj := range objects {
+		detected := types.DetectedObject{
+			ObjectName:     obj.ObjectName,
+			ObjectCategory: obj.ObjectCategory,
+			CategoryPath:   obj.CategoryPath,
+			Confidence:     obj.Confidence,
+			Keywords:       obj.Keywords,
+		}
+		if obj.BoundingBox != nil {
+			detected.BoundingBox = &types.BoundingBox{
+				X:      obj.BoundingBox.X,
+				Y:      obj.BoundingBox.Y,
+				Width:  obj.BoundingBox.Width,
+				Height: obj.BoundingBox.Height,
+			}
+		}
+		result = append(result, detected)
+	}
+	return result
+}
+
+// needsDisambiguation 有多个候选对象且最高置信度低于阈值时，需要孩子选择
+func needsDisambiguation(objects []types.DetectedObject, threshold float64) bool {
+	return threshold > 0 && len(objects) >= 2 && objects[0].Confidence < threshold
+}
+
+// newDisambiguation 生成询问孩子想了解哪个对象的消歧内容
+func newDisambiguation(objects []types.DetectedObject, imageURL string) *types.Disambiguation {
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, obj.ObjectName)
+	}
+	return &types.Disambiguation{
+		Question:   "我在照片里看到了" + strings.Join(names, "、") + "，你想了解哪一个呢？",
+		Candidates: objects,
+		ImageUrl:   imageURL,
+	}
+}
+
+// identificationFromObject 用选中的候选对象生成识别结果上下文
+func identificationFromObject(obj types.DetectedObject, age int) *types.IdentificationContext {
+	return &types.IdentificationContext{
+		ObjectName:     obj.ObjectName,
+		ObjectCategory: obj.ObjectCategory,
+		Confidence:     obj.Confidence,
+		Keywords:       obj.Keywords,
+		Age:            age,
+		BoundingBox:    obj.BoundingBox,
+	}
+}
+
+// pendingDisambiguation 读取会话中待孩子选择的候选对象
+func pendingDisambiguation(store storage.SessionStore, sessionId string) *types.Disambiguation {
+	value, ok := store.GetData(sessionId, disambiguationDataKey)
+	if !ok {
+		return nil
+	}
+	d, _ := value.(*types.Disambiguation)
+	if d == nil || len(d.Candidates) == 0 {
+		return nil
+	}
+	return d
+}
+
+// clearDisambiguation 孩子选择后清除待选择的候选对象
+func clearDisambiguation(store storage.SessionStore, sessionId string) {
+	if pendingDisambiguation(store, sessionId) != nil {
+		store.SetData(sessionId, disambiguationDataKey, (*types.Disambiguation)(nil))
+	}
+}
+
+// matchCandidate 按孩子的回复匹配候选对象（回复中提到对象名称，如“小猫”“我想知道小猫”）
+func matchCandidate(d *types.Disambiguation, message string) (types.DetectedObject, bool) {
+	message = strings.TrimSpace(message)
+	if d == nil || message == "" {
+		return types.DetectedObject{}, false
+	}
+	for _, candidate := range d.Candidates {
+		if candidate.ObjectName != "" && strings.Contains(message, candidate.ObjectName) {
+			return candidate, true
+		}
+	}
+	return types.DetectedObject{}, false
+}
@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/tango/explore/internal/agent/nodes"
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/types"
+)
+
+func TestNeedsDisambiguation(t *testing.T) {
+	cat := types.DetectedObject{ObjectName: "小猫", Confidence: 0.5}
+	sofa := types.DetectedObject{ObjectName: "沙发", Confidence: 0.4}
+
+	if !needsDisambiguation([]types.DetectedObject{cat, sofa}, 0.6) {
+		t.Error("多个候选对象且置信度低于阈值时需要消歧")
+	}
+	if needsDisambiguation([]types.DetectedObject{cat}, 0.6) {
+		t.Error("只有一个候选对象时不需要消歧")
+	}
+	if needsDisambiguation([]types.DetectedObject{{ObjectName: "小猫", Confidence: 0.9}, sofa}, 0.6) {
+		t.Error("最高置信度达到阈值时不需要消歧")
+	}
+	if needsDisambiguation([]types.DetectedObject{cat, sofa}, disambiguationThreshold(config.AIConfig{DisambiguationThreshold: -1})) {
+		t.Error("阈值为负数时关闭消歧")
+	}
+	if disambiguationThreshold(config.AIConfig{}) != config.DefaultDisambiguationThreshold {
+		t.Error("未配置时使用默认阈值")
+	}
+}
+
+func TestDisambiguationFlow(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	sessionId := "session-disambiguation"
+
+	objects := toDetectedObjects([]nodes.RecognizedObject{
+		{ObjectName: "小猫", ObjectCategory: "自然类", Confidence: 0.5, BoundingBox: &nodes.BoundingBox{X: 0.3, Y: 0.2, Width: 0.3, Height: 0.4}},
+		{ObjectName: "沙发", ObjectCategory: "生活类", Confidence: 0.4},
+	})
+	d := newDisambiguation(objects, "https://cdn.example.com/cat.jpg")
+	if d.Question != "我在照片里看到了小猫、沙发，你想了解哪一个呢？" {
+		t.Errorf("Question = %s", d.Question)
+	}
+
+	store.SetData(sessionId, disambiguationDataKey, d)
+	pending := pendingDisambiguation(store, sessionId)
+	if pending == nil {
+		t.Fatal("应读取到待选择的候选对象")
+	}
+
+	if _, ok := matchCandidate(pending, "我也不知道"); ok {
+		t.Error("未提到候选对象时不应匹配")
+	}
+	chosen, ok := matchCandidate(pending, "我想知道小猫！")
+	if !ok || chosen.ObjectName != "小猫" {
+		t.Fatalf("应匹配到小猫, got %+v", chosen)
+	}
+	identCtx := identificationFromObject(chosen, 6)
+	if identCtx.ObjectName != "小猫" || identCtx.Age != 6 || identCtx.BoundingBox == nil || identCtx.BoundingBox.X != 0.3 {
+		t.Errorf("识别结果上下文错误: %+v", identCtx)
+	}
+
+	clearDisambiguation(store, sessionId)
+	if pendingDisambiguation(store, sessionId) != nil {
+		t.Error("选择后应清除候选对象")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/tango/explore/internal/agent/nodes"
+	"github.com/tango/explore/internal/cards"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
@@ -39,8 +42,12 @@ func (l *GenerateCardsLogic) GenerateCards(req *types.GenerateCardsRequest) (res
 	if req.Age < 3 || req.Age > 18 {
 		return nil, utils.ErrInvalidAge
 	}
+	cardTypes, err := resolveCardTypes(req.CardTypes)
+	if err != nil {
+		return nil, err
+	}
 
-	l.Infow("生成知识卡片", logx.Field("objectName", req.ObjectName), logx.Field("category", req.ObjectCategory), logx.Field("age", req.Age))
+	l.Infow("生成知识卡片", logx.Field("objectName", req.ObjectName), logx.Field("category", req.ObjectCategory), logx.Field("age", req.Age), logx.Field("cardTypes", cardTypes))
 
 	// 检查配置：如果UseAIModel为true，必须使用AI模型，不允许Mock降级
 	useAIModel := l.svcCtx.Config.AI.UseAIModel
@@ -57,10 +64,10 @@ func (l *GenerateCardsLogic) GenerateCards(req *types.GenerateCardsRequest) (res
 				return nil, fmt.Errorf("Graph未初始化，无法生成卡片")
 			}
 			// 如果UseAIModel为false，允许使用Mock数据
-			return l.generateCardsMock(req)
+			return l.generateCardsMock(req, cardTypes)
 		}
 		
-		data, err := graph.ExecuteCardGeneration(l.ctx, req.ObjectName, req.ObjectCategory, req.Age, req.Keywords)
+		data, err := graph.ExecuteCardGeneration(l.ctx, req.ObjectName, req.ObjectCategory, req.Age, req.Keywords, cardTypes)
 		if err != nil {
 			l.Errorw("Agent卡片生成失败",
 				logx.Field("error", err),
@@ -75,10 +82,10 @@ func (l *GenerateCardsLogic) GenerateCards(req *types.GenerateCardsRequest) (res
 			l.Infow("USE_AI_MODEL=false，降级到Mock数据",
 				logx.Field("error", err),
 			)
-			return l.generateCardsMock(req)
+			return l.generateCardsMock(req, cardTypes)
 		}
 
-		// 转换Agent返回的卡片数据为types.CardContent
+		// 转换Agent返回的卡片数据为types.CardContent（生成失败的卡片为nil，跳过）
 		cards := make([]types.CardContent, 0, len(data.Cards))
 		for _, cardData := range data.Cards {
 			if cardMap, ok := cardData.(map[string]interface{}); ok {
@@ -105,10 +112,11 @@ func (l *GenerateCardsLogic) GenerateCards(req *types.GenerateCardsRequest) (res
 		}
 
 		resp = &types.GenerateCardsResponse{
-			Cards: cards,
+			Cards:  cards,
+			Errors: toCardErrors(data.CardErrors),
 		}
 
-		l.Infow("卡片生成完成（Agent）", logx.Field("cardCount", len(cards)))
+		l.Infow("卡片生成完成（Agent）", logx.Field("cardCount", len(cards)), logx.Field("failedCount", len(resp.Errors)))
 		return resp, nil
 	}
 
@@ -127,7 +135,7 @@ func (l *GenerateCardsLogic) GenerateCards(req *types.GenerateCardsRequest) (res
 	l.Infow("USE_AI_MODEL=false，使用Mock数据",
 		logx.Field("agentNil", l.svcCtx.Agent == nil),
 	)
-	return l.generateCardsMock(req)
+	return l.generateCardsMock(req, cardTypes)
 }
 
 // GenerateCardsStream 流式生成知识卡片（每生成完一张立即返回）
@@ -142,11 +150,16 @@ func (l *GenerateCardsLogic) GenerateCardsStream(w http.ResponseWriter, req *typ
 	if req.Age < 3 || req.Age > 18 {
 		return utils.ErrInvalidAge
 	}
+	cardTypes, err := resolveCardTypes(req.CardTypes)
+	if err != nil {
+		return err
+	}
 
 	l.Infow("开始流式生成知识卡片",
 		logx.Field("objectName", req.ObjectName),
 		logx.Field("category", req.ObjectCategory),
 		logx.Field("age", req.Age),
+		logx.Field("cardTypes", cardTypes),
 	)
 
 	// 检查配置：如果UseAIModel为true，必须使用AI模型，不允许Mock降级
@@ -181,12 +194,12 @@ func (l *GenerateCardsLogic) GenerateCardsStream(w http.ResponseWriter, req *typ
 				return fmt.Errorf("Graph未初始化，无法生成卡片")
 			}
 			// 如果UseAIModel为false，允许使用Mock数据
-			return l.generateCardsStreamMock(w, req)
+			return l.generateCardsStreamMock(w, req, cardTypes)
 		}
 
 		// 调用ExecuteCardGeneration（并行生成，等待模型返回，不设置超时）
 		// 超时控制由HTTP请求层面的Timeout配置控制（在explore.yaml中配置为180秒）
-		data, err := graph.ExecuteCardGeneration(l.ctx, req.ObjectName, req.ObjectCategory, req.Age, req.Keywords)
+		data, err := graph.ExecuteCardGeneration(l.ctx, req.ObjectName, req.ObjectCategory, req.Age, req.Keywords, cardTypes)
 		if err != nil {
 			l.Errorw("卡片生成失败",
 				logx.Field("error", err),
@@ -208,7 +221,7 @@ func (l *GenerateCardsLogic) GenerateCardsStream(w http.ResponseWriter, req *typ
 			l.Infow("USE_AI_MODEL=false，降级到Mock数据",
 				logx.Field("error", err),
 			)
-			return l.generateCardsStreamMock(w, req)
+			return l.generateCardsStreamMock(w, req, cardTypes)
 		}
 
 		l.Infow("Agent卡片生成成功",
@@ -252,6 +265,18 @@ func (l *GenerateCardsLogic) GenerateCardsStream(w http.ResponseWriter, req *typ
 			}
 		}
 
+		// 部分卡片生成失败时逐张发送错误事件，已生成的卡片照常返回
+		for _, cardErr := range toCardErrors(data.CardErrors) {
+			errorEvent := map[string]interface{}{
+				"type":    "card_error",
+				"content": cardErr,
+				"index":   cardErr.Index,
+			}
+			errorJSON, _ := json.Marshal(errorEvent)
+			fmt.Fprintf(w, "event: card_error\ndata: %s\n\n", string(errorJSON))
+			w.(http.Flusher).Flush()
+		}
+
 		// 发送完成事件
 		doneEvent := map[string]interface{}{
 			"type": "done",
@@ -288,19 +313,18 @@ func (l *GenerateCardsLogic) GenerateCardsStream(w http.ResponseWriter, req *typ
 	l.Infow("USE_AI_MODEL=false，使用Mock数据流式返回",
 		logx.Field("agentNil", l.svcCtx.Agent == nil),
 	)
-	return l.generateCardsStreamMock(w, req)
+	return l.generateCardsStreamMock(w, req, cardTypes)
 }
 
 // generateCardsStreamMock Mock流式返回
-func (l *GenerateCardsLogic) generateCardsStreamMock(w http.ResponseWriter, req *types.GenerateCardsRequest) error {
-	cards := []types.CardContent{
-		l.getMockCardByIndex(0, req.ObjectName, req.Age),
-		l.getMockCardByIndex(1, req.ObjectName, req.Age),
-		l.getMockCardByIndex(2, req.ObjectName, req.Age),
+func (l *GenerateCardsLogic) generateCardsStreamMock(w http.ResponseWriter, req *types.GenerateCardsRequest, cardTypes []string) error {
+	mockCards := make([]types.CardContent, 0, len(cardTypes))
+	for _, cardType := range cardTypes {
+		mockCards = append(mockCards, l.getMockCard(cardType, req.ObjectName, req.Age))
 	}
 
 	// 模拟流式返回，每张卡片间隔100ms
-	for i, card := range cards {
+	for i, card := range mockCards {
 		cardEvent := map[string]interface{}{
 			"type":    "card",
 			"content": card,
@@ -323,92 +347,106 @@ func (l *GenerateCardsLogic) generateCardsStreamMock(w http.ResponseWriter, req
 	return nil
 }
 
-// getMockCardByIndex 根据索引获取Mock卡片
-func (l *GenerateCardsLogic) getMockCardByIndex(idx int, objectName string, age int) types.CardContent {
-	switch idx {
-	case 0: // 科学卡
+// getMockCard 根据卡片类型获取Mock卡片
+func (l *GenerateCardsLogic) getMockCard(cardType string, objectName string, age int) types.CardContent {
+	switch cardType {
+	case cards.TypeScience: // 科学卡
 		return types.CardContent{
 			Type:  "science",
 			Title: objectName + "的科学知识",
-			Content: map[string]interface{}{
-				"name":        objectName,
-				"explanation": l.getScienceExplanation(objectName, age),
-				"facts":       l.getScienceFacts(objectName, age),
-				"funFact":     l.getFunFact(objectName, age),
-			},
+			Content: cards.ToMap(cards.ScienceCard{
+				SchemaVersion: cards.ScienceSchemaVersion,
+				Name:          objectName,
+				Explanation:   l.getScienceExplanation(objectName, age),
+				Facts:         l.getScienceFacts(objectName, age),
+				FunFact:       l.getFunFact(objectName, age),
+			}),
 		}
-	case 1: // 诗词卡
+	case cards.TypePoetry: // 诗词卡
 		return types.CardContent{
 			Type:  "poetry",
 			Title: "古人怎么看" + objectName,
-			Content: map[string]interface{}{
-				"poem":        l.getPoem(objectName),
-				"poemSource":  l.getPoemSource(objectName),
-				"explanation": l.getPoemExplanation(objectName, age),
-				"context":     l.getContext(objectName, age),
-			},
+			Content: cards.ToMap(cards.PoetryCard{
+				SchemaVersion: cards.PoetrySchemaVersion,
+				Poem:          l.getPoem(objectName),
+				PoemSource:    l.getPoemSource(objectName),
+				Explanation:   l.getPoemExplanation(objectName, age),
+				Context:       l.getContext(objectName, age),
+			}),
 		}
-	case 2: // 英语卡
+	case cards.TypeEnglish: // 英语卡
 		return types.CardContent{
 			Type:  "english",
 			Title: "用英语说" + objectName,
-			Content: map[string]interface{}{
-				"keywords":      l.getEnglishKeywords(objectName),
-				"expressions":   l.getEnglishExpressions(objectName, age),
-				"pronunciation": l.getPronunciation(objectName),
-			},
+			Content: cards.ToMap(cards.EnglishCard{
+				SchemaVersion: cards.EnglishSchemaVersion,
+				Keywords:      l.getEnglishKeywords(objectName),
+				Expressions:   l.getEnglishExpressions(objectName, age),
+				Pronunciation: l.getPronunciation(objectName),
+			}),
 		}
-	default:
-		return types.CardContent{}
+	default: // 数学、历史、艺术、安全知识、汉字等通用模板卡片
+		card, _ := toCardContent(nodes.MockCard(cardType, objectName))
+		return card
 	}
 }
 
 // generateCardsMock Mock实现（保留作为回退方案）
-func (l *GenerateCardsLogic) generateCardsMock(req *types.GenerateCardsRequest) (*types.GenerateCardsResponse, error) {
-	// Mock数据：根据对象名称和年龄生成三张卡片
-	cards := []types.CardContent{
-		// 科学认知卡
-		{
-			Type:  "science",
-			Title: req.ObjectName + "的科学知识",
-			Content: map[string]interface{}{
-				"name":        req.ObjectName,
-				"explanation": l.getScienceExplanation(req.ObjectName, req.Age),
-				"facts":       l.getScienceFacts(req.ObjectName, req.Age),
-				"funFact":     l.getFunFact(req.ObjectName, req.Age),
-			},
-		},
-		// 古诗词/人文卡
-		{
-			Type:  "poetry",
-			Title: "古人怎么看" + req.ObjectName,
-			Content: map[string]interface{}{
-				"poem":        l.getPoem(req.ObjectName),
-				"poemSource":  l.getPoemSource(req.ObjectName),
-				"explanation": l.getPoemExplanation(req.ObjectName, req.Age),
-				"context":     l.getContext(req.ObjectName, req.Age),
-			},
-		},
-		// 英语表达卡
-		{
-			Type:  "english",
-			Title: "用英语说" + req.ObjectName,
-			Content: map[string]interface{}{
-				"keywords":      l.getEnglishKeywords(req.ObjectName),
-				"expressions":   l.getEnglishExpressions(req.ObjectName, req.Age),
-				"pronunciation": l.getPronunciation(req.ObjectName),
-			},
-		},
+func (l *GenerateCardsLogic) generateCardsMock(req *types.GenerateCardsRequest, cardTypes []string) (*types.GenerateCardsResponse, error) {
+	// Mock数据：根据对象名称和年龄生成请求的卡片
+	mockCards := make([]types.CardContent, 0, len(cardTypes))
+	for _, cardType := range cardTypes {
+		mockCards = append(mockCards, l.getMockCard(cardType, req.ObjectName, req.Age))
 	}
 
 	resp := &types.GenerateCardsResponse{
-		Cards: cards,
+		Cards: mockCards,
 	}
 
-	l.Infow("卡片生成完成（Mock）", logx.Field("cardCount", len(cards)))
+	l.Infow("卡片生成完成（Mock）", logx.Field("cardCount", len(mockCards)))
 	return resp, nil
 }
 
+// resolveCardTypes 校验请求的卡片类型（去重并保持顺序），为空时使用默认的三种卡片
+func resolveCardTypes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return cards.DefaultTypes, nil
+	}
+	cardTypes := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, cardType := range requested {
+		cardType = strings.ToLower(strings.TrimSpace(cardType))
+		if cardType == "" || seen[cardType] {
+			continue
+		}
+		if !cards.Known(cardType) {
+			return nil, utils.NewAPIError(utils.ErrInvalidCardType.Code, utils.ErrInvalidCardType.Message, cardType)
+		}
+		seen[cardType] = true
+		cardTypes = append(cardTypes, cardType)
+	}
+	if len(cardTypes) == 0 {
+		return cards.DefaultTypes, nil
+	}
+	return cardTypes, nil
+}
+
+// toCardErrors 转换卡片生成错误为API返回的格式
+func toCardErrors(cardErrors []nodes.CardError) []types.CardError {
+	if len(cardErrors) == 0 {
+		return nil
+	}
+	result := make([]types.CardError, 0, len(cardErrors))
+	for _, cardErr := range cardErrors {
+		result = append(result, types.CardError{
+			Index:   cardErr.Index,
+			Type:    cardErr.Type,
+			Message: cardErr.Err.Error(),
+		})
+	}
+	return result
+}
+
 // getString 辅助函数：从map中安全获取string值
 func getString(m map[string]interface{}, key string) string {
 	if val, ok := m[key]; ok {
@@ -2,10 +2,13 @@ package logic
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
 )
 
 func TestGenerateCardsLogic_GenerateCards(t *testing.T) {
@@ -80,3 +83,36 @@ func TestGenerateCardsLogic_GenerateCards(t *testing.T) {
 	}
 }
 
+func TestGenerateCardsLogic_CardTypes(t *testing.T) {
+	logic := NewGenerateCardsLogic(context.Background(), &svc.ServiceContext{})
+
+	resp, err := logic.GenerateCards(&types.GenerateCardsRequest{
+		ObjectName:     "银杏",
+		ObjectCategory: "自然类",
+		Age:            8,
+		CardTypes:      []string{"math", " Math ", "character", "poetry"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCards failed: %v", err)
+	}
+	want := []string{"math", "character", "poetry"}
+	if len(resp.Cards) != len(want) {
+		t.Fatalf("Should generate %d cards, got %d", len(want), len(resp.Cards))
+	}
+	for i, card := range resp.Cards {
+		if card.Type != want[i] || card.Title == "" || len(card.Content) == 0 {
+			t.Errorf("card %d = %+v, want type %s", i, card, want[i])
+		}
+	}
+
+	_, err = logic.GenerateCards(&types.GenerateCardsRequest{
+		ObjectName:     "银杏",
+		ObjectCategory: "自然类",
+		Age:            8,
+		CardTypes:      []string{"science", "music"},
+	})
+	var apiErr *utils.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest || apiErr.Detail != "music" {
+		t.Errorf("Should reject unsupported card type, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/taxonomy"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
 
@@ -32,9 +33,8 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 		return nil, utils.NewAPIError(400, "分享链接ID不能为空")
 	}
 
-	// 从内存存储获取分享数据
-	store := GetShareStore()
-	data, ok := store.Get(req.ShareId)
+	// 从分享存储获取分享数据
+	data, ok := l.svcCtx.ShareStore.Get(req.ShareId)
 	if !ok {
 		return nil, utils.ErrShareNotFound
 	}
@@ -45,11 +45,15 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 	// 统计收藏卡片数
 	totalCollectedCards := len(data.CollectedCards)
 
-	// 计算类别分布
+	// 计算类别分布：类别归一化到类别树上，按请求的层级统计（默认顶层类别）
+	level := req.CategoryLevel
+	if level <= 0 {
+		level = 1
+	}
 	categoryDistribution := make(map[string]int)
 	for _, record := range data.ExplorationRecords {
-		category := record.ObjectCategory
-		categoryDistribution[category]++
+		category := recordCategoryPath(l.svcCtx.Taxonomy, record).Truncate(level)
+		categoryDistribution[category.String()]++
 	}
 
 	// 获取最近收藏的卡片（最多10张）
@@ -86,6 +90,17 @@ func (l *GenerateReportLogic) GenerateReport(req *types.GenerateReportRequest) (
 		GeneratedAt:          time.Now().Format("2006-01-02T15:04:05Z07:00"),
 	}
 
-	l.Infow("生成学习报告", logx.Field("shareId", req.ShareId), logx.Field("totalExplorations", totalExplorations), logx.Field("totalCollectedCards", totalCollectedCards))
+	l.Infow("生成学习报告", logx.Field("shareId", req.ShareId), logx.Field("categoryLevel", level), logx.Field("totalExplorations", totalExplorations), logx.Field("totalCollectedCards", totalCollectedCards))
 	return resp, nil
 }
+
+// recordCategoryPath 探索记录的类别路径
+// 优先使用识别时返回的类别路径，旧记录或路径不在当前类别树上时按类别名称和对象名称重新归类
+func recordCategoryPath(t *taxonomy.Taxonomy, record types.ExplorationRecord) taxonomy.Path {
+	if len(record.CategoryPath) > 0 {
+		if path, ok := t.Resolve(taxonomy.Path(record.CategoryPath).String()); ok {
+			return path
+		}
+	}
+	return t.Classify(record.ObjectCategory, record.ObjectName, nil)
+}
@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetImageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetImageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetImageLogic {
+	return &GetImageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetImage 打开本地存储的图片，调用方负责关闭文件
+// 只有本地磁盘存储提供该接口，其他存储返回图片不存在
+func (l *GetImageLogic) GetImage(req *types.GetImageRequest) (*os.File, error) {
+	opener, ok := l.svcCtx.ImageStore.(storage.ImageOpener)
+	if !ok {
+		return nil, utils.ErrImageNotFound
+	}
+
+	file, err := opener.Open(req.Name)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			l.Errorw("读取本地图片失败",
+				logx.Field("name", req.Name),
+				logx.Field("error", err),
+			)
+		}
+		return nil, utils.ErrImageNotFound
+	}
+	return file, nil
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetModelStatsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetModelStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetModelStatsLogic {
+	return &GetModelStatsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetModelStats 返回各角色模型的路由状态（成功率、延迟、熔断状态），需要管理接口令牌
+func (l *GetModelStatsLogic) GetModelStats(req *types.ModelStatsRequest) (resp *types.ModelStatsResponse, err error) {
+	token := l.svcCtx.Config.Admin.Token
+	if token == "" {
+		return nil, utils.ErrAdminDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(req.AdminToken), []byte(token)) != 1 {
+		l.Infow("查询模型状态被拒绝：管理接口令牌不匹配")
+		return nil, utils.ErrAdminUnauthorized
+	}
+
+	resp = &types.ModelStatsResponse{Models: []types.ModelStat{}}
+	for _, stats := range l.svcCtx.Models.Stats() {
+		stat := types.ModelStat{
+			Role:                string(stats.Role),
+			Model:               stats.Model,
+			Provider:            stats.Provider,
+			Policy:              stats.Policy,
+			Weight:              stats.Weight,
+			State:               stats.State,
+			Requests:            stats.Requests,
+			Successes:           stats.Successes,
+			Failures:            stats.Failures,
+			ConsecutiveFailures: stats.ConsecutiveFailures,
+			SuccessRate:         stats.SuccessRate,
+			AvgLatencyMs:        stats.Latency.Milliseconds(),
+			LastError:           stats.LastError,
+		}
+		if !stats.LastErrorAt.IsZero() {
+			stat.LastErrorAt = stats.LastErrorAt.Format(time.RFC3339)
+		}
+		if !stats.RetryAt.IsZero() {
+			stat.RetryAt = stats.RetryAt.Format(time.RFC3339)
+		}
+		resp.Models = append(resp.Models, stat)
+	}
+	return resp, nil
+}
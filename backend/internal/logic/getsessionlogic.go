@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSessionLogic {
+	return &GetSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSession 获取会话的完整消息列表，用于页面刷新后恢复对话，仅会话所有者（持有所有者令牌）可以查看
+func (l *GetSessionLogic) GetSession(req *types.GetSessionRequest) (resp *types.ConversationSession, err error) {
+	if req.SessionId == "" {
+		return nil, utils.NewAPIError(400, "会话ID不能为空")
+	}
+	if req.OwnerToken == "" {
+		return nil, utils.ErrSessionForbidden
+	}
+
+	session, ok := l.svcCtx.Storage.GetSession(req.SessionId)
+	if !ok {
+		return nil, utils.ErrSessionNotFound
+	}
+	if !sessionOwnedBy(l.svcCtx.Storage, req.SessionId, req.OwnerToken) {
+		l.Infow("查看会话被拒绝：所有者令牌不匹配", logx.Field("sessionId", req.SessionId))
+		return nil, utils.ErrSessionForbidden
+	}
+
+	return &types.ConversationSession{
+		Id:                    req.SessionId,
+		Messages:              sessionMessages(l.svcCtx.Storage, req.SessionId),
+		IdentificationContext: sessionIdentificationContext(l.svcCtx.Storage, req.SessionId),
+		CreatedAt:             formatSessionTime(session.CreatedAt),
+		UpdatedAt:             formatSessionTime(session.LastActive),
+	}, nil
+}
@@ -25,9 +25,8 @@ func NewGetShareLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetShare
 }
 
 func (l *GetShareLogic) GetShare(shareId string) (resp *types.GetShareResponse, err error) {
-	// 从内存存储获取分享数据
-	store := GetShareStore()
-	data, ok := store.Get(shareId)
+	// 从分享存储获取分享数据
+	data, ok := l.svcCtx.ShareStore.Get(shareId)
 	if !ok {
 		return nil, utils.ErrShareNotFound
 	}
@@ -77,6 +77,14 @@ func (l *IdentifyLogic) Identify(req *types.IdentifyRequest) (resp *types.Identi
 		logx.Field("age", req.Age),
 	)
 
+	// 内联图片在识别前统一处理：去除EXIF/GPS等元数据，缩放到配置的最长边
+	if !isURL {
+		req.Image, err = normalizeInlineImage(req.Image, l.svcCtx.Config.Upload, l.Logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// 使用Agent系统进行图片识别
 	if l.svcCtx.Agent != nil {
 		graph := l.svcCtx.Agent.GetGraph()
@@ -101,11 +109,19 @@ func (l *IdentifyLogic) Identify(req *types.IdentifyRequest) (resp *types.Identi
 			return nil, err
 		}
 
+		confidence := data.Confidence
+		if confidence <= 0 {
+			confidence = 0.95 // 模型未返回置信度时的默认值
+		}
+		objects := toDetectedObjects(data.Objects)
 		resp = &types.IdentifyResponse{
-			ObjectName:     data.ObjectName,
-			ObjectCategory: data.ObjectCategory,
-			Confidence:     0.95, // Agent返回的置信度
-			Keywords:       data.Keywords,
+			ObjectName:          data.ObjectName,
+			ObjectCategory:      data.ObjectCategory,
+			CategoryPath:        data.CategoryPath,
+			Confidence:          confidence,
+			Keywords:            data.Keywords,
+			Objects:             objects,
+			NeedsDisambiguation: needsDisambiguation(objects, disambiguationThreshold(l.svcCtx.Config.AI)),
 		}
 
 		// 优化：减少日志详细程度，移除keywords数组（可能很大）
@@ -114,6 +130,8 @@ func (l *IdentifyLogic) Identify(req *types.IdentifyRequest) (resp *types.Identi
 			logx.Field("category", resp.ObjectCategory),
 			logx.Field("confidence", resp.Confidence),
 			logx.Field("keywordsCount", len(resp.Keywords)),
+			logx.Field("objectCount", len(resp.Objects)),
+			logx.Field("needsDisambiguation", resp.NeedsDisambiguation),
 		)
 		return resp, nil
 	}
@@ -147,11 +165,21 @@ func (l *IdentifyLogic) identifyMock(req *types.IdentifyRequest) (*types.Identif
 	// 生成随机置信度（0.85-0.99）
 	confidence := 0.85 + rand.Float64()*0.14
 
+	categoryPath := l.svcCtx.Taxonomy.Classify(selected.category, selected.name, selected.keywords)
+
 	resp := &types.IdentifyResponse{
 		ObjectName:     selected.name,
-		ObjectCategory: selected.category,
+		ObjectCategory: categoryPath.Top(),
+		CategoryPath:   categoryPath,
 		Confidence:     confidence,
 		Keywords:       selected.keywords,
+		Objects: []types.DetectedObject{{
+			ObjectName:     selected.name,
+			ObjectCategory: categoryPath.Top(),
+			CategoryPath:   categoryPath,
+			Confidence:     confidence,
+			Keywords:       selected.keywords,
+		}},
 	}
 
 	l.Infow("识别完成（Mock）",
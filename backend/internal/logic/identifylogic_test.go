@@ -2,10 +2,12 @@ package logic
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
 )
 
 func TestIdentifyLogic_Identify(t *testing.T) {
@@ -15,7 +17,7 @@ func TestIdentifyLogic_Identify(t *testing.T) {
 
 	// 测试正常情况
 	req := &types.IdentifyRequest{
-		Image: "data:image/jpeg;base64,/9j/4AAQSkZJRg==",
+		Image: "data:image/png;base64," + encodeTestPNG(t, 64, 64),
 		Age:   8,
 	}
 
@@ -42,5 +44,11 @@ func TestIdentifyLogic_Identify(t *testing.T) {
 	if err2 == nil {
 		t.Error("Should return error when image is empty")
 	}
+
+	// 无法解码的图片直接拒绝，不把未去除元数据的原图交给识别模型
+	_, err3 := logic.Identify(&types.IdentifyRequest{Image: "data:image/jpeg;base64,/9j/4AAQSkZJRg==", Age: 8})
+	if !errors.Is(err3, utils.ErrImageDataInvalid) {
+		t.Errorf("Should return ErrImageDataInvalid for undecodable image, got %v", err3)
+	}
 }
 
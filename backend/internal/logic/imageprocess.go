@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/utils"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// dataURL 生成图片的 data URL
+func dataURL(contentType string, data []byte) string {
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// normalizeInlineImage 处理base64或data URL形式的图片（按EXIF方向旋转、去除元数据、缩放），返回处理后的data URL
+// 图片URL原样返回（上传接口已处理过）；无法解码或处理失败时返回与上传接口相同的错误，
+// 不把未去除元数据的原图交给识别模型
+func normalizeInlineImage(image string, cfg config.UploadConfig, logger logx.Logger) (string, error) {
+	if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return image, nil
+	}
+
+	payload := image
+	if strings.HasPrefix(image, "data:") {
+		comma := strings.Index(image, ",")
+		if comma < 0 {
+			return "", utils.ErrImageDataInvalid
+		}
+		payload = image[comma+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(utils.CleanBase64String(payload))
+	if err != nil {
+		logger.Infow("图片base64解码失败", logx.Field("error", err))
+		return "", utils.ErrImageDataInvalid
+	}
+
+	processed, err := imageproc.Normalize(data, imageproc.OptionsFromConfig(cfg))
+	if err != nil {
+		logger.Infow("图片处理失败", logx.Field("error", err))
+		if errors.Is(err, imageproc.ErrImageTooLarge) {
+			return "", utils.ErrImageTooLarge
+		}
+		return "", utils.ErrImageDataInvalid
+	}
+	logger.Infow("图片已处理",
+		logx.Field("originalSize", processed.OriginalSize),
+		logx.Field("size", len(processed.Data)),
+		logx.Field("width", processed.Width),
+		logx.Field("height", processed.Height),
+	)
+	return dataURL(processed.ContentType, processed.Data), nil
+}
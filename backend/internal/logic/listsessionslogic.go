@@ -0,0 +1,93 @@
+package logic
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListSessionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListSessionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListSessionsLogic {
+	return &ListSessionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListSessions 分页列出所有者令牌对应的会话（按最后活动时间倒序），附带最后一条消息预览和识别对象名称
+func (l *ListSessionsLogic) ListSessions(req *types.ListSessionsRequest) (resp *types.ListSessionsResponse, err error) {
+	if req.OwnerToken == "" {
+		return nil, utils.ErrSessionForbidden
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultSessionPageSize
+	}
+	if pageSize > maxSessionPageSize {
+		pageSize = maxSessionPageSize
+	}
+
+	// 通过所有者索引只读取属于该所有者的会话
+	sessions := l.svcCtx.Storage.ListOwnerSessions(hashOwnerToken(req.OwnerToken))
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastActive.Equal(sessions[j].LastActive) {
+			return sessions[i].LastActive.After(sessions[j].LastActive)
+		}
+		return sessions[i].SessionId < sessions[j].SessionId
+	})
+
+	resp = &types.ListSessionsResponse{
+		Sessions: []types.SessionSummary{},
+		Total:    len(sessions),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(sessions) {
+		return resp, nil
+	}
+	end := start + pageSize
+	if end > len(sessions) {
+		end = len(sessions)
+	}
+
+	// 只读取当前页会话的消息
+	for _, session := range sessions[start:end] {
+		summary := types.SessionSummary{
+			Id:        session.SessionId,
+			CreatedAt: formatSessionTime(session.CreatedAt),
+			UpdatedAt: formatSessionTime(session.LastActive),
+		}
+		messages := sessionMessages(l.svcCtx.Storage, session.SessionId)
+		summary.MessageCount = len(messages)
+		if len(messages) > 0 {
+			last := messages[len(messages)-1]
+			summary.LastMessage = messagePreview(last)
+			summary.LastMessageType = last.Type
+			summary.LastMessageSender = last.Sender
+		}
+		if identCtx := sessionIdentificationContext(l.svcCtx.Storage, session.SessionId); identCtx != nil {
+			summary.ObjectName = identCtx.ObjectName
+			summary.ObjectCategory = identCtx.ObjectCategory
+		}
+		resp.Sessions = append(resp.Sessions, summary)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,249 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PhotoLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPhotoLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PhotoLogic {
+	return &PhotoLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ExplorePhoto 拍照探索：一次请求完成图片识别和知识卡片生成
+// 识别完成后创建会话并保存识别结果上下文（后续对话可直接使用该会话），
+// 依次发送 identified 事件、按完成顺序发送每张卡片的 card 事件，最后发送 done 事件。
+// 只有参数校验失败时返回错误（此时尚未写入任何事件），识别或生成过程中的错误通过 error 事件返回；
+// 部分卡片生成失败时，失败的卡片各发送一个 card_error 事件，其余卡片照常返回
+func (l *PhotoLogic) ExplorePhoto(w http.ResponseWriter, req *types.PhotoExploreRequest) error {
+	if req.Image == "" {
+		return utils.ErrImageRequired
+	}
+	if req.Age < 3 || req.Age > 18 {
+		return utils.ErrInvalidAge
+	}
+	cardTypes, err := resolveCardTypes(req.CardTypes)
+	if err != nil {
+		return err
+	}
+
+	sessionId := req.SessionId
+	if sessionId == "" {
+		sessionId = uuid.New().String()
+	}
+
+	// 设置SSE响应头
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	// 1. 识别图片（复用识别接口的逻辑：图片处理、模型识别、类别归一化）
+	identified, err := NewIdentifyLogic(l.ctx, l.svcCtx).Identify(&types.IdentifyRequest{
+		Image: req.Image,
+		Age:   req.Age,
+	})
+	if err != nil {
+		l.Errorw("拍照探索识别失败",
+			logx.Field("sessionId", sessionId),
+			logx.Field("error", err),
+		)
+		l.sendEvent(w, types.StreamEvent{
+			Type:      "error",
+			Content:   map[string]interface{}{"message": "图片识别失败: " + err.Error()},
+			SessionId: sessionId,
+		})
+		return nil
+	}
+
+	// 2. 保存识别结果上下文，后续对话直接围绕该对象进行
+	imageURL := ""
+	if isRemoteImage(req.Image) {
+		imageURL = req.Image
+	}
+	if identified.NeedsDisambiguation {
+		// 有多个候选对象且把握不大：保存候选对象，等孩子选择后再生成卡片
+		l.svcCtx.Storage.SetData(sessionId, disambiguationDataKey, newDisambiguation(identified.Objects, imageURL))
+	} else {
+		identCtx := &types.IdentificationContext{
+			ObjectName:     identified.ObjectName,
+			ObjectCategory: identified.ObjectCategory,
+			Confidence:     identified.Confidence,
+			Keywords:       identified.Keywords,
+			Age:            req.Age,
+		}
+		if len(identified.Objects) > 0 {
+			identCtx.BoundingBox = identified.Objects[0].BoundingBox
+		}
+		l.svcCtx.Storage.SetData(sessionId, "identificationContext", identCtx)
+		clearDisambiguation(l.svcCtx.Storage, sessionId)
+	}
+
+	l.sendEvent(w, types.StreamEvent{
+		Type:       "identified",
+		Content:    identified,
+		SessionId:  sessionId,
+		OwnerToken: claimSession(l.svcCtx.Storage, sessionId, req.OwnerToken),
+	})
+
+	l.Infow("拍照探索识别完成",
+		logx.Field("sessionId", sessionId),
+		logx.Field("objectName", identified.ObjectName),
+		logx.Field("category", identified.ObjectCategory),
+		logx.Field("needsDisambiguation", identified.NeedsDisambiguation),
+	)
+
+	// 3. 生成知识卡片（需要孩子先选择对象时不生成）
+	cardCount := 0
+	if !identified.NeedsDisambiguation {
+		cardCount = l.streamCards(w, sessionId, identified, req.Age, cardTypes)
+	}
+
+	l.sendEvent(w, types.StreamEvent{
+		Type:      "done",
+		Content:   map[string]interface{}{"cardCount": cardCount},
+		SessionId: sessionId,
+	})
+	return nil
+}
+
+// streamCards 生成知识卡片，每张卡片完成后立即发送并保存到会话，返回成功发送的卡片数
+func (l *PhotoLogic) streamCards(w http.ResponseWriter, sessionId string, identified *types.IdentifyResponse, age int, cardTypes []string) int {
+	cardCount := 0
+	sendCard := func(idx int, card types.CardContent) {
+		l.sendEvent(w, types.StreamEvent{
+			Type:      "card",
+			Content:   card,
+			Index:     idx,
+			SessionId: sessionId,
+		})
+		l.svcCtx.Storage.AddMessage(sessionId, types.ConversationMessage{
+			Id:     uuid.New().String(),
+			Type:   "card",
+			Sender: "assistant",
+			Content: map[string]interface{}{
+				"type":    card.Type,
+				"title":   card.Title,
+				"content": card.Content,
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+			SessionId: sessionId,
+		})
+		cardCount++
+	}
+
+	useAIModel := l.svcCtx.Config.AI.UseAIModel
+	if l.svcCtx.Agent == nil || l.svcCtx.Agent.GetGraph() == nil {
+		if useAIModel {
+			l.Errorw("Agent未初始化，无法生成卡片", logx.Field("sessionId", sessionId))
+			l.sendEvent(w, types.StreamEvent{
+				Type:      "error",
+				Content:   map[string]interface{}{"message": "Agent未初始化，无法生成卡片。请检查配置：EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY"},
+				SessionId: sessionId,
+			})
+			return 0
+		}
+		// USE_AI_MODEL=false 时使用Mock卡片
+		cardsLogic := NewGenerateCardsLogic(l.ctx, l.svcCtx)
+		for i, cardType := range cardTypes {
+			sendCard(i, cardsLogic.getMockCard(cardType, identified.ObjectName, age))
+		}
+		return cardCount
+	}
+
+	graph := l.svcCtx.Agent.GetGraph()
+	data, err := graph.ExecuteCardGenerationStream(l.ctx, identified.ObjectName, identified.ObjectCategory, age, identified.Keywords, cardTypes,
+		func(idx int, cardData interface{}) {
+			if card, ok := toCardContent(cardData); ok {
+				sendCard(idx, card)
+			}
+		})
+	if err != nil {
+		// 所有卡片都生成失败
+		l.Errorw("拍照探索卡片生成失败",
+			logx.Field("sessionId", sessionId),
+			logx.Field("cardCount", cardCount),
+			logx.Field("error", err),
+		)
+		l.sendEvent(w, types.StreamEvent{
+			Type:      "error",
+			Content:   map[string]interface{}{"message": "卡片生成失败: " + err.Error()},
+			SessionId: sessionId,
+		})
+		return cardCount
+	}
+	// 已完成的卡片已经发送，这里只报告失败的部分
+	for _, cardErr := range toCardErrors(data.CardErrors) {
+		l.Errorw("拍照探索卡片生成失败",
+			logx.Field("sessionId", sessionId),
+			logx.Field("cardType", cardErr.Type),
+			logx.Field("error", cardErr.Message),
+		)
+		l.sendEvent(w, types.StreamEvent{
+			Type:      "card_error",
+			Content:   cardErr,
+			Index:     cardErr.Index,
+			SessionId: sessionId,
+		})
+	}
+	return cardCount
+}
+
+// sendEvent 发送SSE事件
+func (l *PhotoLogic) sendEvent(w http.ResponseWriter, event types.StreamEvent) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		l.Errorw("序列化SSE事件失败", logx.Field("type", event.Type), logx.Field("error", err))
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, string(eventJSON))
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// toCardContent 将Agent返回的卡片数据转换为 types.CardContent
+func toCardContent(cardData interface{}) (types.CardContent, bool) {
+	cardMap, ok := cardData.(map[string]interface{})
+	if !ok {
+		return types.CardContent{}, false
+	}
+	var content map[string]interface{}
+	switch v := cardMap["content"].(type) {
+	case map[string]interface{}:
+		content = v
+	case nil:
+		content = make(map[string]interface{})
+	default:
+		content = map[string]interface{}{"value": v}
+	}
+	return types.CardContent{
+		Type:    getString(cardMap, "type"),
+		Title:   getString(cardMap, "title"),
+		Content: content,
+	}, true
+}
+
+// isRemoteImage 图片是否为 http/https URL
+func isRemoteImage(image string) bool {
+	return len(image) > 7 && (image[:7] == "http://" || (len(image) > 8 && image[:8] == "https://"))
+}
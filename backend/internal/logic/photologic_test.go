@@ -0,0 +1,90 @@
+package logic
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+)
+
+// parseSSEEvents 解析SSE响应中的事件
+func parseSSEEvents(t *testing.T, body string) []types.StreamEvent {
+	t.Helper()
+	var events []types.StreamEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event types.StreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("解析事件失败: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestExplorePhoto_Mock(t *testing.T) {
+	// 未配置Agent且 USE_AI_MODEL=false 时使用Mock识别和Mock卡片
+	svcCtx := &svc.ServiceContext{Storage: storage.NewMemoryStorage()}
+	w := httptest.NewRecorder()
+
+	err := NewPhotoLogic(context.Background(), svcCtx).ExplorePhoto(w, &types.PhotoExploreRequest{
+		Image: "https://cdn.example.com/photo.jpg",
+		Age:   8,
+	})
+	if err != nil {
+		t.Fatalf("ExplorePhoto failed: %v", err)
+	}
+
+	events := parseSSEEvents(t, w.Body.String())
+	if len(events) != 5 {
+		t.Fatalf("expected identified + 3 cards + done, got %d events", len(events))
+	}
+	if events[0].Type != "identified" || events[4].Type != "done" {
+		t.Errorf("事件顺序错误: %s ... %s", events[0].Type, events[4].Type)
+	}
+	sessionId := events[0].SessionId
+	for i, event := range events[1:4] {
+		if event.Type != "card" || event.Index != i || event.SessionId != sessionId {
+			t.Errorf("卡片事件错误: %+v", event)
+		}
+	}
+
+	// 会话已创建并保存识别结果上下文和卡片，后续对话可以直接使用
+	identified, _ := events[0].Content.(map[string]interface{})
+	identCtx := sessionIdentificationContext(svcCtx.Storage, sessionId)
+	if identCtx == nil || identCtx.ObjectName != identified["objectName"] || identCtx.Age != 8 {
+		t.Errorf("识别结果上下文错误: %+v", identCtx)
+	}
+	if messages := sessionMessages(svcCtx.Storage, sessionId); len(messages) != 3 || messages[0].Type != "card" {
+		t.Errorf("会话中应保存3张卡片, got %d", len(messages))
+	}
+}
+
+func TestExplorePhoto_InvalidRequest(t *testing.T) {
+	svcCtx := &svc.ServiceContext{Storage: storage.NewMemoryStorage()}
+	l := NewPhotoLogic(context.Background(), svcCtx)
+
+	for _, req := range []*types.PhotoExploreRequest{
+		{Age: 8},
+		{Image: "https://cdn.example.com/photo.jpg", Age: 2},
+	} {
+		w := httptest.NewRecorder()
+		if err := l.ExplorePhoto(w, req); err == nil {
+			t.Errorf("ExplorePhoto(%+v) 应返回错误", req)
+		}
+		if w.Body.Len() != 0 {
+			t.Error("参数错误时不应发送事件")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeShareLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeShareLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeShareLogic {
+	return &RevokeShareLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RevokeShare 撤销分享链接，仅创建者（持有所有者令牌）可以撤销
+func (l *RevokeShareLogic) RevokeShare(req *types.RevokeShareRequest) (resp *types.RevokeShareResponse, err error) {
+	if req.ShareId == "" {
+		return nil, utils.NewAPIError(400, "分享链接ID不能为空")
+	}
+	if req.OwnerToken == "" {
+		return nil, utils.ErrShareForbidden
+	}
+
+	data, ok := l.svcCtx.ShareStore.Get(req.ShareId)
+	if !ok {
+		return nil, utils.ErrShareNotFound
+	}
+	if !verifyOwnerToken(req.OwnerToken, data.OwnerTokenHash) {
+		l.Infow("撤销分享被拒绝：所有者令牌不匹配", logx.Field("shareId", req.ShareId))
+		return nil, utils.ErrShareForbidden
+	}
+
+	if err := l.svcCtx.ShareStore.Delete(req.ShareId); err != nil {
+		l.Errorw("删除分享数据失败", logx.Field("shareId", req.ShareId), logx.Field("error", err))
+		return nil, utils.ErrInternalServer
+	}
+
+	l.Infow("撤销分享链接", logx.Field("shareId", req.ShareId))
+	return &types.RevokeShareResponse{
+		ShareId: req.ShareId,
+		Revoked: true,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// defaultSessionPageSize 会话列表默认每页数量
+	defaultSessionPageSize = 20
+	// maxSessionPageSize 会话列表每页最大数量
+	maxSessionPageSize = 100
+	// maxMessagePreviewRunes 会话列表中最后一条消息的预览字数
+	maxMessagePreviewRunes = 50
+)
+
+// claimSession 记录会话的所有者：会话还没有所有者时保存令牌摘要并返回令牌（未提供令牌时生成新令牌），
+// 客户端保存返回的令牌，之后列出、查看和删除会话时提供；会话已有所有者（包括并发认领失败）时不改变归属，返回空字符串
+func claimSession(store storage.SessionStore, sessionId string, ownerToken string) string {
+	if _, owned := store.GetData(sessionId, storage.SessionOwnerDataKey); owned {
+		return ""
+	}
+	if ownerToken == "" {
+		token, err := newOwnerToken()
+		if err != nil {
+			logx.Errorw("生成会话所有者令牌失败", logx.Field("sessionId", sessionId), logx.Field("error", err))
+			return ""
+		}
+		ownerToken = token
+	}
+	// 认领由存储原子完成，只有写入成功时才把令牌交给客户端
+	if !store.ClaimOwner(sessionId, hashOwnerToken(ownerToken)) {
+		return ""
+	}
+	return ownerToken
+}
+
+// sessionOwnedBy 会话是否属于持有该令牌的所有者（没有所有者的会话不属于任何人）
+func sessionOwnedBy(store storage.SessionStore, sessionId string, ownerToken string) bool {
+	value, ok := store.GetData(sessionId, storage.SessionOwnerDataKey)
+	if !ok {
+		return false
+	}
+	tokenHash, _ := value.(string)
+	return verifyOwnerToken(ownerToken, tokenHash)
+}
+
+// sessionMessages 读取会话中的对话消息
+func sessionMessages(store storage.SessionStore, sessionId string) []types.ConversationMessage {
+	messagesRaw := store.GetMessages(sessionId)
+	messages := make([]types.ConversationMessage, 0, len(messagesRaw))
+	for _, msgRaw := range messagesRaw {
+		if msg, ok := msgRaw.(types.ConversationMessage); ok {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// sessionIdentificationContext 读取会话关联的识别结果上下文
+func sessionIdentificationContext(store storage.SessionStore, sessionId string) *types.IdentificationContext {
+	value, ok := store.GetData(sessionId, "identificationContext")
+	if !ok {
+		return nil
+	}
+	identCtx, _ := value.(*types.IdentificationContext)
+	return identCtx
+}
+
+// messagePreview 生成消息预览文本（图片、卡片等非文本消息显示为类型标签）
+func messagePreview(msg types.ConversationMessage) string {
+	switch msg.Type {
+	case "image":
+		return "[图片]"
+	case "card":
+		if content, ok := msg.Content.(map[string]interface{}); ok {
+			if title, ok := content["title"].(string); ok && title != "" {
+				return "[卡片] " + title
+			}
+		}
+		return "[卡片]"
+	}
+
+	text, ok := msg.Content.(string)
+	if !ok {
+		if msg.Type == "voice" {
+			return "[语音]"
+		}
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) > maxMessagePreviewRunes {
+		return string(runes[:maxMessagePreviewRunes]) + "…"
+	}
+	return text
+}
+
+// formatSessionTime 格式化会话时间
+func formatSessionTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,150 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+)
+
+const testOwnerToken = "owner-a"
+
+func newSessionTestServiceContext() *svc.ServiceContext {
+	store := storage.NewMemoryStorage()
+	store.SetData("session-old", "identificationContext", &types.IdentificationContext{ObjectName: "银杏", ObjectCategory: "自然类"})
+	store.AddMessage("session-old", types.ConversationMessage{Id: "m1", Type: "text", Sender: "user", Content: "银杏为什么会变黄？"})
+	store.AddMessage("session-old", types.ConversationMessage{Id: "m2", Type: "text", Sender: "assistant", Content: strings.Repeat("因为秋天到了", 20)})
+	time.Sleep(2 * time.Millisecond)
+	store.AddMessage("session-new", types.ConversationMessage{Id: "m3", Type: "image", Sender: "user", Content: "https://example.com/a.jpg"})
+	claimSession(store, "session-old", testOwnerToken)
+	claimSession(store, "session-new", testOwnerToken)
+
+	return &svc.ServiceContext{
+		Storage:       store,
+		MemoryStorage: storage.NewMemoryAgentStorage(),
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	svcCtx := newSessionTestServiceContext()
+	l := NewListSessionsLogic(context.Background(), svcCtx)
+
+	resp, err := l.ListSessions(&types.ListSessionsRequest{Page: 1, PageSize: 20, OwnerToken: testOwnerToken})
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got total=%d len=%d", resp.Total, len(resp.Sessions))
+	}
+
+	// 按最后活动时间倒序
+	if resp.Sessions[0].Id != "session-new" || resp.Sessions[0].LastMessage != "[图片]" {
+		t.Errorf("Unexpected first session: %+v", resp.Sessions[0])
+	}
+	old := resp.Sessions[1]
+	if old.ObjectName != "银杏" || old.MessageCount != 2 || old.LastMessageSender != "assistant" {
+		t.Errorf("Unexpected second session: %+v", old)
+	}
+	if len([]rune(old.LastMessage)) != maxMessagePreviewRunes+1 {
+		t.Errorf("Last message preview should be truncated: %s", old.LastMessage)
+	}
+
+	// 分页
+	resp, _ = l.ListSessions(&types.ListSessionsRequest{Page: 2, PageSize: 1, OwnerToken: testOwnerToken})
+	if resp.Total != 2 || len(resp.Sessions) != 1 || resp.Sessions[0].Id != "session-old" {
+		t.Errorf("Unexpected page 2: %+v", resp)
+	}
+	resp, _ = l.ListSessions(&types.ListSessionsRequest{Page: 3, PageSize: 1, OwnerToken: testOwnerToken})
+	if len(resp.Sessions) != 0 {
+		t.Errorf("Page out of range should be empty: %+v", resp.Sessions)
+	}
+}
+
+func TestGetSession(t *testing.T) {
+	svcCtx := newSessionTestServiceContext()
+	l := NewGetSessionLogic(context.Background(), svcCtx)
+
+	resp, err := l.GetSession(&types.GetSessionRequest{SessionId: "session-old", OwnerToken: testOwnerToken})
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if len(resp.Messages) != 2 || resp.Messages[0].Content != "银杏为什么会变黄？" {
+		t.Errorf("Unexpected messages: %+v", resp.Messages)
+	}
+	if resp.IdentificationContext == nil || resp.IdentificationContext.ObjectName != "银杏" {
+		t.Errorf("Unexpected identification context: %+v", resp.IdentificationContext)
+	}
+
+	if _, err := l.GetSession(&types.GetSessionRequest{SessionId: "missing", OwnerToken: testOwnerToken}); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	svcCtx := newSessionTestServiceContext()
+	svcCtx.MemoryStorage.AddInterestedTopic("session-old", "银杏")
+
+	resp, err := NewDeleteSessionLogic(context.Background(), svcCtx).DeleteSession(&types.DeleteSessionRequest{SessionId: "session-old", OwnerToken: testOwnerToken})
+	if err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if !resp.Deleted {
+		t.Error("Expected Deleted to be true")
+	}
+	if _, ok := svcCtx.Storage.GetSession("session-old"); ok {
+		t.Error("Session should be deleted")
+	}
+	if _, ok := svcCtx.MemoryStorage.GetMemoryRecord("session-old"); ok {
+		t.Error("Memory record should be deleted")
+	}
+
+	if _, err := NewDeleteSessionLogic(context.Background(), svcCtx).DeleteSession(&types.DeleteSessionRequest{SessionId: "session-old", OwnerToken: testOwnerToken}); err == nil {
+		t.Error("Expected error when deleting missing session")
+	}
+}
+
+func TestSessionOwnerScoping(t *testing.T) {
+	svcCtx := newSessionTestServiceContext()
+	ctx := context.Background()
+
+	// 另一个所有者创建自己的会话（未提供令牌时生成新令牌）
+	svcCtx.Storage.AddMessage("session-b", types.ConversationMessage{Id: "m4", Type: "text", Sender: "user", Content: "蝴蝶"})
+	tokenB := claimSession(svcCtx.Storage, "session-b", "")
+	if tokenB == "" || tokenB == testOwnerToken {
+		t.Fatalf("Expected a new owner token, got %q", tokenB)
+	}
+	if token := claimSession(svcCtx.Storage, "session-b", testOwnerToken); token != "" {
+		t.Error("Claiming an owned session should not change its owner")
+	}
+
+	resp, err := NewListSessionsLogic(ctx, svcCtx).ListSessions(&types.ListSessionsRequest{Page: 1, PageSize: 20, OwnerToken: tokenB})
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Sessions) != 1 || resp.Sessions[0].Id != "session-b" {
+		t.Errorf("Owner B should only list its own session: %+v", resp)
+	}
+	if _, err := NewListSessionsLogic(ctx, svcCtx).ListSessions(&types.ListSessionsRequest{Page: 1, PageSize: 20}); !errors.Is(err, utils.ErrSessionForbidden) {
+		t.Errorf("Expected forbidden without token, got %v", err)
+	}
+
+	if _, err := NewGetSessionLogic(ctx, svcCtx).GetSession(&types.GetSessionRequest{SessionId: "session-old", OwnerToken: tokenB}); !errors.Is(err, utils.ErrSessionForbidden) {
+		t.Errorf("Owner B should not read owner A's session, got %v", err)
+	}
+	if _, err := NewGetSessionLogic(ctx, svcCtx).GetSession(&types.GetSessionRequest{SessionId: "session-old"}); !errors.Is(err, utils.ErrSessionForbidden) {
+		t.Errorf("Expected forbidden without token, got %v", err)
+	}
+
+	if _, err := NewDeleteSessionLogic(ctx, svcCtx).DeleteSession(&types.DeleteSessionRequest{SessionId: "session-old", OwnerToken: tokenB}); !errors.Is(err, utils.ErrSessionForbidden) {
+		t.Errorf("Owner B should not delete owner A's session, got %v", err)
+	}
+	if _, ok := svcCtx.Storage.GetSession("session-old"); !ok {
+		t.Error("Owner A's session should still exist")
+	}
+}
@@ -1,87 +1,57 @@
 package logic
 
 import (
-	"sync"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"time"
 
-	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/config"
 )
 
-// ShareStore 分享链接存储（内存实现）
-type ShareStore struct {
-	mu    sync.RWMutex
-	shares map[string]*ShareData
-}
-
-// ShareData 分享数据
-type ShareData struct {
-	ShareId           string
-	ExplorationRecords []types.ExplorationRecord
-	CollectedCards     []types.KnowledgeCard
-	CreatedAt          time.Time
-	ExpiresAt          time.Time
-}
-
-var (
-	shareStore *ShareStore
-	once       sync.Once
+const (
+	// defaultShareTTLHours 分享默认有效期（7天）
+	defaultShareTTLHours = 7 * 24
+	// defaultShareMaxTTLHours 分享最长有效期（30天）
+	defaultShareMaxTTLHours = 30 * 24
 )
 
-// GetShareStore 获取分享存储单例
-func GetShareStore() *ShareStore {
-	once.Do(func() {
-		shareStore = &ShareStore{
-			shares: make(map[string]*ShareData),
-		}
-		// 启动清理goroutine
-		go shareStore.cleanup()
-	})
-	return shareStore
-}
-
-// Save 保存分享数据
-func (s *ShareStore) Save(shareId string, data *ShareData) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.shares[shareId] = data
-}
-
-// Get 获取分享数据
-func (s *ShareStore) Get(shareId string) (*ShareData, bool) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	data, ok := s.shares[shareId]
-	if !ok {
-		return nil, false
+// shareTTLLimits 返回分享的默认有效期和最长有效期
+func shareTTLLimits(cfg config.ShareConfig) (defaultTTL, maxTTL time.Duration) {
+	defaultHours := cfg.DefaultTTLHours
+	if defaultHours <= 0 {
+		defaultHours = defaultShareTTLHours
+	}
+	maxHours := cfg.MaxTTLHours
+	if maxHours <= 0 {
+		maxHours = defaultShareMaxTTLHours
 	}
-	// 检查是否过期
-	if time.Now().After(data.ExpiresAt) {
-		return nil, false
+	if defaultHours > maxHours {
+		defaultHours = maxHours
 	}
-	return data, true
+	return time.Duration(defaultHours) * time.Hour, time.Duration(maxHours) * time.Hour
 }
 
-// Delete 删除分享数据
-func (s *ShareStore) Delete(shareId string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.shares, shareId)
+// newOwnerToken 生成分享所有者令牌
+func newOwnerToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
 }
 
-// cleanup 定期清理过期的分享链接
-func (s *ShareStore) cleanup() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+// hashOwnerToken 计算所有者令牌的摘要（存储中只保存摘要）
+func hashOwnerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	for range ticker.C {
-		s.mu.Lock()
-		now := time.Now()
-		for shareId, data := range s.shares {
-			if now.After(data.ExpiresAt) {
-				delete(s.shares, shareId)
-			}
-		}
-		s.mu.Unlock()
+// verifyOwnerToken 校验所有者令牌
+func verifyOwnerToken(token string, tokenHash string) bool {
+	if token == "" || tokenHash == "" {
+		return false
 	}
+	return subtle.ConstantTimeCompare([]byte(hashOwnerToken(token)), []byte(tokenHash)) == 1
 }
-
@@ -1,19 +1,23 @@
 package logic
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 )
 
 func TestShareStore(t *testing.T) {
-	store := GetShareStore()
+	store := storage.NewMemoryShareStore()
 
 	// 测试保存和获取
 	shareId := "test-share-id"
-	data := &ShareData{
-		ShareId:           shareId,
+	data := &storage.ShareData{
+		ShareId:            shareId,
 		ExplorationRecords: []types.ExplorationRecord{},
 		CollectedCards:     []types.KnowledgeCard{},
 		CreatedAt:          time.Now(),
@@ -39,8 +43,8 @@ func TestShareStore(t *testing.T) {
 
 	// 测试过期数据
 	expiredId := "expired-id"
-	expiredData := &ShareData{
-		ShareId:           expiredId,
+	expiredData := &storage.ShareData{
+		ShareId:            expiredId,
 		ExplorationRecords: []types.ExplorationRecord{},
 		CollectedCards:     []types.KnowledgeCard{},
 		CreatedAt:          time.Now().Add(-8 * 24 * time.Hour),
@@ -61,3 +65,126 @@ func TestShareStore(t *testing.T) {
 	}
 }
 
+func newShareTestServiceContext() *svc.ServiceContext {
+	return &svc.ServiceContext{
+		Config: config.Config{
+			Share: config.ShareConfig{DefaultTTLHours: 24, MaxTTLHours: 48},
+		},
+		ShareStore: storage.NewMemoryShareStore(),
+	}
+}
+
+func TestCreateShare_TTL(t *testing.T) {
+	svcCtx := newShareTestServiceContext()
+	l := NewCreateShareLogic(context.Background(), svcCtx)
+	records := []types.ExplorationRecord{{Id: "r1", ObjectName: "银杏", ObjectCategory: "自然类"}}
+
+	// 默认有效期
+	resp, err := l.CreateShare(&types.CreateShareRequest{ExplorationRecords: records})
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	if resp.OwnerToken == "" {
+		t.Error("OwnerToken should be returned")
+	}
+	data, ok := svcCtx.ShareStore.Get(resp.ShareId)
+	if !ok {
+		t.Fatal("Share should be saved")
+	}
+	if ttl := data.ExpiresAt.Sub(data.CreatedAt); ttl != 24*time.Hour {
+		t.Errorf("Expected default TTL 24h, got %v", ttl)
+	}
+	if data.OwnerTokenHash == resp.OwnerToken {
+		t.Error("Owner token should not be stored in plain text")
+	}
+
+	// 自定义有效期
+	resp, err = l.CreateShare(&types.CreateShareRequest{ExplorationRecords: records, ExpiresInHours: 2})
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	data, _ = svcCtx.ShareStore.Get(resp.ShareId)
+	if ttl := data.ExpiresAt.Sub(data.CreatedAt); ttl != 2*time.Hour {
+		t.Errorf("Expected TTL 2h, got %v", ttl)
+	}
+
+	// 超过最长有效期
+	if _, err := l.CreateShare(&types.CreateShareRequest{ExplorationRecords: records, ExpiresInHours: 49}); err == nil {
+		t.Error("Expected error when TTL exceeds max")
+	}
+}
+
+func TestRevokeShare(t *testing.T) {
+	svcCtx := newShareTestServiceContext()
+	records := []types.ExplorationRecord{{Id: "r1", ObjectName: "银杏", ObjectCategory: "自然类"}}
+
+	created, err := NewCreateShareLogic(context.Background(), svcCtx).CreateShare(&types.CreateShareRequest{ExplorationRecords: records})
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+
+	revoke := NewRevokeShareLogic(context.Background(), svcCtx)
+
+	// 错误的令牌不能撤销
+	if _, err := revoke.RevokeShare(&types.RevokeShareRequest{ShareId: created.ShareId, OwnerToken: "wrong"}); err == nil {
+		t.Error("Expected error with wrong owner token")
+	}
+	if _, err := revoke.RevokeShare(&types.RevokeShareRequest{ShareId: created.ShareId}); err == nil {
+		t.Error("Expected error without owner token")
+	}
+	if _, ok := svcCtx.ShareStore.Get(created.ShareId); !ok {
+		t.Fatal("Share should still exist after rejected revocation")
+	}
+
+	// 正确的令牌可以撤销
+	resp, err := revoke.RevokeShare(&types.RevokeShareRequest{ShareId: created.ShareId, OwnerToken: created.OwnerToken})
+	if err != nil {
+		t.Fatalf("RevokeShare failed: %v", err)
+	}
+	if !resp.Revoked {
+		t.Error("Expected Revoked to be true")
+	}
+	if _, err := NewGetShareLogic(context.Background(), svcCtx).GetShare(created.ShareId); err == nil {
+		t.Error("Share should not be found after revocation")
+	}
+}
+
+func TestGenerateReport_CategoryLevel(t *testing.T) {
+	svcCtx := newShareTestServiceContext()
+	svcCtx.ShareStore.Save("share-report", &storage.ShareData{
+		ShareId: "share-report",
+		ExplorationRecords: []types.ExplorationRecord{
+			{ObjectName: "玫瑰", ObjectCategory: "自然类", CategoryPath: []string{"自然类", "植物", "花卉"}},
+			{ObjectName: "银杏树", ObjectCategory: "自然"},
+			{ObjectName: "蝴蝶", ObjectCategory: "nature"},
+			{ObjectName: "苹果", ObjectCategory: "生活类"},
+		},
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	l := NewGenerateReportLogic(context.Background(), svcCtx)
+
+	// 默认按顶层类别统计，类别的不同写法归到同一类别
+	resp, err := l.GenerateReport(&types.GenerateReportRequest{ShareId: "share-report"})
+	if err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	if len(resp.CategoryDistribution) != 2 || resp.CategoryDistribution["自然类"] != 3 || resp.CategoryDistribution["生活类"] != 1 {
+		t.Errorf("顶层类别分布错误: %v", resp.CategoryDistribution)
+	}
+
+	// 按第二层统计，无法细分的记录按最深层级统计
+	resp, err = l.GenerateReport(&types.GenerateReportRequest{ShareId: "share-report", CategoryLevel: 2})
+	if err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	want := map[string]int{"自然类/植物": 2, "自然类": 1, "生活类/食物": 1}
+	if len(resp.CategoryDistribution) != len(want) {
+		t.Fatalf("第二层类别分布错误: %v", resp.CategoryDistribution)
+	}
+	for category, count := range want {
+		if resp.CategoryDistribution[category] != count {
+			t.Errorf("第二层类别分布错误: %v", resp.CategoryDistribution)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/cloudwego/eino/schema"
 	"github.com/google/uuid"
+	"github.com/tango/explore/internal/agent"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
@@ -125,50 +126,10 @@ func (l *StreamLogic) SendSSEEvent(eventType string, data interface{}) (string,
 	return fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, string(jsonData)), nil
 }
 
-// convertToEinoMessages 将内部消息转换为Eino Message格式
-func (l *StreamLogic) convertToEinoMessages(messages []interface{}, maxRounds int) []*schema.Message {
-	// 只取最后maxRounds轮（maxRounds * 2条消息）
-	start := 0
-	if len(messages) > maxRounds*2 {
-		start = len(messages) - maxRounds*2
-	}
-
-	// 转换为Eino Message格式
-	einoMessages := make([]*schema.Message, 0)
-	for i := start; i < len(messages); i++ {
-		if convMsg, ok := messages[i].(types.ConversationMessage); ok {
-			if convMsg.Sender == "user" {
-				// 提取文本内容
-				content := ""
-				if str, ok := convMsg.Content.(string); ok {
-					content = str
-				} else {
-					// 如果是对象，转换为JSON字符串
-					contentBytes, _ := json.Marshal(convMsg.Content)
-					content = string(contentBytes)
-				}
-				einoMessages = append(einoMessages, schema.UserMessage(content))
-			} else if convMsg.Sender == "assistant" {
-				// 提取文本内容
-				content := ""
-				if str, ok := convMsg.Content.(string); ok {
-					content = str
-				} else {
-					contentBytes, _ := json.Marshal(convMsg.Content)
-					content = string(contentBytes)
-				}
-				einoMessages = append(einoMessages, schema.AssistantMessage(content, nil))
-			}
-		}
-	}
-
-	return einoMessages
-}
-
-// getContextMessages 获取上下文消息（最多20轮）
-func (l *StreamLogic) getContextMessages(sessionId string, maxRounds int) []*schema.Message {
-	messages := l.svcCtx.Storage.GetMessages(sessionId)
-	return l.convertToEinoMessages(messages, maxRounds)
+// getContextMessages 获取上下文消息（最多maxRounds轮），按模型的token预算组装，较早的对话压缩为摘要放在最前面
+func (l *StreamLogic) getContextMessages(sessionId string, maxRounds int, model string) []*schema.Message {
+	messages := l.convertToConversationMessages(l.svcCtx.Storage.GetMessages(sessionId))
+	return l.svcCtx.ContextManager.Build(l.ctx, l.svcCtx.Storage, sessionId, messages, model, maxRounds*2)
 }
 
 // StreamConversation 流式对话，集成Eino流式输出和SSE发送（兼容旧版本）
@@ -199,6 +160,16 @@ func (l *StreamLogic) StreamConversation(
 func (l *StreamLogic) StreamConversationUnified(
 	w http.ResponseWriter,
 	req types.UnifiedStreamConversationRequest,
+) error {
+	return l.streamConversation(w, req, false)
+}
+
+// streamConversation 统一流式对话的实现
+// userMessageSaved 为true时用户消息已由调用方保存（多Agent降级到单Agent），不再重复保存
+func (l *StreamLogic) streamConversation(
+	w http.ResponseWriter,
+	req types.UnifiedStreamConversationRequest,
+	userMessageSaved bool,
 ) error {
 	logger := logx.WithContext(l.ctx)
 
@@ -233,8 +204,9 @@ func (l *StreamLogic) StreamConversationUnified(
 		}
 		objectName = req.IdentificationContext.ObjectName
 		objectCategory = req.IdentificationContext.ObjectCategory
-		// 保存识别结果上下文到会话
+		// 保存识别结果上下文到会话（孩子从候选对象中选择时也通过该字段传入）
 		l.svcCtx.Storage.SetData(sessionId, "identificationContext", req.IdentificationContext)
+		clearDisambiguation(l.svcCtx.Storage, sessionId)
 	} else {
 		// 尝试从会话数据中获取识别结果上下文
 		if ctxData, ok := l.svcCtx.Storage.GetData(sessionId, "identificationContext"); ok {
@@ -335,8 +307,12 @@ func (l *StreamLogic) StreamConversationUnified(
 		messageType = "voice"
 		// 发送语音识别完成事件
 		recognizedEvent := types.StreamEvent{
-			Type:      "voice_recognized",
-			Content:   map[string]interface{}{"text": voiceResp.Text},
+			Type: "voice_recognized",
+			Content: map[string]interface{}{
+				"text":       voiceResp.Text,
+				"confidence": voiceResp.Confidence,
+				"segments":   voiceResp.Segments,
+			},
 			SessionId: sessionId,
 		}
 		recognizedJSON, _ := json.Marshal(recognizedEvent)
@@ -434,21 +410,40 @@ func (l *StreamLogic) StreamConversationUnified(
 		return fmt.Errorf("不支持的messageType: %s", req.MessageType)
 	}
 
+	// 上一张图片有多个候选对象时，孩子回复中提到的对象即为选中的对象
+	if req.IdentificationContext == nil && imageURL == "" {
+		if pending := pendingDisambiguation(l.svcCtx.Storage, sessionId); pending != nil {
+			if chosen, ok := matchCandidate(pending, messageText); ok {
+				l.svcCtx.Storage.SetData(sessionId, "identificationContext", identificationFromObject(chosen, userAge))
+				clearDisambiguation(l.svcCtx.Storage, sessionId)
+				objectName = chosen.ObjectName
+				objectCategory = chosen.ObjectCategory
+				logger.Infow("孩子已选择候选对象",
+					logx.Field("sessionId", sessionId),
+					logx.Field("objectName", objectName),
+				)
+			}
+		}
+	}
+
 	// 保存用户消息到存储
-	userMessage := types.ConversationMessage{
-		Id:        uuid.New().String(),
-		Type:      messageType,
-		Sender:    "user",
-		Content:   messageText,
-		Timestamp: time.Now().Format(time.RFC3339),
-		SessionId: sessionId,
+	if !userMessageSaved {
+		userMessage := types.ConversationMessage{
+			Id:        uuid.New().String(),
+			Type:      messageType,
+			Sender:    "user",
+			Content:   messageText,
+			Timestamp: time.Now().Format(time.RFC3339),
+			SessionId: sessionId,
+		}
+		l.svcCtx.Storage.AddMessage(sessionId, userMessage)
 	}
-	l.svcCtx.Storage.AddMessage(sessionId, userMessage)
 
-	// 发送连接建立事件
+	// 发送连接建立事件（新会话附带所有者令牌）
 	connectedEvent := types.StreamEvent{
-		Type:      "connected",
-		SessionId: sessionId,
+		Type:       "connected",
+		SessionId:  sessionId,
+		OwnerToken: claimSession(l.svcCtx.Storage, sessionId, req.OwnerToken),
 	}
 	connectedJSON, _ := json.Marshal(connectedEvent)
 	fmt.Fprintf(w, "event: connected\ndata: %s\n\n", string(connectedJSON))
@@ -500,9 +495,6 @@ func (l *StreamLogic) StreamConversationUnified(
 		return fmt.Errorf("Graph未初始化")
 	}
 
-	// 获取上下文消息
-	contextMessages := l.getContextMessages(sessionId, maxContextRounds)
-
 	// 获取对话节点
 	conversationNode := graph.GetConversationNode()
 	if conversationNode == nil {
@@ -522,6 +514,22 @@ func (l *StreamLogic) StreamConversationUnified(
 		return fmt.Errorf("ConversationNode未初始化")
 	}
 
+	// 新图片先识别对象：有多个候选对象且把握不大时，先问孩子想了解哪一个
+	if imageURL != "" && req.IdentificationContext == nil && graph.ImageRecognitionEnabled() {
+		identCtx, disambiguation := l.identifyImage(graph, sessionId, imageURL, userAge)
+		if disambiguation != nil {
+			return l.streamDisambiguation(w, sessionId, disambiguation, req.Tts, userAge)
+		}
+		if identCtx != nil {
+			objectName = identCtx.ObjectName
+			objectCategory = identCtx.ObjectCategory
+		}
+	}
+
+	// 选择本次对话使用的模型，按该模型的预算获取上下文消息
+	modelName := conversationNode.SelectModel()
+	contextMessages := l.getContextMessages(sessionId, maxContextRounds, modelName)
+
 	logger.Infow("开始流式对话",
 		logx.Field("sessionId", sessionId),
 		logx.Field("userAge", userAge),
@@ -540,6 +548,7 @@ func (l *StreamLogic) StreamConversationUnified(
 		objectName,
 		objectCategory,
 		imageURL, // 传入图片URL（如果提供）
+		modelName,
 	)
 	if err != nil {
 		logger.Errorw("调用Eino流式接口失败",
@@ -658,6 +667,12 @@ func (l *StreamLogic) StreamConversationUnified(
 	}
 	l.svcCtx.Storage.AddMessage(sessionId, assistantMessage)
 
+	// 语音回复（在完成事件之前发送，客户端收到done即表示全部内容已送达）
+	ttsLogic := NewTTSLogic(l.ctx, l.svcCtx)
+	if ttsLogic.ShouldSpeak(req.Tts, userAge) {
+		ttsLogic.StreamAudio(w, sessionId, assistantMessageId, fullText)
+	}
+
 	// 发送完成事件
 	doneEvent := types.StreamEvent{
 		Type:      "done",
@@ -676,6 +691,87 @@ func (l *StreamLogic) StreamConversationUnified(
 	return nil
 }
 
+// identifyImage 识别对话中的新图片
+// 把握较大时保存主对象为识别结果上下文；有多个候选对象且最高置信度低于阈值时保存候选对象并返回消歧内容。
+// 识别失败不影响对话，返回两个nil
+func (l *StreamLogic) identifyImage(graph *agent.Graph, sessionId string, imageURL string, userAge int) (*types.IdentificationContext, *types.Disambiguation) {
+	logger := logx.WithContext(l.ctx)
+
+	data, err := graph.ExecuteImageRecognition(imageURL, userAge)
+	if err != nil || data.ObjectName == "" {
+		logger.Errorw("对话图片识别失败，直接进行对话",
+			logx.Field("sessionId", sessionId),
+			logx.Field("error", err),
+		)
+		return nil, nil
+	}
+
+	objects := toDetectedObjects(data.Objects)
+	if needsDisambiguation(objects, disambiguationThreshold(l.svcCtx.Config.AI)) {
+		disambiguation := newDisambiguation(objects, imageURL)
+		l.svcCtx.Storage.SetData(sessionId, disambiguationDataKey, disambiguation)
+		logger.Infow("图片中有多个候选对象，询问孩子想了解哪一个",
+			logx.Field("sessionId", sessionId),
+			logx.Field("candidateCount", len(objects)),
+			logx.Field("topConfidence", objects[0].Confidence),
+		)
+		return nil, disambiguation
+	}
+
+	identCtx := &types.IdentificationContext{
+		ObjectName:     data.ObjectName,
+		ObjectCategory: data.ObjectCategory,
+		Confidence:     data.Confidence,
+		Keywords:       data.Keywords,
+		Age:            userAge,
+	}
+	if len(objects) > 0 {
+		identCtx.BoundingBox = objects[0].BoundingBox
+	}
+	l.svcCtx.Storage.SetData(sessionId, "identificationContext", identCtx)
+	clearDisambiguation(l.svcCtx.Storage, sessionId)
+	return identCtx, nil
+}
+
+// streamDisambiguation 发送消歧事件，把询问作为助手消息保存，等待孩子选择后再继续对话
+func (l *StreamLogic) streamDisambiguation(w http.ResponseWriter, sessionId string, disambiguation *types.Disambiguation, tts *bool, userAge int) error {
+	messageId := uuid.New().String()
+
+	event := types.StreamEvent{
+		Type:      "disambiguation",
+		Content:   disambiguation,
+		SessionId: sessionId,
+		MessageId: messageId,
+	}
+	eventJSON, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: disambiguation\ndata: %s\n\n", string(eventJSON))
+	w.(http.Flusher).Flush()
+
+	l.svcCtx.Storage.AddMessage(sessionId, types.ConversationMessage{
+		Id:        messageId,
+		Type:      "text",
+		Sender:    "assistant",
+		Content:   disambiguation.Question,
+		Timestamp: time.Now().Format(time.RFC3339),
+		SessionId: sessionId,
+	})
+
+	ttsLogic := NewTTSLogic(l.ctx, l.svcCtx)
+	if ttsLogic.ShouldSpeak(tts, userAge) {
+		ttsLogic.StreamAudio(w, sessionId, messageId, disambiguation.Question)
+	}
+
+	doneEvent := types.StreamEvent{
+		Type:      "done",
+		SessionId: sessionId,
+		MessageId: messageId,
+	}
+	doneJSON, _ := json.Marshal(doneEvent)
+	fmt.Fprintf(w, "event: done\ndata: %s\n\n", string(doneJSON))
+	w.(http.Flusher).Flush()
+	return nil
+}
+
 // streamTextMock Mock流式文本响应
 func (l *StreamLogic) streamTextMock(w http.ResponseWriter, sessionId string, message string) error {
 	text := fmt.Sprintf("这是一个Mock流式响应 🌟。您的问题是：%s。待接入真实AI模型后，将实现真实的流式文本生成 ✨。", message)
@@ -0,0 +1,185 @@
+package logic
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/speech"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ttsStreamChunkChars 流式对话中每个audio事件合成的最大字数
+// 按句子分段合成，客户端收到第一段即可开始播放
+const ttsStreamChunkChars = 120
+
+type TTSLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTTSLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TTSLogic {
+	return &TTSLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Synthesize 合成语音
+func (l *TTSLogic) Synthesize(req *types.TTSRequest) (*types.TTSResponse, error) {
+	text := speech.SpeechText(req.Text)
+	if text == "" {
+		return nil, utils.ErrTTSTextRequired
+	}
+	if maxChars := l.maxChars(); utf8.RuneCountInString(text) > maxChars {
+		return nil, utils.NewAPIError(utils.ErrTTSTextTooLong.Code, utils.ErrTTSTextTooLong.Message,
+			fmt.Sprintf("最多%d字", maxChars))
+	}
+	// 发音人和语速会传给合成命令或外部服务，先校验发音人名称并把语速限制在合理范围
+	if !speech.ValidVoice(req.Voice) {
+		return nil, utils.ErrTTSVoiceInvalid
+	}
+	speed := speech.ClampSpeed(req.Speed)
+
+	provider, err := l.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	synthesis, err := provider.Synthesize(l.ctx, text, speech.SynthesisOptions{Voice: req.Voice, Speed: speed})
+	if err != nil {
+		l.Errorw("语音合成失败",
+			logx.Field("provider", provider.Name()),
+			logx.Field("error", err),
+		)
+		return nil, utils.NewAPIError(utils.ErrTTSFailed.Code, utils.ErrTTSFailed.Message, err.Error())
+	}
+
+	return &types.TTSResponse{
+		Audio:    base64.StdEncoding.EncodeToString(synthesis.Audio),
+		Format:   string(synthesis.Format),
+		MimeType: synthesis.Format.MimeType(),
+		Duration: synthesis.Duration.Seconds(),
+		Text:     text,
+	}, nil
+}
+
+// ShouldSpeak 判断对话回复是否附带语音：请求显式指定时以请求为准，否则低龄儿童自动开启
+func (l *TTSLogic) ShouldSpeak(tts *bool, userAge int) bool {
+	if tts != nil {
+		return *tts
+	}
+	autoMaxAge := l.svcCtx.Config.AI.TTSAutoMaxAge
+	if autoMaxAge == 0 {
+		autoMaxAge = config.DefaultTTSAutoMaxAge
+	}
+	return userAge > 0 && userAge <= autoMaxAge
+}
+
+// StreamAudio 将助手回复合成语音，按句子分段以audio事件发送（base64音频）
+// 语音是文本回复的补充，合成失败只记录日志并发送结束标记，不影响对话
+func (l *TTSLogic) StreamAudio(w http.ResponseWriter, sessionId string, messageId string, text string) {
+	provider, err := l.provider()
+	if err != nil {
+		l.Infow("语音合成服务不可用，跳过语音回复",
+			logx.Field("sessionId", sessionId),
+			logx.Field("error", err),
+		)
+		return
+	}
+
+	chunks := l.limitChunks(speech.SplitSpeechText(speech.SpeechText(text), ttsStreamChunkChars))
+	if len(chunks) == 0 {
+		return
+	}
+
+	for i, chunk := range chunks {
+		synthesis, err := provider.Synthesize(l.ctx, chunk, speech.SynthesisOptions{})
+		if err != nil {
+			l.Errorw("流式语音合成失败",
+				logx.Field("provider", provider.Name()),
+				logx.Field("sessionId", sessionId),
+				logx.Field("index", i),
+				logx.Field("error", err),
+			)
+			l.sendAudioEvent(w, sessionId, messageId, map[string]interface{}{
+				"messageId": messageId,
+				"index":     i,
+				"final":     true,
+				"error":     "语音合成失败",
+			})
+			return
+		}
+
+		l.sendAudioEvent(w, sessionId, messageId, map[string]interface{}{
+			"messageId": messageId,
+			"index":     i,
+			"final":     i == len(chunks)-1,
+			"text":      chunk,
+			"format":    string(synthesis.Format),
+			"mimeType":  synthesis.Format.MimeType(),
+			"duration":  synthesis.Duration.Seconds(),
+			"data":      base64.StdEncoding.EncodeToString(synthesis.Audio),
+		})
+	}
+
+	l.Infow("语音回复发送完成",
+		logx.Field("provider", provider.Name()),
+		logx.Field("sessionId", sessionId),
+		logx.Field("chunks", len(chunks)),
+	)
+}
+
+// sendAudioEvent 发送audio事件
+func (l *TTSLogic) sendAudioEvent(w http.ResponseWriter, sessionId string, messageId string, content map[string]interface{}) {
+	event := types.StreamEvent{
+		Type:      "audio",
+		Content:   content,
+		SessionId: sessionId,
+		MessageId: messageId,
+	}
+	eventJSON, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: audio\ndata: %s\n\n", string(eventJSON))
+	w.(http.Flusher).Flush()
+}
+
+// provider 获取语音合成提供方（根据配置决定是否允许Mock降级）
+func (l *TTSLogic) provider() (speech.TTSProvider, error) {
+	if l.svcCtx.TTS != nil {
+		return l.svcCtx.TTS, nil
+	}
+	// 未配置语音合成服务：USE_AI_MODEL=false时允许使用Mock数据
+	if l.svcCtx.Config.AI.UseAIModel {
+		return nil, utils.ErrTTSUnavailable
+	}
+	return speech.NewMockTTSProvider(), nil
+}
+
+// maxChars 单次合成最大字数
+func (l *TTSLogic) maxChars() int {
+	if l.svcCtx.Config.AI.TTSMaxChars > 0 {
+		return l.svcCtx.Config.AI.TTSMaxChars
+	}
+	return config.DefaultTTSMaxChars
+}
+
+// limitChunks 截取不超过最大字数的分段，超长回复只朗读前面部分
+func (l *TTSLogic) limitChunks(chunks []string) []string {
+	remaining := l.maxChars()
+	for i, chunk := range chunks {
+		remaining -= utf8.RuneCountInString(chunk)
+		if remaining < 0 {
+			return chunks[:i]
+		}
+	}
+	return chunks
+}
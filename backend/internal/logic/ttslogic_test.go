@@ -0,0 +1,124 @@
+package logic
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/speech"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/tango/explore/internal/utils"
+)
+
+func TestTTSLogic_Synthesize(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config: config.Config{AI: config.AIConfig{TTSMaxChars: 10}},
+		TTS:    speech.NewMockTTSProvider(),
+	}
+	l := NewTTSLogic(context.Background(), svcCtx)
+
+	resp, err := l.Synthesize(&types.TTSRequest{Text: "**你好**🌟"})
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	if resp.Text != "你好" || resp.Format != "wav" || resp.MimeType != "audio/wav" {
+		t.Errorf("响应错误: %+v", resp)
+	}
+	if _, err := base64.StdEncoding.DecodeString(resp.Audio); err != nil {
+		t.Errorf("音频应为base64: %v", err)
+	}
+
+	if _, err := l.Synthesize(&types.TTSRequest{Text: "✨"}); !errors.Is(err, utils.ErrTTSTextRequired) {
+		t.Errorf("去除符号后为空应返回ErrTTSTextRequired, 实际: %v", err)
+	}
+	var apiErr *utils.APIError
+	if _, err := l.Synthesize(&types.TTSRequest{Text: strings.Repeat("长", 11)}); !errors.As(err, &apiErr) || apiErr.Code != utils.ErrTTSTextTooLong.Code {
+		t.Errorf("超长文本应返回ErrTTSTextTooLong, 实际: %v", err)
+	}
+
+	// 发音人名称只允许安全字符，语速超出范围时取边界值
+	if _, err := l.Synthesize(&types.TTSRequest{Text: "你好", Voice: "../../etc/passwd"}); !errors.Is(err, utils.ErrTTSVoiceInvalid) {
+		t.Errorf("非法发音人应返回ErrTTSVoiceInvalid, 实际: %v", err)
+	}
+	fast, err := l.Synthesize(&types.TTSRequest{Text: "你好", Voice: "en+f3", Speed: 1e12})
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	if fast.Duration != 0.1 {
+		t.Errorf("语速应限制为%v倍, 实际时长: %v", speech.MaxSpeed, fast.Duration)
+	}
+
+	// USE_AI_MODEL=true且未配置语音合成时不允许Mock降级
+	unavailable := NewTTSLogic(context.Background(), &svc.ServiceContext{
+		Config: config.Config{AI: config.AIConfig{UseAIModel: true}},
+	})
+	if _, err := unavailable.Synthesize(&types.TTSRequest{Text: "你好"}); !errors.Is(err, utils.ErrTTSUnavailable) {
+		t.Errorf("未配置语音合成应返回ErrTTSUnavailable, 实际: %v", err)
+	}
+}
+
+func TestTTSLogic_ShouldSpeak(t *testing.T) {
+	l := NewTTSLogic(context.Background(), &svc.ServiceContext{})
+	on, off := true, false
+
+	if !l.ShouldSpeak(nil, 5) || l.ShouldSpeak(nil, 8) {
+		t.Error("未指定tts时应只对6岁及以下自动开启")
+	}
+	if !l.ShouldSpeak(&on, 12) || l.ShouldSpeak(&off, 4) {
+		t.Error("显式指定tts时应以请求为准")
+	}
+
+	disabled := NewTTSLogic(context.Background(), &svc.ServiceContext{
+		Config: config.Config{AI: config.AIConfig{TTSAutoMaxAge: -1}},
+	})
+	if disabled.ShouldSpeak(nil, 4) {
+		t.Error("TTSAutoMaxAge为负数时应关闭自动朗读")
+	}
+}
+
+func TestTTSLogic_StreamAudio(t *testing.T) {
+	svcCtx := &svc.ServiceContext{TTS: speech.NewMockTTSProvider()}
+	l := NewTTSLogic(context.Background(), svcCtx)
+
+	w := httptest.NewRecorder()
+	answer := "## 银杏 🌳\n" + strings.Repeat("银杏的叶子像一把小扇子。", 12)
+	l.StreamAudio(w, "session-1", "message-1", answer)
+
+	var events []types.StreamEvent
+	for _, block := range strings.Split(strings.TrimSpace(w.Body.String()), "\n\n") {
+		lines := strings.SplitN(block, "\n", 2)
+		if len(lines) != 2 || lines[0] != "event: audio" {
+			t.Fatalf("事件格式错误: %q", block)
+		}
+		var event types.StreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &event); err != nil {
+			t.Fatalf("解析事件失败: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) < 2 {
+		t.Fatalf("长回复应分段合成, 实际事件数: %d", len(events))
+	}
+	for i, event := range events {
+		content := event.Content.(map[string]interface{})
+		if event.SessionId != "session-1" || content["messageId"] != "message-1" || int(content["index"].(float64)) != i {
+			t.Errorf("第%d个事件字段错误: %+v", i, event)
+		}
+		if content["format"] != "wav" || content["data"] == "" {
+			t.Errorf("第%d个事件缺少音频: %+v", i, content)
+		}
+		if strings.Contains(content["text"].(string), "#") {
+			t.Errorf("朗读文本不应包含Markdown符号: %q", content["text"])
+		}
+		if final := content["final"].(bool); final != (i == len(events)-1) {
+			t.Errorf("第%d个事件final = %v", i, final)
+		}
+	}
+}
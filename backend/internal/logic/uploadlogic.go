@@ -3,8 +3,11 @@ package logic
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
+	"errors"
+	"strings"
 
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/storage"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
@@ -87,85 +90,126 @@ func (l *UploadLogic) Upload(req *types.UploadRequest) (resp *types.UploadRespon
 		return nil, utils.ErrImageDataInvalid
 	}
 
-	// 生成文件名
-	filename := req.Filename
-	if filename == "" {
-		// 从图片数据推断扩展名
-		ext := ".jpg" // 默认 JPEG
-		if len(imageData) >= 4 {
-			if imageData[0] == 0x89 && imageData[1] == 0x50 && imageData[2] == 0x4E && imageData[3] == 0x47 {
-				ext = ".png"
-			} else if len(imageData) >= 12 && imageData[0] == 0x52 && imageData[1] == 0x49 && imageData[2] == 0x46 && imageData[3] == 0x46 {
-				if imageData[8] == 0x57 && imageData[9] == 0x45 && imageData[10] == 0x42 && imageData[11] == 0x50 {
-					ext = ".webp"
-				}
-			} else if len(imageData) >= 6 && imageData[0] == 0x47 && imageData[1] == 0x49 && imageData[2] == 0x46 && imageData[3] == 0x38 {
-				ext = ".gif"
-			}
+	// 统一处理图片：按EXIF方向旋转、去除EXIF/GPS等元数据、缩放并重新编码，同时生成缩略图
+	processed, err := imageproc.Normalize(imageData, imageproc.OptionsFromConfig(l.svcCtx.Config.Upload))
+	if err != nil {
+		l.Errorw("图片处理失败",
+			logx.Field("error", err),
+			logx.Field("size", len(imageData)),
+		)
+		if errors.Is(err, imageproc.ErrImageTooLarge) {
+			return nil, utils.ErrImageTooLarge
 		}
-		filename = utils.GenerateFilename(ext)
+		return nil, utils.ErrImageDataInvalid
 	}
 
+	// 生成文件名：统一按内容摘要命名，相同内容得到相同文件名，不同内容不会覆盖已有文件
+	// （已存储的URL内容不变，可以长期缓存）；客户端提供的文件名只作记录。重新编码后格式可能变化，扩展名以处理后的格式为准
+	contentHash := storage.ContentHash(processed.Data)
+	filename := storage.ContentAddressedName(contentHash, processed.Ext())
+	thumbnailName := strings.TrimSuffix(filename, processed.Ext()) + "-thumb" + processed.Ext()
+
 	l.Infow("开始上传图片",
 		logx.Field("filename", filename),
-		logx.Field("size", len(imageData)),
-		logx.Field("hasGitHubStorage", l.svcCtx.GitHubStorage != nil),
+		logx.Field("originalFilename", req.Filename),
+		logx.Field("originalSize", processed.OriginalSize),
+		logx.Field("size", len(processed.Data)),
+		logx.Field("hasImageStore", l.svcCtx.ImageStore != nil),
 	)
 
-	// 优先上传到 GitHub（默认方式）
+	// 优先上传到配置的图片存储（GitHub、本地磁盘或S3兼容存储）
 	var imageURL string
+	var thumbnailURL string
 	var uploadMethod string
-
-	if l.svcCtx.GitHubStorage != nil {
-		// GitHub 存储已初始化，优先使用 GitHub 上传
-		url, err := l.svcCtx.GitHubStorage.Upload(imageData, filename)
-		if err != nil {
-			// GitHub 上传失败，记录详细错误信息
-			l.Errorw("GitHub 上传失败",
+	var deduplicated bool
+
+	if l.svcCtx.ImageStore != nil {
+		if indexed, ok := l.svcCtx.ImageIndex.Get(contentHash); ok {
+			// 内容相同的图片已上传过，直接复用已有URL
+			uploadMethod = l.svcCtx.ImageStore.Name()
+			imageURL = indexed.URL
+			thumbnailURL = indexed.ThumbnailURL
+			filename = indexed.Filename
+			deduplicated = true
+			l.Infow("图片内容已上传过，复用已有URL",
+				logx.Field("backend", uploadMethod),
+				logx.Field("url", imageURL),
+				logx.Field("filename", filename),
+			)
+		} else if url, err := l.svcCtx.ImageStore.Save(l.ctx, processed.Data, filename); err != nil {
+			// 上传失败，记录详细错误信息
+			l.Errorw("图片存储上传失败",
+				logx.Field("backend", l.svcCtx.ImageStore.Name()),
 				logx.Field("error", err),
-				logx.Field("errorDetail", err.Error()),
 				logx.Field("filename", filename),
-				logx.Field("size", len(imageData)),
+				logx.Field("size", len(processed.Data)),
 			)
-			// 如果GitHub配置了但上传失败，仍然降级到base64（保证功能可用）
-			// 但记录警告，提示检查GitHub配置
-			l.Infow("GitHub 上传失败，降级到 base64（请检查GitHub配置）",
+			// 如果配置了存储但上传失败，仍然降级到base64（保证功能可用）
+			// 但记录警告，提示检查存储配置
+			l.Infow("图片存储上传失败，降级到 base64（请检查存储配置）",
 				logx.Field("filename", filename),
 			)
-			uploadMethod = "base64"
-			imageURL = fmt.Sprintf("data:image/jpeg;base64,%s", req.ImageData)
 		} else {
-			// GitHub 上传成功
-			uploadMethod = "github"
+			uploadMethod = l.svcCtx.ImageStore.Name()
 			imageURL = url
-			l.Infow("图片已上传到 GitHub",
+			l.Infow("图片已上传到图片存储",
+				logx.Field("backend", uploadMethod),
 				logx.Field("url", url),
 				logx.Field("filename", filename),
 			)
+
+			// 缩略图上传失败不影响主图
+			thumbnailURL, err = l.svcCtx.ImageStore.Save(l.ctx, processed.Thumbnail, thumbnailName)
+			if err != nil {
+				l.Errorw("缩略图上传失败",
+					logx.Field("error", err),
+					logx.Field("filename", thumbnailName),
+				)
+				thumbnailURL = ""
+			} else {
+				l.svcCtx.ImageIndex.Put(contentHash, storage.IndexedImage{
+					URL:          imageURL,
+					ThumbnailURL: thumbnailURL,
+					Filename:     filename,
+				})
+			}
 		}
 	} else {
-		// GitHub 存储未初始化，使用 base64（降级方案）
-		l.Infow("GitHub 存储未配置，使用 base64 降级方案",
+		// 图片存储未配置，使用 base64（降级方案）
+		l.Infow("图片存储未配置，使用 base64 降级方案",
 			logx.Field("filename", filename),
-			logx.Field("hint", "如需使用GitHub存储，请配置GITHUB_TOKEN、GITHUB_OWNER、GITHUB_REPO"),
+			logx.Field("hint", "如需保存图片，请配置UPLOAD_BACKEND，或配置GITHUB_TOKEN、GITHUB_OWNER、GITHUB_REPO"),
 		)
+	}
+
+	if imageURL == "" {
 		uploadMethod = "base64"
-		imageURL = fmt.Sprintf("data:image/jpeg;base64,%s", req.ImageData)
+		imageURL = dataURL(processed.ContentType, processed.Data)
+		thumbnailURL = dataURL(processed.ContentType, processed.Thumbnail)
 	}
 
 	// 构建响应
 	resp = &types.UploadResponse{
-		Url:          imageURL,
-		Filename:     filename,
-		Size:         len(imageData),
-		UploadMethod: uploadMethod,
+		Url:              imageURL,
+		Filename:         filename,
+		OriginalFilename: req.Filename,
+		Size:             len(processed.Data),
+		UploadMethod:     uploadMethod,
+		OriginalSize:     processed.OriginalSize,
+		Width:            processed.Width,
+		Height:           processed.Height,
+		OriginalWidth:    processed.OriginalWidth,
+		OriginalHeight:   processed.OriginalHeight,
+		ThumbnailUrl:     thumbnailURL,
+		Deduplicated:     deduplicated,
 	}
 
 	l.Infow("图片上传完成",
 		logx.Field("filename", filename),
-		logx.Field("url", imageURL),
+		logx.Field("url", getDataPreview(imageURL)),
 		logx.Field("method", uploadMethod),
-		logx.Field("size", len(imageData)),
+		logx.Field("originalSize", processed.OriginalSize),
+		logx.Field("size", len(processed.Data)),
 	)
 
 	return resp, nil
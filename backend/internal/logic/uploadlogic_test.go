@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/svc"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestUploadLogic_Base64Fallback(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config: config.Config{Upload: config.UploadConfig{ImageMaxEdge: 100, ThumbnailMaxEdge: 20}},
+	}
+
+	resp, err := NewUploadLogic(context.Background(), svcCtx).Upload(&types.UploadRequest{
+		ImageData: encodeTestPNG(t, 400, 200),
+		Filename:  "photo.png",
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if resp.UploadMethod != "base64" || !strings.HasPrefix(resp.Url, "data:image/jpeg;base64,") {
+		t.Errorf("未配置存储时应返回处理后的 base64, got %s %s", resp.UploadMethod, getDataPreview(resp.Url))
+	}
+	if !strings.HasSuffix(resp.Filename, ".jpg") || resp.Filename == "photo.jpg" || resp.OriginalFilename != "photo.png" {
+		t.Errorf("应按内容摘要命名（扩展名跟随处理后的格式），原始文件名只作记录, got %s %s", resp.Filename, resp.OriginalFilename)
+	}
+	if resp.Width != 100 || resp.Height != 50 || resp.OriginalWidth != 400 || resp.OriginalHeight != 200 {
+		t.Errorf("尺寸错误: %+v", resp)
+	}
+	if resp.OriginalSize == 0 || resp.Size == 0 || !strings.HasPrefix(resp.ThumbnailUrl, "data:image/jpeg;base64,") {
+		t.Errorf("大小或缩略图错误: %+v", resp)
+	}
+}
+
+func TestUploadLogic_LocalStore(t *testing.T) {
+	dir := t.TempDir()
+	uploadCfg := config.UploadConfig{Backend: config.ImageBackendLocal, LocalDir: dir}
+	store, err := storage.NewLocalImageStore(uploadCfg, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewLocalImageStore failed: %v", err)
+	}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{Upload: uploadCfg},
+		ImageStore: store,
+		ImageIndex: storage.NewImageIndex(0),
+	}
+
+	imageData := encodeTestPNG(t, 64, 64)
+	resp, err := NewUploadLogic(context.Background(), svcCtx).Upload(&types.UploadRequest{
+		ImageData: imageData,
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if resp.UploadMethod != config.ImageBackendLocal {
+		t.Errorf("UploadMethod = %s, want local", resp.UploadMethod)
+	}
+	thumbName := strings.TrimSuffix(resp.Filename, ".jpg") + "-thumb.jpg"
+	if !strings.HasSuffix(resp.ThumbnailUrl, "/api/images/"+thumbName) {
+		t.Errorf("ThumbnailUrl = %s", resp.ThumbnailUrl)
+	}
+	for _, name := range []string{resp.Filename, thumbName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("图片未保存: %s, %v", name, err)
+		}
+	}
+	if resp.Deduplicated {
+		t.Error("首次上传不应标记为复用")
+	}
+
+	// 相同内容再次上传，复用已有URL
+	again, err := NewUploadLogic(context.Background(), svcCtx).Upload(&types.UploadRequest{
+		ImageData: imageData,
+		Filename:  "again.png",
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if !again.Deduplicated || again.Url != resp.Url || again.ThumbnailUrl != resp.ThumbnailUrl || again.Filename != resp.Filename {
+		t.Errorf("相同内容应复用已有URL: %+v", again)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "again.jpg")); !os.IsNotExist(err) {
+		t.Error("复用时不应再次写入存储")
+	}
+}
+
+func TestUploadLogic_SameFilenameDifferentContent(t *testing.T) {
+	dir := t.TempDir()
+	uploadCfg := config.UploadConfig{Backend: config.ImageBackendLocal, LocalDir: dir}
+	store, err := storage.NewLocalImageStore(uploadCfg, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewLocalImageStore failed: %v", err)
+	}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{Upload: uploadCfg},
+		ImageStore: store,
+		ImageIndex: storage.NewImageIndex(0),
+	}
+	upload := func(imageData string) *types.UploadResponse {
+		resp, err := NewUploadLogic(context.Background(), svcCtx).Upload(&types.UploadRequest{ImageData: imageData, Filename: "x.jpg"})
+		if err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+		return resp
+	}
+
+	// 以相同文件名依次上传 A、B、A
+	imageA, imageB := encodeTestPNG(t, 64, 64), encodeTestPNG(t, 32, 48)
+	first := upload(imageA)
+	second := upload(imageB)
+	third := upload(imageA)
+
+	if first.Url == second.Url || first.Filename == second.Filename {
+		t.Fatalf("不同内容不应使用同一个文件: %s %s", first.Url, second.Url)
+	}
+	if second.Deduplicated {
+		t.Error("不同内容不应标记为复用")
+	}
+	if !third.Deduplicated || third.Url != first.Url || third.Filename != first.Filename {
+		t.Errorf("再次上传 A 应复用 A 的URL: %+v", third)
+	}
+
+	// A 的文件内容没有被 B 覆盖
+	saved, err := os.ReadFile(filepath.Join(dir, first.Filename))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if storage.ContentAddressedName(storage.ContentHash(saved), ".jpg") != first.Filename {
+		t.Error("A 的文件内容被覆盖")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.jpg")); !os.IsNotExist(err) {
+		t.Error("不应按客户端文件名存储")
+	}
+}
+
+func TestUploadLogic_InvalidImage(t *testing.T) {
+	svcCtx := &svc.ServiceContext{}
+	// PNG 文件头通过格式校验，但内容无法解码
+	data := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\nbroken image data"))
+	if _, err := NewUploadLogic(context.Background(), svcCtx).Upload(&types.UploadRequest{ImageData: data}); err == nil {
+		t.Error("无法解码的图片应返回错误")
+	}
+}
@@ -2,21 +2,25 @@ package logic
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/tango/explore/internal/speech"
 	"github.com/tango/explore/internal/svc"
 	"github.com/tango/explore/internal/types"
 	"github.com/tango/explore/internal/utils"
+	"github.com/zeromicro/go-zero/core/logx"
 )
 
 type VoiceLogic struct {
+	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
 }
 
 func NewVoiceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VoiceLogic {
 	return &VoiceLogic{
+		Logger: logx.WithContext(ctx),
 		ctx:    ctx,
 		svcCtx: svcCtx,
 	}
@@ -24,35 +28,62 @@ func NewVoiceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VoiceLogic
 
 // RecognizeVoice 识别语音
 func (l *VoiceLogic) RecognizeVoice(req *types.VoiceRequest) (*types.VoiceResponse, error) {
-	// 清理 base64 字符串（移除可能的空白字符）
-	audioData := utils.CleanBase64String(req.Audio)
-	
-	// 解码音频数据
-	_, err := base64.StdEncoding.DecodeString(audioData)
+	recognizer := speech.NewRecognizer(l.svcCtx.Config.AI, l.svcCtx.ASR)
+	recognition, err := recognizer.Recognize(l.ctx, req.Audio)
 	if err != nil {
-		return nil, err
+		l.Errorw("语音识别失败", logx.Field("error", err))
+		return nil, voiceAPIError(err)
 	}
 
-	// TODO: 调用语音识别模型（通过eino框架）
-	// 当前使用Mock数据
-	recognizedText := "这是Mock语音识别结果。待接入真实语音识别模型后，将实现真实的语音转文本功能。"
-
 	// 生成或使用现有会话ID
 	sessionId := req.SessionId
 	if sessionId == "" {
 		sessionId = uuid.New().String()
 	}
 
-	// 可选：调用意图识别（当前未使用结果，待后续实现）
-	// intentLogic := NewIntentLogic(l.ctx, l.svcCtx)
-	// intentReq := &types.IntentRequest{
-	// 	Message:   recognizedText,
-	// 	SessionId: sessionId,
-	// }
-	// _, _ = intentLogic.RecognizeIntent(intentReq)
-
 	return &types.VoiceResponse{
-		Text:      recognizedText,
-		SessionId: sessionId,
+		Text:       recognition.Text,
+		SessionId:  sessionId,
+		Format:     string(recognition.Info.Format),
+		Duration:   recognition.Duration,
+		Language:   recognition.Language,
+		Confidence: recognition.Confidence,
+		Segments:   voiceSegments(recognition),
 	}, nil
 }
+
+// voiceSegments 转换分段识别结果
+func voiceSegments(recognition *speech.Recognition) []types.VoiceSegment {
+	segments := make([]types.VoiceSegment, 0, len(recognition.Segments))
+	for _, seg := range recognition.Segments {
+		segments = append(segments, types.VoiceSegment{
+			Text:       seg.Text,
+			Start:      seg.Start,
+			End:        seg.End,
+			Confidence: seg.Confidence,
+		})
+	}
+	return segments
+}
+
+// voiceAPIError 将语音识别流程的错误转换为API错误
+func voiceAPIError(err error) error {
+	switch {
+	case errors.Is(err, speech.ErrAudioRequired):
+		return utils.ErrAudioRequired
+	case errors.Is(err, speech.ErrAudioDataInvalid):
+		return utils.ErrAudioDataInvalid
+	case errors.Is(err, speech.ErrAudioFormatUnsupported):
+		return utils.ErrAudioFormatInvalid
+	case errors.Is(err, speech.ErrAudioTooLarge):
+		return utils.NewAPIError(utils.ErrAudioTooLarge.Code, utils.ErrAudioTooLarge.Message, err.Error())
+	case errors.Is(err, speech.ErrAudioTooLong):
+		return utils.NewAPIError(utils.ErrAudioTooLong.Code, utils.ErrAudioTooLong.Message, err.Error())
+	case errors.Is(err, speech.ErrASRUnavailable):
+		return utils.ErrASRUnavailable
+	case errors.Is(err, speech.ErrSpeechNotRecognized):
+		return utils.ErrSpeechNotRecognized
+	default:
+		return utils.NewAPIError(utils.ErrASRFailed.Code, utils.ErrASRFailed.Message, err.Error())
+	}
+}
@@ -0,0 +1,146 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 语音识别提供方类型
+const (
+	ASRProviderWhisper = "whisper" // OpenAI兼容的 /audio/transcriptions 接口（Whisper风格）
+	ASRProviderMock    = "mock"    // Mock识别结果（仅用于开发测试）
+)
+
+var (
+	// ErrAudioFormatUnsupported 不支持的音频格式
+	ErrAudioFormatUnsupported = errors.New("不支持的音频格式")
+	// ErrAudioTooLarge 音频大小超过限制
+	ErrAudioTooLarge = errors.New("音频大小超过限制")
+	// ErrAudioTooLong 音频时长超过限制
+	ErrAudioTooLong = errors.New("音频时长超过限制")
+)
+
+// Segment 识别结果分段
+type Segment struct {
+	Text       string  // 分段文本
+	Start      float64 // 开始时间（秒）
+	End        float64 // 结束时间（秒）
+	Confidence float64 // 置信度 0-1
+}
+
+// Transcription 语音识别结果
+type Transcription struct {
+	Text       string    // 完整识别文本
+	Language   string    // 识别出的语言
+	Duration   float64   // 音频时长（秒）
+	Confidence float64   // 整体置信度 0-1（按分段时长加权）
+	Segments   []Segment // 分段结果
+}
+
+// ASRProvider 语音识别（语音转文本）提供方
+type ASRProvider interface {
+	// Name 提供方名称
+	Name() string
+	// Transcribe 识别音频，info 为 DetectAudio 的检测结果
+	Transcribe(ctx context.Context, audio []byte, info AudioInfo) (*Transcription, error)
+}
+
+// Limits 音频限制
+type Limits struct {
+	MaxBytes    int           // 最大字节数
+	MaxDuration time.Duration // 最大时长
+}
+
+// LimitsFromConfig 从AI配置读取音频限制（未配置时使用默认值）
+func LimitsFromConfig(cfg config.AIConfig) Limits {
+	limits := Limits{
+		MaxBytes:    config.DefaultASRMaxAudioBytes,
+		MaxDuration: time.Duration(config.DefaultASRMaxDurationSeconds) * time.Second,
+	}
+	if cfg.ASRMaxAudioBytes > 0 {
+		limits.MaxBytes = cfg.ASRMaxAudioBytes
+	}
+	if cfg.ASRMaxDurationSeconds > 0 {
+		limits.MaxDuration = time.Duration(cfg.ASRMaxDurationSeconds) * time.Second
+	}
+	return limits
+}
+
+// Validate 检测音频格式并校验大小和时长
+// 无法从文件头解析时长时（如浏览器录制的WebM），只校验大小
+func (l Limits) Validate(audio []byte) (AudioInfo, error) {
+	if l.MaxBytes > 0 && len(audio) > l.MaxBytes {
+		return AudioInfo{Size: len(audio)}, fmt.Errorf("%w: %d字节，最大%d字节", ErrAudioTooLarge, len(audio), l.MaxBytes)
+	}
+
+	info := DetectAudio(audio)
+	if info.Format == AudioFormatUnknown {
+		return info, ErrAudioFormatUnsupported
+	}
+	if l.MaxDuration > 0 && info.Duration > l.MaxDuration {
+		return info, fmt.Errorf("%w: %.1f秒，最长%.0f秒", ErrAudioTooLong, info.Duration.Seconds(), l.MaxDuration.Seconds())
+	}
+	return info, nil
+}
+
+// NewASRProvider 根据AI配置创建语音识别提供方
+// 未指定提供方时：配置了服务地址则使用Whisper风格接口，USE_AI_MODEL=false时使用Mock，否则返回nil
+func NewASRProvider(cfg config.AIConfig, logger logx.Logger) (ASRProvider, error) {
+	provider := cfg.ASRProvider
+	if provider == "" {
+		switch {
+		case cfg.ASRBaseURL != "" || cfg.EinoBaseURL != "":
+			provider = ASRProviderWhisper
+		case !cfg.UseAIModel:
+			provider = ASRProviderMock
+		default:
+			return nil, nil
+		}
+	}
+
+	switch provider {
+	case ASRProviderWhisper:
+		whisper, err := NewWhisperProvider(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return whisper, nil
+	case ASRProviderMock:
+		return NewMockASRProvider(), nil
+	default:
+		return nil, fmt.Errorf("不支持的语音识别提供方: %s", provider)
+	}
+}
+
+// MockASRProvider Mock语音识别（仅用于开发测试）
+type MockASRProvider struct{}
+
+// NewMockASRProvider 创建Mock语音识别
+func NewMockASRProvider() *MockASRProvider {
+	return &MockASRProvider{}
+}
+
+// Name 提供方名称
+func (p *MockASRProvider) Name() string {
+	return ASRProviderMock
+}
+
+// Transcribe 返回固定的Mock识别结果
+func (p *MockASRProvider) Transcribe(ctx context.Context, audio []byte, info AudioInfo) (*Transcription, error) {
+	text := "这是Mock语音识别结果。待接入真实语音识别模型后，将实现真实的语音转文本功能。"
+	duration := info.Duration.Seconds()
+	return &Transcription{
+		Text:       text,
+		Language:   "zh",
+		Duration:   duration,
+		Confidence: 1,
+		Segments: []Segment{
+			{Text: text, Start: 0, End: duration, Confidence: 1},
+		},
+	}, nil
+}
@@ -0,0 +1,257 @@
+package speech
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// buildWAV 构造16kHz单声道16位PCM的WAV数据
+func buildWAV(seconds float64) []byte {
+	const sampleRate, channels, bitsPerSample = 16000, 1, 16
+	byteRate := sampleRate * channels * bitsPerSample / 8
+	dataSize := int(float64(byteRate) * seconds)
+
+	buf := make([]byte, 44+dataSize)
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(36+dataSize))
+	copy(buf[8:12], "WAVE")
+	copy(buf[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], 1)
+	binary.LittleEndian.PutUint16(buf[22:24], channels)
+	binary.LittleEndian.PutUint32(buf[24:28], sampleRate)
+	binary.LittleEndian.PutUint32(buf[28:32], uint32(byteRate))
+	binary.LittleEndian.PutUint16(buf[32:34], channels*bitsPerSample/8)
+	binary.LittleEndian.PutUint16(buf[34:36], bitsPerSample)
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], uint32(dataSize))
+	return buf
+}
+
+// mp4Box 构造MP4 box
+func mp4Box(typ string, body []byte) []byte {
+	box := make([]byte, 8+len(body))
+	binary.BigEndian.PutUint32(box[0:4], uint32(len(box)))
+	copy(box[4:8], typ)
+	copy(box[8:], body)
+	return box
+}
+
+// buildM4A 构造只包含ftyp和moov/mvhd的M4A数据
+func buildM4A(timescale, duration uint32) []byte {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], timescale)
+	binary.BigEndian.PutUint32(mvhd[16:20], duration)
+
+	data := mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00isom"))
+	data = append(data, mp4Box("moov", mp4Box("mvhd", mvhd))...)
+	return data
+}
+
+// buildWebM 构造包含Info/Duration的WebM数据（Duration单位为TimecodeScale，默认1ms）
+func buildWebM(durationMs float64) []byte {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(durationMs))
+
+	info := append([]byte{0x2A, 0xD7, 0xB1, 0x83, 0x0F, 0x42, 0x40}, 0x44, 0x89, 0x88)
+	info = append(info, duration...)
+
+	data := []byte{0x1A, 0x45, 0xDF, 0xA3, 0x80}                                                // 空EBML头
+	data = append(data, 0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF) // 未知长度Segment
+	data = append(data, 0x15, 0x49, 0xA9, 0x66, byte(0x80|len(info)))
+	data = append(data, info...)
+	return data
+}
+
+// buildMP3 构造128kbps MPEG1 Layer III数据
+func buildMP3(seconds float64) []byte {
+	size := int(128000 / 8 * seconds)
+	data := make([]byte, size)
+	data[0], data[1], data[2], data[3] = 0xFF, 0xFB, 0x90, 0x64
+	return data
+}
+
+func TestDetectAudio(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		format   AudioFormat
+		duration time.Duration
+	}{
+		{"wav", buildWAV(2), AudioFormatWAV, 2 * time.Second},
+		{"m4a", buildM4A(44100, 44100*3), AudioFormatM4A, 3 * time.Second},
+		{"webm", buildWebM(1500), AudioFormatWebM, 1500 * time.Millisecond},
+		{"webm无时长", []byte{0x1A, 0x45, 0xDF, 0xA3, 0x80}, AudioFormatWebM, 0},
+		{"mp3", buildMP3(4), AudioFormatMP3, 4 * time.Second},
+		{"mp3带ID3", append([]byte("ID3\x04\x00\x00\x00\x00\x00\x00"), buildMP3(1)...), AudioFormatMP3, time.Second},
+		{"未知格式", []byte("not audio at all"), AudioFormatUnknown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := DetectAudio(tt.data)
+			if info.Format != tt.format {
+				t.Errorf("Format = %q, 期望 %q", info.Format, tt.format)
+			}
+			if diff := info.Duration - tt.duration; diff > time.Millisecond || diff < -time.Millisecond {
+				t.Errorf("Duration = %v, 期望 %v", info.Duration, tt.duration)
+			}
+		})
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	limits := Limits{MaxBytes: 100000, MaxDuration: 2 * time.Second}
+
+	if _, err := limits.Validate(buildWAV(1)); err != nil {
+		t.Errorf("合法音频不应报错: %v", err)
+	}
+	if _, err := limits.Validate(make([]byte, 100001)); !errors.Is(err, ErrAudioTooLarge) {
+		t.Errorf("超过大小限制应返回ErrAudioTooLarge, 实际: %v", err)
+	}
+	if _, err := limits.Validate(buildM4A(1000, 3000)); !errors.Is(err, ErrAudioTooLong) {
+		t.Errorf("超过时长限制应返回ErrAudioTooLong, 实际: %v", err)
+	}
+	if _, err := limits.Validate([]byte("plain text")); !errors.Is(err, ErrAudioFormatUnsupported) {
+		t.Errorf("未知格式应返回ErrAudioFormatUnsupported, 实际: %v", err)
+	}
+}
+
+func TestWhisperProvider_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("请求路径 = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer app:key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("解析multipart失败: %v", err)
+		}
+		if r.FormValue("model") != config.DefaultASRModel || r.FormValue("response_format") != "verbose_json" || r.FormValue("language") != "zh" {
+			t.Errorf("表单字段错误: %v", r.MultipartForm.Value)
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil || header.Filename != "audio.wav" || header.Header.Get("Content-Type") != "audio/wav" {
+			t.Errorf("音频文件字段错误: %v %+v", err, header)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"text": " 这是银杏树吗 ",
+			"language": "chinese",
+			"duration": 3,
+			"segments": [
+				{"start": 0, "end": 1, "text": " 这是", "avg_logprob": 0, "no_speech_prob": 0},
+				{"start": 1, "end": 3, "text": "银杏树吗", "avg_logprob": -0.6931471805599453, "no_speech_prob": 0.5}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewASRProvider(config.AIConfig{
+		EinoBaseURL: server.URL + "/v1/",
+		AppID:       "app",
+		AppKey:      "key",
+		ASRLanguage: "zh",
+		UseAIModel:  true,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("创建语音识别失败: %v", err)
+	}
+	if provider.Name() != ASRProviderWhisper {
+		t.Fatalf("配置了服务地址时应使用whisper, 实际: %s", provider.Name())
+	}
+
+	audio := buildWAV(0.5)
+	result, err := provider.Transcribe(context.Background(), audio, DetectAudio(audio))
+	if err != nil {
+		t.Fatalf("识别失败: %v", err)
+	}
+
+	if result.Text != "这是银杏树吗" || result.Language != "chinese" || result.Duration != 3 {
+		t.Errorf("识别结果错误: %+v", result)
+	}
+	if len(result.Segments) != 2 {
+		t.Fatalf("分段数量 = %d, 期望 2", len(result.Segments))
+	}
+	// 分段置信度 = exp(avg_logprob) × (1 - no_speech_prob)
+	if got := result.Segments[0].Confidence; math.Abs(got-1) > 1e-9 {
+		t.Errorf("第一段置信度 = %f, 期望 1", got)
+	}
+	if got := result.Segments[1].Confidence; math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("第二段置信度 = %f, 期望 0.25", got)
+	}
+	// 整体置信度按时长加权：(1×1 + 0.25×2) / 3
+	if got := result.Confidence; math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("整体置信度 = %f, 期望 0.5", got)
+	}
+}
+
+func TestNewASRProvider(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+
+	provider, err := NewASRProvider(config.AIConfig{UseAIModel: true}, logger)
+	if err != nil || provider != nil {
+		t.Errorf("未配置服务地址且USE_AI_MODEL=true时应返回nil, 实际: %v %v", provider, err)
+	}
+
+	provider, err = NewASRProvider(config.AIConfig{UseAIModel: false}, logger)
+	if err != nil || provider == nil || provider.Name() != ASRProviderMock {
+		t.Errorf("USE_AI_MODEL=false时应使用mock, 实际: %v %v", provider, err)
+	}
+
+	if _, err := NewASRProvider(config.AIConfig{ASRProvider: ASRProviderWhisper}, logger); err == nil {
+		t.Error("whisper未配置服务地址时应返回错误")
+	}
+	if _, err := NewASRProvider(config.AIConfig{ASRProvider: "unknown"}, logger); err == nil {
+		t.Error("未知提供方应返回错误")
+	}
+}
+
+func TestRecognizer_Recognize(t *testing.T) {
+	ctx := context.Background()
+	recognizer := NewRecognizer(config.AIConfig{}, nil)
+
+	audio := "data:audio/wav;base64," + base64.StdEncoding.EncodeToString(buildWAV(1))
+	recognition, err := recognizer.Recognize(ctx, audio)
+	if err != nil {
+		t.Fatalf("识别失败: %v", err)
+	}
+	if recognition.Provider != ASRProviderMock || recognition.Text == "" || recognition.Info.Format != AudioFormatWAV {
+		t.Errorf("识别结果错误: %+v", recognition)
+	}
+
+	tests := []struct {
+		name  string
+		audio string
+		want  error
+	}{
+		{"空音频", "  ", ErrAudioRequired},
+		{"非base64", "!!!", ErrAudioDataInvalid},
+		{"未知格式", base64.StdEncoding.EncodeToString([]byte("plain text")), ErrAudioFormatUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := recognizer.Recognize(ctx, tt.audio); !errors.Is(err, tt.want) || !IsRecognitionError(err) {
+				t.Errorf("Recognize() error = %v, 期望 %v", err, tt.want)
+			}
+		})
+	}
+
+	// USE_AI_MODEL=true 且未配置提供方时不允许Mock降级
+	unavailable := NewRecognizer(config.AIConfig{UseAIModel: true}, nil)
+	if _, err := unavailable.Recognize(ctx, audio); !errors.Is(err, ErrASRUnavailable) {
+		t.Errorf("未配置提供方应返回ErrASRUnavailable, 实际: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+package speech
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// AudioFormat 音频格式
+type AudioFormat string
+
+const (
+	AudioFormatUnknown AudioFormat = ""
+	AudioFormatWAV     AudioFormat = "wav"
+	AudioFormatWebM    AudioFormat = "webm"
+	AudioFormatMP3     AudioFormat = "mp3"
+	AudioFormatM4A     AudioFormat = "m4a"
+)
+
+// MimeType 返回音频格式对应的MIME类型
+func (f AudioFormat) MimeType() string {
+	switch f {
+	case AudioFormatWAV:
+		return "audio/wav"
+	case AudioFormatWebM:
+		return "audio/webm"
+	case AudioFormatMP3:
+		return "audio/mpeg"
+	case AudioFormatM4A:
+		return "audio/mp4"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// AudioInfo 音频基本信息
+type AudioInfo struct {
+	Format   AudioFormat
+	Size     int
+	Duration time.Duration // 时长，无法从文件头解析时为0
+}
+
+// DetectAudio 根据文件头识别音频格式并解析时长
+func DetectAudio(data []byte) AudioInfo {
+	info := AudioInfo{Format: DetectAudioFormat(data), Size: len(data)}
+	switch info.Format {
+	case AudioFormatWAV:
+		info.Duration = wavDuration(data)
+	case AudioFormatWebM:
+		info.Duration = webmDuration(data)
+	case AudioFormatMP3:
+		info.Duration = mp3Duration(data)
+	case AudioFormatM4A:
+		info.Duration = m4aDuration(data)
+	}
+	return info
+}
+
+// DetectAudioFormat 根据文件头（magic bytes）识别音频格式
+func DetectAudioFormat(data []byte) AudioFormat {
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		return AudioFormatWAV
+	case len(data) >= 4 && bytes.Equal(data[0:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return AudioFormatWebM
+	case len(data) >= 12 && bytes.Equal(data[4:8], []byte("ftyp")):
+		return AudioFormatM4A
+	case len(data) >= 3 && bytes.Equal(data[0:3], []byte("ID3")):
+		return AudioFormatMP3
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		// MPEG音频帧同步（layer位为0的是AAC ADTS，不属于MP3）
+		return AudioFormatMP3
+	default:
+		return AudioFormatUnknown
+	}
+}
+
+// wavDuration 解析WAV时长：data块大小 / fmt块中的字节率
+func wavDuration(data []byte) time.Duration {
+	var byteRate uint32
+	offset := 12
+	for offset+8 <= len(data) {
+		chunkID := string(data[offset : offset+4])
+		chunkSize := binary.LittleEndian.Uint32(data[offset+4 : offset+8])
+		body := offset + 8
+
+		switch chunkID {
+		case "fmt ":
+			if body+12 <= len(data) {
+				byteRate = binary.LittleEndian.Uint32(data[body+8 : body+12])
+			}
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			// 流式录音的data块大小可能未回填，以实际数据长度为上限
+			size := int64(chunkSize)
+			if remain := int64(len(data) - body); size > remain || size == 0 {
+				size = remain
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second))
+		}
+
+		// 块按偶数字节对齐
+		offset = body + int(chunkSize) + int(chunkSize&1)
+	}
+	return 0
+}
+
+// EBML元素ID（WebM/Matroska）
+const (
+	ebmlIDSegment       = 0x18538067
+	ebmlIDInfo          = 0x1549A966
+	ebmlIDTimecodeScale = 0x2AD7B1
+	ebmlIDDuration      = 0x4489
+)
+
+// webmDuration 解析WebM时长：Segment › Info › Duration × TimecodeScale
+// 浏览器MediaRecorder录制的WebM通常不写入Duration，此时返回0
+func webmDuration(data []byte) time.Duration {
+	// 跳过EBML头
+	_, headerSize, n := readEBMLElement(data, 0)
+	if n == 0 {
+		return 0
+	}
+	offset := n + int(headerSize)
+
+	id, size, n := readEBMLElement(data, offset)
+	if n == 0 || id != ebmlIDSegment {
+		return 0
+	}
+	offset += n
+	segmentEnd := len(data)
+	if size >= 0 && offset+int(size) < segmentEnd {
+		segmentEnd = offset + int(size)
+	}
+
+	for offset < segmentEnd {
+		id, size, n := readEBMLElement(data, offset)
+		if n == 0 || size < 0 {
+			return 0
+		}
+		offset += n
+		if id != ebmlIDInfo {
+			offset += int(size)
+			continue
+		}
+
+		infoEnd := offset + int(size)
+		if infoEnd > len(data) {
+			infoEnd = len(data)
+		}
+		timecodeScale := uint64(1000000)
+		var duration float64
+		for offset < infoEnd {
+			id, size, n := readEBMLElement(data, offset)
+			if n == 0 || size < 0 || offset+n+int(size) > infoEnd {
+				break
+			}
+			body := data[offset+n : offset+n+int(size)]
+			switch id {
+			case ebmlIDTimecodeScale:
+				var v uint64
+				for _, b := range body {
+					v = v<<8 | uint64(b)
+				}
+				if v > 0 {
+					timecodeScale = v
+				}
+			case ebmlIDDuration:
+				switch len(body) {
+				case 4:
+					duration = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+				case 8:
+					duration = math.Float64frombits(binary.BigEndian.Uint64(body))
+				}
+			}
+			offset += n + int(size)
+		}
+		if duration <= 0 || math.IsNaN(duration) || math.IsInf(duration, 0) {
+			return 0
+		}
+		return time.Duration(duration * float64(timecodeScale))
+	}
+	return 0
+}
+
+// readEBMLElement 读取EBML元素头，返回元素ID、数据长度（未知长度为-1）和头部字节数（失败为0）
+func readEBMLElement(data []byte, offset int) (id uint32, size int64, n int) {
+	idLen := ebmlVintLength(data, offset)
+	if idLen == 0 || idLen > 4 || offset+idLen > len(data) {
+		return 0, 0, 0
+	}
+	for _, b := range data[offset : offset+idLen] {
+		id = id<<8 | uint32(b)
+	}
+
+	sizeOffset := offset + idLen
+	sizeLen := ebmlVintLength(data, sizeOffset)
+	if sizeLen == 0 || sizeOffset+sizeLen > len(data) {
+		return 0, 0, 0
+	}
+	value := uint64(data[sizeOffset]) & (0xFF >> sizeLen)
+	allOnes := value == uint64(0xFF>>sizeLen)
+	for _, b := range data[sizeOffset+1 : sizeOffset+sizeLen] {
+		value = value<<8 | uint64(b)
+		allOnes = allOnes && b == 0xFF
+	}
+	if allOnes {
+		return id, -1, idLen + sizeLen
+	}
+	return id, int64(value), idLen + sizeLen
+}
+
+// ebmlVintLength 根据首字节前导零个数计算EBML变长整数的字节数
+func ebmlVintLength(data []byte, offset int) int {
+	if offset >= len(data) {
+		return 0
+	}
+	first := data[offset]
+	for i := 0; i < 8; i++ {
+		if first&(0x80>>i) != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// MPEG Layer III 帧头比特率索引对应的比特率（kbps）
+var (
+	mp3BitratesV1L3 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3BitratesV2L3 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+)
+
+// mp3Duration 按首个音频帧的比特率估算MP3时长（对CBR准确，VBR为近似值）
+func mp3Duration(data []byte) time.Duration {
+	offset := 0
+	// 跳过ID3v2标签
+	if len(data) >= 10 && bytes.Equal(data[0:3], []byte("ID3")) {
+		tagSize := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		offset = 10 + tagSize
+	}
+
+	// 查找帧同步
+	for ; offset+4 <= len(data); offset++ {
+		if data[offset] != 0xFF || data[offset+1]&0xE0 != 0xE0 {
+			continue
+		}
+		version := (data[offset+1] >> 3) & 0x03 // 3: MPEG1, 2: MPEG2, 0: MPEG2.5
+		layer := (data[offset+1] >> 1) & 0x03   // 1: Layer III
+		bitrateIndex := data[offset+2] >> 4
+		if version == 1 || layer != 1 {
+			continue
+		}
+
+		var kbps int
+		if version == 3 {
+			kbps = mp3BitratesV1L3[bitrateIndex]
+		} else {
+			kbps = mp3BitratesV2L3[bitrateIndex]
+		}
+		if kbps == 0 {
+			continue
+		}
+		audioBytes := len(data) - offset
+		return time.Duration(float64(audioBytes*8) / float64(kbps*1000) * float64(time.Second))
+	}
+	return 0
+}
+
+// m4aDuration 解析M4A时长：moov › mvhd 中的 duration / timescale
+func m4aDuration(data []byte) time.Duration {
+	moov := findMP4Box(data, "moov")
+	if moov == nil {
+		return 0
+	}
+	mvhd := findMP4Box(moov, "mvhd")
+	if len(mvhd) < 4 {
+		return 0
+	}
+
+	var timescale uint32
+	var duration uint64
+	if mvhd[0] == 1 {
+		// version 1: 8字节创建/修改时间和时长
+		if len(mvhd) < 32 {
+			return 0
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[20:24])
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	} else {
+		if len(mvhd) < 20 {
+			return 0
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[12:16])
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// findMP4Box 在同一层级中查找指定类型的box，返回box内容
+func findMP4Box(data []byte, boxType string) []byte {
+	offset := 0
+	for offset+8 <= len(data) {
+		size := uint64(binary.BigEndian.Uint32(data[offset : offset+4]))
+		typ := string(data[offset+4 : offset+8])
+		header := 8
+		switch size {
+		case 0:
+			// 延伸到文件末尾
+			size = uint64(len(data) - offset)
+		case 1:
+			// 64位扩展长度
+			if offset+16 > len(data) {
+				return nil
+			}
+			size = binary.BigEndian.Uint64(data[offset+8 : offset+16])
+			header = 16
+		}
+		if size < uint64(header) {
+			return nil
+		}
+
+		end := uint64(offset) + size
+		if end > uint64(len(data)) {
+			end = uint64(len(data))
+		}
+		if typ == boxType {
+			return data[offset+header : end]
+		}
+		offset = int(end)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package speech
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/utils"
+)
+
+var (
+	// ErrAudioRequired 音频数据为空
+	ErrAudioRequired = errors.New("音频数据不能为空")
+	// ErrAudioDataInvalid 音频数据不是合法的base64
+	ErrAudioDataInvalid = errors.New("音频数据格式无效")
+	// ErrASRUnavailable 未配置语音识别服务
+	ErrASRUnavailable = errors.New("语音识别服务未配置")
+	// ErrTranscriptionFailed 语音识别服务调用失败
+	ErrTranscriptionFailed = errors.New("语音识别失败")
+	// ErrSpeechNotRecognized 未识别到有效语音
+	ErrSpeechNotRecognized = errors.New("未识别到有效语音")
+)
+
+// Recognition 语音识别流程的结果
+type Recognition struct {
+	*Transcription
+	Info     AudioInfo // 音频格式和时长
+	Provider string    // 语音识别提供方
+}
+
+// Recognizer 语音识别流程：解码base64音频、校验格式/大小/时长、调用语音识别提供方
+// VoiceLogic 和 MultiAgentGraph 共用同一流程
+type Recognizer struct {
+	provider ASRProvider
+	limits   Limits
+}
+
+// NewRecognizer 创建语音识别流程
+// provider 为nil时：USE_AI_MODEL=false允许使用Mock识别，否则识别时返回 ErrASRUnavailable
+func NewRecognizer(cfg config.AIConfig, provider ASRProvider) *Recognizer {
+	if provider == nil && !cfg.UseAIModel {
+		provider = NewMockASRProvider()
+	}
+	return &Recognizer{
+		provider: provider,
+		limits:   LimitsFromConfig(cfg),
+	}
+}
+
+// Recognize 识别base64编码的音频（支持data URL前缀）
+// 返回的错误可用 errors.Is 判断：ErrAudioRequired、ErrAudioDataInvalid、ErrAudioFormatUnsupported、
+// ErrAudioTooLarge、ErrAudioTooLong、ErrASRUnavailable、ErrTranscriptionFailed、ErrSpeechNotRecognized
+func (r *Recognizer) Recognize(ctx context.Context, audioBase64 string) (*Recognition, error) {
+	audio, err := DecodeAudioBase64(audioBase64)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.limits.Validate(audio)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.provider == nil {
+		return nil, ErrASRUnavailable
+	}
+	transcription, err := r.provider.Transcribe(ctx, audio, info)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTranscriptionFailed, err)
+	}
+	if strings.TrimSpace(transcription.Text) == "" {
+		return nil, ErrSpeechNotRecognized
+	}
+
+	return &Recognition{
+		Transcription: transcription,
+		Info:          info,
+		Provider:      r.provider.Name(),
+	}, nil
+}
+
+// DecodeAudioBase64 解码base64音频，移除空白字符和 data URL 前缀
+func DecodeAudioBase64(data string) ([]byte, error) {
+	data = utils.CleanBase64String(data)
+	if idx := strings.Index(data, ";base64,"); strings.HasPrefix(data, "data:") && idx >= 0 {
+		data = data[idx+len(";base64,"):]
+	}
+	if data == "" {
+		return nil, ErrAudioRequired
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, ErrAudioDataInvalid
+	}
+	return audio, nil
+}
+
+// IsRecognitionError 是否为语音识别流程返回的错误（音频无效、服务不可用或识别失败）
+func IsRecognitionError(err error) bool {
+	for _, target := range []error{
+		ErrAudioRequired, ErrAudioDataInvalid, ErrAudioFormatUnsupported, ErrAudioTooLarge,
+		ErrAudioTooLong, ErrASRUnavailable, ErrTranscriptionFailed, ErrSpeechNotRecognized,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
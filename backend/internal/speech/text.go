@@ -0,0 +1,104 @@
+package speech
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	codeBlockRe  = regexp.MustCompile("(?s)```.*?```")
+	imageRe      = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	linkRe       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	urlRe        = regexp.MustCompile(`https?://\S+`)
+	headingRe    = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s*`)
+	listMarkerRe = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+\.)\s+`)
+	quoteRe      = regexp.MustCompile(`(?m)^\s*>\s?`)
+	ruleRe       = regexp.MustCompile(`(?m)^\s*(?:[-*_]\s*){3,}$`)
+	tableRuleRe  = regexp.MustCompile(`(?m)^\s*\|?(?:\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	emphasisRe   = regexp.MustCompile("[*_~`]+")
+	spacesRe     = regexp.MustCompile(`[ \t]+`)
+	newlinesRe   = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// SpeechText 将助手回复（可能包含Markdown和emoji）转换为适合朗读的纯文本
+func SpeechText(text string) string {
+	text = codeBlockRe.ReplaceAllString(text, "")
+	text = imageRe.ReplaceAllString(text, "")
+	text = linkRe.ReplaceAllString(text, "$1")
+	text = urlRe.ReplaceAllString(text, "")
+	text = tableRuleRe.ReplaceAllString(text, "")
+	text = ruleRe.ReplaceAllString(text, "")
+	text = headingRe.ReplaceAllString(text, "")
+	text = listMarkerRe.ReplaceAllString(text, "")
+	text = quoteRe.ReplaceAllString(text, "")
+	text = emphasisRe.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "|", " ")
+
+	// 去掉emoji等符号，避免被逐字念出
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200d' || r == '\ufe0f':
+			// 零宽连接符、变体选择符
+			return -1
+		case (r >= 0x1F000 && r <= 0x1FAFF) || unicode.Is(unicode.So, r):
+			// emoji区块及其他符号（含肤色修饰符）
+			return -1
+		}
+		return r
+	}, text)
+
+	text = spacesRe.ReplaceAllString(text, " ")
+	text = newlinesRe.ReplaceAllString(text, "\n")
+	return strings.TrimSpace(text)
+}
+
+// isSentenceEnd 是否为句末标点
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '！', '？', '；', '!', '?', ';', '\n':
+		return true
+	}
+	return false
+}
+
+// SplitSpeechText 按句子切分文本，并将相邻句子合并为不超过 maxChars 字的片段
+// 逐段合成可以让客户端更早开始播放；超长句子按 maxChars 硬切分
+func SplitSpeechText(text string, maxChars int) []string {
+	var sentences []string
+	var current []rune
+	for _, r := range text {
+		current = append(current, r)
+		if isSentenceEnd(r) {
+			sentences = append(sentences, string(current))
+			current = current[:0]
+		}
+	}
+	if len(current) > 0 {
+		sentences = append(sentences, string(current))
+	}
+
+	var chunks []string
+	var chunk strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(chunk.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		chunk.Reset()
+	}
+	for _, sentence := range sentences {
+		if maxChars > 0 && utf8.RuneCountInString(chunk.String())+utf8.RuneCountInString(sentence) > maxChars {
+			flush()
+		}
+		runes := []rune(sentence)
+		for maxChars > 0 && len(runes) > maxChars {
+			chunk.WriteString(string(runes[:maxChars]))
+			flush()
+			runes = runes[maxChars:]
+		}
+		chunk.WriteString(string(runes))
+	}
+	flush()
+	return chunks
+}
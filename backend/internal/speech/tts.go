@@ -0,0 +1,161 @@
+package speech
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 语音合成提供方类型
+const (
+	TTSProviderOpenAI = "openai" // OpenAI兼容的 /audio/speech 接口
+	TTSProviderEspeak = "espeak" // 本地espeak-ng命令（离线可用，适合测试和开发）
+	TTSProviderMock   = "mock"   // 静音音频（仅用于开发测试）
+)
+
+// 语速倍率的允许范围
+const (
+	MinSpeed = 0.25
+	MaxSpeed = 4.0
+)
+
+// voicePattern 发音人名称允许的字符（如 cmn、en-us、en+f3、alloy），
+// 发音人会作为命令行参数或请求参数传给提供方，不允许路径分隔符等字符
+var voicePattern = regexp.MustCompile(`^[A-Za-z0-9+_-]{1,64}$`)
+
+// ValidVoice 发音人名称是否合法，空字符串表示使用默认发音人，视为合法
+func ValidVoice(voice string) bool {
+	return voice == "" || voicePattern.MatchString(voice)
+}
+
+// ClampSpeed 将语速倍率限制在 MinSpeed-MaxSpeed 之间，未设置（小于等于0）时返回0表示使用默认语速
+func ClampSpeed(speed float64) float64 {
+	switch {
+	case speed <= 0:
+		return 0
+	case speed < MinSpeed:
+		return MinSpeed
+	case speed > MaxSpeed:
+		return MaxSpeed
+	}
+	return speed
+}
+
+// SynthesisOptions 语音合成选项，零值表示使用提供方默认值
+type SynthesisOptions struct {
+	Voice string  // 发音人
+	Speed float64 // 语速倍率（1.0为正常语速）
+}
+
+// Synthesis 语音合成结果
+type Synthesis struct {
+	Audio    []byte        // 音频数据
+	Format   AudioFormat   // 音频格式
+	Duration time.Duration // 音频时长，无法解析时为0
+}
+
+// TTSProvider 语音合成（文本转语音）提供方
+type TTSProvider interface {
+	// Name 提供方名称
+	Name() string
+	// Synthesize 将文本合成为音频
+	Synthesize(ctx context.Context, text string, opts SynthesisOptions) (*Synthesis, error)
+}
+
+// NewTTSProvider 根据AI配置创建语音合成提供方
+// 未指定提供方时：配置了服务地址则使用OpenAI兼容接口，USE_AI_MODEL=false时使用Mock，否则返回nil
+func NewTTSProvider(cfg config.AIConfig, logger logx.Logger) (TTSProvider, error) {
+	provider := cfg.TTSProvider
+	if provider == "" {
+		switch {
+		case cfg.TTSBaseURL != "" || cfg.EinoBaseURL != "":
+			provider = TTSProviderOpenAI
+		case !cfg.UseAIModel:
+			provider = TTSProviderMock
+		default:
+			return nil, nil
+		}
+	}
+
+	switch provider {
+	case TTSProviderOpenAI:
+		openai, err := NewOpenAITTSProvider(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return openai, nil
+	case TTSProviderEspeak:
+		espeak, err := NewEspeakProvider(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return espeak, nil
+	case TTSProviderMock:
+		return NewMockTTSProvider(), nil
+	default:
+		return nil, fmt.Errorf("不支持的语音合成提供方: %s", provider)
+	}
+}
+
+// newSynthesis 根据音频数据构造合成结果，无法识别格式时使用 fallback
+func newSynthesis(audio []byte, fallback AudioFormat) *Synthesis {
+	info := DetectAudio(audio)
+	if info.Format == AudioFormatUnknown {
+		info.Format = fallback
+	}
+	return &Synthesis{Audio: audio, Format: info.Format, Duration: info.Duration}
+}
+
+// MockTTSProvider Mock语音合成，按文本长度生成静音WAV（仅用于开发测试）
+type MockTTSProvider struct{}
+
+// NewMockTTSProvider 创建Mock语音合成
+func NewMockTTSProvider() *MockTTSProvider {
+	return &MockTTSProvider{}
+}
+
+// Name 提供方名称
+func (p *MockTTSProvider) Name() string {
+	return TTSProviderMock
+}
+
+// Synthesize 生成与文本长度相当的静音音频（每字200毫秒）
+func (p *MockTTSProvider) Synthesize(ctx context.Context, text string, opts SynthesisOptions) (*Synthesis, error) {
+	duration := time.Duration(utf8.RuneCountInString(text)) * 200 * time.Millisecond
+	if opts.Speed > 0 {
+		duration = time.Duration(float64(duration) / opts.Speed)
+	}
+	return newSynthesis(silentWAV(duration), AudioFormatWAV), nil
+}
+
+// silentWAV 生成8kHz单声道8位PCM的静音WAV
+func silentWAV(duration time.Duration) []byte {
+	const sampleRate = 8000
+	dataSize := int(duration.Seconds() * sampleRate)
+
+	buf := make([]byte, 44+dataSize)
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(36+dataSize))
+	copy(buf[8:12], "WAVE")
+	copy(buf[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(buf[22:24], 1) // 单声道
+	binary.LittleEndian.PutUint32(buf[24:28], sampleRate)
+	binary.LittleEndian.PutUint32(buf[28:32], sampleRate) // 字节率
+	binary.LittleEndian.PutUint16(buf[32:34], 1)
+	binary.LittleEndian.PutUint16(buf[34:36], 8)
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], uint32(dataSize))
+	// 8位PCM以128为静音
+	for i := 44; i < len(buf); i++ {
+		buf[i] = 0x80
+	}
+	return buf
+}
@@ -0,0 +1,111 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// espeakDefaultCommand 默认espeak命令
+	espeakDefaultCommand = "espeak-ng"
+	// espeakDefaultVoice 默认发音人（普通话）
+	espeakDefaultVoice = "cmn"
+	// espeakBaseWPM espeak正常语速（每分钟词数）
+	espeakBaseWPM = 175
+)
+
+// EspeakProvider 基于本地espeak-ng命令的语音合成
+// 离线可用、无需网络，适合开发和测试环境；可通过 TTS_COMMAND 替换为兼容的命令
+type EspeakProvider struct {
+	command string
+	voice   string
+	speed   float64
+	logger  logx.Logger
+}
+
+// NewEspeakProvider 创建espeak语音合成，命令不存在时返回错误
+func NewEspeakProvider(cfg config.AIConfig, logger logx.Logger) (*EspeakProvider, error) {
+	command := cfg.TTSCommand
+	if command == "" {
+		command = espeakDefaultCommand
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("未找到语音合成命令 %s: %w", command, err)
+	}
+
+	voice := cfg.TTSVoice
+	if voice == "" {
+		voice = espeakDefaultVoice
+	}
+
+	return &EspeakProvider{
+		command: path,
+		voice:   voice,
+		speed:   cfg.TTSSpeed,
+		logger:  logger,
+	}, nil
+}
+
+// Name 提供方名称
+func (p *EspeakProvider) Name() string {
+	return TTSProviderEspeak
+}
+
+// Synthesize 调用espeak将文本合成为WAV音频，文本通过标准输入传递
+func (p *EspeakProvider) Synthesize(ctx context.Context, text string, opts SynthesisOptions) (*Synthesis, error) {
+	voice := opts.Voice
+	if voice == "" {
+		voice = p.voice
+	}
+	if !ValidVoice(voice) {
+		return nil, fmt.Errorf("发音人名称无效: %q", voice)
+	}
+	speed := ClampSpeed(opts.Speed)
+	if speed == 0 {
+		speed = ClampSpeed(p.speed)
+	}
+	if speed == 0 {
+		speed = 1
+	}
+
+	args := []string{
+		"--stdout",
+		"-b", "1", // 输入为UTF-8
+		"-v", voice,
+		"-s", strconv.Itoa(int(espeakBaseWPM * speed)),
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.command, args...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("执行语音合成命令失败: %w: %s", err, truncate(strings.TrimSpace(stderr.String()), 200))
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("语音合成命令未输出音频")
+	}
+
+	synthesis := newSynthesis(stdout.Bytes(), AudioFormatWAV)
+	p.logger.Infow("语音合成完成",
+		logx.Field("command", p.command),
+		logx.Field("voice", voice),
+		logx.Field("chars", len([]rune(text))),
+		logx.Field("size", stdout.Len()),
+		logx.Field("duration", synthesis.Duration.String()),
+		logx.Field("elapsed", time.Since(start).String()),
+	)
+	return synthesis, nil
+}
@@ -0,0 +1,145 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// openAITTSTimeout 单次合成请求超时
+	openAITTSTimeout = 60 * time.Second
+	// openAITTSDefaultVoice 默认发音人
+	openAITTSDefaultVoice = "alloy"
+	// openAITTSMaxAudioBytes 合成音频最大字节数
+	openAITTSMaxAudioBytes = 20 * 1024 * 1024
+)
+
+// OpenAITTSProvider OpenAI兼容的语音合成
+// 调用 {baseURL}/audio/speech，返回mp3音频
+type OpenAITTSProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	voice    string
+	speed    float64
+	client   *http.Client
+	logger   logx.Logger
+}
+
+// NewOpenAITTSProvider 创建OpenAI兼容的语音合成
+// 服务地址和认证未单独配置时，沿用eino的服务地址和 AppID:AppKey 认证
+func NewOpenAITTSProvider(cfg config.AIConfig, logger logx.Logger) (*OpenAITTSProvider, error) {
+	baseURL := cfg.TTSBaseURL
+	if baseURL == "" {
+		baseURL = cfg.EinoBaseURL
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("未配置语音合成服务地址（TTS_BASE_URL或EINO_BASE_URL）")
+	}
+
+	model := cfg.TTSModel
+	if model == "" {
+		model = config.DefaultTTSModel
+	}
+	voice := cfg.TTSVoice
+	if voice == "" {
+		voice = openAITTSDefaultVoice
+	}
+
+	return &OpenAITTSProvider{
+		endpoint: strings.TrimSuffix(baseURL, "/") + "/audio/speech",
+		apiKey:   serviceAPIKey(cfg.TTSAPIKey, cfg),
+		model:    model,
+		voice:    voice,
+		speed:    cfg.TTSSpeed,
+		client:   &http.Client{Timeout: openAITTSTimeout},
+		logger:   logger,
+	}, nil
+}
+
+// Name 提供方名称
+func (p *OpenAITTSProvider) Name() string {
+	return TTSProviderOpenAI
+}
+
+// openAISpeechRequest /audio/speech 请求体
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// Synthesize 合成语音
+func (p *OpenAITTSProvider) Synthesize(ctx context.Context, text string, opts SynthesisOptions) (*Synthesis, error) {
+	voice := opts.Voice
+	if voice == "" {
+		voice = p.voice
+	}
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = p.speed
+	}
+
+	body, err := json.Marshal(openAISpeechRequest{
+		Model:          p.model,
+		Input:          text,
+		Voice:          voice,
+		ResponseFormat: string(AudioFormatMP3),
+		Speed:          speed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("构造语音合成请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建语音合成请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("调用语音合成服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, openAITTSMaxAudioBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取语音合成响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("语音合成服务返回HTTP %d: %s", resp.StatusCode, truncate(string(audio), 200))
+	}
+	if len(audio) > openAITTSMaxAudioBytes {
+		return nil, fmt.Errorf("语音合成音频超过%d字节", openAITTSMaxAudioBytes)
+	}
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("语音合成服务返回空音频")
+	}
+
+	synthesis := newSynthesis(audio, AudioFormatMP3)
+	p.logger.Infow("语音合成完成",
+		logx.Field("model", p.model),
+		logx.Field("voice", voice),
+		logx.Field("chars", len([]rune(text))),
+		logx.Field("size", len(audio)),
+		logx.Field("format", string(synthesis.Format)),
+		logx.Field("elapsed", time.Since(start).String()),
+	)
+	return synthesis, nil
+}
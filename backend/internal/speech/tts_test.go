@@ -0,0 +1,167 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// writeEspeakStub 写入模拟espeak的脚本：记录参数和标准输入，输出固定的WAV
+func writeEspeakStub(t *testing.T) (command string, dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "out.wav"), buildWAV(1.5), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" > \"" + dir + "/args.txt\"\n" +
+		"cat > \"" + dir + "/input.txt\"\n" +
+		"cat \"" + dir + "/out.wav\"\n"
+	command = filepath.Join(dir, "espeak-stub")
+	if err := os.WriteFile(command, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return command, dir
+}
+
+func TestEspeakProvider_Synthesize(t *testing.T) {
+	command, dir := writeEspeakStub(t)
+
+	provider, err := NewTTSProvider(config.AIConfig{
+		TTSProvider: TTSProviderEspeak,
+		TTSCommand:  command,
+		TTSSpeed:    0.8,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("创建语音合成失败: %v", err)
+	}
+
+	synthesis, err := provider.Synthesize(context.Background(), "银杏是活化石", SynthesisOptions{})
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	if synthesis.Format != AudioFormatWAV || synthesis.Duration != 1500*time.Millisecond {
+		t.Errorf("合成结果 = %s %v, 期望 wav 1.5s", synthesis.Format, synthesis.Duration)
+	}
+
+	input, _ := os.ReadFile(filepath.Join(dir, "input.txt"))
+	if string(input) != "银杏是活化石" {
+		t.Errorf("文本应通过标准输入传递, 实际: %q", input)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if got := strings.TrimSpace(string(args)); got != "--stdout -b 1 -v cmn -s 140" {
+		t.Errorf("命令参数 = %q", got)
+	}
+
+	if _, err := provider.Synthesize(context.Background(), "你好", SynthesisOptions{Voice: "-w/tmp/x"}); err == nil {
+		t.Error("非法发音人应返回错误")
+	}
+	if _, err := provider.Synthesize(context.Background(), "你好", SynthesisOptions{Voice: "en-us", Speed: 1e300}); err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	args, _ = os.ReadFile(filepath.Join(dir, "args.txt"))
+	if got := strings.TrimSpace(string(args)); got != "--stdout -b 1 -v en-us -s 700" {
+		t.Errorf("语速应限制在最大倍率内, 命令参数 = %q", got)
+	}
+
+	if _, err := NewTTSProvider(config.AIConfig{
+		TTSProvider: TTSProviderEspeak,
+		TTSCommand:  filepath.Join(dir, "not-exist"),
+	}, logx.WithContext(context.Background())); err == nil {
+		t.Error("命令不存在时应返回错误")
+	}
+}
+
+func TestOpenAITTSProvider_Synthesize(t *testing.T) {
+	audio := buildMP3(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" {
+			t.Errorf("请求路径 = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tts-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req openAISpeechRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if req.Model != config.DefaultTTSModel || req.Input != "你好" || req.Voice != "nova" || req.ResponseFormat != "mp3" {
+			t.Errorf("请求体错误: %+v", req)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(audio)
+	}))
+	defer server.Close()
+
+	provider, err := NewTTSProvider(config.AIConfig{
+		TTSBaseURL: server.URL + "/v1",
+		TTSAPIKey:  "tts-key",
+		UseAIModel: true,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("创建语音合成失败: %v", err)
+	}
+	if provider.Name() != TTSProviderOpenAI {
+		t.Fatalf("配置了服务地址时应使用openai, 实际: %s", provider.Name())
+	}
+
+	synthesis, err := provider.Synthesize(context.Background(), "你好", SynthesisOptions{Voice: "nova"})
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	if synthesis.Format != AudioFormatMP3 || len(synthesis.Audio) != len(audio) || synthesis.Duration != 2*time.Second {
+		t.Errorf("合成结果错误: %s %d %v", synthesis.Format, len(synthesis.Audio), synthesis.Duration)
+	}
+}
+
+func TestMockTTSProvider_Synthesize(t *testing.T) {
+	synthesis, err := NewMockTTSProvider().Synthesize(context.Background(), "你好呀", SynthesisOptions{})
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	if info := DetectAudio(synthesis.Audio); info.Format != AudioFormatWAV || info.Duration != 600*time.Millisecond {
+		t.Errorf("Mock音频 = %s %v, 期望 wav 600ms", info.Format, info.Duration)
+	}
+}
+
+func TestSpeechText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"纯文本", "银杏是一种古老的树。", "银杏是一种古老的树。"},
+		{"标题和强调", "## 🌳 银杏\n**银杏**是*活化石* ✨", "银杏\n银杏是活化石"},
+		{"列表和链接", "- 叶子像[扇子](https://example.com)\n1. 秋天变黄🍂", "叶子像扇子\n秋天变黄"},
+		{"图片和代码", "看这里![图](http://a.png)\n```go\nfmt.Println()\n```\n好玩吗？", "看这里\n好玩吗？"},
+		{"生僻字保留", "𠀋字不应被当成emoji👍", "𠀋字不应被当成emoji"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpeechText(tt.in); got != tt.want {
+				t.Errorf("SpeechText() = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSpeechText(t *testing.T) {
+	chunks := SplitSpeechText("第一句。第二句！第三句比较长一些？", 8)
+	want := []string{"第一句。第二句！", "第三句比较长一些", "？"}
+	if strings.Join(chunks, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitSpeechText() = %q, 期望 %q", chunks, want)
+	}
+
+	if chunks := SplitSpeechText("  ", 10); len(chunks) != 0 {
+		t.Errorf("空白文本不应产生分段, 实际: %q", chunks)
+	}
+}
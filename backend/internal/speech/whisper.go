@@ -0,0 +1,228 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// whisperTimeout 单次识别请求超时
+const whisperTimeout = 60 * time.Second
+
+// WhisperProvider OpenAI兼容的Whisper风格语音识别
+// 调用 {baseURL}/audio/transcriptions，使用 verbose_json 获取分段结果
+type WhisperProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	language string
+	client   *http.Client
+	logger   logx.Logger
+}
+
+// NewWhisperProvider 创建Whisper风格语音识别
+// 服务地址和认证未单独配置时，沿用eino的服务地址和 AppID:AppKey 认证
+func NewWhisperProvider(cfg config.AIConfig, logger logx.Logger) (*WhisperProvider, error) {
+	baseURL := cfg.ASRBaseURL
+	if baseURL == "" {
+		baseURL = cfg.EinoBaseURL
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("未配置语音识别服务地址（ASR_BASE_URL或EINO_BASE_URL）")
+	}
+
+	model := cfg.ASRModel
+	if model == "" {
+		model = config.DefaultASRModel
+	}
+
+	return &WhisperProvider{
+		endpoint: strings.TrimSuffix(baseURL, "/") + "/audio/transcriptions",
+		apiKey:   serviceAPIKey(cfg.ASRAPIKey, cfg),
+		model:    model,
+		language: cfg.ASRLanguage,
+		client:   &http.Client{Timeout: whisperTimeout},
+		logger:   logger,
+	}, nil
+}
+
+// Name 提供方名称
+func (p *WhisperProvider) Name() string {
+	return ASRProviderWhisper
+}
+
+// whisperSegment verbose_json 中的分段
+type whisperSegment struct {
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Text         string  `json:"text"`
+	AvgLogprob   float64 `json:"avg_logprob"`
+	NoSpeechProb float64 `json:"no_speech_prob"`
+}
+
+// whisperResponse verbose_json 响应
+type whisperResponse struct {
+	Text     string           `json:"text"`
+	Language string           `json:"language"`
+	Duration float64          `json:"duration"`
+	Segments []whisperSegment `json:"segments"`
+}
+
+// Transcribe 识别音频
+func (p *WhisperProvider) Transcribe(ctx context.Context, audio []byte, info AudioInfo) (*Transcription, error) {
+	body, contentType, err := p.buildRequestBody(audio, info)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("创建语音识别请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("调用语音识别服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取语音识别响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("语音识别服务返回HTTP %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+	}
+
+	var result whisperResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("解析语音识别响应失败: %w", err)
+	}
+
+	transcription := toTranscription(&result)
+	if transcription.Duration == 0 {
+		transcription.Duration = info.Duration.Seconds()
+	}
+
+	p.logger.Infow("语音识别完成",
+		logx.Field("model", p.model),
+		logx.Field("format", string(info.Format)),
+		logx.Field("size", info.Size),
+		logx.Field("duration", transcription.Duration),
+		logx.Field("segments", len(transcription.Segments)),
+		logx.Field("confidence", transcription.Confidence),
+		logx.Field("elapsed", time.Since(start).String()),
+	)
+	return transcription, nil
+}
+
+// buildRequestBody 构造multipart请求体
+func (p *WhisperProvider) buildRequestBody(audio []byte, info AudioInfo) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="audio.%s"`, info.Format))
+	header.Set("Content-Type", info.Format.MimeType())
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", fmt.Errorf("构造语音识别请求失败: %w", err)
+	}
+	if _, err := part.Write(audio); err != nil {
+		return nil, "", fmt.Errorf("构造语音识别请求失败: %w", err)
+	}
+
+	fields := map[string]string{
+		"model":                     p.model,
+		"response_format":           "verbose_json",
+		"timestamp_granularities[]": "segment",
+	}
+	if p.language != "" {
+		fields["language"] = p.language
+	}
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			return nil, "", fmt.Errorf("构造语音识别请求失败: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("构造语音识别请求失败: %w", err)
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// toTranscription 转换识别结果
+// 分段置信度 = exp(avg_logprob) × (1 - no_speech_prob)，整体置信度按分段时长加权
+func toTranscription(result *whisperResponse) *Transcription {
+	transcription := &Transcription{
+		Text:     strings.TrimSpace(result.Text),
+		Language: result.Language,
+		Duration: result.Duration,
+		Segments: make([]Segment, 0, len(result.Segments)),
+	}
+
+	var weighted, totalDuration float64
+	for _, seg := range result.Segments {
+		confidence := math.Exp(seg.AvgLogprob) * (1 - seg.NoSpeechProb)
+		confidence = math.Max(0, math.Min(1, confidence))
+
+		transcription.Segments = append(transcription.Segments, Segment{
+			Text:       strings.TrimSpace(seg.Text),
+			Start:      seg.Start,
+			End:        seg.End,
+			Confidence: confidence,
+		})
+
+		// 时长异常的分段按1秒计权，避免被忽略
+		d := seg.End - seg.Start
+		if d <= 0 {
+			d = 1
+		}
+		weighted += confidence * d
+		totalDuration += d
+	}
+	if totalDuration > 0 {
+		transcription.Confidence = weighted / totalDuration
+	}
+	return transcription
+}
+
+// serviceAPIKey 返回语音服务的API Key，未单独配置时沿用eino的认证
+func serviceAPIKey(apiKey string, cfg config.AIConfig) string {
+	if apiKey != "" {
+		return apiKey
+	}
+	// 认证：使用 Bearer Token 格式 ${TAL_MLOPS_APP_ID}:${TAL_MLOPS_APP_KEY}
+	if cfg.AppID != "" && cfg.AppKey != "" {
+		return cfg.AppID + ":" + cfg.AppKey
+	} else if cfg.AppKey != "" {
+		return cfg.AppKey
+	}
+	return cfg.AppID
+}
+
+// truncate 截断过长的字符串（用于错误信息）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	bolt "go.etcd.io/bbolt"
+)
+
+// learnersBucket bbolt中存放学习档案的bucket名
+var learnersBucket = []byte("learners")
+
+// FileLearnerStore 基于bbolt的本地文件学习档案存储
+type FileLearnerStore struct {
+	db     *bolt.DB
+	logger logx.Logger
+}
+
+// NewFileLearnerStore 创建文件学习档案存储实例
+func NewFileLearnerStore(path string, logger logx.Logger) (*FileLearnerStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("创建学习档案存储目录失败: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("打开学习档案存储文件失败: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(learnersBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化学习档案存储失败: %w", err)
+	}
+
+	return &FileLearnerStore{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// Get 获取学习档案
+func (f *FileLearnerStore) Get(learnerId string) (*types.LearnerProfile, bool) {
+	var profile *types.LearnerProfile
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(learnersBucket).Get([]byte(learnerId))
+		if raw == nil {
+			return nil
+		}
+		var p types.LearnerProfile
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return fmt.Errorf("解析学习档案失败: %w", err)
+		}
+		profile = &p
+		return nil
+	}); err != nil {
+		f.logger.Errorw("读取学习档案失败", logx.Field("learnerId", learnerId), logx.Field("error", err))
+		return nil, false
+	}
+	return profile, profile != nil
+}
+
+// Save 保存学习档案
+func (f *FileLearnerStore) Save(profile *types.LearnerProfile) error {
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("序列化学习档案失败: %w", err)
+	}
+	return f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(learnersBucket).Put([]byte(profile.LearnerId), raw)
+	})
+}
+
+// Update 在同一事务中读取、修改并保存学习档案
+func (f *FileLearnerStore) Update(learnerId string, update func(profile *types.LearnerProfile)) (*types.LearnerProfile, error) {
+	var profile *types.LearnerProfile
+	err := f.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(learnersBucket)
+		profile = NewLearnerProfile(learnerId)
+		if raw := bucket.Get([]byte(learnerId)); raw != nil {
+			if err := json.Unmarshal(raw, profile); err != nil {
+				return fmt.Errorf("解析学习档案失败: %w", err)
+			}
+		}
+		update(profile)
+		raw, err := json.Marshal(profile)
+		if err != nil {
+			return fmt.Errorf("序列化学习档案失败: %w", err)
+		}
+		return bucket.Put([]byte(learnerId), raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Delete 删除学习档案
+func (f *FileLearnerStore) Delete(learnerId string) error {
+	return f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(learnersBucket).Delete([]byte(learnerId))
+	})
+}
+
+// Close 关闭数据库文件
+func (f *FileLearnerStore) Close() error {
+	return f.db.Close()
+}
@@ -0,0 +1,438 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// sessionsBucket bbolt中存放会话的bucket名
+	sessionsBucket = []byte("sessions")
+	// sessionOwnersBucket 所有者索引bucket，每个所有者令牌摘要一个子bucket，键为会话ID
+	sessionOwnersBucket = []byte("session_owners")
+)
+
+// FileSessionStore 基于bbolt的本地文件会话存储
+// 单机部署时可在重启后恢复会话；每个会话整体序列化为一条记录，读改写在同一事务中完成
+type FileSessionStore struct {
+	db     *bolt.DB
+	ttl    time.Duration
+	logger logx.Logger
+	done   chan struct{}
+}
+
+// NewFileSessionStore 创建文件会话存储实例
+func NewFileSessionStore(path string, ttl time.Duration, logger logx.Logger) (*FileSessionStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("创建会话存储目录失败: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("打开会话存储文件失败: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(sessionsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(sessionOwnersBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化会话存储失败: %w", err)
+	}
+
+	store := &FileSessionStore{
+		db:     db,
+		ttl:    ttl,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	// 启动清理协程
+	go store.startCleanup()
+	return store, nil
+}
+
+// SetSession 设置会话数据
+func (f *FileSessionStore) SetSession(sessionId string, data *SessionData) {
+	data.LastActive = time.Now()
+	ps, err := encodeSession(data)
+	if err != nil {
+		f.logError("SetSession", sessionId, err)
+		return
+	}
+	if err := f.db.Update(func(tx *bolt.Tx) error {
+		return putSession(tx, sessionId, ps)
+	}); err != nil {
+		f.logError("SetSession", sessionId, err)
+	}
+}
+
+// GetSession 获取会话数据
+func (f *FileSessionStore) GetSession(sessionId string) (*SessionData, bool) {
+	var data *SessionData
+	err := f.db.Update(func(tx *bolt.Tx) error {
+		ps, err := getSession(tx, sessionId)
+		if err != nil || ps == nil || f.expired(ps) {
+			return err
+		}
+		// 更新最后活动时间
+		ps.LastActive = time.Now()
+		if err := putSession(tx, sessionId, ps); err != nil {
+			return err
+		}
+		data, err = decodeSession(ps)
+		return err
+	})
+	if err != nil {
+		f.logError("GetSession", sessionId, err)
+		return nil, false
+	}
+	return data, data != nil
+}
+
+// DeleteSession 删除会话
+func (f *FileSessionStore) DeleteSession(sessionId string) {
+	if err := f.db.Update(func(tx *bolt.Tx) error {
+		ps, err := getSession(tx, sessionId)
+		if err != nil {
+			return err
+		}
+		if err := unindexOwner(tx, sessionId, ps); err != nil {
+			return err
+		}
+		return tx.Bucket(sessionsBucket).Delete([]byte(sessionId))
+	}); err != nil {
+		f.logError("DeleteSession", sessionId, err)
+	}
+}
+
+// AddMessage 添加消息到会话
+func (f *FileSessionStore) AddMessage(sessionId string, message interface{}) {
+	sv, err := encodeValue(message)
+	if err != nil {
+		f.logError("AddMessage", sessionId, err)
+		return
+	}
+	if err := f.db.Update(func(tx *bolt.Tx) error {
+		ps, err := f.loadOrCreate(tx, sessionId)
+		if err != nil {
+			return err
+		}
+		ps.Messages = append(ps.Messages, sv)
+		// 限制上下文长度，与内存存储保持一致
+		if len(ps.Messages) > maxSessionMessages {
+			ps.Messages = ps.Messages[len(ps.Messages)-maxSessionMessages:]
+		}
+		ps.LastActive = time.Now()
+		return putSession(tx, sessionId, ps)
+	}); err != nil {
+		f.logError("AddMessage", sessionId, err)
+	}
+}
+
+// GetMessages 获取会话消息列表
+func (f *FileSessionStore) GetMessages(sessionId string) []interface{} {
+	messages := []interface{}{}
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		ps, err := getSession(tx, sessionId)
+		if err != nil || ps == nil || f.expired(ps) {
+			return err
+		}
+		for _, sv := range ps.Messages {
+			msg, err := decodeValue(sv)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, msg)
+		}
+		return nil
+	}); err != nil {
+		f.logError("GetMessages", sessionId, err)
+		return []interface{}{}
+	}
+	return messages
+}
+
+// SetData 设置会话的额外数据
+func (f *FileSessionStore) SetData(sessionId string, key string, value interface{}) {
+	sv, err := encodeValue(value)
+	if err != nil {
+		f.logError("SetData", sessionId, err)
+		return
+	}
+	if err := f.db.Update(func(tx *bolt.Tx) error {
+		ps, err := f.loadOrCreate(tx, sessionId)
+		if err != nil {
+			return err
+		}
+		ps.Data[key] = sv
+		ps.LastActive = time.Now()
+		return putSession(tx, sessionId, ps)
+	}); err != nil {
+		f.logError("SetData", sessionId, err)
+	}
+}
+
+// GetData 获取会话的额外数据
+func (f *FileSessionStore) GetData(sessionId string, key string) (interface{}, bool) {
+	var value interface{}
+	var found bool
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		ps, err := getSession(tx, sessionId)
+		if err != nil || ps == nil || f.expired(ps) {
+			return err
+		}
+		sv, ok := ps.Data[key]
+		if !ok {
+			return nil
+		}
+		value, err = decodeValue(sv)
+		found = err == nil
+		return err
+	}); err != nil {
+		f.logError("GetData", sessionId, err)
+		return nil, false
+	}
+	return value, found
+}
+
+// ListSessions 列出未过期的会话
+func (f *FileSessionStore) ListSessions() []SessionInfo {
+	sessions := []SessionInfo{}
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(k, v []byte) error {
+			var ps persistedSession
+			if err := json.Unmarshal(v, &ps); err != nil || f.expired(&ps) {
+				return nil
+			}
+			sessions = append(sessions, SessionInfo{
+				SessionId:  string(k),
+				CreatedAt:  ps.CreatedAt,
+				LastActive: ps.LastActive,
+			})
+			return nil
+		})
+	}); err != nil {
+		f.logError("ListSessions", "", err)
+		return []SessionInfo{}
+	}
+	return sessions
+}
+
+// ClaimOwner 会话还没有所有者时记录所有者令牌摘要并加入所有者索引（检查和写入在同一事务中完成）
+func (f *FileSessionStore) ClaimOwner(sessionId string, ownerHash string) bool {
+	sv, err := encodeValue(ownerHash)
+	if err != nil {
+		f.logError("ClaimOwner", sessionId, err)
+		return false
+	}
+	var claimed bool
+	if err := f.db.Update(func(tx *bolt.Tx) error {
+		ps, err := f.loadOrCreate(tx, sessionId)
+		if err != nil {
+			return err
+		}
+		if _, owned := ps.Data[SessionOwnerDataKey]; owned {
+			return nil
+		}
+		ps.Data[SessionOwnerDataKey] = sv
+		ps.LastActive = time.Now()
+		if err := putSession(tx, sessionId, ps); err != nil {
+			return err
+		}
+		owners, err := tx.Bucket(sessionOwnersBucket).CreateBucketIfNotExists([]byte(ownerHash))
+		if err != nil {
+			return err
+		}
+		if err := owners.Put([]byte(sessionId), []byte{}); err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	}); err != nil {
+		f.logError("ClaimOwner", sessionId, err)
+		return false
+	}
+	return claimed
+}
+
+// ListOwnerSessions 通过所有者索引列出属于该所有者的未过期会话
+func (f *FileSessionStore) ListOwnerSessions(ownerHash string) []SessionInfo {
+	sessions := []SessionInfo{}
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		owners := tx.Bucket(sessionOwnersBucket).Bucket([]byte(ownerHash))
+		if owners == nil {
+			return nil
+		}
+		return owners.ForEach(func(k, _ []byte) error {
+			ps, err := getSession(tx, string(k))
+			if err != nil || ps == nil || f.expired(ps) || sessionOwnerHash(ps) != ownerHash {
+				return nil
+			}
+			sessions = append(sessions, SessionInfo{
+				SessionId:  string(k),
+				CreatedAt:  ps.CreatedAt,
+				LastActive: ps.LastActive,
+			})
+			return nil
+		})
+	}); err != nil {
+		f.logError("ListOwnerSessions", "", err)
+		return []SessionInfo{}
+	}
+	return sessions
+}
+
+// Close 停止清理协程并关闭数据库文件
+func (f *FileSessionStore) Close() error {
+	close(f.done)
+	return f.db.Close()
+}
+
+// loadOrCreate 读取会话，不存在或已过期时创建新会话
+func (f *FileSessionStore) loadOrCreate(tx *bolt.Tx, sessionId string) (*persistedSession, error) {
+	ps, err := getSession(tx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if ps == nil || f.expired(ps) {
+		// 过期会话被新会话替换，旧的所有者索引随之失效
+		if err := unindexOwner(tx, sessionId, ps); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		ps = &persistedSession{
+			SessionId:  sessionId,
+			Messages:   []storedValue{},
+			CreatedAt:  now,
+			LastActive: now,
+		}
+	}
+	if ps.Data == nil {
+		ps.Data = make(map[string]storedValue)
+	}
+	return ps, nil
+}
+
+// expired 判断会话是否已过期
+func (f *FileSessionStore) expired(ps *persistedSession) bool {
+	return time.Since(ps.LastActive) > f.ttl
+}
+
+// startCleanup 启动清理协程，定期删除过期会话
+func (f *FileSessionStore) startCleanup() {
+	ticker := time.NewTicker(5 * time.Minute) // 每5分钟检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			if err := f.db.Update(func(tx *bolt.Tx) error {
+				bucket := tx.Bucket(sessionsBucket)
+				expiredSessions := map[string]*persistedSession{}
+				if err := bucket.ForEach(func(k, v []byte) error {
+					var ps persistedSession
+					if err := json.Unmarshal(v, &ps); err != nil {
+						expiredSessions[string(k)] = nil
+					} else if f.expired(&ps) {
+						expiredSessions[string(k)] = &ps
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+				for sessionId, ps := range expiredSessions {
+					if err := unindexOwner(tx, sessionId, ps); err != nil {
+						return err
+					}
+					if err := bucket.Delete([]byte(sessionId)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				f.logger.Errorw("清理过期会话失败", logx.Field("error", err))
+			}
+		}
+	}
+}
+
+// logError 记录存储错误（接口方法不返回错误，失败时降级为空结果）
+func (f *FileSessionStore) logError(op string, sessionId string, err error) {
+	f.logger.Errorw("文件会话存储操作失败",
+		logx.Field("op", op),
+		logx.Field("sessionId", sessionId),
+		logx.Field("error", err),
+	)
+}
+
+// getSession 从事务中读取会话，不存在时返回nil
+func getSession(tx *bolt.Tx, sessionId string) (*persistedSession, error) {
+	raw := tx.Bucket(sessionsBucket).Get([]byte(sessionId))
+	if raw == nil {
+		return nil, nil
+	}
+	var ps persistedSession
+	if err := json.Unmarshal(raw, &ps); err != nil {
+		return nil, fmt.Errorf("解析会话数据失败: %w", err)
+	}
+	return &ps, nil
+}
+
+// sessionOwnerHash 读取会话记录中的所有者令牌摘要，没有所有者时返回空字符串
+func sessionOwnerHash(ps *persistedSession) string {
+	sv, ok := ps.Data[SessionOwnerDataKey]
+	if !ok {
+		return ""
+	}
+	value, err := decodeValue(sv)
+	if err != nil {
+		return ""
+	}
+	ownerHash, _ := value.(string)
+	return ownerHash
+}
+
+// unindexOwner 在事务中把会话从所有者索引中移除（会话不存在或没有所有者时不做处理）
+func unindexOwner(tx *bolt.Tx, sessionId string, ps *persistedSession) error {
+	if ps == nil {
+		return nil
+	}
+	ownerHash := sessionOwnerHash(ps)
+	if ownerHash == "" {
+		return nil
+	}
+	owners := tx.Bucket(sessionOwnersBucket)
+	sessions := owners.Bucket([]byte(ownerHash))
+	if sessions == nil {
+		return nil
+	}
+	if err := sessions.Delete([]byte(sessionId)); err != nil {
+		return err
+	}
+	if k, _ := sessions.Cursor().First(); k == nil {
+		return owners.DeleteBucket([]byte(ownerHash))
+	}
+	return nil
+}
+
+// putSession 在事务中写入会话
+func putSession(tx *bolt.Tx, sessionId string, ps *persistedSession) error {
+	raw, err := json.Marshal(ps)
+	if err != nil {
+		return fmt.Errorf("序列化会话数据失败: %w", err)
+	}
+	return tx.Bucket(sessionsBucket).Put([]byte(sessionId), raw)
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	bolt "go.etcd.io/bbolt"
+)
+
+// sharesBucket bbolt中存放分享数据的bucket名
+var sharesBucket = []byte("shares")
+
+// FileShareStore 基于bbolt的本地文件分享存储
+type FileShareStore struct {
+	db     *bolt.DB
+	logger logx.Logger
+	done   chan struct{}
+}
+
+// NewFileShareStore 创建文件分享存储实例
+func NewFileShareStore(path string, logger logx.Logger) (*FileShareStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("创建分享存储目录失败: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("打开分享存储文件失败: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(sharesBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化分享存储失败: %w", err)
+	}
+
+	store := &FileShareStore{
+		db:     db,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	// 启动清理协程
+	go store.cleanup()
+	return store, nil
+}
+
+// Save 保存分享数据
+func (f *FileShareStore) Save(shareId string, data *ShareData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("序列化分享数据失败: %w", err)
+	}
+	return f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sharesBucket).Put([]byte(shareId), raw)
+	})
+}
+
+// Get 获取分享数据
+func (f *FileShareStore) Get(shareId string) (*ShareData, bool) {
+	var data *ShareData
+	if err := f.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(sharesBucket).Get([]byte(shareId))
+		if raw == nil {
+			return nil
+		}
+		var d ShareData
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("解析分享数据失败: %w", err)
+		}
+		data = &d
+		return nil
+	}); err != nil {
+		f.logger.Errorw("读取分享数据失败", logx.Field("shareId", shareId), logx.Field("error", err))
+		return nil, false
+	}
+	if data == nil || data.Expired() {
+		return nil, false
+	}
+	return data, true
+}
+
+// Delete 删除分享数据
+func (f *FileShareStore) Delete(shareId string) error {
+	return f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sharesBucket).Delete([]byte(shareId))
+	})
+}
+
+// Close 停止清理协程并关闭数据库文件
+func (f *FileShareStore) Close() error {
+	close(f.done)
+	return f.db.Close()
+}
+
+// cleanup 定期清理过期的分享链接
+func (f *FileShareStore) cleanup() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			if err := f.db.Update(func(tx *bolt.Tx) error {
+				bucket := tx.Bucket(sharesBucket)
+				var expiredKeys [][]byte
+				if err := bucket.ForEach(func(k, v []byte) error {
+					var d ShareData
+					if err := json.Unmarshal(v, &d); err != nil || d.Expired() {
+						expiredKeys = append(expiredKeys, append([]byte(nil), k...))
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+				for _, k := range expiredKeys {
+					if err := bucket.Delete(k); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				f.logger.Errorw("清理过期分享失败", logx.Field("error", err))
+			}
+		}
+	}
+}
@@ -2,7 +2,10 @@ package storage
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -143,6 +146,33 @@ func (g *GitHubStorage) getFileSHA(filePath string) (string, error) {
 	return fileInfo.SHA, nil
 }
 
+// gitBlobSHA 计算内容的git blob哈希（与GitHub contents API返回的sha一致）
+func gitBlobSHA(data []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(data))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Name 存储方式名称
+func (g *GitHubStorage) Name() string {
+	return config.ImageBackendGitHub
+}
+
+// URLPrefix 仓库中图片 raw URL 的公共前缀
+func (g *GitHubStorage) URLPrefix() string {
+	filePath := g.config.GitHubPath
+	if filePath != "" && !strings.HasSuffix(filePath, "/") {
+		filePath += "/"
+	}
+	return g.rawURL + "/" + filePath
+}
+
+// Save 上传图片到 GitHub（实现 ImageStore 接口）
+func (g *GitHubStorage) Save(ctx context.Context, data []byte, filename string) (string, error) {
+	return g.Upload(data, filename)
+}
+
 // Upload 上传图片到 GitHub
 func (g *GitHubStorage) Upload(imageData []byte, filename string) (string, error) {
 	// 检查配置
@@ -173,6 +203,15 @@ func (g *GitHubStorage) Upload(imageData []byte, filename string) (string, error
 		)
 	} else {
 		fileSHA = sha
+		// GitHub返回的SHA是文件内容的git blob哈希，内容相同时直接复用已有文件，不再提交
+		if fileSHA != "" && fileSHA == gitBlobSHA(imageData) {
+			imageURL := fmt.Sprintf("%s/%s", g.rawURL, filePath)
+			g.logger.Infow("文件已存在且内容相同，跳过上传",
+				logx.Field("filePath", filePath),
+				logx.Field("url", imageURL),
+			)
+			return imageURL, nil
+		}
 		if fileSHA != "" {
 			g.logger.Infow("文件已存在，将更新文件",
 				logx.Field("filePath", filePath),
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// defaultImageIndexSize 内容索引默认最多记录的图片数
+const defaultImageIndexSize = 10000
+
+// ContentHash 图片内容的SHA-256摘要（十六进制）
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentAddressedName 按内容摘要生成文件名，相同内容得到相同文件名
+// 服务重启后内容索引为空时，存储后端仍可按文件名判断图片是否已存在
+func ContentAddressedName(hash string, ext string) string {
+	if len(hash) > 32 {
+		hash = hash[:32]
+	}
+	return hash + ext
+}
+
+// IndexedImage 已上传图片的访问信息
+type IndexedImage struct {
+	URL          string
+	ThumbnailURL string
+	Filename     string
+}
+
+// ImageIndex 图片内容索引：内容摘要 -> 已上传图片的URL
+// 相同内容的图片再次上传时直接复用已有URL，不再重复写入存储（GitHub每次上传都是一次提交）
+type ImageIndex struct {
+	mu      sync.Mutex
+	images  map[string]IndexedImage
+	order   []string // 写入顺序，超出容量时淘汰最早的
+	maxSize int
+}
+
+// NewImageIndex 创建图片内容索引，maxSize<=0时使用默认容量
+func NewImageIndex(maxSize int) *ImageIndex {
+	if maxSize <= 0 {
+		maxSize = defaultImageIndexSize
+	}
+	return &ImageIndex{
+		images:  make(map[string]IndexedImage),
+		maxSize: maxSize,
+	}
+}
+
+// Get 按内容摘要查找已上传的图片
+func (i *ImageIndex) Get(hash string) (IndexedImage, bool) {
+	if i == nil {
+		return IndexedImage{}, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	image, ok := i.images[hash]
+	return image, ok
+}
+
+// Put 记录已上传的图片
+func (i *ImageIndex) Put(hash string, image IndexedImage) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.images[hash]; !ok {
+		i.order = append(i.order, hash)
+	}
+	i.images[hash] = image
+	for len(i.order) > i.maxSize {
+		delete(i.images, i.order[0])
+		i.order = i.order[1:]
+	}
+}
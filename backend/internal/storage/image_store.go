@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ImageStore 图片存储接口
+// GitHub、本地磁盘、S3兼容存储等后端实现该接口，由 svc.ServiceContext 按配置选择
+type ImageStore interface {
+	// Save 保存图片，返回可访问的URL
+	Save(ctx context.Context, data []byte, filename string) (string, error)
+	// Name 存储方式名称（github/local/s3），作为上传响应的uploadMethod
+	Name() string
+	// URLPrefix 该存储保存的图片URL的公共前缀
+	// 上传的图片按内容命名，该前缀下URL相同的图片内容相同，可以按URL缓存识别结果
+	URLPrefix() string
+}
+
+// ImageOpener 可以读取已保存图片的存储
+// 本地磁盘存储通过 GET /api/images/:name 对外提供图片
+type ImageOpener interface {
+	// Open 打开图片文件，不存在时返回 os.ErrNotExist
+	Open(name string) (*os.File, error)
+}
+
+// NewImageStore 根据配置创建图片存储
+// 未配置存储后端且未配置GitHub参数时返回nil，调用方使用base64 data URL
+func NewImageStore(cfg config.UploadConfig, logger logx.Logger) (ImageStore, error) {
+	backend := cfg.Backend
+	if backend == "" && cfg.GitHubToken != "" && cfg.GitHubOwner != "" && cfg.GitHubRepo != "" {
+		backend = config.ImageBackendGitHub
+	}
+
+	switch backend {
+	case "":
+		return nil, nil
+	case config.ImageBackendGitHub:
+		if cfg.GitHubToken == "" || cfg.GitHubOwner == "" || cfg.GitHubRepo == "" {
+			return nil, fmt.Errorf("GitHub 图片存储需要配置 GITHUB_TOKEN、GITHUB_OWNER、GITHUB_REPO")
+		}
+		return NewGitHubStorage(cfg, logger), nil
+	case config.ImageBackendLocal:
+		store, err := NewLocalImageStore(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case config.ImageBackendS3:
+		store, err := NewS3ImageStore(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("不支持的图片存储后端: %s", cfg.Backend)
+	}
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var testPNG = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00}
+
+func TestNewImageStore(t *testing.T) {
+	logger := logx.WithContext(context.Background())
+
+	store, err := NewImageStore(config.UploadConfig{}, logger)
+	if err != nil || store != nil {
+		t.Errorf("未配置时应返回nil, got %v, %v", store, err)
+	}
+
+	store, err = NewImageStore(config.UploadConfig{GitHubToken: "token", GitHubOwner: "owner", GitHubRepo: "repo"}, logger)
+	if err != nil || store == nil || store.Name() != config.ImageBackendGitHub {
+		t.Errorf("配置了GitHub参数时应使用github, got %v, %v", store, err)
+	}
+
+	store, err = NewImageStore(config.UploadConfig{Backend: config.ImageBackendLocal, LocalDir: t.TempDir()}, logger)
+	if err != nil || store == nil || store.Name() != config.ImageBackendLocal {
+		t.Errorf("应使用本地存储, got %v, %v", store, err)
+	}
+
+	if _, err := NewImageStore(config.UploadConfig{Backend: config.ImageBackendS3}, logger); err == nil {
+		t.Error("S3缺少配置时应返回错误")
+	}
+	if _, err := NewImageStore(config.UploadConfig{Backend: "ftp"}, logger); err == nil {
+		t.Error("不支持的后端应返回错误")
+	}
+}
+
+func TestLocalImageStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalImageStore(config.UploadConfig{LocalDir: dir, PublicBaseURL: "https://explore.example.com/"},
+		logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewLocalImageStore failed: %v", err)
+	}
+
+	url, err := store.Save(context.Background(), testPNG, "leaf.png")
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if url != "https://explore.example.com/api/images/leaf.png" {
+		t.Errorf("url = %q", url)
+	}
+
+	file, err := store.Open("leaf.png")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if string(data) != string(testPNG) {
+		t.Errorf("读取的图片内容不一致")
+	}
+
+	// 临时文件不应残留
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("目录中应只有1个文件, 实际: %d", len(entries))
+	}
+
+	for _, name := range []string{"../leaf.png", ".upload-123", "leaf.txt", ""} {
+		if _, err := store.Save(context.Background(), testPNG, name); err == nil {
+			t.Errorf("Save(%q) 应返回错误", name)
+		}
+		if _, err := store.Open(name); !os.IsNotExist(err) {
+			t.Errorf("Open(%q) 应返回不存在, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "leaf.png")); err == nil {
+		t.Error("不应写入目录之外")
+	}
+}
+
+func TestS3ImageStore(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store, err := NewS3ImageStore(config.UploadConfig{
+		S3Endpoint:  server.URL,
+		S3Bucket:    "explore",
+		S3AccessKey: "minioadmin",
+		S3SecretKey: "minioadmin",
+		S3PathStyle: true,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewS3ImageStore failed: %v", err)
+	}
+	store.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	url, err := store.Save(context.Background(), testPNG, "leaf.png")
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if url != server.URL+"/explore/images/leaf.png" {
+		t.Errorf("url = %q", url)
+	}
+
+	if received.Method != http.MethodPut || received.URL.Path != "/explore/images/leaf.png" {
+		t.Errorf("请求错误: %s %s", received.Method, received.URL.Path)
+	}
+	if string(body) != string(testPNG) {
+		t.Error("上传内容不一致")
+	}
+	if received.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("Content-Type = %q", received.Header.Get("Content-Type"))
+	}
+	if received.Header.Get("X-Amz-Date") != "20250102T030405Z" || received.Header.Get("X-Amz-Content-Sha256") != sha256Hex(testPNG) {
+		t.Errorf("签名头错误: %v", received.Header)
+	}
+	auth := received.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=minioadmin/20250102/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestS3ImageStore_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store, err := NewS3ImageStore(config.UploadConfig{
+		S3Endpoint: server.URL, S3Bucket: "explore", S3AccessKey: "ak", S3SecretKey: "sk", S3PathStyle: true,
+		S3PublicURL: "https://cdn.example.com",
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewS3ImageStore failed: %v", err)
+	}
+	if _, err := store.Save(context.Background(), testPNG, "leaf.png"); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("上传失败时应返回错误, got %v", err)
+	}
+}
+
+func TestS3ImageStore_ObjectURL(t *testing.T) {
+	store, err := NewS3ImageStore(config.UploadConfig{
+		S3Endpoint: "https://s3.example.com", S3Bucket: "explore", S3AccessKey: "ak", S3SecretKey: "sk",
+		S3Prefix: "kids",
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewS3ImageStore failed: %v", err)
+	}
+	if got := store.objectURL(store.prefix + "leaf.png"); got != "https://explore.s3.example.com/kids/leaf.png" {
+		t.Errorf("虚拟主机风格URL = %q", got)
+	}
+}
+
+func TestGitHubStorage_SkipSameContent(t *testing.T) {
+	puts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			puts++
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{}`)
+			return
+		}
+		io.WriteString(w, `{"sha":"`+gitBlobSHA(testPNG)+`","path":"images/leaf.png"}`)
+	}))
+	defer server.Close()
+
+	store := NewGitHubStorage(config.UploadConfig{
+		GitHubToken: "token", GitHubOwner: "tango", GitHubRepo: "images",
+	}, logx.WithContext(context.Background()))
+	store.baseURL = server.URL
+
+	url, err := store.Save(context.Background(), testPNG, "leaf.png")
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if puts != 0 {
+		t.Errorf("内容相同时不应重新提交, got %d PUT", puts)
+	}
+	if url != "https://raw.githubusercontent.com/tango/images/main/images/leaf.png" {
+		t.Errorf("url = %q", url)
+	}
+
+	// 内容不同时照常提交
+	if _, err := store.Save(context.Background(), append([]byte{}, testPNG[:8]...), "leaf.png"); err != nil || puts != 1 {
+		t.Errorf("内容不同时应提交, puts=%d err=%v", puts, err)
+	}
+}
+
+func TestGitBlobSHA(t *testing.T) {
+	// git hash-object /dev/null
+	if got := gitBlobSHA(nil); got != "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391" {
+		t.Errorf("gitBlobSHA(empty) = %s", got)
+	}
+}
+
+func TestImageIndex(t *testing.T) {
+	index := NewImageIndex(2)
+	index.Put("a", IndexedImage{URL: "https://cdn/a.jpg"})
+	index.Put("b", IndexedImage{URL: "https://cdn/b.jpg"})
+	index.Put("a", IndexedImage{URL: "https://cdn/a2.jpg"})
+	if got, ok := index.Get("a"); !ok || got.URL != "https://cdn/a2.jpg" {
+		t.Errorf("Get(a) = %+v, %v", got, ok)
+	}
+
+	// 超出容量时淘汰最早写入的
+	index.Put("c", IndexedImage{URL: "https://cdn/c.jpg"})
+	if _, ok := index.Get("a"); ok {
+		t.Error("最早写入的条目应被淘汰")
+	}
+	if _, ok := index.Get("c"); !ok {
+		t.Error("新条目应存在")
+	}
+
+	var nilIndex *ImageIndex
+	nilIndex.Put("a", IndexedImage{})
+	if _, ok := nilIndex.Get("a"); ok {
+		t.Error("nil索引不应命中")
+	}
+
+	hash := ContentHash(testPNG)
+	if len(hash) != 64 || ContentAddressedName(hash, ".png") != hash[:32]+".png" {
+		t.Errorf("ContentAddressedName = %s", ContentAddressedName(hash, ".png"))
+	}
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// defaultLearnerFilePath 学习档案文件存储默认路径
+	defaultLearnerFilePath = "data/learners.db"
+	// maxProfileTopics 学习档案每类主题最多保留的条数
+	maxProfileTopics = 50
+	// maxProfilePoints 学习档案每类知识点最多保留的条数
+	maxProfilePoints = 20
+	// maxProfileSessions 学习档案最多记录的会话数
+	maxProfileSessions = 20
+	// maxProfilePointRunes 知识点摘要的最大字数（记忆记录中保存的是完整回答）
+	maxProfilePointRunes = 80
+)
+
+// LearnerProfileStore 学习档案存储接口，按孩子标识跨会话保存
+type LearnerProfileStore interface {
+	// Get 获取学习档案，不存在返回false
+	Get(learnerId string) (*types.LearnerProfile, bool)
+	// Save 保存学习档案
+	Save(profile *types.LearnerProfile) error
+	// Update 原子地读取、修改并保存学习档案（不存在时从空档案开始），返回更新后的档案
+	// 同一孩子的多个会话同时合并记忆时不会互相覆盖
+	Update(learnerId string, update func(profile *types.LearnerProfile)) (*types.LearnerProfile, error)
+	// Delete 删除学习档案
+	Delete(learnerId string) error
+}
+
+// NewLearnerProfileStore 根据配置创建学习档案存储
+// 未配置或配置为memory时返回内存存储
+func NewLearnerProfileStore(cfg config.LearnerConfig, logger logx.Logger) (LearnerProfileStore, error) {
+	switch cfg.Backend {
+	case "", config.StoreBackendMemory:
+		return NewMemoryLearnerStore(), nil
+	case config.StoreBackendFile:
+		path := cfg.FilePath
+		if path == "" {
+			path = defaultLearnerFilePath
+		}
+		return NewFileLearnerStore(path, logger)
+	case config.StoreBackendRedis:
+		return NewRedisLearnerStore(cfg, logger)
+	default:
+		return nil, fmt.Errorf("不支持的学习档案存储后端: %s", cfg.Backend)
+	}
+}
+
+// NewLearnerProfile 创建空的学习档案
+func NewLearnerProfile(learnerId string) *types.LearnerProfile {
+	now := time.Now()
+	return &types.LearnerProfile{
+		LearnerId:          learnerId,
+		InterestedTopics:   []string{},
+		UnderstoodTopics:   []string{},
+		UnderstoodPoints:   []string{},
+		UnunderstoodPoints: []string{},
+		SessionIds:         []string{},
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+// MergeMemoryRecord 将会话的记忆记录合并进学习档案
+// 条目去重，重复出现的移到最后（最近），超过上限时丢弃最早的条目
+func MergeMemoryRecord(profile *types.LearnerProfile, record *types.MemoryRecord) {
+	if record == nil {
+		return
+	}
+	for _, topic := range record.InterestedTopics {
+		profile.InterestedTopics = appendRecent(profile.InterestedTopics, topic, maxProfileTopics)
+	}
+	for _, point := range record.UnderstoodPoints {
+		point = summarizePoint(point)
+		profile.UnderstoodPoints = appendRecent(profile.UnderstoodPoints, point, maxProfilePoints)
+		profile.UnunderstoodPoints = removeItem(profile.UnunderstoodPoints, point)
+	}
+	for _, point := range record.UnunderstoodPoints {
+		point = summarizePoint(point)
+		profile.UnunderstoodPoints = appendRecent(profile.UnunderstoodPoints, point, maxProfilePoints)
+		profile.UnderstoodPoints = removeItem(profile.UnderstoodPoints, point)
+	}
+	if record.SessionId != "" {
+		profile.SessionIds = appendRecent(profile.SessionIds, record.SessionId, maxProfileSessions)
+	}
+	profile.UpdatedAt = time.Now()
+}
+
+// MarkTopic 记录孩子对主题的理解情况：理解则加入已理解主题，困惑则移出（下次换种方式讲解）
+func MarkTopic(profile *types.LearnerProfile, topic string, understood bool) {
+	if topic == "" {
+		return
+	}
+	if understood {
+		profile.UnderstoodTopics = appendRecent(profile.UnderstoodTopics, topic, maxProfileTopics)
+	} else {
+		profile.UnderstoodTopics = removeItem(profile.UnderstoodTopics, topic)
+	}
+	profile.UpdatedAt = time.Now()
+}
+
+// appendRecent 追加条目（去重，重复的移到最后），超过上限时丢弃最早的条目
+func appendRecent(items []string, item string, max int) []string {
+	if item == "" {
+		return items
+	}
+	items = append(removeItem(items, item), item)
+	if len(items) > max {
+		items = items[len(items)-max:]
+	}
+	return items
+}
+
+// removeItem 移除条目
+func removeItem(items []string, item string) []string {
+	result := items[:0:0]
+	for _, existing := range items {
+		if existing != item {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
+
+// summarizePoint 截取知识点摘要
+func summarizePoint(point string) string {
+	runes := []rune(point)
+	if len(runes) <= maxProfilePointRunes {
+		return point
+	}
+	return string(runes[:maxProfilePointRunes]) + "…"
+}
+
+// cloneProfile 复制学习档案，避免调用方修改存储中的数据
+func cloneProfile(profile *types.LearnerProfile) *types.LearnerProfile {
+	clone := *profile
+	clone.InterestedTopics = append([]string{}, profile.InterestedTopics...)
+	clone.UnderstoodTopics = append([]string{}, profile.UnderstoodTopics...)
+	clone.UnderstoodPoints = append([]string{}, profile.UnderstoodPoints...)
+	clone.UnunderstoodPoints = append([]string{}, profile.UnunderstoodPoints...)
+	clone.SessionIds = append([]string{}, profile.SessionIds...)
+	return &clone
+}
+
+// MemoryLearnerStore 学习档案存储（内存实现）
+type MemoryLearnerStore struct {
+	mu       sync.RWMutex
+	profiles map[string]*types.LearnerProfile
+}
+
+// NewMemoryLearnerStore 创建内存学习档案存储实例
+func NewMemoryLearnerStore() *MemoryLearnerStore {
+	return &MemoryLearnerStore{
+		profiles: make(map[string]*types.LearnerProfile),
+	}
+}
+
+// Get 获取学习档案
+func (s *MemoryLearnerStore) Get(learnerId string) (*types.LearnerProfile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	profile, ok := s.profiles[learnerId]
+	if !ok {
+		return nil, false
+	}
+	return cloneProfile(profile), true
+}
+
+// Save 保存学习档案
+func (s *MemoryLearnerStore) Save(profile *types.LearnerProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[profile.LearnerId] = cloneProfile(profile)
+	return nil
+}
+
+// Update 在写锁内读取、修改并保存学习档案
+func (s *MemoryLearnerStore) Update(learnerId string, update func(profile *types.LearnerProfile)) (*types.LearnerProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[learnerId]
+	if ok {
+		profile = cloneProfile(profile)
+	} else {
+		profile = NewLearnerProfile(learnerId)
+	}
+	update(profile)
+	s.profiles[learnerId] = cloneProfile(profile)
+	return profile, nil
+}
+
+// Delete 删除学习档案
+func (s *MemoryLearnerStore) Delete(learnerId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.profiles, learnerId)
+	return nil
+}
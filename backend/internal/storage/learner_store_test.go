@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis/redistest"
+)
+
+// testLearnerProfileStore 各学习档案存储后端共用的行为测试
+func testLearnerProfileStore(t *testing.T, store LearnerProfileStore) {
+	profile := NewLearnerProfile("child-1")
+	profile.UserAge = 8
+	MarkTopic(profile, "银杏", true)
+
+	if err := store.Save(profile); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	retrieved, ok := store.Get("child-1")
+	if !ok {
+		t.Fatal("Should be able to get saved profile")
+	}
+	if retrieved.UserAge != 8 || len(retrieved.UnderstoodTopics) != 1 || retrieved.UnderstoodTopics[0] != "银杏" {
+		t.Errorf("Unexpected profile: %+v", retrieved)
+	}
+
+	if _, ok := store.Get("non-existent-id"); ok {
+		t.Error("Should return false for non-existent learnerId")
+	}
+
+	if err := store.Delete("child-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := store.Get("child-1"); ok {
+		t.Error("Should return false after deletion")
+	}
+}
+
+// testConcurrentLearnerUpdate 同一档案的并发更新都应保留
+func testConcurrentLearnerUpdate(t *testing.T, store LearnerProfileStore) {
+	const updaters = 10
+	var wg sync.WaitGroup
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := store.Update("child-3", func(profile *types.LearnerProfile) {
+				MarkTopic(profile, fmt.Sprintf("主题%d", i), true)
+			}); err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	profile, ok := store.Get("child-3")
+	if !ok {
+		t.Fatal("Should be able to get updated profile")
+	}
+	if len(profile.UnderstoodTopics) != updaters {
+		t.Errorf("Expected %d topics after concurrent updates, got %v", updaters, profile.UnderstoodTopics)
+	}
+}
+
+func TestMemoryLearnerStore(t *testing.T) {
+	store := NewMemoryLearnerStore()
+	testLearnerProfileStore(t, store)
+	testConcurrentLearnerUpdate(t, store)
+
+	// 修改读取到的档案不应影响存储中的数据
+	store.Save(NewLearnerProfile("child-2"))
+	profile, _ := store.Get("child-2")
+	MarkTopic(profile, "蝴蝶", true)
+	if stored, _ := store.Get("child-2"); len(stored.UnderstoodTopics) != 0 {
+		t.Errorf("Stored profile should not be modified: %+v", stored.UnderstoodTopics)
+	}
+}
+
+func TestFileLearnerStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learners.db")
+	store, err := NewFileLearnerStore(path, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileLearnerStore failed: %v", err)
+	}
+	defer store.Close()
+
+	testLearnerProfileStore(t, store)
+	testConcurrentLearnerUpdate(t, store)
+}
+
+func TestRedisLearnerStore(t *testing.T) {
+	rds := redistest.CreateRedis(t)
+	store := newRedisLearnerStore(rds, "test:", logx.WithContext(context.Background()))
+
+	testLearnerProfileStore(t, store)
+
+	// 读取后、写入前档案被另一个请求修改，比较并写入失败后应重新读取并保留两次修改
+	// （miniredis不支持新连接的握手命令，并发建立连接会触发熔断，这里在回调中制造冲突）
+	attempts := 0
+	profile, err := store.Update("child-3", func(profile *types.LearnerProfile) {
+		attempts++
+		if attempts == 1 {
+			if _, err := store.Update("child-3", func(other *types.LearnerProfile) {
+				MarkTopic(other, "蜻蜓", true)
+			}); err != nil {
+				t.Fatalf("Concurrent update failed: %v", err)
+			}
+		}
+		MarkTopic(profile, "蝴蝶", true)
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected update to retry once after conflict, got %d attempts", attempts)
+	}
+	if len(profile.UnderstoodTopics) != 2 {
+		t.Errorf("Expected both updates to be kept, got %v", profile.UnderstoodTopics)
+	}
+}
+
+func TestMergeMemoryRecord(t *testing.T) {
+	profile := NewLearnerProfile("child-1")
+
+	MergeMemoryRecord(profile, &types.MemoryRecord{
+		SessionId:          "session-1",
+		InterestedTopics:   []string{"银杏", "蝴蝶"},
+		UnunderstoodPoints: []string{"光合作用"},
+	})
+	MergeMemoryRecord(profile, &types.MemoryRecord{
+		SessionId:        "session-2",
+		InterestedTopics: []string{"银杏"},
+		UnderstoodPoints: []string{"光合作用", strings.Repeat("长", 200)},
+	})
+
+	// 重复的主题移到最后
+	if strings.Join(profile.InterestedTopics, ",") != "蝴蝶,银杏" {
+		t.Errorf("InterestedTopics = %v", profile.InterestedTopics)
+	}
+	// 后来理解的知识点从未理解列表中移除
+	if len(profile.UnunderstoodPoints) != 0 {
+		t.Errorf("UnunderstoodPoints = %v", profile.UnunderstoodPoints)
+	}
+	if len(profile.UnderstoodPoints) != 2 || len([]rune(profile.UnderstoodPoints[1])) != maxProfilePointRunes+1 {
+		t.Errorf("UnderstoodPoints = %v", profile.UnderstoodPoints)
+	}
+	if strings.Join(profile.SessionIds, ",") != "session-1,session-2" {
+		t.Errorf("SessionIds = %v", profile.SessionIds)
+	}
+
+	// 超过上限时丢弃最早的会话
+	for i := 0; i < maxProfileSessions+5; i++ {
+		MergeMemoryRecord(profile, &types.MemoryRecord{SessionId: "s" + string(rune('a'+i))})
+	}
+	if len(profile.SessionIds) != maxProfileSessions {
+		t.Errorf("SessionIds should be capped at %d, got %d", maxProfileSessions, len(profile.SessionIds))
+	}
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/utils"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// defaultImageLocalDir 本地图片存储默认目录
+	defaultImageLocalDir = "data/images"
+	// localImageRoutePrefix 本地图片的访问路径前缀
+	localImageRoutePrefix = "/api/images/"
+)
+
+// LocalImageStore 图片存储（本地磁盘实现）
+type LocalImageStore struct {
+	dir     string
+	baseURL string
+	logger  logx.Logger
+}
+
+// NewLocalImageStore 创建本地磁盘图片存储，目录不存在时自动创建
+func NewLocalImageStore(cfg config.UploadConfig, logger logx.Logger) (*LocalImageStore, error) {
+	dir := cfg.LocalDir
+	if dir == "" {
+		dir = defaultImageLocalDir
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建图片目录失败: %w", err)
+	}
+	if cfg.PublicBaseURL == "" {
+		logger.Infow("未配置 UPLOAD_PUBLIC_BASE_URL，本地图片将返回相对路径，外部模型无法直接访问",
+			logx.Field("dir", dir),
+		)
+	}
+	return &LocalImageStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(cfg.PublicBaseURL, "/"),
+		logger:  logger,
+	}, nil
+}
+
+// Name 存储方式名称
+func (s *LocalImageStore) Name() string {
+	return config.ImageBackendLocal
+}
+
+// URLPrefix 本地图片URL的公共前缀（未配置 UPLOAD_PUBLIC_BASE_URL 时为相对路径）
+func (s *LocalImageStore) URLPrefix() string {
+	return s.baseURL + localImageRoutePrefix
+}
+
+// Save 保存图片到本地目录（先写临时文件再重命名，避免读到写了一半的文件）
+func (s *LocalImageStore) Save(ctx context.Context, data []byte, filename string) (string, error) {
+	if err := validateImageName(filename); err != nil {
+		return "", err
+	}
+	imageURL := s.baseURL + localImageRoutePrefix + url.PathEscape(filename)
+
+	// 文件名按内容生成，已存在且内容相同时不再重复写入
+	path := filepath.Join(s.dir, filename)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return imageURL, nil
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("写入图片失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("写入图片失败: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("设置图片权限失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("保存图片失败: %w", err)
+	}
+
+	return imageURL, nil
+}
+
+// Open 打开已保存的图片
+func (s *LocalImageStore) Open(name string) (*os.File, error) {
+	if err := validateImageName(name); err != nil {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+// validateImageName 校验图片文件名（只允许目录下的图片文件，不允许路径和隐藏文件）
+func validateImageName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") {
+		return utils.ErrFilenameInvalid
+	}
+	return utils.ValidateFilename(name)
+}
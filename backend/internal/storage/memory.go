@@ -16,13 +16,26 @@ type SessionData struct {
 
 // MemoryStorage 内存缓存实现
 type MemoryStorage struct {
-	sessions sync.Map // key: sessionId, value: *SessionData
-	mu       sync.RWMutex
+	sessions sync.Map                       // key: sessionId, value: *SessionData
+	mu       sync.RWMutex                   // 保护会话的额外数据和所有者索引
+	owners   map[string]map[string]struct{} // key: 所有者令牌摘要, value: 会话ID集合
+	ttl      time.Duration                  // 会话无活动的过期时间
 }
 
-// NewMemoryStorage 创建新的内存存储实例
+// NewMemoryStorage 创建新的内存存储实例（使用默认的会话过期时间）
 func NewMemoryStorage() *MemoryStorage {
-	storage := &MemoryStorage{}
+	return NewMemoryStorageWithTTL(defaultSessionTTL)
+}
+
+// NewMemoryStorageWithTTL 创建新的内存存储实例，会话无活动超过 ttl 后清理
+func NewMemoryStorageWithTTL(ttl time.Duration) *MemoryStorage {
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+	storage := &MemoryStorage{
+		owners: make(map[string]map[string]struct{}),
+		ttl:    ttl,
+	}
 	// 启动清理协程
 	go storage.startCleanup()
 	return storage
@@ -48,6 +61,11 @@ func (m *MemoryStorage) GetSession(sessionId string) (*SessionData, bool) {
 
 // DeleteSession 删除会话
 func (m *MemoryStorage) DeleteSession(sessionId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if value, ok := m.sessions.Load(sessionId); ok {
+		m.unindexOwner(value.(*SessionData))
+	}
 	m.sessions.Delete(sessionId)
 }
 
@@ -69,9 +87,9 @@ func (m *MemoryStorage) AddMessage(sessionId string, message interface{}) {
 
 	session := value.(*SessionData)
 	session.Messages = append(session.Messages, message)
-	// 限制上下文长度，最多保留20轮对话（40条消息）
-	if len(session.Messages) > 40 {
-		session.Messages = session.Messages[len(session.Messages)-40:]
+	// 防止会话无限增长，最多保留 maxSessionMessages 条消息（模型上下文由滚动摘要控制）
+	if len(session.Messages) > maxSessionMessages {
+		session.Messages = session.Messages[len(session.Messages)-maxSessionMessages:]
 	}
 	session.LastActive = time.Now()
 	m.sessions.Store(sessionId, session)
@@ -89,6 +107,8 @@ func (m *MemoryStorage) GetMessages(sessionId string) []interface{} {
 
 // SetData 设置会话的额外数据
 func (m *MemoryStorage) SetData(sessionId string, key string, value interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	valueData, ok := m.sessions.Load(sessionId)
 	if !ok {
 		session := &SessionData{
@@ -113,6 +133,8 @@ func (m *MemoryStorage) SetData(sessionId string, key string, value interface{})
 
 // GetData 获取会话的额外数据
 func (m *MemoryStorage) GetData(sessionId string, key string) (interface{}, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	value, ok := m.sessions.Load(sessionId)
 	if !ok {
 		return nil, false
@@ -125,20 +147,107 @@ func (m *MemoryStorage) GetData(sessionId string, key string) (interface{}, bool
 	return val, ok
 }
 
-// startCleanup 启动清理协程，定期清理过期会话（30分钟无活动）
+// ListSessions 列出未过期的会话（清理协程尚未删除的过期会话不列出）
+func (m *MemoryStorage) ListSessions() []SessionInfo {
+	sessions := []SessionInfo{}
+	m.sessions.Range(func(key, value interface{}) bool {
+		session := value.(*SessionData)
+		if time.Since(session.LastActive) > m.ttl {
+			return true
+		}
+		sessions = append(sessions, SessionInfo{
+			SessionId:  session.SessionId,
+			CreatedAt:  session.CreatedAt,
+			LastActive: session.LastActive,
+		})
+		return true
+	})
+	return sessions
+}
+
+// ClaimOwner 会话还没有所有者时记录所有者令牌摘要并加入所有者索引（检查和写入在同一把锁内完成）
+func (m *MemoryStorage) ClaimOwner(sessionId string, ownerHash string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	value, _ := m.sessions.LoadOrStore(sessionId, &SessionData{
+		SessionId:  sessionId,
+		Messages:   []interface{}{},
+		CreatedAt:  now,
+		LastActive: now,
+		Data:       make(map[string]interface{}),
+	})
+	session := value.(*SessionData)
+	if session.Data == nil {
+		session.Data = make(map[string]interface{})
+	}
+	if _, owned := session.Data[SessionOwnerDataKey]; owned {
+		return false
+	}
+	session.Data[SessionOwnerDataKey] = ownerHash
+	session.LastActive = now
+
+	if m.owners[ownerHash] == nil {
+		m.owners[ownerHash] = make(map[string]struct{})
+	}
+	m.owners[ownerHash][sessionId] = struct{}{}
+	return true
+}
+
+// ListOwnerSessions 通过所有者索引列出属于该所有者的未过期会话
+func (m *MemoryStorage) ListOwnerSessions(ownerHash string) []SessionInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := []SessionInfo{}
+	for sessionId := range m.owners[ownerHash] {
+		value, ok := m.sessions.Load(sessionId)
+		if !ok {
+			continue
+		}
+		session := value.(*SessionData)
+		if time.Since(session.LastActive) > m.ttl || session.Data[SessionOwnerDataKey] != ownerHash {
+			continue
+		}
+		sessions = append(sessions, SessionInfo{
+			SessionId:  session.SessionId,
+			CreatedAt:  session.CreatedAt,
+			LastActive: session.LastActive,
+		})
+	}
+	return sessions
+}
+
+// unindexOwner 从所有者索引中移除会话（调用方需持有写锁）
+func (m *MemoryStorage) unindexOwner(session *SessionData) {
+	ownerHash, _ := session.Data[SessionOwnerDataKey].(string)
+	if ownerHash == "" {
+		return
+	}
+	delete(m.owners[ownerHash], session.SessionId)
+	if len(m.owners[ownerHash]) == 0 {
+		delete(m.owners, ownerHash)
+	}
+}
+
+// startCleanup 启动清理协程，定期清理过期会话（默认30分钟无活动）
 func (m *MemoryStorage) startCleanup() {
 	ticker := time.NewTicker(5 * time.Minute) // 每5分钟检查一次
 	defer ticker.Stop()
 
 	for range ticker.C {
 		now := time.Now()
+		m.mu.Lock()
 		m.sessions.Range(func(key, value interface{}) bool {
 			session := value.(*SessionData)
-			// 如果30分钟无活动，删除会话
-			if now.Sub(session.LastActive) > 30*time.Minute {
+			// 超过过期时间无活动，删除会话
+			if now.Sub(session.LastActive) > m.ttl {
+				m.unindexOwner(session)
 				m.sessions.Delete(key)
 			}
 			return true
 		})
+		m.mu.Unlock()
 	}
 }
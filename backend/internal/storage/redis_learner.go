@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// maxLearnerUpdateRetries 并发更新冲突时的最大重试次数
+const maxLearnerUpdateRetries = 10
+
+// compareAndSetScript 档案未被其他副本修改时才写入（ARGV[1]为读取时的内容，空字符串表示读取时不存在）
+var compareAndSetScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if (current or "") ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2])
+return 1
+`)
+
+// errLearnerUpdateConflict 多次重试后仍与其他更新冲突
+var errLearnerUpdateConflict = errors.New("学习档案并发更新冲突")
+
+// RedisLearnerStore 基于Redis的学习档案存储（不过期）
+type RedisLearnerStore struct {
+	rds    *redis.Redis
+	prefix string
+	logger logx.Logger
+}
+
+// NewRedisLearnerStore 创建Redis学习档案存储实例
+func NewRedisLearnerStore(cfg config.LearnerConfig, logger logx.Logger) (*RedisLearnerStore, error) {
+	redisType := cfg.RedisType
+	if redisType == "" {
+		redisType = redis.NodeType
+	}
+
+	rds, err := redis.NewRedis(redis.RedisConf{
+		Host:        cfg.RedisHost,
+		Type:        redisType,
+		Pass:        cfg.RedisPass,
+		PingTimeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接学习档案Redis失败: %w", err)
+	}
+
+	prefix := cfg.RedisKeyPrefix
+	if prefix == "" {
+		prefix = defaultRedisKeyPrefix
+	}
+	return newRedisLearnerStore(rds, prefix, logger), nil
+}
+
+// newRedisLearnerStore 使用已有的Redis客户端创建学习档案存储
+func newRedisLearnerStore(rds *redis.Redis, prefix string, logger logx.Logger) *RedisLearnerStore {
+	return &RedisLearnerStore{
+		rds:    rds,
+		prefix: prefix,
+		logger: logger,
+	}
+}
+
+// Get 获取学习档案
+func (r *RedisLearnerStore) Get(learnerId string) (*types.LearnerProfile, bool) {
+	raw, err := r.rds.GetCtx(context.Background(), r.key(learnerId))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.logger.Errorw("读取学习档案失败", logx.Field("learnerId", learnerId), logx.Field("error", err))
+		return nil, false
+	}
+	if raw == "" {
+		return nil, false
+	}
+
+	var profile types.LearnerProfile
+	if err := json.Unmarshal([]byte(raw), &profile); err != nil {
+		r.logger.Errorw("解析学习档案失败", logx.Field("learnerId", learnerId), logx.Field("error", err))
+		return nil, false
+	}
+	return &profile, true
+}
+
+// Save 保存学习档案
+func (r *RedisLearnerStore) Save(profile *types.LearnerProfile) error {
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("序列化学习档案失败: %w", err)
+	}
+	return r.rds.SetCtx(context.Background(), r.key(profile.LearnerId), string(raw))
+}
+
+// Update 读取、修改后以比较并写入的方式保存学习档案，期间档案被其他请求修改时重新读取并重试
+func (r *RedisLearnerStore) Update(learnerId string, update func(profile *types.LearnerProfile)) (*types.LearnerProfile, error) {
+	ctx := context.Background()
+	key := r.key(learnerId)
+	for i := 0; i < maxLearnerUpdateRetries; i++ {
+		current, err := r.rds.GetCtx(ctx, key)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("读取学习档案失败: %w", err)
+		}
+
+		profile := NewLearnerProfile(learnerId)
+		if current != "" {
+			if err := json.Unmarshal([]byte(current), profile); err != nil {
+				return nil, fmt.Errorf("解析学习档案失败: %w", err)
+			}
+		}
+		update(profile)
+		raw, err := json.Marshal(profile)
+		if err != nil {
+			return nil, fmt.Errorf("序列化学习档案失败: %w", err)
+		}
+
+		written, err := r.rds.ScriptRunCtx(ctx, compareAndSetScript, []string{key}, current, string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("保存学习档案失败: %w", err)
+		}
+		if n, _ := written.(int64); n == 1 {
+			return profile, nil
+		}
+	}
+	return nil, errLearnerUpdateConflict
+}
+
+// Delete 删除学习档案
+func (r *RedisLearnerStore) Delete(learnerId string) error {
+	_, err := r.rds.DelCtx(context.Background(), r.key(learnerId))
+	return err
+}
+
+// key 返回学习档案的键
+func (r *RedisLearnerStore) key(learnerId string) string {
+	return r.prefix + "learner:" + learnerId
+}
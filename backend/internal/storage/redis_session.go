@@ -0,0 +1,465 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// 会话元数据字段
+const (
+	sessionFieldCreatedAt  = "createdAt"
+	sessionFieldLastActive = "lastActive"
+)
+
+// sessionScanCount 列出会话时每次SCAN的键数量
+const sessionScanCount = 100
+
+// RedisSessionStore 基于Redis的会话存储
+// 每个会话拆分为三个键：元数据（hash）、消息列表（list）、额外数据（hash），
+// 追加消息和设置数据都是单键原子操作，多个副本可以同时读写同一会话；过期由Redis TTL负责。
+// 所有者索引为每个所有者令牌摘要一个会话ID集合，随所属会话的活动一起续期
+type RedisSessionStore struct {
+	rds    *redis.Redis
+	prefix string
+	ttl    time.Duration
+	logger logx.Logger
+}
+
+// NewRedisSessionStore 创建Redis会话存储实例
+func NewRedisSessionStore(cfg config.SessionConfig, ttl time.Duration, logger logx.Logger) (*RedisSessionStore, error) {
+	redisType := cfg.RedisType
+	if redisType == "" {
+		redisType = redis.NodeType
+	}
+
+	rds, err := redis.NewRedis(redis.RedisConf{
+		Host:        cfg.RedisHost,
+		Type:        redisType,
+		Pass:        cfg.RedisPass,
+		PingTimeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接会话Redis失败: %w", err)
+	}
+
+	prefix := cfg.RedisKeyPrefix
+	if prefix == "" {
+		prefix = defaultRedisKeyPrefix
+	}
+	return newRedisSessionStore(rds, prefix, ttl, logger), nil
+}
+
+// newRedisSessionStore 使用已有的Redis客户端创建会话存储
+func newRedisSessionStore(rds *redis.Redis, prefix string, ttl time.Duration, logger logx.Logger) *RedisSessionStore {
+	return &RedisSessionStore{
+		rds:    rds,
+		prefix: prefix,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// SetSession 设置会话数据（覆盖已有会话）
+func (r *RedisSessionStore) SetSession(sessionId string, data *SessionData) {
+	data.LastActive = time.Now()
+	ps, err := encodeSession(data)
+	if err != nil {
+		r.logError("SetSession", sessionId, err)
+		return
+	}
+
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	err = r.rds.PipelinedCtx(ctx, func(p redis.Pipeliner) error {
+		p.Del(ctx, metaKey, messagesKey, dataKey)
+		p.HSet(ctx, metaKey,
+			sessionFieldCreatedAt, ps.CreatedAt.Format(time.RFC3339Nano),
+			sessionFieldLastActive, ps.LastActive.Format(time.RFC3339Nano),
+		)
+		for _, sv := range ps.Messages {
+			raw, err := json.Marshal(sv)
+			if err != nil {
+				return err
+			}
+			p.RPush(ctx, messagesKey, string(raw))
+		}
+		for key, sv := range ps.Data {
+			raw, err := json.Marshal(sv)
+			if err != nil {
+				return err
+			}
+			p.HSet(ctx, dataKey, key, string(raw))
+		}
+		r.expire(ctx, p, metaKey, messagesKey, dataKey)
+		r.expireOwner(ctx, p, sessionOwnerHash(ps))
+		return nil
+	})
+	if err != nil {
+		r.logError("SetSession", sessionId, err)
+	}
+}
+
+// GetSession 获取会话数据
+func (r *RedisSessionStore) GetSession(sessionId string) (*SessionData, bool) {
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+
+	meta, err := r.rds.HgetallCtx(ctx, metaKey)
+	if err != nil {
+		r.logError("GetSession", sessionId, err)
+		return nil, false
+	}
+	if len(meta) == 0 {
+		return nil, false
+	}
+
+	rawMessages, err := r.rds.LrangeCtx(ctx, messagesKey, 0, -1)
+	if err != nil {
+		r.logError("GetSession", sessionId, err)
+		return nil, false
+	}
+	rawData, err := r.rds.HgetallCtx(ctx, dataKey)
+	if err != nil {
+		r.logError("GetSession", sessionId, err)
+		return nil, false
+	}
+
+	ps := &persistedSession{
+		SessionId: sessionId,
+		Messages:  make([]storedValue, 0, len(rawMessages)),
+		Data:      make(map[string]storedValue, len(rawData)),
+	}
+	ps.CreatedAt, _ = time.Parse(time.RFC3339Nano, meta[sessionFieldCreatedAt])
+	for _, raw := range rawMessages {
+		var sv storedValue
+		if err := json.Unmarshal([]byte(raw), &sv); err != nil {
+			r.logError("GetSession", sessionId, err)
+			return nil, false
+		}
+		ps.Messages = append(ps.Messages, sv)
+	}
+	for key, raw := range rawData {
+		var sv storedValue
+		if err := json.Unmarshal([]byte(raw), &sv); err != nil {
+			r.logError("GetSession", sessionId, err)
+			return nil, false
+		}
+		ps.Data[key] = sv
+	}
+
+	// 更新最后活动时间
+	ps.LastActive = time.Now()
+	if err := r.touch(ctx, sessionId, sessionOwnerHash(ps)); err != nil {
+		r.logError("GetSession", sessionId, err)
+	}
+
+	data, err := decodeSession(ps)
+	if err != nil {
+		r.logError("GetSession", sessionId, err)
+		return nil, false
+	}
+	return data, true
+}
+
+// DeleteSession 删除会话
+func (r *RedisSessionStore) DeleteSession(sessionId string) {
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	if ownerHash := r.sessionOwner(ctx, sessionId); ownerHash != "" {
+		if _, err := r.rds.SremCtx(ctx, r.ownerKey(ownerHash), sessionId); err != nil {
+			r.logError("DeleteSession", sessionId, err)
+		}
+	}
+	if _, err := r.rds.DelCtx(ctx, metaKey, messagesKey, dataKey); err != nil {
+		r.logError("DeleteSession", sessionId, err)
+	}
+}
+
+// AddMessage 添加消息到会话
+func (r *RedisSessionStore) AddMessage(sessionId string, message interface{}) {
+	raw, err := r.marshalValue(message)
+	if err != nil {
+		r.logError("AddMessage", sessionId, err)
+		return
+	}
+
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	ownerHash := r.sessionOwner(ctx, sessionId)
+	err = r.rds.PipelinedCtx(ctx, func(p redis.Pipeliner) error {
+		p.RPush(ctx, messagesKey, raw)
+		// 限制上下文长度，与内存存储保持一致
+		p.LTrim(ctx, messagesKey, -maxSessionMessages, -1)
+		r.markActive(ctx, p, metaKey)
+		r.expire(ctx, p, metaKey, messagesKey, dataKey)
+		r.expireOwner(ctx, p, ownerHash)
+		return nil
+	})
+	if err != nil {
+		r.logError("AddMessage", sessionId, err)
+	}
+}
+
+// GetMessages 获取会话消息列表
+func (r *RedisSessionStore) GetMessages(sessionId string) []interface{} {
+	_, messagesKey, _ := r.keys(sessionId)
+	rawMessages, err := r.rds.LrangeCtx(context.Background(), messagesKey, 0, -1)
+	if err != nil {
+		r.logError("GetMessages", sessionId, err)
+		return []interface{}{}
+	}
+
+	messages := make([]interface{}, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		msg, err := r.unmarshalValue(raw)
+		if err != nil {
+			r.logError("GetMessages", sessionId, err)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// SetData 设置会话的额外数据
+func (r *RedisSessionStore) SetData(sessionId string, key string, value interface{}) {
+	raw, err := r.marshalValue(value)
+	if err != nil {
+		r.logError("SetData", sessionId, err)
+		return
+	}
+
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	ownerHash := r.sessionOwner(ctx, sessionId)
+	err = r.rds.PipelinedCtx(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, dataKey, key, raw)
+		r.markActive(ctx, p, metaKey)
+		r.expire(ctx, p, metaKey, messagesKey, dataKey)
+		r.expireOwner(ctx, p, ownerHash)
+		return nil
+	})
+	if err != nil {
+		r.logError("SetData", sessionId, err)
+	}
+}
+
+// GetData 获取会话的额外数据
+func (r *RedisSessionStore) GetData(sessionId string, key string) (interface{}, bool) {
+	_, _, dataKey := r.keys(sessionId)
+	raw, err := r.rds.HgetCtx(context.Background(), dataKey, key)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			r.logError("GetData", sessionId, err)
+		}
+		return nil, false
+	}
+
+	value, err := r.unmarshalValue(raw)
+	if err != nil {
+		r.logError("GetData", sessionId, err)
+		return nil, false
+	}
+	return value, true
+}
+
+// ListSessions 列出未过期的会话（按元数据键扫描）
+func (r *RedisSessionStore) ListSessions() []SessionInfo {
+	ctx := context.Background()
+	sessions := []SessionInfo{}
+	base := r.prefix + "session:"
+	var cursor uint64
+	for {
+		keys, next, err := r.rds.ScanCtx(ctx, cursor, base+"*:meta", sessionScanCount)
+		if err != nil {
+			r.logError("ListSessions", "", err)
+			return sessions
+		}
+		for _, key := range keys {
+			sessionId := strings.TrimSuffix(strings.TrimPrefix(key, base), ":meta")
+			meta, err := r.rds.HmgetCtx(ctx, key, sessionFieldCreatedAt, sessionFieldLastActive)
+			if err != nil || len(meta) != 2 || meta[1] == "" {
+				// 扫描期间过期或删除的会话
+				continue
+			}
+			info := SessionInfo{SessionId: sessionId}
+			info.CreatedAt, _ = time.Parse(time.RFC3339Nano, meta[0])
+			info.LastActive, _ = time.Parse(time.RFC3339Nano, meta[1])
+			sessions = append(sessions, info)
+		}
+		if next == 0 {
+			return sessions
+		}
+		cursor = next
+	}
+}
+
+// ClaimOwner 会话还没有所有者时记录所有者令牌摘要并加入所有者索引
+// 通过HSETNX保证多个副本同时认领时只有一个成功
+func (r *RedisSessionStore) ClaimOwner(sessionId string, ownerHash string) bool {
+	raw, err := r.marshalValue(ownerHash)
+	if err != nil {
+		r.logError("ClaimOwner", sessionId, err)
+		return false
+	}
+
+	ctx := context.Background()
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	claimed, err := r.rds.HsetnxCtx(ctx, dataKey, SessionOwnerDataKey, raw)
+	if err != nil {
+		r.logError("ClaimOwner", sessionId, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	ownerKey := r.ownerKey(ownerHash)
+	err = r.rds.PipelinedCtx(ctx, func(p redis.Pipeliner) error {
+		p.SAdd(ctx, ownerKey, sessionId)
+		r.markActive(ctx, p, metaKey)
+		r.expire(ctx, p, metaKey, messagesKey, dataKey, ownerKey)
+		return nil
+	})
+	if err != nil {
+		r.logError("ClaimOwner", sessionId, err)
+	}
+	return true
+}
+
+// ListOwnerSessions 通过所有者索引列出属于该所有者的未过期会话，顺带清理索引中已过期或被删除的会话
+func (r *RedisSessionStore) ListOwnerSessions(ownerHash string) []SessionInfo {
+	ctx := context.Background()
+	sessions := []SessionInfo{}
+	ownerKey := r.ownerKey(ownerHash)
+	sessionIds, err := r.rds.SmembersCtx(ctx, ownerKey)
+	if err != nil {
+		r.logError("ListOwnerSessions", "", err)
+		return sessions
+	}
+
+	var stale []interface{}
+	for _, sessionId := range sessionIds {
+		metaKey, _, _ := r.keys(sessionId)
+		meta, err := r.rds.HmgetCtx(ctx, metaKey, sessionFieldCreatedAt, sessionFieldLastActive)
+		if err != nil {
+			r.logError("ListOwnerSessions", sessionId, err)
+			continue
+		}
+		if len(meta) != 2 || meta[1] == "" || r.sessionOwner(ctx, sessionId) != ownerHash {
+			stale = append(stale, sessionId)
+			continue
+		}
+		info := SessionInfo{SessionId: sessionId}
+		info.CreatedAt, _ = time.Parse(time.RFC3339Nano, meta[0])
+		info.LastActive, _ = time.Parse(time.RFC3339Nano, meta[1])
+		sessions = append(sessions, info)
+	}
+	if len(stale) > 0 {
+		if _, err := r.rds.SremCtx(ctx, ownerKey, stale...); err != nil {
+			r.logError("ListOwnerSessions", "", err)
+		}
+	}
+	return sessions
+}
+
+// keys 返回会话的元数据、消息列表、额外数据三个键
+func (r *RedisSessionStore) keys(sessionId string) (metaKey, messagesKey, dataKey string) {
+	base := r.prefix + "session:" + sessionId
+	return base + ":meta", base + ":messages", base + ":data"
+}
+
+// ownerKey 返回所有者索引的键
+func (r *RedisSessionStore) ownerKey(ownerHash string) string {
+	return r.prefix + "session_owner:" + ownerHash
+}
+
+// sessionOwner 读取会话的所有者令牌摘要，没有所有者或读取失败时返回空字符串
+func (r *RedisSessionStore) sessionOwner(ctx context.Context, sessionId string) string {
+	_, _, dataKey := r.keys(sessionId)
+	raw, err := r.rds.HgetCtx(ctx, dataKey, SessionOwnerDataKey)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			r.logError("sessionOwner", sessionId, err)
+		}
+		return ""
+	}
+	value, err := r.unmarshalValue(raw)
+	if err != nil {
+		r.logError("sessionOwner", sessionId, err)
+		return ""
+	}
+	ownerHash, _ := value.(string)
+	return ownerHash
+}
+
+// touch 更新最后活动时间并续期（包括会话所属的所有者索引）
+func (r *RedisSessionStore) touch(ctx context.Context, sessionId string, ownerHash string) error {
+	metaKey, messagesKey, dataKey := r.keys(sessionId)
+	return r.rds.PipelinedCtx(ctx, func(p redis.Pipeliner) error {
+		r.markActive(ctx, p, metaKey)
+		r.expire(ctx, p, metaKey, messagesKey, dataKey)
+		r.expireOwner(ctx, p, ownerHash)
+		return nil
+	})
+}
+
+// markActive 记录创建时间（仅首次）和最后活动时间
+func (r *RedisSessionStore) markActive(ctx context.Context, p redis.Pipeliner, metaKey string) {
+	now := time.Now().Format(time.RFC3339Nano)
+	p.HSetNX(ctx, metaKey, sessionFieldCreatedAt, now)
+	p.HSet(ctx, metaKey, sessionFieldLastActive, now)
+}
+
+// expire 为会话的所有键设置过期时间
+func (r *RedisSessionStore) expire(ctx context.Context, p redis.Pipeliner, keys ...string) {
+	for _, key := range keys {
+		p.Expire(ctx, key, r.ttl)
+	}
+}
+
+// expireOwner 续期所有者索引，使其不早于所属的任何会话过期
+func (r *RedisSessionStore) expireOwner(ctx context.Context, p redis.Pipeliner, ownerHash string) {
+	if ownerHash != "" {
+		p.Expire(ctx, r.ownerKey(ownerHash), r.ttl)
+	}
+}
+
+// marshalValue 序列化单个会话值
+func (r *RedisSessionStore) marshalValue(value interface{}) (string, error) {
+	sv, err := encodeValue(value)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(sv)
+	if err != nil {
+		return "", fmt.Errorf("序列化会话数据失败: %w", err)
+	}
+	return string(raw), nil
+}
+
+// unmarshalValue 反序列化单个会话值
+func (r *RedisSessionStore) unmarshalValue(raw string) (interface{}, error) {
+	var sv storedValue
+	if err := json.Unmarshal([]byte(raw), &sv); err != nil {
+		return nil, fmt.Errorf("解析会话数据失败: %w", err)
+	}
+	return decodeValue(sv)
+}
+
+// logError 记录存储错误（接口方法不返回错误，失败时降级为空结果）
+func (r *RedisSessionStore) logError(op string, sessionId string, err error) {
+	r.logger.Errorw("Redis会话存储操作失败",
+		logx.Field("op", op),
+		logx.Field("sessionId", sessionId),
+		logx.Field("error", err),
+	)
+}
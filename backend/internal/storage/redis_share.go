@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// RedisShareStore 基于Redis的分享存储，过期由Redis TTL负责
+type RedisShareStore struct {
+	rds    *redis.Redis
+	prefix string
+	logger logx.Logger
+}
+
+// NewRedisShareStore 创建Redis分享存储实例
+func NewRedisShareStore(cfg config.ShareConfig, logger logx.Logger) (*RedisShareStore, error) {
+	redisType := cfg.RedisType
+	if redisType == "" {
+		redisType = redis.NodeType
+	}
+
+	rds, err := redis.NewRedis(redis.RedisConf{
+		Host:        cfg.RedisHost,
+		Type:        redisType,
+		Pass:        cfg.RedisPass,
+		PingTimeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接分享Redis失败: %w", err)
+	}
+
+	prefix := cfg.RedisKeyPrefix
+	if prefix == "" {
+		prefix = defaultRedisKeyPrefix
+	}
+	return newRedisShareStore(rds, prefix, logger), nil
+}
+
+// newRedisShareStore 使用已有的Redis客户端创建分享存储
+func newRedisShareStore(rds *redis.Redis, prefix string, logger logx.Logger) *RedisShareStore {
+	return &RedisShareStore{
+		rds:    rds,
+		prefix: prefix,
+		logger: logger,
+	}
+}
+
+// Save 保存分享数据
+func (r *RedisShareStore) Save(shareId string, data *ShareData) error {
+	ttl := int(time.Until(data.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return fmt.Errorf("分享已过期: %s", shareId)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("序列化分享数据失败: %w", err)
+	}
+	return r.rds.SetexCtx(context.Background(), r.key(shareId), string(raw), ttl)
+}
+
+// Get 获取分享数据
+func (r *RedisShareStore) Get(shareId string) (*ShareData, bool) {
+	raw, err := r.rds.GetCtx(context.Background(), r.key(shareId))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.logger.Errorw("读取分享数据失败", logx.Field("shareId", shareId), logx.Field("error", err))
+		return nil, false
+	}
+	if raw == "" {
+		return nil, false
+	}
+
+	var data ShareData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		r.logger.Errorw("解析分享数据失败", logx.Field("shareId", shareId), logx.Field("error", err))
+		return nil, false
+	}
+	if data.Expired() {
+		return nil, false
+	}
+	return &data, true
+}
+
+// Delete 删除分享数据
+func (r *RedisShareStore) Delete(shareId string) error {
+	_, err := r.rds.DelCtx(context.Background(), r.key(shareId))
+	return err
+}
+
+// key 返回分享数据的键
+func (r *RedisShareStore) key(shareId string) string {
+	return r.prefix + "share:" + shareId
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// defaultS3Region S3默认区域（MinIO默认使用该区域）
+	defaultS3Region = "us-east-1"
+	// defaultS3Prefix S3对象键默认前缀
+	defaultS3Prefix = "images/"
+	// s3TimeFormat SigV4签名时间格式
+	s3TimeFormat = "20060102T150405Z"
+)
+
+// S3ImageStore 图片存储（S3兼容对象存储实现，使用SigV4签名的PUT Object接口）
+type S3ImageStore struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	prefix    string
+	publicURL string
+	client    *http.Client
+	logger    logx.Logger
+	now       func() time.Time
+}
+
+// NewS3ImageStore 创建S3兼容图片存储
+func NewS3ImageStore(cfg config.UploadConfig, logger logx.Logger) (*S3ImageStore, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3 图片存储需要配置 S3_ENDPOINT、S3_BUCKET、S3_ACCESS_KEY、S3_SECRET_KEY")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.S3Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3 服务地址无效: %s", cfg.S3Endpoint)
+	}
+
+	region := cfg.S3Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	prefix := cfg.S3Prefix
+	if prefix == "" {
+		prefix = defaultS3Prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &S3ImageStore{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		pathStyle: cfg.S3PathStyle,
+		prefix:    strings.TrimPrefix(prefix, "/"),
+		publicURL: strings.TrimSuffix(cfg.S3PublicURL, "/"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+		logger:    logger,
+		now:       time.Now,
+	}, nil
+}
+
+// Name 存储方式名称
+func (s *S3ImageStore) Name() string {
+	return config.ImageBackendS3
+}
+
+// URLPrefix 存储桶中图片URL的公共前缀
+func (s *S3ImageStore) URLPrefix() string {
+	if s.publicURL != "" {
+		return s.publicURL + "/" + escapeS3Path(s.prefix)
+	}
+	return s.objectURL(s.prefix)
+}
+
+// Save 上传图片到存储桶
+func (s *S3ImageStore) Save(ctx context.Context, data []byte, filename string) (string, error) {
+	if err := validateImageName(filename); err != nil {
+		return "", err
+	}
+	key := s.prefix + filename
+
+	objectURL := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("创建S3请求失败: %w", err)
+	}
+	if contentType := mime.TypeByExtension(path.Ext(filename)); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("S3 上传请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("S3 上传失败: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+
+	if s.publicURL != "" {
+		return s.publicURL + "/" + escapeS3Path(key), nil
+	}
+	return objectURL, nil
+}
+
+// objectURL 对象的访问地址（路径风格：endpoint/bucket/key，虚拟主机风格：bucket.endpoint/key）
+func (s *S3ImageStore) objectURL(key string) string {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+	u.RawPath = escapeS3Path(u.Path)
+	return u.String()
+}
+
+// sign 使用AWS Signature Version 4签名请求
+func (s *S3ImageStore) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapeS3Path(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// escapeS3Path 按S3规则编码路径（除 A-Z a-z 0-9 - _ . ~ 和 / 外全部编码）
+func escapeS3Path(p string) string {
+	var b strings.Builder
+	for _, c := range []byte(p) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sha256Hex 计算SHA256并返回十六进制字符串
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
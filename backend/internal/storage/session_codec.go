@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tango/explore/internal/types"
+)
+
+// 持久化值的类型标记，用于反序列化时还原为调用方期望的具体类型
+const (
+	valueKindMessage               = "conversationMessage"
+	valueKindIdentificationContext = "identificationContext"
+	valueKindContextSummary        = "contextSummary"
+	valueKindDisambiguation        = "disambiguation"
+	valueKindJSON                  = "json"
+)
+
+// storedValue 持久化的会话值
+// 调用方会对 GetMessages/GetData 的结果做类型断言（如 types.ConversationMessage），
+// 因此需要记录原始类型，读取时还原，而不是返回 map[string]interface{}
+type storedValue struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+// persistedSession 持久化的会话数据
+type persistedSession struct {
+	SessionId  string                 `json:"sessionId"`
+	Messages   []storedValue          `json:"messages"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	LastActive time.Time              `json:"lastActive"`
+	Data       map[string]storedValue `json:"data"`
+}
+
+// encodeValue 序列化会话值
+func encodeValue(value interface{}) (storedValue, error) {
+	kind := valueKindJSON
+	switch v := value.(type) {
+	case types.ConversationMessage:
+		kind = valueKindMessage
+	case *types.ConversationMessage:
+		kind = valueKindMessage
+	case types.IdentificationContext:
+		kind = valueKindIdentificationContext
+	case *types.IdentificationContext:
+		if v != nil {
+			kind = valueKindIdentificationContext
+		}
+	case *types.ContextSummary:
+		if v != nil {
+			kind = valueKindContextSummary
+		}
+	case *types.Disambiguation:
+		if v != nil {
+			kind = valueKindDisambiguation
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return storedValue{}, fmt.Errorf("序列化会话数据失败: %w", err)
+	}
+	return storedValue{Kind: kind, Value: raw}, nil
+}
+
+// decodeValue 反序列化会话值
+// 消息还原为 types.ConversationMessage，识别上下文还原为 *types.IdentificationContext，
+// 上下文摘要还原为 *types.ContextSummary，待选择的候选对象还原为 *types.Disambiguation
+func decodeValue(sv storedValue) (interface{}, error) {
+	switch sv.Kind {
+	case valueKindMessage:
+		var msg types.ConversationMessage
+		if err := json.Unmarshal(sv.Value, &msg); err != nil {
+			return nil, fmt.Errorf("反序列化消息失败: %w", err)
+		}
+		return msg, nil
+	case valueKindIdentificationContext:
+		var ctx types.IdentificationContext
+		if err := json.Unmarshal(sv.Value, &ctx); err != nil {
+			return nil, fmt.Errorf("反序列化识别上下文失败: %w", err)
+		}
+		return &ctx, nil
+	case valueKindContextSummary:
+		var summary types.ContextSummary
+		if err := json.Unmarshal(sv.Value, &summary); err != nil {
+			return nil, fmt.Errorf("反序列化上下文摘要失败: %w", err)
+		}
+		return &summary, nil
+	case valueKindDisambiguation:
+		var disambiguation types.Disambiguation
+		if err := json.Unmarshal(sv.Value, &disambiguation); err != nil {
+			return nil, fmt.Errorf("反序列化候选对象失败: %w", err)
+		}
+		return &disambiguation, nil
+	default:
+		var v interface{}
+		if err := json.Unmarshal(sv.Value, &v); err != nil {
+			return nil, fmt.Errorf("反序列化会话数据失败: %w", err)
+		}
+		return v, nil
+	}
+}
+
+// encodeSession 将会话数据转换为持久化格式
+func encodeSession(data *SessionData) (*persistedSession, error) {
+	ps := &persistedSession{
+		SessionId:  data.SessionId,
+		Messages:   make([]storedValue, 0, len(data.Messages)),
+		CreatedAt:  data.CreatedAt,
+		LastActive: data.LastActive,
+		Data:       make(map[string]storedValue, len(data.Data)),
+	}
+	for _, msg := range data.Messages {
+		sv, err := encodeValue(msg)
+		if err != nil {
+			return nil, err
+		}
+		ps.Messages = append(ps.Messages, sv)
+	}
+	for key, value := range data.Data {
+		sv, err := encodeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		ps.Data[key] = sv
+	}
+	return ps, nil
+}
+
+// decodeSession 将持久化格式还原为会话数据
+func decodeSession(ps *persistedSession) (*SessionData, error) {
+	data := &SessionData{
+		SessionId:  ps.SessionId,
+		Messages:   make([]interface{}, 0, len(ps.Messages)),
+		CreatedAt:  ps.CreatedAt,
+		LastActive: ps.LastActive,
+		Data:       make(map[string]interface{}, len(ps.Data)),
+	}
+	for _, sv := range ps.Messages {
+		msg, err := decodeValue(sv)
+		if err != nil {
+			return nil, err
+		}
+		data.Messages = append(data.Messages, msg)
+	}
+	for key, sv := range ps.Data {
+		value, err := decodeValue(sv)
+		if err != nil {
+			return nil, err
+		}
+		data.Data[key] = value
+	}
+	return data, nil
+}
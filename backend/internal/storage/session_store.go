@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// defaultSessionTTL 会话默认过期时间（无活动）
+	defaultSessionTTL = 30 * time.Minute
+	// maxSessionMessages 每个会话最多保留的消息数（100轮对话）
+	// 模型上下文由滚动摘要控制，这里只是防止会话无限增长的安全上限；
+	// 裁剪掉摘要游标消息后，history.Manager 按游标的时间戳跳过已压缩的消息
+	maxSessionMessages = 200
+	// defaultSessionFilePath 文件存储默认路径
+	defaultSessionFilePath = "data/sessions.db"
+	// defaultRedisKeyPrefix Redis键默认前缀
+	defaultRedisKeyPrefix = "explore:"
+)
+
+// SessionOwnerDataKey 会话额外数据中保存所有者令牌摘要的键（由 ClaimOwner 写入）
+const SessionOwnerDataKey = "ownerTokenHash"
+
+// SessionStore 会话存储接口
+// 内存、文件、Redis等后端实现该接口，由 svc.ServiceContext 按配置选择
+type SessionStore interface {
+	// SetSession 设置会话数据
+	SetSession(sessionId string, data *SessionData)
+	// GetSession 获取会话数据
+	GetSession(sessionId string) (*SessionData, bool)
+	// DeleteSession 删除会话
+	DeleteSession(sessionId string)
+	// AddMessage 添加消息到会话
+	AddMessage(sessionId string, message interface{})
+	// GetMessages 获取会话消息列表
+	GetMessages(sessionId string) []interface{}
+	// SetData 设置会话的额外数据
+	SetData(sessionId string, key string, value interface{})
+	// GetData 获取会话的额外数据
+	GetData(sessionId string, key string) (interface{}, bool)
+	// ListSessions 列出未过期的会话（不更新最后活动时间）
+	ListSessions() []SessionInfo
+	// ClaimOwner 会话还没有所有者时原子地记录所有者令牌摘要并加入所有者索引，
+	// 返回是否由本次调用写入；会话已有所有者时不改变归属
+	ClaimOwner(sessionId string, ownerHash string) bool
+	// ListOwnerSessions 通过所有者索引列出属于该所有者的未过期会话（不更新最后活动时间）
+	ListOwnerSessions(ownerHash string) []SessionInfo
+}
+
+// SessionInfo 会话基本信息（用于会话列表）
+type SessionInfo struct {
+	SessionId  string
+	CreatedAt  time.Time
+	LastActive time.Time
+}
+
+// NewSessionStore 根据配置创建会话存储
+// 未配置或配置为memory时返回内存存储
+func NewSessionStore(cfg config.SessionConfig, logger logx.Logger) (SessionStore, error) {
+	ttl := defaultSessionTTL
+	if cfg.TTLMinutes > 0 {
+		ttl = time.Duration(cfg.TTLMinutes) * time.Minute
+	}
+
+	switch cfg.Backend {
+	case "", config.StoreBackendMemory:
+		return NewMemoryStorageWithTTL(ttl), nil
+	case config.StoreBackendFile:
+		path := cfg.FilePath
+		if path == "" {
+			path = defaultSessionFilePath
+		}
+		return NewFileSessionStore(path, ttl, logger)
+	case config.StoreBackendRedis:
+		return NewRedisSessionStore(cfg, ttl, logger)
+	default:
+		return nil, fmt.Errorf("不支持的会话存储后端: %s", cfg.Backend)
+	}
+}
@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis/redistest"
+)
+
+// testSessionStore 各会话存储后端共用的行为测试
+func testSessionStore(t *testing.T, store SessionStore) {
+	sessionId := "test-session-123"
+
+	// 测试消息读写，读取后应还原为 types.ConversationMessage
+	store.AddMessage(sessionId, types.ConversationMessage{
+		Id:      "msg-1",
+		Type:    "text",
+		Sender:  "user",
+		Content: "银杏为什么会变黄？",
+	})
+	store.AddMessage(sessionId, types.ConversationMessage{
+		Id:      "msg-2",
+		Type:    "text",
+		Sender:  "assistant",
+		Content: "因为秋天到了",
+	})
+
+	messages := store.GetMessages(sessionId)
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	msg, ok := messages[0].(types.ConversationMessage)
+	if !ok {
+		t.Fatalf("Expected types.ConversationMessage, got %T", messages[0])
+	}
+	if msg.Id != "msg-1" || msg.Content != "银杏为什么会变黄？" {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+
+	// 测试额外数据读写，识别上下文应还原为 *types.IdentificationContext
+	store.SetData(sessionId, "identificationContext", &types.IdentificationContext{
+		ObjectName:     "银杏",
+		ObjectCategory: "自然类",
+		Confidence:     0.9,
+		Age:            8,
+	})
+	value, ok := store.GetData(sessionId, "identificationContext")
+	if !ok {
+		t.Fatal("identificationContext should exist")
+	}
+	identCtx, ok := value.(*types.IdentificationContext)
+	if !ok {
+		t.Fatalf("Expected *types.IdentificationContext, got %T", value)
+	}
+	if identCtx.ObjectName != "银杏" || identCtx.Age != 8 {
+		t.Errorf("Unexpected identification context: %+v", identCtx)
+	}
+
+	// 上下文摘要应还原为 *types.ContextSummary
+	store.SetData(sessionId, "contextSummary", &types.ContextSummary{Summary: "聊过银杏", LastMessageId: "msg-1", SummarizedMessages: 1})
+	value, ok = store.GetData(sessionId, "contextSummary")
+	if summary, isSummary := value.(*types.ContextSummary); !ok || !isSummary || summary.LastMessageId != "msg-1" {
+		t.Errorf("Expected *types.ContextSummary, got %T", value)
+	}
+
+	// 待选择的候选对象应还原为 *types.Disambiguation
+	store.SetData(sessionId, "disambiguation", &types.Disambiguation{
+		Question:   "你想了解哪一个呢？",
+		Candidates: []types.DetectedObject{{ObjectName: "小猫", BoundingBox: &types.BoundingBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4}}},
+	})
+	value, ok = store.GetData(sessionId, "disambiguation")
+	if d, isDisambiguation := value.(*types.Disambiguation); !ok || !isDisambiguation || d.Candidates[0].BoundingBox.Width != 0.3 {
+		t.Errorf("Expected *types.Disambiguation, got %T", value)
+	}
+
+	if _, ok := store.GetData(sessionId, "missing"); ok {
+		t.Error("Missing key should not exist")
+	}
+
+	// 测试整体读取会话
+	session, ok := store.GetSession(sessionId)
+	if !ok {
+		t.Fatal("Session should exist")
+	}
+	if len(session.Messages) != 2 || len(session.Data) != 3 {
+		t.Errorf("Expected 2 messages and 3 data entries, got %d and %d", len(session.Messages), len(session.Data))
+	}
+
+	// 测试消息数量上限
+	for i := 0; i < maxSessionMessages+5; i++ {
+		store.AddMessage(sessionId, types.ConversationMessage{Id: "overflow", Type: "text", Sender: "user", Content: "hi"})
+	}
+	if got := len(store.GetMessages(sessionId)); got != maxSessionMessages {
+		t.Errorf("Expected %d messages after trimming, got %d", maxSessionMessages, got)
+	}
+
+	// 测试列出会话
+	if !containsSession(store.ListSessions(), sessionId) {
+		t.Error("ListSessions should include the session")
+	}
+
+	// 测试认领所有者：只有第一次认领成功，会话进入所有者索引
+	if !store.ClaimOwner(sessionId, "owner-a") {
+		t.Error("First claim should succeed")
+	}
+	if store.ClaimOwner(sessionId, "owner-b") {
+		t.Error("Second claim should not change the owner")
+	}
+	if value, ok := store.GetData(sessionId, SessionOwnerDataKey); !ok || value != "owner-a" {
+		t.Errorf("Expected owner-a, got %v", value)
+	}
+	if !containsSession(store.ListOwnerSessions("owner-a"), sessionId) {
+		t.Error("ListOwnerSessions should include the claimed session")
+	}
+	if len(store.ListOwnerSessions("owner-b")) != 0 {
+		t.Error("ListOwnerSessions should not include sessions of other owners")
+	}
+
+	// 测试删除会话
+	store.DeleteSession(sessionId)
+	if _, ok := store.GetSession(sessionId); ok {
+		t.Error("Session should not exist after delete")
+	}
+	if len(store.ListOwnerSessions("owner-a")) != 0 {
+		t.Error("ListOwnerSessions should not include deleted session")
+	}
+	if containsSession(store.ListSessions(), sessionId) {
+		t.Error("ListSessions should not include deleted session")
+	}
+	if len(store.GetMessages(sessionId)) != 0 {
+		t.Error("Messages should be empty after delete")
+	}
+}
+
+// testConcurrentClaim 并发认领同一会话时只有一个所有者成功
+func testConcurrentClaim(t *testing.T, store SessionStore) {
+	const claimers = 10
+	var wg sync.WaitGroup
+	results := make([]bool, claimers)
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = store.ClaimOwner("contested-session", fmt.Sprintf("owner-%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	winners := 0
+	for i, claimed := range results {
+		if !claimed {
+			continue
+		}
+		winners++
+		owned := store.ListOwnerSessions(fmt.Sprintf("owner-%d", i))
+		if !containsSession(owned, "contested-session") {
+			t.Errorf("Winner owner-%d should list the session", i)
+		}
+	}
+	if winners != 1 {
+		t.Errorf("Expected exactly 1 successful claim, got %d", winners)
+	}
+}
+
+// containsSession 会话列表中是否包含指定会话
+func containsSession(sessions []SessionInfo, sessionId string) bool {
+	for _, session := range sessions {
+		if session.SessionId == sessionId {
+			return !session.LastActive.IsZero()
+		}
+	}
+	return false
+}
+
+func TestMemoryStorage_SessionStore(t *testing.T) {
+	testSessionStore(t, NewMemoryStorage())
+}
+
+func TestMemoryStorage_ListSessionsSkipsExpired(t *testing.T) {
+	store := NewMemoryStorageWithTTL(time.Millisecond)
+	store.AddMessage("s1", types.ConversationMessage{Id: "msg-1", Type: "text", Sender: "user", Content: "你好"})
+	time.Sleep(5 * time.Millisecond)
+
+	if containsSession(store.ListSessions(), "s1") {
+		t.Error("ListSessions should not include expired session")
+	}
+}
+
+func TestMemoryStorage_ConcurrentClaim(t *testing.T) {
+	testConcurrentClaim(t, NewMemoryStorage())
+}
+
+func TestFileSessionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := NewFileSessionStore(path, time.Hour, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	defer store.Close()
+
+	testSessionStore(t, store)
+}
+
+func TestFileSessionStore_ConcurrentClaim(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := NewFileSessionStore(path, time.Hour, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	defer store.Close()
+
+	testConcurrentClaim(t, store)
+}
+
+func TestFileSessionStore_PersistAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := NewFileSessionStore(path, time.Hour, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	store.AddMessage("s1", types.ConversationMessage{Id: "msg-1", Type: "text", Sender: "user", Content: "你好"})
+	store.Close()
+
+	// 模拟服务重启
+	reopened, err := NewFileSessionStore(path, time.Hour, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+
+	messages := reopened.GetMessages("s1")
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message after reopen, got %d", len(messages))
+	}
+}
+
+func TestFileSessionStore_Expired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := NewFileSessionStore(path, time.Millisecond, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	defer store.Close()
+
+	store.AddMessage("s1", types.ConversationMessage{Id: "msg-1", Type: "text", Sender: "user", Content: "你好"})
+	time.Sleep(5 * time.Millisecond)
+
+	if len(store.GetMessages("s1")) != 0 {
+		t.Error("Expired session should have no messages")
+	}
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	rds := redistest.CreateRedis(t)
+	store := newRedisSessionStore(rds, "test:", time.Hour, logx.WithContext(context.Background()))
+
+	testSessionStore(t, store)
+}
+
+// TestRedisSessionStore_ClaimOwnerOnce 认领的原子性由HSETNX保证，这里依次认领
+// （miniredis不支持新连接的握手命令，并发建立连接会触发熔断）
+func TestRedisSessionStore_ClaimOwnerOnce(t *testing.T) {
+	rds := redistest.CreateRedis(t)
+	store := newRedisSessionStore(rds, "test:", time.Hour, logx.WithContext(context.Background()))
+
+	if !store.ClaimOwner("contested-session", "owner-0") {
+		t.Fatal("First claim should succeed")
+	}
+	for i := 1; i < 3; i++ {
+		owner := fmt.Sprintf("owner-%d", i)
+		if store.ClaimOwner("contested-session", owner) {
+			t.Errorf("Claim by %s should fail after the session is owned", owner)
+		}
+		if len(store.ListOwnerSessions(owner)) != 0 {
+			t.Errorf("%s should not list the session", owner)
+		}
+	}
+	if !containsSession(store.ListOwnerSessions("owner-0"), "contested-session") {
+		t.Error("Winner owner-0 should list the session")
+	}
+}
+
+func TestNewSessionStore_UnknownBackend(t *testing.T) {
+	_, err := NewSessionStore(config.SessionConfig{Backend: "mongo"}, logx.WithContext(context.Background()))
+	if err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+}
+
+func TestNewSessionStore_MemoryTTL(t *testing.T) {
+	store, err := NewSessionStore(config.SessionConfig{TTLMinutes: 120}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewSessionStore failed: %v", err)
+	}
+	if ttl := store.(*MemoryStorage).ttl; ttl != 2*time.Hour {
+		t.Errorf("内存存储应使用配置的过期时间，ttl = %v", ttl)
+	}
+	if ttl := NewMemoryStorage().ttl; ttl != defaultSessionTTL {
+		t.Errorf("未配置时应使用默认过期时间，ttl = %v", ttl)
+	}
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// defaultShareFilePath 分享文件存储默认路径
+const defaultShareFilePath = "data/shares.db"
+
+// ShareData 分享数据
+type ShareData struct {
+	ShareId            string                    `json:"shareId"`
+	ExplorationRecords []types.ExplorationRecord `json:"explorationRecords"`
+	CollectedCards     []types.KnowledgeCard     `json:"collectedCards"`
+	OwnerTokenHash     string                    `json:"ownerTokenHash"` // 所有者令牌的SHA-256摘要，不保存明文
+	CreatedAt          time.Time                 `json:"createdAt"`
+	ExpiresAt          time.Time                 `json:"expiresAt"`
+}
+
+// Expired 判断分享是否已过期
+func (d *ShareData) Expired() bool {
+	return time.Now().After(d.ExpiresAt)
+}
+
+// ShareStore 分享链接存储接口
+type ShareStore interface {
+	// Save 保存分享数据，过期时间由 data.ExpiresAt 决定
+	Save(shareId string, data *ShareData) error
+	// Get 获取分享数据，不存在或已过期返回false
+	Get(shareId string) (*ShareData, bool)
+	// Delete 删除分享数据
+	Delete(shareId string) error
+}
+
+// NewShareStore 根据配置创建分享存储
+// 未配置或配置为memory时返回内存存储
+func NewShareStore(cfg config.ShareConfig, logger logx.Logger) (ShareStore, error) {
+	switch cfg.Backend {
+	case "", config.StoreBackendMemory:
+		return NewMemoryShareStore(), nil
+	case config.StoreBackendFile:
+		path := cfg.FilePath
+		if path == "" {
+			path = defaultShareFilePath
+		}
+		return NewFileShareStore(path, logger)
+	case config.StoreBackendRedis:
+		return NewRedisShareStore(cfg, logger)
+	default:
+		return nil, fmt.Errorf("不支持的分享存储后端: %s", cfg.Backend)
+	}
+}
+
+// MemoryShareStore 分享链接存储（内存实现）
+type MemoryShareStore struct {
+	mu     sync.RWMutex
+	shares map[string]*ShareData
+}
+
+// NewMemoryShareStore 创建内存分享存储实例
+func NewMemoryShareStore() *MemoryShareStore {
+	store := &MemoryShareStore{
+		shares: make(map[string]*ShareData),
+	}
+	// 启动清理goroutine
+	go store.cleanup()
+	return store
+}
+
+// Save 保存分享数据
+func (s *MemoryShareStore) Save(shareId string, data *ShareData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shares[shareId] = data
+	return nil
+}
+
+// Get 获取分享数据
+func (s *MemoryShareStore) Get(shareId string) (*ShareData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.shares[shareId]
+	if !ok {
+		return nil, false
+	}
+	// 检查是否过期
+	if data.Expired() {
+		return nil, false
+	}
+	return data, true
+}
+
+// Delete 删除分享数据
+func (s *MemoryShareStore) Delete(shareId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.shares, shareId)
+	return nil
+}
+
+// cleanup 定期清理过期的分享链接
+func (s *MemoryShareStore) cleanup() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+		for shareId, data := range s.shares {
+			if data.Expired() {
+				delete(s.shares, shareId)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis/redistest"
+)
+
+// testShareStore 各分享存储后端共用的行为测试
+func testShareStore(t *testing.T, store ShareStore) {
+	shareId := "test-share-id"
+	data := &ShareData{
+		ShareId: shareId,
+		ExplorationRecords: []types.ExplorationRecord{
+			{Id: "r1", ObjectName: "银杏", ObjectCategory: "自然类"},
+		},
+		CollectedCards: []types.KnowledgeCard{},
+		OwnerTokenHash: "hash",
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	if err := store.Save(shareId, data); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	retrieved, ok := store.Get(shareId)
+	if !ok {
+		t.Fatal("Should be able to get saved share data")
+	}
+	if len(retrieved.ExplorationRecords) != 1 || retrieved.ExplorationRecords[0].ObjectName != "银杏" {
+		t.Errorf("Unexpected exploration records: %+v", retrieved.ExplorationRecords)
+	}
+	if retrieved.OwnerTokenHash != "hash" {
+		t.Errorf("OwnerTokenHash mismatch: %s", retrieved.OwnerTokenHash)
+	}
+
+	if _, ok := store.Get("non-existent-id"); ok {
+		t.Error("Should return false for non-existent shareId")
+	}
+
+	if err := store.Delete(shareId); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := store.Get(shareId); ok {
+		t.Error("Should return false after deletion")
+	}
+}
+
+func TestFileShareStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.db")
+	store, err := NewFileShareStore(path, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("NewFileShareStore failed: %v", err)
+	}
+	defer store.Close()
+
+	testShareStore(t, store)
+
+	// 过期数据不可读取
+	store.Save("expired-id", &ShareData{
+		ShareId:   "expired-id",
+		CreatedAt: time.Now().Add(-2 * time.Hour),
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	if _, ok := store.Get("expired-id"); ok {
+		t.Error("Should return false for expired share data")
+	}
+}
+
+func TestRedisShareStore(t *testing.T) {
+	rds := redistest.CreateRedis(t)
+	store := newRedisShareStore(rds, "test:", logx.WithContext(context.Background()))
+
+	testShareStore(t, store)
+
+	// 已过期的数据不应写入
+	err := store.Save("expired-id", &ShareData{
+		ShareId:   "expired-id",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	if err == nil {
+		t.Error("Expected error when saving expired share")
+	}
+}
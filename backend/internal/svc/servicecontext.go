@@ -2,28 +2,65 @@ package svc
 
 import (
 	"context"
-	"strings"
+	"io"
+	"time"
 
 	"github.com/tango/explore/internal/agent"
+	"github.com/tango/explore/internal/agent/history"
+	"github.com/tango/explore/internal/agent/llm"
 	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/imageproc"
+	"github.com/tango/explore/internal/speech"
 	"github.com/tango/explore/internal/storage"
+	"github.com/tango/explore/internal/taxonomy"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
 type ServiceContext struct {
 	Config        config.Config
-	Storage       *storage.MemoryStorage
-	Agent         *agent.Agent
-	GitHubStorage *storage.GitHubStorage
+	Storage       storage.SessionStore
+	ShareStore    storage.ShareStore
+	LearnerStore  storage.LearnerProfileStore
+	MemoryStorage *storage.MemoryAgentStorage
+	// ContextManager 对话上下文管理器（较早的对话压缩为滚动摘要）
+	ContextManager *history.Manager
+	Agent          *agent.Agent
+	ImageStore     storage.ImageStore
+	// ImageIndex 已上传图片的内容索引（相同内容的图片复用已有URL）
+	ImageIndex *storage.ImageIndex
+	// Taxonomy 对象类别树（识别结果归一化、学习报告按层级统计）
+	Taxonomy *taxonomy.Taxonomy
+	// Models 模型工厂（按角色创建意图识别、图片识别、文本、卡片、图片生成模型客户端）
+	Models *llm.ModelFactory
+	ASR            speech.ASRProvider
+	TTS            speech.TTSProvider
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	ctx := context.Background()
 	logger := logx.WithContext(ctx)
 
+	// 加载对象类别树（加载失败时使用内置类别树）
+	categoryTree, err := taxonomy.Load(c.Taxonomy.FilePath)
+	if err != nil {
+		logger.Errorw("类别树加载失败，使用内置类别树",
+			logx.Field("filePath", c.Taxonomy.FilePath),
+			logx.Field("error", err),
+		)
+		categoryTree = taxonomy.Default()
+	}
+
+	// 初始化模型工厂（各角色可以使用不同的提供方）
+	models := llm.NewModelFactory(c.AI)
+	enabledRoles := make([]string, 0, len(llm.Roles))
+	for _, role := range llm.Roles {
+		if models.Enabled(role) {
+			enabledRoles = append(enabledRoles, string(role)+"="+models.Provider(role))
+		}
+	}
+
 	// 初始化Agent系统
 	var aiAgent *agent.Agent
-	var err error
 
 	// 检查eino配置
 	hasEinoBaseURL := c.AI.EinoBaseURL != ""
@@ -34,11 +71,12 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		logx.Field("hasEinoBaseURL", hasEinoBaseURL),
 		logx.Field("hasAppID", hasAppID),
 		logx.Field("hasAppKey", hasAppKey),
+		logx.Field("enabledRoles", enabledRoles),
 	)
 
-	if hasEinoBaseURL || hasAppID {
-		// 如果配置了eino相关配置，初始化Agent
-		aiAgent, err = agent.NewAgent(ctx, c.AI)
+	if hasEinoBaseURL || hasAppID || len(enabledRoles) > 0 {
+		// 如果配置了eino相关配置或其他模型服务，初始化Agent
+		aiAgent, err = agent.NewAgent(ctx, c.AI, models)
 		if err != nil {
 			logger.Errorw("Agent初始化失败，将使用Mock数据",
 				logx.Field("error", err),
@@ -47,48 +85,130 @@ func NewServiceContext(c config.Config) *ServiceContext {
 			// 继续运行，使用Mock数据
 		} else {
 			logger.Info("Agent系统初始化成功，将使用真实模型")
+			// 远程图片下载遵循上传配置的大小限制和允许的主机
+			aiAgent.GetGraph().SetImageFetcher(imageproc.NewFetcher(c.Upload))
+			aiAgent.GetGraph().SetTaxonomy(categoryTree)
 		}
 	} else {
 		logger.Errorw("未配置eino参数（EINO_BASE_URL或TAL_MLOPS_APP_ID），将使用Mock数据")
 		logger.Info("如需使用真实模型，请在.env文件中配置：EINO_BASE_URL、TAL_MLOPS_APP_ID、TAL_MLOPS_APP_KEY")
 	}
 
-	// 初始化 GitHub 存储
-	var githubStorage *storage.GitHubStorage
-	if c.Upload.GitHubToken != "" && c.Upload.GitHubOwner != "" && c.Upload.GitHubRepo != "" {
-		// 检查token是否是占位符
-		isPlaceholder := strings.Contains(c.Upload.GitHubToken, "xxxxx") || strings.HasPrefix(c.Upload.GitHubToken, "ghp_xxxxxxxx")
-		if isPlaceholder {
-			logger.Errorw("GitHub token 是占位符，GitHub 存储将无法正常工作",
-				logx.Field("hint", "请将 .env 文件中的 GITHUB_TOKEN 替换为真实的 token"),
-				logx.Field("owner", c.Upload.GitHubOwner),
-				logx.Field("repo", c.Upload.GitHubRepo),
-			)
-			logger.Info("⚠️  警告：由于 GitHub token 是占位符，图片上传将降级到 base64 方案")
-			// 仍然创建实例，但在Upload时会返回错误并降级到base64
-		}
-		githubStorage = storage.NewGitHubStorage(c.Upload, logger)
-		if !isPlaceholder {
-			logger.Info("GitHub 存储初始化成功",
-				logx.Field("owner", c.Upload.GitHubOwner),
-				logx.Field("repo", c.Upload.GitHubRepo),
-				logx.Field("branch", c.Upload.GitHubBranch),
-				logx.Field("path", c.Upload.GitHubPath),
-			)
-		}
+	// 初始化语音识别
+	asrProvider, err := speech.NewASRProvider(c.AI, logger)
+	if err != nil {
+		logger.Errorw("语音识别初始化失败，语音消息将无法识别", logx.Field("error", err))
+	} else if asrProvider == nil {
+		logger.Errorw("未配置语音识别服务（ASR_BASE_URL或EINO_BASE_URL），语音消息将无法识别")
 	} else {
-		logger.Infow("未配置 GitHub 上传参数，图片上传将使用 base64 降级方案",
+		logger.Infow("语音识别初始化成功", logx.Field("provider", asrProvider.Name()))
+	}
+
+	// 初始化语音合成
+	ttsProvider, err := speech.NewTTSProvider(c.AI, logger)
+	if err != nil {
+		logger.Errorw("语音合成初始化失败，回复将不附带语音", logx.Field("error", err))
+	} else if ttsProvider == nil {
+		logger.Errorw("未配置语音合成服务（TTS_BASE_URL或EINO_BASE_URL），回复将不附带语音")
+	} else {
+		logger.Infow("语音合成初始化成功", logx.Field("provider", ttsProvider.Name()))
+	}
+
+	// 初始化图片存储（GitHub、本地磁盘或S3兼容存储，未配置时使用base64降级方案）
+	imageStore, err := storage.NewImageStore(c.Upload, logger)
+	if err != nil {
+		logger.Errorw("图片存储初始化失败，图片上传将使用 base64 降级方案",
+			logx.Field("backend", c.Upload.Backend),
+			logx.Field("error", err),
+		)
+	} else if imageStore == nil {
+		logger.Infow("未配置图片存储，图片上传将使用 base64 降级方案",
 			logx.Field("hasToken", c.Upload.GitHubToken != ""),
 			logx.Field("hasOwner", c.Upload.GitHubOwner != ""),
 			logx.Field("hasRepo", c.Upload.GitHubRepo != ""),
 		)
-		logger.Info("如需使用 GitHub 存储，请在.env文件中配置：GITHUB_TOKEN、GITHUB_OWNER、GITHUB_REPO")
+		logger.Info("如需保存图片，请配置 UPLOAD_BACKEND（github/local/s3），或在.env文件中配置：GITHUB_TOKEN、GITHUB_OWNER、GITHUB_REPO")
+	} else {
+		logger.Infow("图片存储初始化成功", logx.Field("backend", imageStore.Name()))
+		if aiAgent != nil {
+			// 图片存储中的图片按内容命名，识别结果可以按URL缓存
+			aiAgent.GetGraph().SetImageStorePrefix(imageStore.URLPrefix())
+		}
+	}
+
+	// 初始化会话存储（默认内存，可配置为文件或Redis持久化）
+	sessionStore, err := storage.NewSessionStore(c.Session, logger)
+	if err != nil {
+		logger.Errorw("会话存储初始化失败，降级为内存存储",
+			logx.Field("backend", c.Session.Backend),
+			logx.Field("error", err),
+		)
+		sessionStore = storage.NewMemoryStorageWithTTL(time.Duration(c.Session.TTLMinutes) * time.Minute)
+	} else {
+		logger.Infow("会话存储初始化成功", logx.Field("backend", c.Session.Backend))
+	}
+
+	// 初始化分享存储（默认内存，可配置为文件或Redis持久化）
+	shareStore, err := storage.NewShareStore(c.Share, logger)
+	if err != nil {
+		logger.Errorw("分享存储初始化失败，降级为内存存储",
+			logx.Field("backend", c.Share.Backend),
+			logx.Field("error", err),
+		)
+		shareStore = storage.NewMemoryShareStore()
+	} else {
+		logger.Infow("分享存储初始化成功", logx.Field("backend", c.Share.Backend))
+	}
+
+	// 初始化学习档案存储（默认内存，可配置为文件或Redis持久化）
+	learnerStore, err := storage.NewLearnerProfileStore(c.Learner, logger)
+	if err != nil {
+		logger.Errorw("学习档案存储初始化失败，降级为内存存储",
+			logx.Field("backend", c.Learner.Backend),
+			logx.Field("error", err),
+		)
+		learnerStore = storage.NewMemoryLearnerStore()
+	} else {
+		logger.Infow("学习档案存储初始化成功", logx.Field("backend", c.Learner.Backend))
 	}
 
 	return &ServiceContext{
-		Config:        c,
-		Storage:       storage.NewMemoryStorage(),
-		Agent:         aiAgent,
-		GitHubStorage: githubStorage,
+		Config:         c,
+		Storage:        sessionStore,
+		ShareStore:     shareStore,
+		LearnerStore:   learnerStore,
+		MemoryStorage:  storage.NewMemoryAgentStorage(),
+		ContextManager: history.NewManager(ctx, c.AI, models, logger),
+		Agent:          aiAgent,
+		ImageStore:     imageStore,
+		ImageIndex:     storage.NewImageIndex(0),
+		Taxonomy:       categoryTree,
+		Models:         models,
+		ASR:            asrProvider,
+		TTS:            ttsProvider,
+	}
+}
+
+// Close 释放服务资源（关闭Agent、持久化会话存储、分享存储、学习档案存储等）
+func (s *ServiceContext) Close() {
+	if s.Agent != nil {
+		if err := s.Agent.Close(); err != nil {
+			logx.Errorw("关闭Agent失败", logx.Field("error", err))
+		}
+	}
+	if closer, ok := s.Storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logx.Errorw("关闭会话存储失败", logx.Field("error", err))
+		}
+	}
+	if closer, ok := s.ShareStore.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logx.Errorw("关闭分享存储失败", logx.Field("error", err))
+		}
+	}
+	if closer, ok := s.LearnerStore.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logx.Errorw("关闭学习档案存储失败", logx.Field("error", err))
+		}
 	}
 }
@@ -0,0 +1,293 @@
+// Package taxonomy 对象分类体系：可加载的多级类别树（如 自然类/植物/花卉），支持别名，
+// 用于把识别模型返回的类别归一化到固定的类别上，并按任意层级统计
+package taxonomy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Separator 类别路径的分隔符（如 "自然类/植物/花卉"）
+const Separator = "/"
+
+//go:embed default.json
+var defaultTree []byte
+
+var (
+	defaultOnce     sync.Once
+	defaultTaxonomy *Taxonomy
+)
+
+// Category 类别树节点
+type Category struct {
+	Name     string      `json:"name"`
+	Aliases  []string    `json:"aliases,omitempty"`
+	Children []*Category `json:"children,omitempty"`
+}
+
+// tree 类别树文件格式
+type tree struct {
+	// Fallback 无法归类时使用的类别（名称、别名或路径），未设置时使用第一个顶层类别
+	Fallback   string      `json:"fallback,omitempty"`
+	Categories []*Category `json:"categories"`
+}
+
+// Path 类别路径，从顶层类别到具体子类
+type Path []string
+
+// String 返回以 Separator 连接的路径
+func (p Path) String() string {
+	return strings.Join(p, Separator)
+}
+
+// Top 返回顶层类别
+func (p Path) Top() string {
+	if len(p) == 0 {
+		return ""
+	}
+	return p[0]
+}
+
+// Truncate 截断到指定层级（1为顶层），层级不足时返回完整路径，level<=0 返回完整路径
+func (p Path) Truncate(level int) Path {
+	if level <= 0 || level >= len(p) {
+		return p
+	}
+	return p[:level]
+}
+
+// ParsePath 解析以 /、›、> 分隔的类别路径
+func ParsePath(s string) Path {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '›' || r == '>' || r == '＞'
+	})
+	path := make(Path, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			path = append(path, field)
+		}
+	}
+	return path
+}
+
+// Taxonomy 类别树，创建后只读，可并发使用
+type Taxonomy struct {
+	roots    []*Category
+	fallback Path
+	// index 归一化后的名称/别名 -> 节点路径（同一别名可以出现在不同分支）
+	index map[string][]Path
+}
+
+// Default 返回内置的默认类别树（自然类、生活类、人文类及其子类）
+func Default() *Taxonomy {
+	defaultOnce.Do(func() {
+		t, err := Parse(defaultTree)
+		if err != nil {
+			panic(fmt.Sprintf("内置类别树无效: %v", err))
+		}
+		defaultTaxonomy = t
+	})
+	return defaultTaxonomy
+}
+
+// Load 从JSON文件加载类别树，path为空时返回默认类别树
+func Load(path string) (*Taxonomy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取类别树文件失败: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse 解析JSON格式的类别树
+func Parse(data []byte) (*Taxonomy, error) {
+	var t tree
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("解析类别树失败: %w", err)
+	}
+	return New(t.Categories, t.Fallback)
+}
+
+// New 根据类别节点创建类别树，fallback 为无法归类时使用的类别（为空时使用第一个顶层类别）
+func New(roots []*Category, fallback string) (*Taxonomy, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("类别树为空")
+	}
+	t := &Taxonomy{
+		roots: roots,
+		index: make(map[string][]Path),
+	}
+	if err := t.indexCategories(roots, nil); err != nil {
+		return nil, err
+	}
+
+	if fallback == "" {
+		t.fallback = Path{roots[0].Name}
+	} else {
+		path, ok := t.Resolve(fallback)
+		if !ok {
+			return nil, fmt.Errorf("兜底类别不存在: %s", fallback)
+		}
+		t.fallback = path
+	}
+	return t, nil
+}
+
+// indexCategories 校验节点并建立名称/别名索引
+func (t *Taxonomy) indexCategories(categories []*Category, parent Path) error {
+	siblings := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		if c == nil || strings.TrimSpace(c.Name) == "" {
+			return fmt.Errorf("类别名称不能为空（上级类别: %s）", parent)
+		}
+		if strings.ContainsAny(c.Name, "/›>＞") {
+			return fmt.Errorf("类别名称不能包含路径分隔符: %s", c.Name)
+		}
+		if siblings[c.Name] {
+			return fmt.Errorf("类别名称重复: %s", append(append(Path{}, parent...), c.Name))
+		}
+		siblings[c.Name] = true
+
+		path := append(append(make(Path, 0, len(parent)+1), parent...), c.Name)
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			if key := normalizeKey(name); key != "" {
+				t.index[key] = append(t.index[key], path)
+			}
+		}
+		if err := t.indexCategories(c.Children, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fallback 返回无法归类时使用的类别
+func (t *Taxonomy) Fallback() Path {
+	return append(Path{}, t.orDefault().fallback...)
+}
+
+// Roots 返回顶层类别
+func (t *Taxonomy) Roots() []*Category {
+	return t.orDefault().roots
+}
+
+// Resolve 把类别名称、别名或路径（如 "自然/植物"）解析为完整路径
+// 路径中每一段都会解析，优先返回与前面各段一致的最深节点
+func (t *Taxonomy) Resolve(category string) (Path, bool) {
+	t = t.orDefault()
+	segments := ParsePath(category)
+	var resolved Path
+	for _, segment := range segments {
+		candidates := t.lookup(segment)
+		if len(candidates) == 0 {
+			continue
+		}
+		chosen := candidates[0]
+		for _, candidate := range candidates {
+			if hasPrefix(candidate, resolved) {
+				chosen = candidate
+				break
+			}
+		}
+		if len(chosen) > len(resolved) || !hasPrefix(resolved, chosen) {
+			resolved = chosen
+		}
+	}
+	if len(resolved) == 0 {
+		return nil, false
+	}
+	return append(Path{}, resolved...), true
+}
+
+// Classify 把识别结果归一化到类别树上
+// 先解析模型返回的类别，再用对象名称和关键词在该类别下找更具体的子类；
+// 类别无法识别时在整棵树中按对象名称和关键词匹配，仍无法归类时返回兜底类别
+func (t *Taxonomy) Classify(category, objectName string, keywords []string) Path {
+	t = t.orDefault()
+	base, _ := t.Resolve(category)
+
+	// 匹配优先级：对象名称完全匹配 > 对象名称后缀匹配（如 "银杏树" 匹配 "树"）> 关键词匹配，同优先级取最深的节点
+	best := base
+	bestRank := -1
+	consider := func(paths []Path, rank int) {
+		for _, path := range paths {
+			if !hasPrefix(path, base) {
+				continue
+			}
+			if len(path) > len(best) || (len(path) == len(best) && rank > bestRank && len(path) > len(base)) {
+				best, bestRank = path, rank
+			}
+		}
+	}
+
+	name := normalizeKey(objectName)
+	if name != "" {
+		consider(t.index[name], 3)
+		// 从长到短尝试后缀，同深度时较长的后缀优先
+		runes := []rune(name)
+		for i := 1; i < len(runes); i++ {
+			consider(t.index[string(runes[i:])], 2)
+		}
+	}
+	for _, keyword := range keywords {
+		consider(t.lookup(keyword), 1)
+	}
+
+	if len(best) == 0 {
+		return t.Fallback()
+	}
+	return append(Path{}, best...)
+}
+
+// lookup 按名称或别名查找节点，兼容 "自然" / "自然类" / "自然类别" 这类写法差异
+func (t *Taxonomy) lookup(name string) []Path {
+	key := normalizeKey(name)
+	if key == "" {
+		return nil
+	}
+	if paths, ok := t.index[key]; ok {
+		return paths
+	}
+	for _, suffix := range []string{"类别", "类"} {
+		if trimmed, ok := strings.CutSuffix(key, suffix); ok && trimmed != "" {
+			if paths, ok := t.index[trimmed]; ok {
+				return paths
+			}
+			if paths, ok := t.index[trimmed+"类"]; ok {
+				return paths
+			}
+		}
+	}
+	return t.index[key+"类"]
+}
+
+func (t *Taxonomy) orDefault() *Taxonomy {
+	if t == nil {
+		return Default()
+	}
+	return t
+}
+
+// normalizeKey 归一化名称：去掉空白并转小写
+func normalizeKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+func hasPrefix(path, prefix Path) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
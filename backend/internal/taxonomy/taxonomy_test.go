@@ -0,0 +1,79 @@
+package taxonomy
+
+import (
+	"testing"
+)
+
+func TestDefaultClassify(t *testing.T) {
+	tx := Default()
+	tests := []struct {
+		category   string
+		objectName string
+		keywords   []string
+		want       string
+	}{
+		{"自然类", "银杏", []string{"植物", "树木", "秋天"}, "自然类/植物/树木"},
+		{"自然", "玫瑰花", nil, "自然类/植物/花卉"},
+		{"生活类", "苹果", []string{"水果", "食物"}, "生活类/食物/水果"},
+		{"生活类", "汽车", nil, "生活类/交通工具"},
+		{"人文类", "钢琴", []string{"乐器", "音乐"}, "人文类/艺术/音乐"},
+		{"Nature", "蝴蝶", []string{"昆虫"}, "自然类/动物/昆虫"},
+		{"自然类别", "石头", nil, "自然类"},
+		{"", "小猫", []string{"哺乳动物"}, "自然类/动物/哺乳动物"},
+		{"未知类", "某物", nil, "生活类"},
+		// 关键词属于其他顶层类别时不跨类别归类
+		{"人文类", "苹果", []string{"水果"}, "人文类"},
+		// 类别直接给出子类路径
+		{"自然类 › 植物", "某种植物", nil, "自然类/植物"},
+	}
+	for _, tt := range tests {
+		if got := tx.Classify(tt.category, tt.objectName, tt.keywords).String(); got != tt.want {
+			t.Errorf("Classify(%q, %q, %v) = %s, want %s", tt.category, tt.objectName, tt.keywords, got, tt.want)
+		}
+	}
+}
+
+func TestPathTruncate(t *testing.T) {
+	path := ParsePath("自然类/植物/花卉")
+	if got := path.Truncate(1).String(); got != "自然类" {
+		t.Errorf("Truncate(1) = %s", got)
+	}
+	if got := path.Truncate(2).String(); got != "自然类/植物" {
+		t.Errorf("Truncate(2) = %s", got)
+	}
+	if got := path.Truncate(5).String(); got != "自然类/植物/花卉" {
+		t.Errorf("Truncate(5) = %s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tx, err := Parse([]byte(`{
+  "fallback": "其他",
+  "categories": [
+    {"name": "交通", "aliases": ["vehicle"], "children": [{"name": "飞机", "aliases": ["airplane", "客机"]}]},
+    {"name": "其他"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := tx.Classify("vehicle", "大客机", nil).String(); got != "交通/飞机" {
+		t.Errorf("Classify = %s", got)
+	}
+	if got := tx.Classify("自然类", "银杏", nil).String(); got != "其他" {
+		t.Errorf("未匹配时应使用兜底类别, got %s", got)
+	}
+
+	invalid := []string{
+		`{"categories": []}`,
+		`{"categories": [{"name": ""}]}`,
+		`{"categories": [{"name": "a"}, {"name": "a"}]}`,
+		`{"categories": [{"name": "a/b"}]}`,
+		`{"fallback": "不存在", "categories": [{"name": "a"}]}`,
+	}
+	for _, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%s) 应返回错误", data)
+		}
+	}
+}
@@ -23,34 +23,7 @@ func (a *EinoToolAdapter) Info(ctx context.Context) (*schema.ToolInfo, error) {
 	params := a.tool.Parameters()
 
 	// 转换参数定义
-	paramInfos := make(map[string]*schema.ParameterInfo)
-	if props, ok := params["properties"].(map[string]interface{}); ok {
-		for name, prop := range props {
-			if propMap, ok := prop.(map[string]interface{}); ok {
-				paramType := getString(propMap, "type", "string")
-				// 转换类型字符串到schema.DataType
-				var dataType schema.DataType
-				switch paramType {
-				case "string":
-					dataType = schema.String
-				case "number", "integer":
-					dataType = schema.Number
-				case "boolean":
-					dataType = schema.Boolean
-				default:
-					dataType = schema.String
-				}
-
-				paramInfo := &schema.ParameterInfo{
-					Type: dataType,
-				}
-				if desc, ok := propMap["description"].(string); ok {
-					paramInfo.Desc = desc
-				}
-				paramInfos[name] = paramInfo
-			}
-		}
-	}
+	paramInfos := convertProperties(params)
 
 	return &schema.ToolInfo{
 		Name:        a.tool.Name(),
@@ -86,6 +59,88 @@ func (a *EinoToolAdapter) InvokableRun(ctx context.Context, argumentsInJSON stri
 	return string(resultJSON), nil
 }
 
+// convertProperties 将JSON Schema对象的properties转换为eino参数定义（递归处理嵌套对象和数组）
+func convertProperties(objSchema map[string]interface{}) map[string]*schema.ParameterInfo {
+	paramInfos := make(map[string]*schema.ParameterInfo)
+	props, ok := objSchema["properties"].(map[string]interface{})
+	if !ok {
+		return paramInfos
+	}
+
+	required := make(map[string]bool)
+	for _, name := range getStringSlice(objSchema, "required") {
+		required[name] = true
+	}
+
+	for name, prop := range props {
+		if propMap, ok := prop.(map[string]interface{}); ok {
+			paramInfo := convertParameter(propMap)
+			paramInfo.Required = required[name]
+			paramInfos[name] = paramInfo
+		}
+	}
+	return paramInfos
+}
+
+// convertParameter 将单个JSON Schema属性转换为eino参数定义
+func convertParameter(propMap map[string]interface{}) *schema.ParameterInfo {
+	// 转换类型字符串到schema.DataType
+	var dataType schema.DataType
+	switch getString(propMap, "type", "string") {
+	case "string":
+		dataType = schema.String
+	case "number":
+		dataType = schema.Number
+	case "integer":
+		dataType = schema.Integer
+	case "boolean":
+		dataType = schema.Boolean
+	case "array":
+		dataType = schema.Array
+	case "object":
+		dataType = schema.Object
+	default:
+		dataType = schema.String
+	}
+
+	paramInfo := &schema.ParameterInfo{
+		Type: dataType,
+		Desc: getString(propMap, "description", ""),
+		Enum: getStringSlice(propMap, "enum"),
+	}
+
+	switch dataType {
+	case schema.Array:
+		if items, ok := propMap["items"].(map[string]interface{}); ok {
+			paramInfo.ElemInfo = convertParameter(items)
+		} else {
+			paramInfo.ElemInfo = &schema.ParameterInfo{Type: schema.String}
+		}
+	case schema.Object:
+		if subParams := convertProperties(propMap); len(subParams) > 0 {
+			paramInfo.SubParams = subParams
+		}
+	}
+	return paramInfo
+}
+
+// getStringSlice 读取字符串数组（兼容[]string和JSON解码得到的[]interface{}）
+func getStringSlice(m map[string]interface{}, key string) []string {
+	switch v := m[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
 // getString 辅助函数
 func getString(m map[string]interface{}, key, defaultValue string) string {
 	if v, ok := m[key].(string); ok {
@@ -3,102 +3,325 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tango/explore/internal/config"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+const (
+	// requestTimeout 单次MCP请求的默认超时
+	requestTimeout = 30 * time.Second
+
+	// 传输方式
+	transportSSE            = "sse"
+	transportStreamableHTTP = "streamable-http"
+)
+
 // MCPClient MCP客户端
+// 首次调用时完成 initialize 握手，连接断开或会话失效时自动重新握手
 type MCPClient struct {
 	config config.MCPServerConfig
 	logger logx.Logger
-	client *http.Client
+
+	mu          sync.Mutex
+	transport   transport
+	serverInfo  implementationInfo
+	initialized bool
+	nextID      atomic.Int64
 }
 
-// NewMCPClient 创建MCP客户端
+// NewMCPClient 创建MCP客户端（不立即建立连接）
 func NewMCPClient(cfg config.MCPServerConfig, logger logx.Logger) (*MCPClient, error) {
+	switch cfg.Type {
+	case "url":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("MCP服务器URL不能为空")
+		}
+		switch cfg.Transport {
+		case "", transportSSE, transportStreamableHTTP:
+		default:
+			return nil, fmt.Errorf("不支持的MCP传输方式: %s", cfg.Transport)
+		}
+	case "command":
+		if cfg.Command == "" {
+			return nil, fmt.Errorf("MCP服务器命令不能为空")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的MCP服务器类型: %s", cfg.Type)
+	}
+
 	client := &MCPClient{
 		config: cfg,
 		logger: logger,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
 	}
 
 	return client, nil
 }
 
-// CallResource 调用MCP资源
-func (c *MCPClient) CallResource(ctx context.Context, resourceName string, params map[string]interface{}) (interface{}, error) {
-	if c.config.Type != "url" || c.config.URL == "" {
-		return nil, fmt.Errorf("不支持的MCP服务器类型: %s", c.config.Type)
+// Connect 建立连接并完成握手
+func (c *MCPClient) Connect(ctx context.Context) error {
+	_, err := c.ensureInitialized(ctx)
+	return err
+}
+
+// ListTools 列出MCP服务器提供的全部工具（自动翻页）
+func (c *MCPClient) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	cursor := ""
+	for {
+		var result listToolsResult
+		if err := c.call(ctx, methodToolsList, listToolsParams{Cursor: cursor}, &result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = result.NextCursor
 	}
+}
 
-	// 构建请求
-	reqBody := map[string]interface{}{
-		"method": "resources/call",
-		"params": map[string]interface{}{
-			"resource": resourceName,
-			"arguments": params,
-		},
+// CallTool 调用MCP工具
+func (c *MCPClient) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallToolResult, error) {
+	var result CallToolResult
+	if err := c.call(ctx, methodToolsCall, callToolParams{Name: name, Arguments: arguments}, &result); err != nil {
+		return nil, err
+	}
+
+	c.logger.Infow("MCP工具调用成功",
+		logx.Field("server", c.serverLabel()),
+		logx.Field("tool", name),
+		logx.Field("isError", result.IsError),
+	)
+	return &result, nil
+}
+
+// Close 关闭连接
+func (c *MCPClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resetLocked()
+}
+
+// call 发送请求，连接断开或会话失效时重新握手并重试一次
+// 对于command类型的服务器，连接断开意味着子进程已退出，重新握手时会重启进程
+func (c *MCPClient) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
+	resp, err := c.roundTrip(ctx, method, params)
+	if errors.Is(err, errTransportClosed) || errors.Is(err, errSessionExpired) {
+		c.logger.Infow("MCP连接已失效，重新握手", logx.Field("server", c.serverLabel()), logx.Field("error", err))
+		c.mu.Lock()
+		c.resetLocked()
+		c.mu.Unlock()
+		resp, err = c.roundTrip(ctx, method, params)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
 	}
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("解析MCP %s 结果失败: %w", method, err)
+		}
+	}
+	return nil
+}
 
-	// 创建HTTP请求
-	req, err := http.NewRequestWithContext(ctx, "POST", c.config.URL, strings.NewReader(string(jsonBody)))
+// roundTrip 在已握手的连接上发送一次请求
+func (c *MCPClient) roundTrip(ctx context.Context, method string, params interface{}) (*jsonrpcMessage, error) {
+	t, err := c.ensureInitialized(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, err
 	}
+	req, err := newRequest(c.nextID.Add(1), method, params)
+	if err != nil {
+		return nil, err
+	}
+	return t.Call(ctx, req)
+}
+
+// ensureInitialized 确保连接已建立且完成握手
+func (c *MCPClient) ensureInitialized(ctx context.Context) (transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	// 设置请求头
-	req.Header.Set("Content-Type", "application/json")
-	for k, v := range c.config.Headers {
-		req.Header.Set(k, v)
+	if c.initialized {
+		return c.transport, nil
 	}
 
-	// TODO: 对于SSE端点，需要实现SSE连接处理
-	// 当前实现简单的HTTP POST请求
+	t, result, err := c.connectLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.transport = t
+	c.serverInfo = result.ServerInfo
+	c.initialized = true
+
+	c.logger.Infow("MCP服务器握手成功",
+		logx.Field("server", c.serverLabel()),
+		logx.Field("serverName", result.ServerInfo.Name),
+		logx.Field("serverVersion", result.ServerInfo.Version),
+		logx.Field("protocolVersion", result.ProtocolVersion),
+	)
+	return t, nil
+}
+
+// connectLocked 选择传输方式并完成握手
+// command类型使用stdio传输；url类型未指定传输方式时优先尝试流式HTTP，服务器返回4xx时回退到HTTP+SSE（规范要求的向后兼容方式）
+func (c *MCPClient) connectLocked(ctx context.Context) (transport, *initializeResult, error) {
+	if c.config.Type == "command" {
+		return c.handshake(ctx, newStdioTransport(c.config.Command, c.config.Args, c.config.Env, c.logger))
+	}
 
-	// 发送请求
-	resp, err := c.client.Do(req)
+	mode := c.config.Transport
+	if mode == "" && isLegacySSEURL(c.config.URL) {
+		mode = transportSSE
+	}
+
+	switch mode {
+	case transportSSE:
+		return c.handshake(ctx, newSSETransport(c.config.URL, c.config.Headers, c.logger))
+	case transportStreamableHTTP:
+		return c.handshake(ctx, newStreamableHTTPTransport(c.config.URL, c.config.Headers, c.logger))
+	}
+
+	t, result, err := c.handshake(ctx, newStreamableHTTPTransport(c.config.URL, c.config.Headers, c.logger))
+	var statusErr *httpStatusError
+	if err != nil && errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+		c.logger.Infow("流式HTTP握手失败，回退到HTTP+SSE传输",
+			logx.Field("server", c.serverLabel()),
+			logx.Field("status", statusErr.StatusCode),
+		)
+		return c.handshake(ctx, newSSETransport(c.config.URL, c.config.Headers, c.logger))
+	}
+	return t, result, err
+}
+
+// handshake 在传输层上执行 initialize 握手
+func (c *MCPClient) handshake(ctx context.Context, t transport) (transport, *initializeResult, error) {
+	if err := t.Start(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := newRequest(c.nextID.Add(1), methodInitialize, initializeParams{
+		ProtocolVersion: protocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo: implementationInfo{
+			Name:    clientName,
+			Version: clientVersion,
+		},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %w", err)
+		t.Close()
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	resp, err := t.Call(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		t.Close()
+		return nil, nil, fmt.Errorf("MCP initialize失败: %w", err)
+	}
+	if resp.Error != nil {
+		t.Close()
+		return nil, nil, fmt.Errorf("MCP initialize失败: %w", resp.Error)
 	}
 
-	// 解析响应
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+	var result initializeResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Close()
+		return nil, nil, fmt.Errorf("解析MCP initialize结果失败: %w", err)
 	}
 
-	c.logger.Infow("MCP资源调用成功",
-		logx.Field("resource", resourceName),
-		logx.Field("status", resp.StatusCode),
-	)
+	if aware, ok := t.(protocolVersionAware); ok && result.ProtocolVersion != "" {
+		aware.setProtocolVersion(result.ProtocolVersion)
+	}
 
-	return result, nil
+	if err := t.Notify(ctx, newNotification(methodInitialized)); err != nil {
+		t.Close()
+		return nil, nil, fmt.Errorf("发送MCP initialized通知失败: %w", err)
+	}
+
+	return t, &result, nil
 }
 
-// DiscoverResources 发现MCP服务器可用资源
-func (c *MCPClient) DiscoverResources(ctx context.Context) ([]string, error) {
-	// TODO: 实现资源发现逻辑
-	// 当前返回空列表，后续可以调用MCP的list_resources方法
-	return []string{}, nil
+// resetLocked 关闭当前连接，下次调用时重新握手
+func (c *MCPClient) resetLocked() error {
+	c.initialized = false
+	if c.transport == nil {
+		return nil
+	}
+	err := c.transport.Close()
+	c.transport = nil
+	return err
 }
 
+// serverLabel 日志中标识服务器的字符串
+func (c *MCPClient) serverLabel() string {
+	if c.config.Type == "command" {
+		return c.config.Command
+	}
+	return c.config.URL
+}
+
+// isLegacySSEURL 判断URL是否为HTTP+SSE端点（路径以/sse结尾）
+func isLegacySSEURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/sse")
+}
+
+// toolResultValue 将工具调用结果转换为Tool.Execute的返回值
+// 优先使用结构化结果；单个文本块若为JSON则解析后返回，否则返回文本
+func toolResultValue(result *CallToolResult) interface{} {
+	if result.StructuredContent != nil {
+		return result.StructuredContent
+	}
+
+	texts := make([]string, 0, len(result.Content))
+	for _, block := range result.Content {
+		if block.Type != "text" {
+			return map[string]interface{}{"content": result.Content}
+		}
+		texts = append(texts, block.Text)
+	}
+
+	if len(texts) == 1 {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(texts[0]), &parsed); err == nil {
+			return parsed
+		}
+		return texts[0]
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toolResultError 提取工具执行失败时的错误信息
+func toolResultError(name string, result *CallToolResult) error {
+	texts := make([]string, 0, len(result.Content))
+	for _, block := range result.Content {
+		if block.Type == "text" && block.Text != "" {
+			texts = append(texts, block.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return fmt.Errorf("MCP工具 %s 执行失败", name)
+	}
+	return fmt.Errorf("MCP工具 %s 执行失败: %s", name, strings.Join(texts, "; "))
+}
@@ -0,0 +1,611 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tango/explore/internal/config"
+	"github.com/tango/explore/internal/tools"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// fakeServer 模拟MCP服务器的协议处理部分
+type fakeServer struct {
+	initialized atomic.Int32
+	notified    atomic.Int32
+}
+
+// handle 处理一条客户端消息，通知返回nil
+func (s *fakeServer) handle(msg *jsonrpcMessage) *jsonrpcMessage {
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: msg.ID}
+	var result interface{}
+
+	switch msg.Method {
+	case methodInitialize:
+		var params initializeParams
+		json.Unmarshal(msg.Params, &params)
+		if params.ProtocolVersion != protocolVersion || params.ClientInfo.Name != clientName {
+			resp.Error = &jsonrpcError{Code: -32602, Message: "invalid initialize params"}
+			return resp
+		}
+		s.initialized.Add(1)
+		result = initializeResult{
+			ProtocolVersion: protocolVersion,
+			ServerInfo:      implementationInfo{Name: "fake", Version: "1.0"},
+		}
+	case methodInitialized:
+		s.notified.Add(1)
+		return nil
+	case methodToolsList:
+		var params listToolsParams
+		json.Unmarshal(msg.Params, &params)
+		if params.Cursor == "" {
+			result = listToolsResult{
+				Tools: []ToolInfo{{
+					Name:        "get_time",
+					Description: "获取当前时间",
+					InputSchema: map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"timezone": map[string]interface{}{"type": "string", "description": "时区"},
+						},
+						"required": []string{"timezone"},
+					},
+				}},
+				NextCursor: "page2",
+			}
+		} else {
+			result = listToolsResult{
+				Tools: []ToolInfo{{Name: "fail", InputSchema: map[string]interface{}{"type": "object"}}},
+			}
+		}
+	case methodToolsCall:
+		var params callToolParams
+		json.Unmarshal(msg.Params, &params)
+		switch params.Name {
+		case "get_time":
+			text, _ := json.Marshal(map[string]interface{}{"timezone": params.Arguments["timezone"], "hour": 9})
+			result = CallToolResult{Content: []ContentBlock{{Type: "text", Text: string(text)}}}
+		case "fail":
+			result = CallToolResult{Content: []ContentBlock{{Type: "text", Text: "boom"}}, IsError: true}
+		default:
+			resp.Error = &jsonrpcError{Code: -32602, Message: "unknown tool"}
+			return resp
+		}
+	default:
+		resp.Error = &jsonrpcError{Code: -32601, Message: "method not found"}
+		return resp
+	}
+
+	resp.Result, _ = json.Marshal(result)
+	return resp
+}
+
+// newStreamableServer 流式HTTP服务器：tools/list以SSE流返回，其余以JSON返回
+func newStreamableServer(t *testing.T, fake *fakeServer) *httptest.Server {
+	var sessions atomic.Int32
+	var mu sync.Mutex
+	active := map[string]bool{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			delete(active, r.Header.Get(headerSessionID))
+			mu.Unlock()
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var msg jsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if msg.Method == methodInitialize {
+			id := fmt.Sprintf("session-%d", sessions.Add(1))
+			mu.Lock()
+			active[id] = true
+			mu.Unlock()
+			w.Header().Set(headerSessionID, id)
+		} else {
+			mu.Lock()
+			ok := active[r.Header.Get(headerSessionID)]
+			mu.Unlock()
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if got := r.Header.Get(headerProtocolVersion); got != protocolVersion {
+				t.Errorf("协议版本请求头 = %q, 期望 %q", got, protocolVersion)
+			}
+		}
+
+		resp := fake.handle(&msg)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		body, _ := json.Marshal(resp)
+		if msg.Method == methodToolsList {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":99,\"method\":\"ping\"}\n\n")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", body)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+}
+
+// newLegacySSEServer HTTP+SSE服务器：GET建立事件流，消息POST到endpoint
+// 对根路径的POST返回405，用于验证自动探测时的回退
+func newLegacySSEServer(t *testing.T, fake *fakeServer) *httptest.Server {
+	out := make(chan []byte, 16)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, "event: endpoint\ndata: /messages?sessionId=abc\n\n")
+		flusher.Flush()
+		for {
+			select {
+			case body := <-out:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", body)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("sessionId"); got != "abc" {
+			t.Errorf("sessionId = %q, 期望 abc", got)
+		}
+		var msg jsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if resp := fake.handle(&msg); resp != nil {
+			body, _ := json.Marshal(resp)
+			out <- body
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newTestClient(t *testing.T, url, transport string) *MCPClient {
+	client, err := NewMCPClient(config.MCPServerConfig{
+		Type:      "url",
+		URL:       url,
+		Transport: transport,
+		Enabled:   true,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("创建MCP客户端失败: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// testClientRoundTrip 握手、分页列出工具、调用工具
+func testClientRoundTrip(t *testing.T, client *MCPClient, fake *fakeServer) {
+	ctx := context.Background()
+
+	list, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools失败: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "get_time" || list[1].Name != "fail" {
+		t.Fatalf("ListTools应翻页返回两个工具, 实际: %+v", list)
+	}
+	if fake.initialized.Load() != 1 || fake.notified.Load() != 1 {
+		t.Errorf("应完成一次握手, initialize=%d initialized=%d", fake.initialized.Load(), fake.notified.Load())
+	}
+
+	result, err := client.CallTool(ctx, "get_time", map[string]interface{}{"timezone": "Asia/Shanghai"})
+	if err != nil {
+		t.Fatalf("CallTool失败: %v", err)
+	}
+	want := map[string]interface{}{"timezone": "Asia/Shanghai", "hour": float64(9)}
+	if got := toolResultValue(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("工具结果 = %v, 期望 %v", got, want)
+	}
+
+	if _, err := client.CallTool(ctx, "unknown", nil); err == nil {
+		t.Error("调用不存在的工具应返回错误")
+	}
+
+	// 后续请求复用已握手的连接
+	if fake.initialized.Load() != 1 {
+		t.Errorf("不应重复握手, initialize=%d", fake.initialized.Load())
+	}
+}
+
+func TestMCPClient_StreamableHTTP(t *testing.T) {
+	fake := &fakeServer{}
+	server := newStreamableServer(t, fake)
+	t.Cleanup(server.Close)
+
+	client := newTestClient(t, server.URL+"/mcp", "")
+	testClientRoundTrip(t, client, fake)
+}
+
+func TestMCPClient_StreamableHTTPSessionExpired(t *testing.T) {
+	fake := &fakeServer{}
+	server := newStreamableServer(t, fake)
+	t.Cleanup(server.Close)
+
+	client := newTestClient(t, server.URL, transportStreamableHTTP)
+	if _, err := client.ListTools(context.Background()); err != nil {
+		t.Fatalf("ListTools失败: %v", err)
+	}
+
+	// 服务端丢弃会话后，客户端应重新握手并重试
+	client.mu.Lock()
+	client.transport.(*streamableHTTPTransport).sessionID = "stale"
+	client.mu.Unlock()
+
+	if _, err := client.CallTool(context.Background(), "get_time", map[string]interface{}{"timezone": "UTC"}); err != nil {
+		t.Fatalf("会话失效后应重新握手并重试: %v", err)
+	}
+	if fake.initialized.Load() != 2 {
+		t.Errorf("应重新握手一次, initialize=%d", fake.initialized.Load())
+	}
+}
+
+func TestMCPClient_LegacySSE(t *testing.T) {
+	fake := &fakeServer{}
+	server := newLegacySSEServer(t, fake)
+	t.Cleanup(server.Close)
+
+	client := newTestClient(t, server.URL+"/sse", "")
+	testClientRoundTrip(t, client, fake)
+}
+
+func TestMCPClient_FallbackToSSE(t *testing.T) {
+	fake := &fakeServer{}
+	server := newLegacySSEServer(t, fake)
+	t.Cleanup(server.Close)
+
+	// 地址不以/sse结尾，先尝试流式HTTP，收到405后回退到HTTP+SSE
+	client := newTestClient(t, server.URL, "")
+	testClientRoundTrip(t, client, fake)
+	if _, ok := client.transport.(*sseTransport); !ok {
+		t.Errorf("应回退到HTTP+SSE传输, 实际: %T", client.transport)
+	}
+}
+
+func TestDiscoverAndRegisterMCPTools(t *testing.T) {
+	fake := &fakeServer{}
+	server := newStreamableServer(t, fake)
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { CloseMCPClients() })
+
+	logger := logx.WithContext(context.Background())
+	registry := tools.NewToolRegistry(logger)
+	registry.Register(NewMCPToolWrapper("fail", "本地同名工具", nil, logger, nil))
+
+	err := DiscoverAndRegisterMCPTools(&config.MCPConfig{
+		Enabled: true,
+		Servers: map[string]config.MCPServerConfig{
+			"demo": {Type: "url", URL: server.URL, Enabled: true},
+		},
+	}, registry, logger)
+	if err != nil {
+		t.Fatalf("发现MCP工具失败: %v", err)
+	}
+
+	timeTool, ok := registry.GetTool("get_time")
+	if !ok {
+		t.Fatal("get_time工具未注册")
+	}
+	if timeTool.Description() != "获取当前时间" {
+		t.Errorf("工具描述 = %q", timeTool.Description())
+	}
+
+	// 参数定义应使用服务器声明的inputSchema，并正确转换为eino参数
+	infos, err := tools.ConvertToEinoTools([]tools.Tool{timeTool}, context.Background())
+	if err != nil {
+		t.Fatalf("转换eino工具失败: %v", err)
+	}
+	params, err := infos[0].ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("生成JSON Schema失败: %v", err)
+	}
+	if !reflect.DeepEqual(params.Required, []string{"timezone"}) {
+		t.Errorf("required = %v, 期望 [timezone]", params.Required)
+	}
+
+	result, err := timeTool.Execute(context.Background(), map[string]interface{}{"timezone": "UTC"})
+	if err != nil {
+		t.Fatalf("执行工具失败: %v", err)
+	}
+	if got := result.(map[string]interface{})["timezone"]; got != "UTC" {
+		t.Errorf("timezone = %v, 期望 UTC", got)
+	}
+
+	// 与已注册工具重名时加上服务器名前缀，远端工具名不变
+	failTool, ok := registry.GetTool("demo_fail")
+	if !ok {
+		t.Fatal("重名工具应以demo_fail注册")
+	}
+	if _, err := failTool.Execute(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("isError结果应转换为错误, 实际: %v", err)
+	}
+}
+
+// TestStdioServerProcess 作为stdio MCP服务器子进程运行（由stdio相关测试通过环境变量启动）
+// 调用crash工具时进程直接退出，用于验证崩溃后重启
+func TestStdioServerProcess(t *testing.T) {
+	if os.Getenv("MCP_STDIO_TEST_SERVER") != "1" {
+		return
+	}
+
+	fake := &fakeServer{}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid message:", err)
+			continue
+		}
+		if msg.Method == methodToolsCall && strings.Contains(string(msg.Params), `"crash"`) {
+			os.Exit(3)
+		}
+		if resp := fake.handle(&msg); resp != nil {
+			body, _ := json.Marshal(resp)
+			fmt.Fprintf(os.Stdout, "%s\n", body)
+		}
+	}
+	os.Exit(0)
+}
+
+func newStdioTestClient(t *testing.T) *MCPClient {
+	client, err := NewMCPClient(config.MCPServerConfig{
+		Type:    "command",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestStdioServerProcess$"},
+		Env:     map[string]string{"MCP_STDIO_TEST_SERVER": "1"},
+		Enabled: true,
+	}, logx.WithContext(context.Background()))
+	if err != nil {
+		t.Fatalf("创建MCP客户端失败: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestMCPClient_Stdio(t *testing.T) {
+	client := newStdioTestClient(t)
+	ctx := context.Background()
+
+	list, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools失败: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("ListTools应翻页返回两个工具, 实际: %+v", list)
+	}
+
+	result, err := client.CallTool(ctx, "get_time", map[string]interface{}{"timezone": "UTC"})
+	if err != nil {
+		t.Fatalf("CallTool失败: %v", err)
+	}
+	if got := toolResultValue(result).(map[string]interface{})["timezone"]; got != "UTC" {
+		t.Errorf("timezone = %v, 期望 UTC", got)
+	}
+
+	// 子进程崩溃后，下一次调用应重启进程并成功
+	first := client.transport.(*stdioTransport)
+	if _, err := client.CallTool(ctx, "crash", nil); err == nil {
+		t.Error("子进程崩溃时调用应返回错误")
+	}
+	if _, err := client.CallTool(ctx, "get_time", map[string]interface{}{"timezone": "UTC"}); err != nil {
+		t.Fatalf("子进程崩溃后应自动重启: %v", err)
+	}
+	second := client.transport.(*stdioTransport)
+	if first == second || first.cmd.Process.Pid == second.cmd.Process.Pid {
+		t.Error("崩溃后应启动新的子进程")
+	}
+
+	// 关闭客户端应结束子进程
+	if err := client.Close(); err != nil {
+		t.Fatalf("关闭客户端失败: %v", err)
+	}
+	select {
+	case <-second.exited:
+	default:
+		t.Error("关闭客户端后子进程应已退出")
+	}
+	if code := second.cmd.ProcessState.ExitCode(); code != 0 {
+		t.Errorf("子进程应在stdin关闭后正常退出, 退出码: %d", code)
+	}
+}
+
+func TestCloseMCPClients(t *testing.T) {
+	t.Cleanup(func() { CloseMCPClients() })
+
+	logger := logx.WithContext(context.Background())
+	registry := tools.NewToolRegistry(logger)
+	mcpConfig := &config.MCPConfig{
+		Enabled: true,
+		Servers: map[string]config.MCPServerConfig{
+			"local": {
+				Type:    "command",
+				Command: os.Args[0],
+				Args:    []string{"-test.run=^TestStdioServerProcess$"},
+				Env:     map[string]string{"MCP_STDIO_TEST_SERVER": "1"},
+				Enabled: true,
+			},
+		},
+	}
+
+	if err := DiscoverAndRegisterMCPTools(mcpConfig, registry, logger); err != nil {
+		t.Fatalf("发现MCP工具失败: %v", err)
+	}
+	tool, ok := registry.GetTool("get_time")
+	if !ok {
+		t.Fatal("get_time工具未注册")
+	}
+	client := tool.(*MCPToolWrapper).client
+
+	// 重复发现不应重复启动子进程
+	if err := DiscoverAndRegisterMCPTools(mcpConfig, registry, logger); err != nil {
+		t.Fatalf("发现MCP工具失败: %v", err)
+	}
+	if len(activeClients) != 1 {
+		t.Errorf("活跃客户端数量 = %d, 期望 1", len(activeClients))
+	}
+
+	client.mu.Lock()
+	proc := client.transport.(*stdioTransport)
+	client.mu.Unlock()
+
+	if err := CloseMCPClients(); err != nil {
+		t.Fatalf("关闭MCP客户端失败: %v", err)
+	}
+	select {
+	case <-proc.exited:
+	default:
+		t.Error("CloseMCPClients后子进程应已退出")
+	}
+}
+
+func TestDiscoverAndRegisterMCPTools_RetriesFailedServer(t *testing.T) {
+	t.Cleanup(func() { CloseMCPClients() })
+	current := time.Unix(1700000000, 0)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+
+	// 服务器启动时不可用，恢复后应能重新发现
+	var down atomic.Bool
+	down.Store(true)
+	backend := newStreamableServer(t, &fakeServer{})
+	t.Cleanup(backend.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	logger := logx.WithContext(context.Background())
+	registry := tools.NewToolRegistry(logger)
+	mcpConfig := &config.MCPConfig{
+		Enabled: true,
+		Servers: map[string]config.MCPServerConfig{
+			"demo": {Type: "url", URL: server.URL, Transport: "streamable-http", Enabled: true},
+		},
+	}
+
+	DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+	if _, ok := registry.GetTool("get_time"); ok {
+		t.Fatal("服务器不可用时不应注册工具")
+	}
+
+	// 未到重试时间不再尝试
+	down.Store(false)
+	DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+	if _, ok := registry.GetTool("get_time"); ok {
+		t.Fatal("未到重试时间不应重新发现")
+	}
+
+	current = current.Add(discoveryRetryInterval)
+	DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+	if _, ok := registry.GetTool("get_time"); !ok {
+		t.Fatal("到重试时间后应重新发现并注册工具")
+	}
+
+	// 发现成功后不再重复连接
+	DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+	if len(activeClients) != 1 {
+		t.Errorf("活跃客户端数量 = %d, 期望 1", len(activeClients))
+	}
+}
+
+func TestDiscoverAndRegisterMCPTools_DoesNotBlockOnSlowServer(t *testing.T) {
+	t.Cleanup(func() { CloseMCPClients() })
+
+	// 握手一直挂起的服务器，直到测试放行
+	release := make(chan struct{})
+	entered := make(chan struct{}, 1)
+	backend := newStreamableServer(t, &fakeServer{})
+	t.Cleanup(backend.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case entered <- struct{}{}:
+		default:
+		}
+		<-release
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+
+	logger := logx.WithContext(context.Background())
+	registry := tools.NewToolRegistry(logger)
+	mcpConfig := &config.MCPConfig{
+		Enabled: true,
+		Servers: map[string]config.MCPServerConfig{
+			"slow": {Type: "url", URL: server.URL, Transport: "streamable-http", Enabled: true},
+		},
+	}
+
+	firstDone := make(chan struct{})
+	go func() {
+		DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+		close(firstDone)
+	}()
+	<-entered
+
+	// 第一个调用正在等待握手，并发调用应跳过该服务器立即返回
+	secondDone := make(chan struct{})
+	go func() {
+		DiscoverAndRegisterMCPTools(mcpConfig, registry, logger)
+		close(secondDone)
+	}()
+	select {
+	case <-secondDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("并发调用不应等待正在进行的发现")
+	}
+
+	close(release)
+	<-firstDone
+	if _, ok := registry.GetTool("get_time"); !ok {
+		t.Error("慢服务器握手完成后应注册工具")
+	}
+}
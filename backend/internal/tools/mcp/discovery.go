@@ -3,13 +3,44 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/tango/explore/internal/config"
 	"github.com/tango/explore/internal/tools"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+// discoveryTimeout 单个MCP服务器工具发现（握手+tools/list）的超时
+const discoveryTimeout = 15 * time.Second
+
+// discoveryRetryInterval 工具发现失败的MCP服务器再次尝试的最短间隔
+// 避免服务器不可用时每次请求都等待连接超时
+const discoveryRetryInterval = time.Minute
+
+// discoveryState 注册表的MCP工具发现状态
+type discoveryState struct {
+	done     map[string]bool      // 已成功发现并注册工具的服务器
+	retryAt  map[string]time.Time // 发现失败的服务器及下次可重试的时间
+	inFlight map[string]bool      // 正在发现的服务器，其他调用直接跳过，不等待
+}
+
+var (
+	// clientsMu 保护发现状态和活跃客户端（不在持锁期间建立连接）
+	clientsMu sync.Mutex
+	// discovered 各注册表的MCP工具发现状态，成功的服务器不会重复建立连接、重复启动子进程
+	discovered = make(map[*tools.ToolRegistry]*discoveryState)
+	// activeClients 已创建的MCP客户端，由 CloseMCPClients 统一关闭
+	activeClients []*MCPClient
+	// now 当前时间（测试中替换）
+	now = time.Now
+)
+
 // DiscoverAndRegisterMCPTools 发现并注册MCP工具
+// 同一注册表中每个服务器只会成功发现一次；发现失败的服务器在下次调用时重试（间隔至少 discoveryRetryInterval），
+// 因此启动时的短暂故障不会让该服务器的工具在整个进程生命周期内不可用。
+// 连接和子进程启动在锁外进行，同一服务器同时只有一个调用在发现，其他并发调用跳过该服务器，不会被慢握手阻塞。
+// MCP客户端（连接、子进程）在进程内共享，需调用 CloseMCPClients 释放
 func DiscoverAndRegisterMCPTools(mcpConfig *config.MCPConfig, registry *tools.ToolRegistry, logger logx.Logger) error {
 	if !mcpConfig.Enabled {
 		logger.Info("MCP功能未启用，跳过MCP工具发现")
@@ -21,70 +52,156 @@ func DiscoverAndRegisterMCPTools(mcpConfig *config.MCPConfig, registry *tools.To
 		return nil
 	}
 
+	state, pending := claimPendingServers(mcpConfig, registry, logger)
+	if len(pending) == 0 {
+		return nil
+	}
+
 	logger.Infow("开始发现MCP工具",
 		logx.Field("server_count", len(mcpConfig.Servers)),
+		logx.Field("pending_count", len(pending)),
 	)
+	for _, serverName := range pending {
+		result, err := connectServer(serverName, mcpConfig.Servers[serverName], logger)
+		finishServer(state, serverName, result, err, registry, logger)
+	}
+	logger.Infow("MCP工具发现完成", logx.Field("server_count", len(pending)))
+	return nil
+}
 
-	// 遍历所有MCP服务器
+// claimPendingServers 选出需要发现的服务器（未发现、未在发现中、已到重试时间），并标记为发现中
+func claimPendingServers(mcpConfig *config.MCPConfig, registry *tools.ToolRegistry, logger logx.Logger) (*discoveryState, []string) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	state, ok := discovered[registry]
+	if !ok {
+		state = &discoveryState{done: make(map[string]bool), retryAt: make(map[string]time.Time), inFlight: make(map[string]bool)}
+		discovered[registry] = state
+	}
+
+	var pending []string
 	for serverName, serverConfig := range mcpConfig.Servers {
+		if state.done[serverName] || state.inFlight[serverName] {
+			continue
+		}
 		if !serverConfig.Enabled {
 			logger.Infow("跳过未启用的MCP服务器", logx.Field("server", serverName))
+			state.done[serverName] = true
 			continue
 		}
+		if retryAt, failed := state.retryAt[serverName]; failed && now().Before(retryAt) {
+			continue
+		}
+		state.inFlight[serverName] = true
+		pending = append(pending, serverName)
+	}
+	return state, pending
+}
+
+// serverConnection 单个MCP服务器的连接结果
+type serverConnection struct {
+	client      *MCPClient
+	tool        tools.Tool // 专用工具（如 tal_time），为nil时按 remoteTools 通用包装
+	remoteTools []ToolInfo
+}
+
+// connectServer 连接单个MCP服务器并获取其工具列表（网络I/O、启动子进程，不持锁）
+func connectServer(serverName string, serverConfig config.MCPServerConfig, logger logx.Logger) (*serverConnection, error) {
+	// 根据服务器名称创建对应的工具
+	if serverName == "tal_time" {
+		tool, err := NewTalTimeTool(serverConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("创建tal_time工具失败: %w", err)
+		}
+		return &serverConnection{client: tool.(*TalTimeTool).client, tool: tool}, nil
+	}
+
+	// 对于其他MCP服务器，尝试通用包装
+	client, err := NewMCPClient(serverConfig, logger)
+	if err != nil {
+		return nil, fmt.Errorf("创建MCP客户端失败: %w", err)
+	}
+
+	// 通过 tools/list 发现服务器提供的工具
+	discoverCtx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	remoteTools, err := client.ListTools(discoverCtx)
+	cancel()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("获取工具列表失败: %w", err)
+	}
+	return &serverConnection{client: client, remoteTools: remoteTools}, nil
+}
+
+// finishServer 记录服务器的发现结果，成功时注册其工具
+// 发现期间调用了 CloseMCPClients 时关闭新建的客户端，不再注册
+func finishServer(state *discoveryState, serverName string, conn *serverConnection, err error, registry *tools.ToolRegistry, logger logx.Logger) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
 
-		// 根据服务器名称创建对应的工具
-		switch serverName {
-		case "tal_time":
-			tool, err := NewTalTimeTool(serverConfig, logger)
-			if err != nil {
-				logger.Errorw("创建tal_time工具失败",
-					logx.Field("error", err),
-				)
-				continue
-			}
-			registry.Register(tool)
-			logger.Infow("已注册MCP工具", logx.Field("tool", "tal_time"))
-
-		default:
-			// 对于其他MCP服务器，尝试通用包装
-			client, err := NewMCPClient(serverConfig, logger)
-			if err != nil {
-				logger.Errorw("创建MCP客户端失败",
-					logx.Field("server", serverName),
-					logx.Field("error", err),
-				)
-				continue
-			}
-
-			// 尝试发现资源
-			resources, err := client.DiscoverResources(context.Background())
-			if err != nil {
-				logger.Errorw("发现MCP资源失败",
-					logx.Field("server", serverName),
-					logx.Field("error", err),
-				)
-				// 继续处理，即使发现失败
-			}
-
-			// 为每个资源创建工具包装器
-			for _, resourceName := range resources {
-				wrapper := NewMCPToolWrapper(
-					resourceName,
-					fmt.Sprintf("MCP资源: %s/%s", serverName, resourceName),
-					client,
-					logger,
-					nil, // 使用默认参数定义
-				)
-				registry.Register(wrapper)
-				logger.Infow("已注册MCP工具",
-					logx.Field("tool", resourceName),
-					logx.Field("server", serverName),
-				)
-			}
+	delete(state.inFlight, serverName)
+	if discovered[registry] != state {
+		if conn != nil {
+			conn.client.Close()
 		}
+		return
+	}
+	if err != nil {
+		logger.Errorw("发现MCP工具失败，稍后重试",
+			logx.Field("server", serverName),
+			logx.Field("retryAfter", discoveryRetryInterval),
+			logx.Field("error", err),
+		)
+		state.retryAt[serverName] = now().Add(discoveryRetryInterval)
+		return
 	}
 
-	logger.Info("MCP工具发现完成")
-	return nil
+	activeClients = append(activeClients, conn.client)
+	state.done[serverName] = true
+	delete(state.retryAt, serverName)
+
+	if conn.tool != nil {
+		registry.Register(conn.tool)
+		logger.Infow("已注册MCP工具", logx.Field("tool", conn.tool.Name()))
+		return
+	}
+
+	// 为每个工具创建包装器，参数定义使用服务器声明的inputSchema
+	for _, info := range conn.remoteTools {
+		name := info.Name
+		if _, exists := registry.GetTool(name); exists {
+			// 与已注册工具重名时加上服务器名前缀
+			name = serverName + "_" + info.Name
+		}
+		description := info.Description
+		if description == "" {
+			description = fmt.Sprintf("MCP工具: %s/%s", serverName, info.Name)
+		}
+
+		wrapper := newMCPToolWrapper(name, info.Name, description, conn.client, logger, info.InputSchema)
+		registry.Register(wrapper)
+		logger.Infow("已注册MCP工具",
+			logx.Field("tool", name),
+			logx.Field("server", serverName),
+		)
+	}
+}
+
+// CloseMCPClients 关闭所有MCP客户端（断开连接、结束stdio子进程）
+// 关闭后再次调用 DiscoverAndRegisterMCPTools 会重新发现
+func CloseMCPClients() error {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	var firstErr error
+	for _, client := range activeClients {
+		if err := client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	activeClients = nil
+	discovered = make(map[*tools.ToolRegistry]*discoveryState)
+	return firstErr
 }
 
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// jsonrpcVersion JSON-RPC协议版本
+	jsonrpcVersion = "2.0"
+	// protocolVersion 客户端支持的最新MCP协议版本
+	protocolVersion = "2025-03-26"
+	// clientName 客户端名称（initialize握手时上报）
+	clientName = "tango-explore"
+	// clientVersion 客户端版本
+	clientVersion = "1.0.0"
+)
+
+// MCP方法名
+const (
+	methodInitialize  = "initialize"
+	methodInitialized = "notifications/initialized"
+	methodPing        = "ping"
+	methodToolsList   = "tools/list"
+	methodToolsCall   = "tools/call"
+)
+
+// jsonrpcMessage JSON-RPC消息（请求、通知、响应共用）
+// 请求带ID和Method，通知只有Method，响应带ID和Result/Error
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+// jsonrpcError JSON-RPC错误
+type jsonrpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error 实现error接口
+func (e *jsonrpcError) Error() string {
+	return fmt.Sprintf("MCP错误 %d: %s", e.Code, e.Message)
+}
+
+// isResponse 是否为响应消息
+func (m *jsonrpcMessage) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+// isRequest 是否为服务端发起的请求（需要回复）
+func (m *jsonrpcMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// idKey 返回用于匹配请求与响应的ID键
+func (m *jsonrpcMessage) idKey() string {
+	return string(m.ID)
+}
+
+// newRequest 创建JSON-RPC请求
+func newRequest(id int64, method string, params interface{}) (*jsonrpcMessage, error) {
+	msg := &jsonrpcMessage{
+		JSONRPC: jsonrpcVersion,
+		ID:      json.RawMessage(strconv.FormatInt(id, 10)),
+		Method:  method,
+	}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("序列化请求参数失败: %w", err)
+		}
+		msg.Params = raw
+	}
+	return msg, nil
+}
+
+// newNotification 创建JSON-RPC通知
+func newNotification(method string) *jsonrpcMessage {
+	return &jsonrpcMessage{
+		JSONRPC: jsonrpcVersion,
+		Method:  method,
+	}
+}
+
+// newEmptyResult 创建空结果响应（用于回复服务端的ping）
+func newEmptyResult(id json.RawMessage) *jsonrpcMessage {
+	return &jsonrpcMessage{
+		JSONRPC: jsonrpcVersion,
+		ID:      id,
+		Result:  json.RawMessage("{}"),
+	}
+}
+
+// decodeMessages 解析单条或批量JSON-RPC消息
+func decodeMessages(data []byte) ([]*jsonrpcMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []*jsonrpcMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("解析JSON-RPC批量消息失败: %w", err)
+		}
+		return batch, nil
+	}
+
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("解析JSON-RPC消息失败: %w", err)
+	}
+	return []*jsonrpcMessage{&msg}, nil
+}
+
+// initializeParams initialize请求参数
+type initializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      implementationInfo     `json:"clientInfo"`
+}
+
+// implementationInfo 客户端/服务端实现信息
+type implementationInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// initializeResult initialize响应
+type initializeResult struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ServerInfo      implementationInfo     `json:"serverInfo"`
+	Instructions    string                 `json:"instructions,omitempty"`
+}
+
+// ToolInfo MCP服务器提供的工具定义
+type ToolInfo struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// listToolsParams tools/list请求参数
+type listToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// listToolsResult tools/list响应
+type listToolsResult struct {
+	Tools      []ToolInfo `json:"tools"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// callToolParams tools/call请求参数
+type callToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// ContentBlock 工具调用结果中的内容块
+type ContentBlock struct {
+	Type     string `json:"type"`               // text/image/audio/resource
+	Text     string `json:"text,omitempty"`     // type为text时的文本
+	Data     string `json:"data,omitempty"`     // type为image/audio时的base64数据
+	MimeType string `json:"mimeType,omitempty"` // 媒体类型
+}
+
+// CallToolResult tools/call响应
+type CallToolResult struct {
+	Content           []ContentBlock `json:"content"`
+	StructuredContent interface{}    `json:"structuredContent,omitempty"`
+	IsError           bool           `json:"isError,omitempty"`
+}
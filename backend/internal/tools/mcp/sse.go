@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// sseEvent Server-Sent Events事件
+type sseEvent struct {
+	Event string // 事件类型，未指定时为 "message"
+	Data  string // 事件数据（多行data以换行拼接）
+	ID    string // 事件ID
+}
+
+// sseReader Server-Sent Events流解析器
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+// newSSEReader 创建SSE解析器
+func newSSEReader(r io.Reader) *sseReader {
+	scanner := bufio.NewScanner(r)
+	// 工具结果可能较大，放宽单行长度限制
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	return &sseReader{scanner: scanner}
+}
+
+// Next 读取下一个事件，流结束时返回io.EOF
+func (r *sseReader) Next() (*sseEvent, error) {
+	var event sseEvent
+	var data []string
+	hasField := false
+
+	for r.scanner.Scan() {
+		line := strings.TrimSuffix(r.scanner.Text(), "\r")
+
+		// 空行表示事件结束
+		if line == "" {
+			if !hasField {
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			if event.Event == "" {
+				event.Event = "message"
+			}
+			return &event, nil
+		}
+
+		// 注释行
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		hasField = true
+
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	// 流结束时仍有未分发的事件
+	if hasField && len(data) > 0 {
+		event.Data = strings.Join(data, "\n")
+		if event.Event == "" {
+			event.Event = "message"
+		}
+		return &event, nil
+	}
+	return nil, io.EOF
+}
@@ -2,6 +2,9 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/tango/explore/internal/config"
@@ -14,6 +17,9 @@ import (
 type TalTimeTool struct {
 	client *MCPClient
 	logger logx.Logger
+
+	mu         sync.Mutex
+	remoteName string // 服务器上的时间工具名称，首次调用时通过 tools/list 确定
 }
 
 // NewTalTimeTool 创建tal_time工具实例
@@ -52,9 +58,9 @@ func (t *TalTimeTool) Parameters() map[string]interface{} {
 func (t *TalTimeTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 	t.logger.Info("执行tal_time工具")
 
-	// 尝试调用MCP资源
+	// 尝试调用MCP工具
 	if t.client != nil {
-		result, err := t.client.CallResource(ctx, "time", params)
+		result, err := t.callRemote(ctx, params)
 		if err == nil {
 			return result, nil
 		}
@@ -80,6 +86,51 @@ func (t *TalTimeTool) Execute(ctx context.Context, params map[string]interface{}
 	}, nil
 }
 
+// callRemote 调用MCP服务器上的时间工具
+func (t *TalTimeTool) callRemote(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	name, err := t.resolveRemoteName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.client.CallTool(ctx, name, params)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, toolResultError(name, result)
+	}
+	return toolResultValue(result), nil
+}
+
+// resolveRemoteName 确定服务器上的时间工具名称
+// 优先选择名称包含time的工具，否则使用第一个工具
+func (t *TalTimeTool) resolveRemoteName(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.remoteName != "" {
+		return t.remoteName, nil
+	}
+
+	remoteTools, err := t.client.ListTools(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(remoteTools) == 0 {
+		return "", fmt.Errorf("MCP服务器未提供任何工具")
+	}
+
+	t.remoteName = remoteTools[0].Name
+	for _, info := range remoteTools {
+		if strings.Contains(strings.ToLower(info.Name), "time") {
+			t.remoteName = info.Name
+			break
+		}
+	}
+	return t.remoteName, nil
+}
+
 // getWeekdayCN 获取中文星期
 func getWeekdayCN(weekday time.Weekday) string {
 	weekdays := map[time.Weekday]string{
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// errTransportClosed 连接已关闭（需要重新建立连接并重新握手）
+	errTransportClosed = errors.New("MCP连接已关闭")
+	// errSessionExpired 服务端会话已失效（需要重新握手）
+	errSessionExpired = errors.New("MCP会话已失效")
+)
+
+// transport MCP传输层接口
+// 负责JSON-RPC消息的收发，协议握手由 MCPClient 完成
+type transport interface {
+	// Start 建立连接
+	Start(ctx context.Context) error
+	// Call 发送请求并等待对应ID的响应
+	Call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error)
+	// Notify 发送通知（无响应）
+	Notify(ctx context.Context, msg *jsonrpcMessage) error
+	// Close 关闭连接
+	Close() error
+}
+
+// protocolVersionAware 需要在后续请求中携带协商后协议版本的传输层
+type protocolVersionAware interface {
+	setProtocolVersion(version string)
+}
+
+// httpStatusError HTTP状态码错误
+type httpStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error 实现error接口
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("MCP服务器返回HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// pendingCalls 等待响应的请求表
+// 用于基于消息流的传输层（SSE、stdio），按ID将响应分发给等待的调用方
+type pendingCalls struct {
+	mu     sync.Mutex
+	calls  map[string]chan *jsonrpcMessage
+	closed error
+}
+
+// newPendingCalls 创建请求表
+func newPendingCalls() *pendingCalls {
+	return &pendingCalls{
+		calls: make(map[string]chan *jsonrpcMessage),
+	}
+}
+
+// add 登记等待响应的请求
+func (p *pendingCalls) add(key string) (chan *jsonrpcMessage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed != nil {
+		return nil, p.closed
+	}
+	ch := make(chan *jsonrpcMessage, 1)
+	p.calls[key] = ch
+	return ch, nil
+}
+
+// remove 移除请求
+func (p *pendingCalls) remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.calls, key)
+}
+
+// resolve 分发响应，返回是否找到对应请求
+func (p *pendingCalls) resolve(msg *jsonrpcMessage) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch, ok := p.calls[msg.idKey()]
+	if !ok {
+		return false
+	}
+	delete(p.calls, msg.idKey())
+	ch <- msg
+	return true
+}
+
+// closeAll 连接断开时关闭请求表，之后的 add 返回错误
+func (p *pendingCalls) closeAll(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed != nil {
+		return
+	}
+	p.closed = err
+	for key, ch := range p.calls {
+		close(ch)
+		delete(p.calls, key)
+	}
+}
+
+// err 返回关闭原因
+func (p *pendingCalls) err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// wait 等待响应
+func (p *pendingCalls) wait(ctx context.Context, ch chan *jsonrpcMessage) (*jsonrpcMessage, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case resp, ok := <-ch:
+		if !ok {
+			if err := p.err(); err != nil {
+				return nil, err
+			}
+			return nil, errTransportClosed
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// headerSessionID 流式HTTP传输的会话ID请求头
+	headerSessionID = "Mcp-Session-Id"
+	// headerProtocolVersion 协商后的协议版本请求头
+	headerProtocolVersion = "MCP-Protocol-Version"
+)
+
+// streamableHTTPTransport 流式HTTP传输（MCP 2025-03-26）
+// 每个请求单独POST到服务器地址，响应为JSON或SSE流；会话由 Mcp-Session-Id 头维持
+type streamableHTTPTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	logger  logx.Logger
+
+	mu              sync.RWMutex
+	sessionID       string
+	protocolVersion string
+}
+
+// newStreamableHTTPTransport 创建流式HTTP传输
+func newStreamableHTTPTransport(serverURL string, headers map[string]string, logger logx.Logger) *streamableHTTPTransport {
+	return &streamableHTTPTransport{
+		url:     serverURL,
+		headers: headers,
+		// 响应可能是SSE流，不设置整体超时，单次请求的超时由ctx控制
+		client: &http.Client{},
+		logger: logger,
+	}
+}
+
+// Start 流式HTTP无需预先建立连接
+func (t *streamableHTTPTransport) Start(ctx context.Context) error {
+	return nil
+}
+
+// Call 发送请求并等待响应
+func (t *streamableHTTPTransport) Call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, t.statusError(resp)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("读取MCP响应失败: %w", err)
+		}
+		msgs, err := decodeMessages(body)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg.isResponse() && msg.idKey() == req.idKey() {
+				return msg, nil
+			}
+		}
+		return nil, fmt.Errorf("MCP响应中缺少请求 %s 的结果", req.idKey())
+	case "text/event-stream":
+		return t.readStream(ctx, resp.Body, req)
+	default:
+		return nil, fmt.Errorf("不支持的MCP响应类型: %s", resp.Header.Get("Content-Type"))
+	}
+}
+
+// Notify 发送通知
+func (t *streamableHTTPTransport) Notify(ctx context.Context, msg *jsonrpcMessage) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return t.statusError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close 结束服务端会话（尽力而为）
+func (t *streamableHTTPTransport) Close() error {
+	t.mu.RLock()
+	sessionID := t.sessionID
+	t.mu.RUnlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return nil
+	}
+	t.setHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// setProtocolVersion 记录协商后的协议版本，后续请求携带
+func (t *streamableHTTPTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolVersion = version
+}
+
+// post 发送JSON-RPC消息
+func (t *streamableHTTPTransport) post(ctx context.Context, msg *jsonrpcMessage) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("序列化MCP消息失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建MCP请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送MCP请求失败: %w", err)
+	}
+
+	// 服务端在initialize响应中分配会话ID
+	if sessionID := resp.Header.Get(headerSessionID); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// setHeaders 设置自定义请求头、会话ID和协议版本
+func (t *streamableHTTPTransport) setHeaders(req *http.Request) {
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+}
+
+// statusError 将非成功状态码转换为错误；携带会话ID时返回404表示会话已失效
+func (t *streamableHTTPTransport) statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	t.mu.Lock()
+	hadSession := t.sessionID != ""
+	if resp.StatusCode == http.StatusNotFound && hadSession {
+		t.sessionID = ""
+	}
+	t.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotFound && hadSession {
+		return errSessionExpired
+	}
+	return &httpStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// readStream 从SSE响应流中读取目标请求的响应
+func (t *streamableHTTPTransport) readStream(ctx context.Context, body io.Reader, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	reader := newSSEReader(body)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		event, err := reader.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("MCP响应流在返回结果前结束")
+			}
+			return nil, fmt.Errorf("读取MCP响应流失败: %w", err)
+		}
+		if event.Event != "message" {
+			continue
+		}
+
+		msgs, err := decodeMessages([]byte(event.Data))
+		if err != nil {
+			t.logger.Errorw("解析MCP SSE消息失败", logx.Field("error", err))
+			continue
+		}
+		for _, msg := range msgs {
+			switch {
+			case msg.isResponse() && msg.idKey() == req.idKey():
+				return msg, nil
+			case msg.isRequest() && msg.Method == methodPing:
+				go t.replyPing(msg.ID)
+			}
+		}
+	}
+}
+
+// replyPing 回复服务端的ping请求
+func (t *streamableHTTPTransport) replyPing(id json.RawMessage) {
+	resp, err := t.post(context.Background(), newEmptyResult(id))
+	if err != nil {
+		t.logger.Errorw("回复MCP ping失败", logx.Field("error", err))
+		return
+	}
+	resp.Body.Close()
+}
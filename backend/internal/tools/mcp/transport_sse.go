@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// sseTransport HTTP+SSE传输（MCP 2024-11-05）
+// 客户端通过GET建立SSE长连接，服务端先发送endpoint事件告知消息提交地址，
+// 之后客户端将请求POST到该地址，响应通过SSE连接的message事件返回
+type sseTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	logger  logx.Logger
+
+	pending  *pendingCalls
+	mu       sync.RWMutex
+	endpoint string
+	cancel   context.CancelFunc
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newSSETransport 创建HTTP+SSE传输
+func newSSETransport(serverURL string, headers map[string]string, logger logx.Logger) *sseTransport {
+	return &sseTransport{
+		url:     serverURL,
+		headers: headers,
+		// SSE是长连接，不设置整体超时，单次请求的超时由ctx控制
+		client:  &http.Client{},
+		logger:  logger,
+		pending: newPendingCalls(),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start 建立SSE连接并等待endpoint事件
+func (t *sseTransport) Start(ctx context.Context) error {
+	// SSE连接的生命周期与传输层一致，不能跟随Start的ctx结束
+	streamCtx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, t.url, nil)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("创建SSE请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("建立SSE连接失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		cancel()
+		return &httpStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	endpointCh := make(chan string, 1)
+	go t.readLoop(resp.Body, endpointCh)
+
+	select {
+	case endpoint := <-endpointCh:
+		resolved, err := t.resolveEndpoint(endpoint)
+		if err != nil {
+			t.Close()
+			return err
+		}
+		t.mu.Lock()
+		t.endpoint = resolved
+		t.mu.Unlock()
+		return nil
+	case <-t.done:
+		return t.pending.err()
+	case <-ctx.Done():
+		t.Close()
+		return fmt.Errorf("等待SSE endpoint事件超时: %w", ctx.Err())
+	}
+}
+
+// Call 发送请求并等待响应
+func (t *sseTransport) Call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	ch, err := t.pending.add(req.idKey())
+	if err != nil {
+		return nil, err
+	}
+	defer t.pending.remove(req.idKey())
+
+	if err := t.post(ctx, req); err != nil {
+		return nil, err
+	}
+	return t.pending.wait(ctx, ch)
+}
+
+// Notify 发送通知
+func (t *sseTransport) Notify(ctx context.Context, msg *jsonrpcMessage) error {
+	return t.post(ctx, msg)
+}
+
+// Close 关闭SSE连接
+func (t *sseTransport) Close() error {
+	t.once.Do(func() {
+		if t.cancel != nil {
+			t.cancel()
+		}
+		t.pending.closeAll(errTransportClosed)
+	})
+	return nil
+}
+
+// readLoop 读取SSE事件，分发响应
+func (t *sseTransport) readLoop(body io.ReadCloser, endpointCh chan<- string) {
+	defer close(t.done)
+	defer body.Close()
+
+	reader := newSSEReader(body)
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			t.pending.closeAll(fmt.Errorf("%w: %v", errTransportClosed, err))
+			return
+		}
+
+		switch event.Event {
+		case "endpoint":
+			select {
+			case endpointCh <- strings.TrimSpace(event.Data):
+			default:
+			}
+		case "message":
+			msgs, err := decodeMessages([]byte(event.Data))
+			if err != nil {
+				t.logger.Errorw("解析MCP SSE消息失败", logx.Field("error", err))
+				continue
+			}
+			for _, msg := range msgs {
+				t.dispatch(msg)
+			}
+		}
+	}
+}
+
+// dispatch 处理服务端消息：响应交给等待方，ping请求直接回复，其余忽略
+func (t *sseTransport) dispatch(msg *jsonrpcMessage) {
+	switch {
+	case msg.isResponse():
+		t.pending.resolve(msg)
+	case msg.isRequest() && msg.Method == methodPing:
+		go func() {
+			if err := t.post(context.Background(), newEmptyResult(msg.ID)); err != nil {
+				t.logger.Errorw("回复MCP ping失败", logx.Field("error", err))
+			}
+		}()
+	}
+}
+
+// post 将消息POST到endpoint
+func (t *sseTransport) post(ctx context.Context, msg *jsonrpcMessage) error {
+	t.mu.RLock()
+	endpoint := t.endpoint
+	t.mu.RUnlock()
+	if endpoint == "" {
+		return errTransportClosed
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化MCP消息失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建MCP请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送MCP请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &httpStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// resolveEndpoint 解析endpoint地址（可能是相对路径），并要求与SSE地址同源
+func (t *sseTransport) resolveEndpoint(endpoint string) (string, error) {
+	base, err := url.Parse(t.url)
+	if err != nil {
+		return "", fmt.Errorf("解析MCP服务器地址失败: %w", err)
+	}
+	ref, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("解析MCP endpoint失败: %w", err)
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		return "", fmt.Errorf("MCP endpoint与服务器地址不同源: %s", resolved.String())
+	}
+	return resolved.String(), nil
+}
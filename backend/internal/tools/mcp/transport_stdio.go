@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// stdioStopTimeout 关闭stdin后等待子进程自行退出的时间，超时后依次发送SIGTERM、SIGKILL
+	stdioStopTimeout = 2 * time.Second
+	// stdioMaxMessageSize 单条stdio消息的最大长度
+	stdioMaxMessageSize = 4 * 1024 * 1024
+)
+
+// stdioTransport stdio传输
+// 启动配置的命令作为子进程，通过stdin/stdout收发换行分隔的JSON-RPC消息，stderr输出记入日志
+type stdioTransport struct {
+	command string
+	args    []string
+	env     map[string]string
+	logger  logx.Logger
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+	pending *pendingCalls
+	exited  chan struct{}
+	once    sync.Once
+}
+
+// newStdioTransport 创建stdio传输
+func newStdioTransport(command string, args []string, env map[string]string, logger logx.Logger) *stdioTransport {
+	return &stdioTransport{
+		command: command,
+		args:    args,
+		env:     env,
+		logger:  logger,
+		pending: newPendingCalls(),
+		exited:  make(chan struct{}),
+	}
+}
+
+// Start 启动子进程
+func (t *stdioTransport) Start(ctx context.Context) error {
+	// 子进程的生命周期与传输层一致，不能跟随Start的ctx结束
+	cmd := exec.Command(t.command, t.args...)
+	cmd.Env = os.Environ()
+	for k, v := range t.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stderr = &stderrLogger{logger: t.logger, command: t.command}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("创建MCP进程stdin失败: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("创建MCP进程stdout失败: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动MCP服务器进程失败: %w", err)
+	}
+
+	t.cmd = cmd
+	t.stdin = stdin
+	t.logger.Infow("MCP服务器进程已启动",
+		logx.Field("command", t.command),
+		logx.Field("pid", cmd.Process.Pid),
+	)
+
+	go t.readLoop(stdout)
+	return nil
+}
+
+// Call 发送请求并等待响应
+func (t *stdioTransport) Call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	ch, err := t.pending.add(req.idKey())
+	if err != nil {
+		return nil, err
+	}
+	defer t.pending.remove(req.idKey())
+
+	if err := t.write(req); err != nil {
+		return nil, err
+	}
+	return t.pending.wait(ctx, ch)
+}
+
+// Notify 发送通知
+func (t *stdioTransport) Notify(ctx context.Context, msg *jsonrpcMessage) error {
+	return t.write(msg)
+}
+
+// Close 关闭子进程：先关闭stdin让服务器自行退出，超时后发送SIGTERM，仍未退出则强制结束
+func (t *stdioTransport) Close() error {
+	t.once.Do(func() {
+		t.pending.closeAll(errTransportClosed)
+		if t.cmd == nil {
+			return
+		}
+
+		t.stdin.Close()
+		if t.waitExit(stdioStopTimeout) {
+			return
+		}
+
+		t.logger.Infow("MCP服务器进程未在关闭stdin后退出，发送SIGTERM", logx.Field("command", t.command))
+		if err := t.cmd.Process.Signal(syscall.SIGTERM); err == nil && t.waitExit(stdioStopTimeout) {
+			return
+		}
+
+		t.logger.Errorw("MCP服务器进程未响应SIGTERM，强制结束", logx.Field("command", t.command))
+		t.cmd.Process.Kill()
+		<-t.exited
+	})
+	return nil
+}
+
+// waitExit 等待子进程退出，返回是否在超时前退出
+func (t *stdioTransport) waitExit(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-t.exited:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// readLoop 逐行读取stdout并分发消息，stdout关闭（进程退出）后回收进程
+func (t *stdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), stdioMaxMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		msgs, err := decodeMessages(line)
+		if err != nil {
+			t.logger.Errorw("解析MCP stdio消息失败",
+				logx.Field("command", t.command),
+				logx.Field("error", err),
+			)
+			continue
+		}
+		for _, msg := range msgs {
+			t.dispatch(msg)
+		}
+	}
+
+	// 必须在stdout读取结束后再调用Wait
+	err := t.cmd.Wait()
+	close(t.exited)
+
+	reason := fmt.Errorf("%w: MCP服务器进程已退出", errTransportClosed)
+	if err != nil {
+		reason = fmt.Errorf("%w: MCP服务器进程已退出: %v", errTransportClosed, err)
+	}
+	t.pending.closeAll(reason)
+	t.logger.Infow("MCP服务器进程已退出",
+		logx.Field("command", t.command),
+		logx.Field("error", err),
+	)
+}
+
+// dispatch 处理服务端消息：响应交给等待方，ping请求直接回复，其余忽略
+func (t *stdioTransport) dispatch(msg *jsonrpcMessage) {
+	switch {
+	case msg.isResponse():
+		t.pending.resolve(msg)
+	case msg.isRequest() && msg.Method == methodPing:
+		if err := t.write(newEmptyResult(msg.ID)); err != nil {
+			t.logger.Errorw("回复MCP ping失败", logx.Field("error", err))
+		}
+	}
+}
+
+// write 写入一条消息（以换行结尾）
+func (t *stdioTransport) write(msg *jsonrpcMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化MCP消息失败: %w", err)
+	}
+	body = append(body, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if t.stdin == nil {
+		return errTransportClosed
+	}
+	if _, err := t.stdin.Write(body); err != nil {
+		return fmt.Errorf("%w: 写入MCP进程stdin失败: %v", errTransportClosed, err)
+	}
+	return nil
+}
+
+// stderrLogger 将子进程stderr输出按行写入日志
+type stderrLogger struct {
+	logger  logx.Logger
+	command string
+	buf     []byte
+}
+
+// Write 实现io.Writer接口
+func (w *stderrLogger) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		if line := strings.TrimSpace(string(w.buf[:idx])); line != "" {
+			w.logger.Infow("MCP服务器stderr", logx.Field("command", w.command), logx.Field("line", line))
+		}
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
@@ -9,9 +9,10 @@ import (
 )
 
 // MCPToolWrapper MCP工具包装器
-// 将MCP资源包装为Tool接口实现
+// 将MCP服务器通过 tools/list 发现的工具包装为Tool接口实现
 type MCPToolWrapper struct {
-	name        string
+	name        string // 注册到工具注册表的名称
+	remoteName  string // MCP服务器上的工具名称
 	description string
 	client      *MCPClient
 	logger      logx.Logger
@@ -20,8 +21,14 @@ type MCPToolWrapper struct {
 
 // NewMCPToolWrapper 创建MCP工具包装器
 func NewMCPToolWrapper(name string, description string, client *MCPClient, logger logx.Logger, parameters map[string]interface{}) tools.Tool {
+	return newMCPToolWrapper(name, name, description, client, logger, parameters)
+}
+
+// newMCPToolWrapper 创建MCP工具包装器（注册名称与远端工具名称可以不同）
+func newMCPToolWrapper(name, remoteName, description string, client *MCPClient, logger logx.Logger, parameters map[string]interface{}) *MCPToolWrapper {
 	return &MCPToolWrapper{
 		name:        name,
+		remoteName:  remoteName,
 		description: description,
 		client:      client,
 		logger:      logger,
@@ -63,16 +70,19 @@ func (t *MCPToolWrapper) Execute(ctx context.Context, params map[string]interfac
 		logx.Field("params", params),
 	)
 
-	// 调用MCP资源
-	result, err := t.client.CallResource(ctx, t.name, params)
+	// 调用MCP工具
+	result, err := t.client.CallTool(ctx, t.remoteName, params)
 	if err != nil {
-		t.logger.Errorw("MCP资源调用失败",
+		t.logger.Errorw("MCP工具调用失败",
 			logx.Field("tool", t.name),
 			logx.Field("error", err),
 		)
 		return nil, err
 	}
+	if result.IsError {
+		return nil, toolResultError(t.remoteName, result)
+	}
 
-	return result, nil
+	return toolResultValue(result), nil
 }
 
@@ -12,6 +12,7 @@ type SupervisorState struct {
 	RecentOutputLength int                    `json:"recentOutputLength"` // 最近输出长度（字符数）
 	AgentResults       map[string]interface{} `json:"agentResults"`      // 子Agent的返回结果
 	SessionId          string                 `json:"sessionId"`         // 会话ID
+	LearnerProfile     *LearnerProfile        `json:"learnerProfile,optional"` // 孩子的学习档案（跨会话，可选）
 }
 
 // FollowUpIntentResult 多Agent系统意图识别结果（区别于原有的IntentResult）
@@ -67,6 +68,28 @@ type MemoryRecord struct {
 	UpdatedAt         time.Time `json:"updatedAt"`         // 更新时间
 }
 
+// LearnerProfile 孩子的学习档案，按learnerId跨会话合并记忆记录
+type LearnerProfile struct {
+	LearnerId          string    `json:"learnerId"`          // 孩子标识
+	UserAge            int       `json:"userAge,optional"`   // 最近一次对话的年龄
+	InterestedTopics   []string  `json:"interestedTopics"`   // 感兴趣的主题（识别对象）
+	UnderstoodTopics   []string  `json:"understoodTopics"`   // 已理解的主题（识别对象），避免重复讲解
+	UnderstoodPoints   []string  `json:"understoodPoints"`   // 已理解的点（回答摘要）
+	UnunderstoodPoints []string  `json:"ununderstoodPoints"` // 未理解的点（回答摘要），需要换种方式解释
+	SessionIds         []string  `json:"sessionIds"`         // 合并过的会话（最近的在后）
+	CreatedAt          time.Time `json:"createdAt"`          // 创建时间
+	UpdatedAt          time.Time `json:"updatedAt"`          // 更新时间
+}
+
+// ContextSummary 较早对话的滚动摘要，保存在会话数据中
+type ContextSummary struct {
+	Summary            string    `json:"summary"`            // 摘要内容
+	LastMessageId      string    `json:"lastMessageId"`      // 已压缩进摘要的最后一条消息ID
+	LastMessageTime    string    `json:"lastMessageTime"`    // 已压缩进摘要的最后一条消息的时间戳，该消息被存储上限裁剪后用于跳过已压缩的消息
+	SummarizedMessages int       `json:"summarizedMessages"` // 已压缩的消息总数
+	UpdatedAt          time.Time `json:"updatedAt"`          // 更新时间
+}
+
 // GraphExecutionState Graph执行状态
 type GraphExecutionState struct {
 	CurrentNode        string                 `json:"currentNode"`        // 当前执行的Agent节点
@@ -75,5 +98,16 @@ type GraphExecutionState struct {
 	ErrorState         string                 `json:"errorState,optional"` // 错误状态（可选）
 	StartTime          time.Time               `json:"startTime"`          // 开始时间
 	EndTime            *time.Time              `json:"endTime,optional"`    // 结束时间（可选）
+	Steps              []AgentStep             `json:"steps"`              // 各节点的执行步骤（开始、结束、决策和耗时）
+}
+
+// AgentStep Agent节点执行步骤（debug模式下通过agent_step事件推送）
+type AgentStep struct {
+	Node      string                 `json:"node"`               // 节点名称：Supervisor、Intent、CognitiveLoad、LearningPlanner、Science、Language、Humanities、Tool、Interaction、Reflection、Memory
+	Status    string                 `json:"status"`             // 状态：started、completed、failed
+	Decision  map[string]interface{} `json:"decision,optional"`  // 节点决策（结束时，如意图、策略、领域Agent、调用的工具）
+	LatencyMs int64                  `json:"latencyMs,optional"` // 节点耗时（毫秒，结束时）
+	Error     string                 `json:"error,optional"`     // 错误信息（失败时，降级结果仍记录在decision中）
+	Time      time.Time              `json:"time"`               // 事件时间
 }
 
@@ -18,12 +18,25 @@ type BadgeLevel struct {
 	Description string `json:"description"` // 等级描述
 }
 
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
 type CardContent struct {
-	Type    string                 `json:"type"`    // 卡片类型：science/poetry/english
+	Type    string                 `json:"type"`    // 卡片类型：science/poetry/english/math/history/art/safety/character
 	Title   string                 `json:"title"`   // 卡片标题
 	Content map[string]interface{} `json:"content"` // 卡片内容（根据类型不同结构不同）
 }
 
+type CardError struct {
+	Index   int    `json:"index"`   // 卡片在请求的卡片类型中的位置
+	Type    string `json:"type"`    // 卡片类型
+	Message string `json:"message"` // 错误信息
+}
+
 type ConversationMessage struct {
 	Id            string      `json:"id"`                     // 消息ID
 	Type          string      `json:"type"`                   // 消息类型：text/image/voice/card
@@ -37,35 +50,65 @@ type ConversationMessage struct {
 }
 
 type ConversationRequest struct {
-	Message               string                 `json:"message"`                        // 用户消息（文本）
-	Image                 string                 `json:"image,optional"`                 // 图片（base64，可选）
-	Voice                 string                 `json:"voice,optional"`                 // 语音（base64，可选）
-	SessionId             string                 `json:"sessionId,optional"`             // 会话ID（可选）
-	IdentificationContext *IdentificationContext `json:"identificationContext,optional"` // 识别结果上下文（可选）
+	Message               string                 `json:"message"`                          // 用户消息（文本）
+	Image                 string                 `json:"image,optional"`                   // 图片（base64，可选）
+	Voice                 string                 `json:"voice,optional"`                   // 语音（base64，可选）
+	SessionId             string                 `json:"sessionId,optional"`               // 会话ID（可选）
+	IdentificationContext *IdentificationContext `json:"identificationContext,optional"`   // 识别结果上下文（可选）
+	OwnerToken            string                 `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌（可选，未提供时为新会话生成）
 }
 
 type ConversationResponse struct {
-	Message   ConversationMessage `json:"message"`   // 回复消息
-	SessionId string              `json:"sessionId"` // 会话ID
-	Type      string              `json:"type"`      // 响应类型：text/cards
+	Message    ConversationMessage `json:"message"`             // 回复消息
+	SessionId  string              `json:"sessionId"`           // 会话ID
+	Type       string              `json:"type"`                // 响应类型：text/cards
+	OwnerToken string              `json:"ownerToken,optional"` // 会话所有者令牌（仅会话创建时返回，查看和删除会话时需提供）
 }
 
 type ConversationSession struct {
-	Id        string                `json:"id"`        // 会话ID
-	Messages  []ConversationMessage `json:"messages"`  // 消息列表
-	CreatedAt string                `json:"createdAt"` // 创建时间
-	UpdatedAt string                `json:"updatedAt"` // 更新时间
+	Id                    string                 `json:"id"`                             // 会话ID
+	Messages              []ConversationMessage  `json:"messages"`                       // 消息列表
+	IdentificationContext *IdentificationContext `json:"identificationContext,optional"` // 识别结果上下文（恢复对话时使用）
+	CreatedAt             string                 `json:"createdAt"`                      // 创建时间
+	UpdatedAt             string                 `json:"updatedAt"`                      // 更新时间
 }
 
 type CreateShareRequest struct {
-	ExplorationRecords []ExplorationRecord `json:"explorationRecords"` // 探索记录列表
-	CollectedCards     []KnowledgeCard     `json:"collectedCards"`     // 收藏的卡片列表
+	ExplorationRecords []ExplorationRecord `json:"explorationRecords"`      // 探索记录列表
+	CollectedCards     []KnowledgeCard     `json:"collectedCards"`          // 收藏的卡片列表
+	ExpiresInHours     int                 `json:"expiresInHours,optional"` // 有效期（小时，可选，默认7天）
 }
 
 type CreateShareResponse struct {
-	ShareId   string `json:"shareId"`   // 分享链接ID
-	ShareUrl  string `json:"shareUrl"`  // 分享链接URL
-	ExpiresAt string `json:"expiresAt"` // 过期时间
+	ShareId    string `json:"shareId"`    // 分享链接ID
+	ShareUrl   string `json:"shareUrl"`   // 分享链接URL
+	ExpiresAt  string `json:"expiresAt"`  // 过期时间
+	OwnerToken string `json:"ownerToken"` // 所有者令牌（仅创建时返回，撤销分享时需提供）
+}
+
+type DeleteSessionRequest struct {
+	SessionId  string `path:"sessionId"`                        // 会话ID
+	OwnerToken string `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌（创建会话时返回）
+}
+
+type DeleteSessionResponse struct {
+	SessionId string `json:"sessionId"` // 会话ID
+	Deleted   bool   `json:"deleted"`   // 是否已删除
+}
+
+type DetectedObject struct {
+	ObjectName     string       `json:"objectName"`            // 对象名称（中文）
+	ObjectCategory string       `json:"objectCategory"`        // 对象类别
+	CategoryPath   []string     `json:"categoryPath,optional"` // 归一化后的类别路径
+	Confidence     float64      `json:"confidence"`            // 识别置信度 0-1
+	Keywords       []string     `json:"keywords,optional"`     // 相关关键词
+	BoundingBox    *BoundingBox `json:"boundingBox,optional"`  // 对象在图片中的位置
+}
+
+type Disambiguation struct {
+	Question   string           `json:"question"`          // 询问孩子的话
+	Candidates []DetectedObject `json:"candidates"`        // 候选对象
+	ImageUrl   string           `json:"imageUrl,optional"` // 图片URL
 }
 
 type ErrorResponse struct {
@@ -75,28 +118,32 @@ type ErrorResponse struct {
 }
 
 type ExplorationRecord struct {
-	Id             string        `json:"id"`                 // 探索记录ID
-	Timestamp      string        `json:"timestamp"`          // 探索时间
-	ObjectName     string        `json:"objectName"`         // 对象名称
-	ObjectCategory string        `json:"objectCategory"`     // 对象类别
-	Age            int           `json:"age"`                // 探索时的年龄
-	ImageData      string        `json:"imageData,optional"` // 原始图片数据（base64，可选）
-	Cards          []CardContent `json:"cards"`              // 生成的知识卡片
+	Id             string        `json:"id"`                    // 探索记录ID
+	Timestamp      string        `json:"timestamp"`             // 探索时间
+	ObjectName     string        `json:"objectName"`            // 对象名称
+	ObjectCategory string        `json:"objectCategory"`        // 对象类别
+	CategoryPath   []string      `json:"categoryPath,optional"` // 归一化后的类别路径（识别接口返回的 categoryPath）
+	Age            int           `json:"age"`                   // 探索时的年龄
+	ImageData      string        `json:"imageData,optional"`    // 原始图片数据（base64，可选）
+	Cards          []CardContent `json:"cards"`                 // 生成的知识卡片
 }
 
 type GenerateCardsRequest struct {
-	ObjectName     string   `json:"objectName"`        // 对象名称
-	ObjectCategory string   `json:"objectCategory"`    // 对象类别
-	Age            int      `json:"age"`               // 孩子年龄（必填，用于内容分级）
-	Keywords       []string `json:"keywords,optional"` // 相关关键词
+	ObjectName     string   `json:"objectName"`         // 对象名称
+	ObjectCategory string   `json:"objectCategory"`     // 对象类别
+	Age            int      `json:"age"`                // 孩子年龄（必填，用于内容分级）
+	Keywords       []string `json:"keywords,optional"`  // 相关关键词
+	CardTypes      []string `json:"cardTypes,optional"` // 需要生成的卡片类型：science/poetry/english/math/history/art/safety/character（可选，默认 science/poetry/english）
 }
 
 type GenerateCardsResponse struct {
-	Cards []CardContent `json:"cards"` // 三张知识卡片
+	Cards  []CardContent `json:"cards"`           // 生成成功的知识卡片（按请求的卡片类型顺序）
+	Errors []CardError   `json:"errors,optional"` // 生成失败的卡片（部分成功时返回）
 }
 
 type GenerateReportRequest struct {
-	ShareId string `json:"shareId"` // 分享链接ID
+	ShareId       string `json:"shareId"`                // 分享链接ID
+	CategoryLevel int    `json:"categoryLevel,optional"` // 类别分布的统计层级：1为顶层类别（默认），2为子类，依此类推
 }
 
 type GenerateReportResponse struct {
@@ -113,6 +160,15 @@ type GetBadgeStatsRequest struct {
 	ConversationCount int `json:"conversationCount"` // 对话次数
 }
 
+type GetImageRequest struct {
+	Name string `path:"name"` // 图片文件名
+}
+
+type GetSessionRequest struct {
+	SessionId  string `path:"sessionId"`                        // 会话ID
+	OwnerToken string `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌（创建会话时返回）
+}
+
 type GetShareResponse struct {
 	ExplorationRecords []ExplorationRecord `json:"explorationRecords"` // 探索记录列表
 	CollectedCards     []KnowledgeCard     `json:"collectedCards"`     // 收藏的卡片列表
@@ -121,11 +177,12 @@ type GetShareResponse struct {
 }
 
 type IdentificationContext struct {
-	ObjectName     string   `json:"objectName"`        // 对象名称
-	ObjectCategory string   `json:"objectCategory"`    // 对象类别
-	Confidence     float64  `json:"confidence"`        // 识别置信度
-	Keywords       []string `json:"keywords,optional"` // 相关关键词
-	Age            int      `json:"age,optional"`      // 用户年龄
+	ObjectName     string       `json:"objectName"`           // 对象名称
+	ObjectCategory string       `json:"objectCategory"`       // 对象类别
+	Confidence     float64      `json:"confidence"`           // 识别置信度
+	Keywords       []string     `json:"keywords,optional"`    // 相关关键词
+	Age            int          `json:"age,optional"`         // 用户年龄
+	BoundingBox    *BoundingBox `json:"boundingBox,optional"` // 选中对象在图片中的位置（多对象图片）
 }
 
 type IdentifyRequest struct {
@@ -134,10 +191,13 @@ type IdentifyRequest struct {
 }
 
 type IdentifyResponse struct {
-	ObjectName     string   `json:"objectName"`        // 对象名称（中文）
-	ObjectCategory string   `json:"objectCategory"`    // 对象类别：自然类/生活类/人文类
-	Confidence     float64  `json:"confidence"`        // 识别置信度 0-1
-	Keywords       []string `json:"keywords,optional"` // 相关关键词
+	ObjectName          string           `json:"objectName"`                   // 对象名称（中文）
+	ObjectCategory      string           `json:"objectCategory"`               // 对象类别：自然类/生活类/人文类
+	CategoryPath        []string         `json:"categoryPath,optional"`        // 归一化后的类别路径，如 ["自然类","植物","花卉"]
+	Confidence          float64          `json:"confidence"`                   // 识别置信度 0-1
+	Keywords            []string         `json:"keywords,optional"`            // 相关关键词
+	Objects             []DetectedObject `json:"objects,optional"`             // 图片中的候选对象（按置信度从高到低，第一个即上面的主对象）
+	NeedsDisambiguation bool             `json:"needsDisambiguation,optional"` // 置信度较低且有多个候选对象，需要孩子选择
 }
 
 type IntentRequest struct {
@@ -161,12 +221,81 @@ type KnowledgeCard struct {
 	CollectedAt   string                 `json:"collectedAt,optional"` // 收藏时间
 }
 
+type ListSessionsRequest struct {
+	Page       int    `form:"page,optional,default=1"`          // 页码（从1开始）
+	PageSize   int    `form:"pageSize,optional,default=20"`     // 每页数量（最大100）
+	OwnerToken string `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌，只列出该令牌的会话
+}
+
+type ListSessionsResponse struct {
+	Sessions []SessionSummary `json:"sessions"` // 会话列表（按最后活动时间倒序）
+	Total    int              `json:"total"`    // 会话总数
+	Page     int              `json:"page"`     // 页码
+	PageSize int              `json:"pageSize"` // 每页数量
+}
+
+type ModelStat struct {
+	Role                string  `json:"role"`                 // 模型角色：intent/vision/text/card/image_gen
+	Model               string  `json:"model"`                // 模型名称
+	Provider            string  `json:"provider"`             // 提供方：ark/openai/ollama
+	Policy              string  `json:"policy"`               // 路由策略：weighted/priority
+	Weight              float64 `json:"weight"`               // 路由权重（weighted策略）
+	State               string  `json:"state"`                // 熔断状态：closed/open/half_open
+	Requests            int64   `json:"requests"`             // 调用次数
+	Successes           int64   `json:"successes"`            // 成功次数
+	Failures            int64   `json:"failures"`             // 失败次数
+	ConsecutiveFailures int     `json:"consecutiveFailures"`  // 连续失败次数
+	SuccessRate         float64 `json:"successRate"`          // 近期成功率 0-1（指数移动平均）
+	AvgLatencyMs        int64   `json:"avgLatencyMs"`         // 近期成功调用的平均延迟（毫秒）
+	LastError           string  `json:"lastError,optional"`   // 最近一次错误
+	LastErrorAt         string  `json:"lastErrorAt,optional"` // 最近一次错误时间
+	RetryAt             string  `json:"retryAt,optional"`     // 熔断中的模型允许探测的时间
+}
+
+type ModelStatsRequest struct {
+	AdminToken string `header:"X-Admin-Token,optional"` // 管理接口令牌（与 ADMIN_TOKEN 一致）
+}
+
+type ModelStatsResponse struct {
+	Models []ModelStat `json:"models"` // 各角色模型的路由状态
+}
+
+type PhotoExploreRequest struct {
+	Image      string   `json:"image"`                            // 图片（base64或URL）
+	Age        int      `json:"age"`                              // 孩子年龄（必填，用于内容分级）
+	SessionId  string   `json:"sessionId,optional"`               // 会话ID（可选，未提供时创建新会话）
+	CardTypes  []string `json:"cardTypes,optional"`               // 需要生成的卡片类型（可选，默认 science/poetry/english）
+	OwnerToken string   `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌（可选，未提供时为新会话生成）
+}
+
 type RecentUpgrade struct {
 	FromLevel  int    `json:"fromLevel"`  // 原等级
 	ToLevel    int    `json:"toLevel"`    // 新等级
 	UpgradedAt string `json:"upgradedAt"` // 升级时间
 }
 
+type RevokeShareRequest struct {
+	ShareId    string `path:"shareId"`                        // 分享链接ID
+	OwnerToken string `header:"X-Share-Owner-Token,optional"` // 所有者令牌（创建分享时返回）
+}
+
+type RevokeShareResponse struct {
+	ShareId string `json:"shareId"` // 分享链接ID
+	Revoked bool   `json:"revoked"` // 是否已撤销
+}
+
+type SessionSummary struct {
+	Id                string `json:"id"`                         // 会话ID
+	ObjectName        string `json:"objectName,optional"`        // 识别对象名称
+	ObjectCategory    string `json:"objectCategory,optional"`    // 识别对象类别
+	LastMessage       string `json:"lastMessage"`                // 最后一条消息预览
+	LastMessageType   string `json:"lastMessageType,optional"`   // 最后一条消息类型：text/image/voice/card
+	LastMessageSender string `json:"lastMessageSender,optional"` // 最后一条消息发送者：user/assistant
+	MessageCount      int    `json:"messageCount"`               // 消息数量
+	CreatedAt         string `json:"createdAt"`                  // 创建时间
+	UpdatedAt         string `json:"updatedAt"`                  // 最后活动时间
+}
+
 type StreamConversationRequest struct {
 	SessionId             string                 `json:"sessionId,optional"`             // 会话ID，如果为空则创建新会话
 	Message               string                 `json:"message"`                        // 用户消息内容（文本）
@@ -179,36 +308,63 @@ type StreamConversationRequest struct {
 }
 
 type StreamEvent struct {
-	Type      string      `json:"type"`               // 事件类型：connected/message/image_progress/image_done/card/error/done
-	Content   interface{} `json:"content"`            // 事件内容
-	Index     int         `json:"index,optional"`     // 文本消息的字符索引（用于打字机效果）
-	Progress  int         `json:"progress,optional"`  // 图片生成进度（0-100）
-	SessionId string      `json:"sessionId,optional"` // 会话ID
-	MessageId string      `json:"messageId,optional"` // 消息ID
-	Markdown  bool        `json:"markdown,optional"`  // 内容是否包含Markdown格式（仅文本消息）
+	Type       string      `json:"type"`                // 事件类型：connected/identified/message/image_progress/image_done/card/card_error/audio/agent_step/disambiguation/error/done
+	Content    interface{} `json:"content"`             // 事件内容
+	Index      int         `json:"index,optional"`      // 文本消息的字符索引（用于打字机效果）；卡片事件为卡片在请求的卡片类型中的位置（默认 0-科学卡, 1-诗词卡, 2-英语卡）
+	Progress   int         `json:"progress,optional"`   // 图片生成进度（0-100）
+	SessionId  string      `json:"sessionId,optional"`  // 会话ID
+	MessageId  string      `json:"messageId,optional"`  // 消息ID
+	Markdown   bool        `json:"markdown,optional"`   // 内容是否包含Markdown格式（仅文本消息）
+	OwnerToken string      `json:"ownerToken,optional"` // 会话所有者令牌（仅在新会话的第一个事件中返回，查看和删除会话时需提供）
+}
+
+type TTSRequest struct {
+	Text  string  `json:"text"`           // 待合成文本（支持Markdown，朗读前会去除格式符号和emoji）
+	Voice string  `json:"voice,optional"` // 发音人（可选，默认使用服务配置；只能包含字母、数字和 +_-）
+	Speed float64 `json:"speed,optional"` // 语速倍率（可选，1.0为正常语速，超出0.25-4.0时取边界值）
+}
+
+type TTSResponse struct {
+	Audio    string  `json:"audio"`             // base64编码的音频数据
+	Format   string  `json:"format"`            // 音频格式：wav/mp3
+	MimeType string  `json:"mimeType"`          // 音频MIME类型
+	Duration float64 `json:"duration,optional"` // 音频时长（秒），无法解析时为0
+	Text     string  `json:"text"`              // 实际朗读的文本
 }
 
 type UnifiedStreamConversationRequest struct {
-	MessageType           string                 `json:"messageType"`                    // 消息类型（必填）：text/voice/image
-	Message               string                 `json:"message,optional"`               // 文本消息，当messageType为text时必填
-	Audio                 string                 `json:"audio,optional"`                 // 语音数据（base64），当messageType为voice时必填
-	Image                 string                 `json:"image,optional"`                 // 图片数据（base64或URL），当messageType为image时必填
-	SessionId             string                 `json:"sessionId,optional"`             // 会话ID，如果为空则创建新会话
-	IdentificationContext *IdentificationContext `json:"identificationContext,optional"` // 识别结果上下文（可选）
-	UserAge               int                    `json:"userAge,optional"`               // 用户年龄（3-18岁），用于内容适配
-	MaxContextRounds      int                    `json:"maxContextRounds,optional"`      // 最大上下文轮次，默认20轮
+	MessageType           string                 `json:"messageType"`                      // 消息类型（必填）：text/voice/image
+	Message               string                 `json:"message,optional"`                 // 文本消息，当messageType为text时必填
+	Audio                 string                 `json:"audio,optional"`                   // 语音数据（base64），当messageType为voice时必填
+	Image                 string                 `json:"image,optional"`                   // 图片数据（base64或URL），当messageType为image时必填
+	SessionId             string                 `json:"sessionId,optional"`               // 会话ID，如果为空则创建新会话
+	IdentificationContext *IdentificationContext `json:"identificationContext,optional"`   // 识别结果上下文（可选）
+	UserAge               int                    `json:"userAge,optional"`                 // 用户年龄（3-18岁），用于内容适配
+	MaxContextRounds      int                    `json:"maxContextRounds,optional"`        // 最大上下文轮次，默认20轮
+	Tts                   *bool                  `json:"tts,optional"`                     // 是否附带语音（audio事件），未指定时低龄儿童自动开启
+	Debug                 bool                   `json:"debug,optional"`                   // 是否推送Agent执行步骤（agent_step事件），用于排查回答原因
+	LearnerId             string                 `json:"learnerId,optional"`               // 孩子标识（可选），用于跨会话的学习档案
+	OwnerToken            string                 `header:"X-Session-Owner-Token,optional"` // 会话所有者令牌（可选，未提供时为新会话生成）
 }
 
 type UploadRequest struct {
 	ImageData string `json:"imageData"`         // base64编码的图片数据（不含 data URL 前缀）
-	Filename  string `json:"filename,optional"` // 可选：文件名（如果不提供，后端自动生成）
+	Filename  string `json:"filename,optional"` // 可选：原始文件名（仅作记录，存储时统一按内容摘要命名）
 }
 
 type UploadResponse struct {
-	Url          string `json:"url"`                   // 图片的访问URL（GitHub raw URL 或 base64 data URL）
-	Filename     string `json:"filename"`              // 实际存储的文件名
-	Size         int    `json:"size,optional"`         // 图片大小（字节）
-	UploadMethod string `json:"uploadMethod,optional"` // 上传方式（"github" 或 "base64"）
+	Url              string `json:"url"`                       // 图片的访问URL（图片存储地址或 base64 data URL）
+	Filename         string `json:"filename"`                  // 实际存储的文件名
+	OriginalFilename string `json:"originalFilename,optional"` // 客户端提供的原始文件名（仅作记录）
+	Size             int    `json:"size,optional"`             // 处理后的图片大小（字节）
+	UploadMethod     string `json:"uploadMethod,optional"`     // 上传方式（"github"、"local"、"s3" 或 "base64"）
+	OriginalSize     int    `json:"originalSize,optional"`     // 原图大小（字节）
+	Width            int    `json:"width,optional"`            // 处理后的宽度（像素）
+	Height           int    `json:"height,optional"`           // 处理后的高度（像素）
+	OriginalWidth    int    `json:"originalWidth,optional"`    // 原图宽度（像素，按EXIF方向旋转后）
+	OriginalHeight   int    `json:"originalHeight,optional"`   // 原图高度（像素，按EXIF方向旋转后）
+	ThumbnailUrl     string `json:"thumbnailUrl,optional"`     // 缩略图URL
+	Deduplicated     bool   `json:"deduplicated,optional"`     // 是否复用了内容相同的已上传图片
 }
 
 type UserStats struct {
@@ -228,6 +384,18 @@ type VoiceRequest struct {
 }
 
 type VoiceResponse struct {
-	Text      string `json:"text"`               // 识别的文本
-	SessionId string `json:"sessionId,optional"` // 会话ID（可选）
+	Text       string         `json:"text"`                // 识别的文本
+	SessionId  string         `json:"sessionId,optional"`  // 会话ID（可选）
+	Format     string         `json:"format,optional"`     // 音频格式：wav/webm/mp3/m4a
+	Duration   float64        `json:"duration,optional"`   // 音频时长（秒）
+	Language   string         `json:"language,optional"`   // 识别出的语言
+	Confidence float64        `json:"confidence,optional"` // 整体置信度 0-1
+	Segments   []VoiceSegment `json:"segments,optional"`   // 分段识别结果
+}
+
+type VoiceSegment struct {
+	Text       string  `json:"text"`       // 分段文本
+	Start      float64 `json:"start"`      // 开始时间（秒）
+	End        float64 `json:"end"`        // 结束时间（秒）
+	Confidence float64 `json:"confidence"` // 置信度 0-1
 }
@@ -41,14 +41,38 @@ var (
 	ErrInvalidAge         = NewAPIError(http.StatusBadRequest, "年龄必须在3-18之间")
 	ErrObjectNameRequired = NewAPIError(http.StatusBadRequest, "对象名称不能为空")
 	ErrCategoryRequired   = NewAPIError(http.StatusBadRequest, "对象类别不能为空")
+	ErrInvalidCardType    = NewAPIError(http.StatusBadRequest, "不支持的卡片类型")
 	ErrShareNotFound      = NewAPIError(http.StatusNotFound, "分享链接不存在或已过期")
+	ErrShareForbidden     = NewAPIError(http.StatusForbidden, "无权撤销该分享链接")
+	ErrSessionNotFound    = NewAPIError(http.StatusNotFound, "会话不存在或已过期")
+	ErrSessionForbidden   = NewAPIError(http.StatusForbidden, "无权访问该会话")
 	ErrInternalServer     = NewAPIError(http.StatusInternalServerError, "服务器内部错误")
+	// 管理接口相关错误
+	ErrAdminDisabled     = NewAPIError(http.StatusNotFound, "管理接口未启用")
+	ErrAdminUnauthorized = NewAPIError(http.StatusUnauthorized, "管理接口令牌无效")
 	// 图片上传相关错误
 	ErrImageDataRequired  = NewAPIError(http.StatusBadRequest, "图片数据不能为空")
 	ErrImageDataInvalid   = NewAPIError(http.StatusBadRequest, "图片数据格式无效")
 	ErrImageTooLarge      = NewAPIError(http.StatusBadRequest, "图片大小超过限制")
 	ErrImageFormatInvalid = NewAPIError(http.StatusBadRequest, "不支持的图片格式")
 	ErrFilenameInvalid    = NewAPIError(http.StatusBadRequest, "文件名格式无效")
+	ErrImageNotFound      = NewAPIError(http.StatusNotFound, "图片不存在")
 	ErrGitHubUploadFailed = NewAPIError(http.StatusBadGateway, "GitHub 上传失败")
 	ErrGitHubRateLimit    = NewAPIError(http.StatusTooManyRequests, "GitHub API 速率限制")
+	// 语音识别相关错误
+	ErrAudioRequired       = NewAPIError(http.StatusBadRequest, "音频数据不能为空")
+	ErrAudioDataInvalid    = NewAPIError(http.StatusBadRequest, "音频数据格式无效")
+	ErrAudioFormatInvalid  = NewAPIError(http.StatusBadRequest, "不支持的音频格式，仅支持 wav/webm/mp3/m4a")
+	ErrAudioTooLarge       = NewAPIError(http.StatusBadRequest, "音频大小超过限制")
+	ErrAudioTooLong        = NewAPIError(http.StatusBadRequest, "音频时长超过限制")
+	ErrSpeechNotRecognized = NewAPIError(http.StatusUnprocessableEntity, "未识别到有效语音")
+	ErrASRUnavailable      = NewAPIError(http.StatusServiceUnavailable, "语音识别服务未配置")
+	ErrASRFailed           = NewAPIError(http.StatusBadGateway, "语音识别失败")
+
+	// 语音合成相关错误
+	ErrTTSTextRequired = NewAPIError(http.StatusBadRequest, "合成文本不能为空")
+	ErrTTSTextTooLong  = NewAPIError(http.StatusBadRequest, "合成文本超过长度限制")
+	ErrTTSVoiceInvalid = NewAPIError(http.StatusBadRequest, "发音人名称无效")
+	ErrTTSUnavailable  = NewAPIError(http.StatusServiceUnavailable, "语音合成服务未配置")
+	ErrTTSFailed       = NewAPIError(http.StatusBadGateway, "语音合成失败")
 )